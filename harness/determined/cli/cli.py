@@ -21,6 +21,7 @@ from determined.cli import (
     checkpoint,
     command,
     config_policies,
+    deployment,
     dev,
     errors,
     experiment,
@@ -163,6 +164,7 @@ all_args_description: cli.ArgsDescription = (
     + workspace.args_description
     + sso.args_description
     + oauth.args_description
+    + deployment.args_description
     + dev.args_description
     + config_policies.args_description
 )
@@ -0,0 +1,184 @@
+import argparse
+from typing import Any, List
+
+from determined import cli
+from determined.cli import ntsc, render, workspace
+from determined.common.api import bindings
+
+
+def _print_deployment(d: bindings.v1Deployment) -> None:
+    print(f"Deployment {d.name} ({d.id}): {d.state.value.replace('DEPLOYMENT_STATE_', '')}")
+    print(f"Model:    {d.modelName} version {d.modelVersion} (checkpoint {d.checkpointUuid})")
+    print(f"Replicas: {sum(r.ready for r in d.replicas)} ready of {d.targetReplicas}")
+    print(f"Route:    {d.route}")
+
+
+def create(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    config = ntsc.parse_config(args.config_file, args.entrypoint, args.config, args.volume)
+    req = bindings.v1CreateDeploymentRequest(
+        name=args.name,
+        modelName=args.model_name,
+        modelVersion=args.version,
+        replicas=args.replicas,
+        workspaceId=workspace.get_workspace_id_from_args(args),
+        config=config,
+        templateName=args.template,
+        healthPath=args.health_path,
+    )
+    resp = bindings.post_CreateDeployment(sess, body=req)
+    if args.json:
+        render.print_json(resp.deployment.to_json())
+        return
+
+    if resp.warnings:
+        cli.print_launch_warnings(resp.warnings)
+    _print_deployment(resp.deployment)
+    print(f"Requests are served at {sess.master}{resp.deployment.route}")
+
+
+def list_deployments(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    deployments = bindings.get_GetDeployments(
+        sess, workspaceId=workspace.get_workspace_id_from_args(args)
+    ).deployments
+    if args.json:
+        render.print_json([d.to_json() for d in deployments])
+        return
+
+    headers = ["ID", "Name", "Model", "Version", "State", "Ready", "Replicas", "Route"]
+    values = [
+        [
+            d.id,
+            d.name,
+            d.modelName,
+            d.modelVersion,
+            d.state.value.replace("DEPLOYMENT_STATE_", ""),
+            sum(r.ready for r in d.replicas),
+            d.targetReplicas,
+            d.route,
+        ]
+        for d in deployments
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def describe(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    d = bindings.get_GetDeployment(sess, id=args.deployment_id).deployment
+    if args.json:
+        render.print_json(d.to_json())
+        return
+
+    _print_deployment(d)
+    print()
+    headers = ["Task ID", "Version", "State", "Ready", "Stopping"]
+    values = [
+        [r.taskId, r.modelVersion, r.state.value.replace("STATE_", ""), r.ready, r.stopping]
+        for r in d.replicas
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def _patch(args: argparse.Namespace, req: bindings.v1PatchDeploymentRequest) -> None:
+    sess = cli.setup_session(args)
+    d = bindings.patch_PatchDeployment(sess, body=req, id=args.deployment_id).deployment
+    _print_deployment(d)
+
+
+def scale(args: argparse.Namespace) -> None:
+    _patch(args, bindings.v1PatchDeploymentRequest(id=args.deployment_id, replicas=args.replicas))
+
+
+def update(args: argparse.Namespace) -> None:
+    _patch(
+        args, bindings.v1PatchDeploymentRequest(id=args.deployment_id, modelVersion=args.version)
+    )
+
+
+def delete(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    bindings.delete_DeleteDeployment(sess, id=args.deployment_id)
+    print(f"Deleting deployment {args.deployment_id}; its replicas are being stopped")
+
+
+deployment_id_arg = cli.Arg("deployment_id", type=int, help="deployment ID")
+
+args_description: List[Any] = [
+    cli.Cmd(
+        "deployment",
+        None,
+        "manage model deployments",
+        [
+            cli.Cmd(
+                "list ls",
+                list_deployments,
+                "list deployments",
+                [workspace.workspace_arg, cli.output_format_args["json"]],
+                is_default=True,
+            ),
+            cli.Cmd(
+                "describe",
+                describe,
+                "describe a deployment and its replicas",
+                [deployment_id_arg, cli.output_format_args["json"]],
+            ),
+            cli.Cmd(
+                "create",
+                create,
+                "deploy a model version",
+                [
+                    cli.Arg("name", help="deployment name, used in its route"),
+                    cli.Arg("model_name", help="name or ID of the model to deploy"),
+                    cli.Arg("version", type=int, help="model version to deploy"),
+                    cli.Arg(
+                        "entrypoint",
+                        type=str,
+                        nargs=argparse.REMAINDER,
+                        help="serving command, which must listen on $DET_DEPLOYMENT_PORT and "
+                        "can load the checkpoint from $DET_CHECKPOINT_DIR",
+                    ),
+                    cli.Arg("-r", "--replicas", type=int, default=1, help="number of replicas"),
+                    cli.Arg(
+                        "--health-path",
+                        default="/",
+                        help="path replicas are polled on until they answer",
+                    ),
+                    cli.Arg(
+                        "--config-file",
+                        default=None,
+                        type=argparse.FileType("r"),
+                        help="command config file (.yaml)",
+                    ),
+                    cli.Arg("-v", "--volume", action="append", default=[], help=ntsc.VOLUME_DESC),
+                    workspace.workspace_arg,
+                    cli.Arg("--config", action="append", default=[], help=ntsc.CONFIG_DESC),
+                    cli.Arg(
+                        "--template",
+                        type=str,
+                        help="name of template to apply to the replica configuration",
+                    ),
+                    cli.output_format_args["json"],
+                ],
+            ),
+            cli.Cmd(
+                "scale",
+                scale,
+                "change the number of replicas of a deployment",
+                [deployment_id_arg, cli.Arg("replicas", type=int, help="number of replicas")],
+            ),
+            cli.Cmd(
+                "update",
+                update,
+                "roll a deployment to another version of its model",
+                [deployment_id_arg, cli.Arg("version", type=int, help="model version")],
+            ),
+            cli.Cmd(
+                "delete",
+                delete,
+                "stop the replicas of a deployment and delete it",
+                [deployment_id_arg],
+            ),
+        ],
+    )
+]
@@ -603,6 +603,194 @@ def download_model_def(args: argparse.Namespace) -> None:
         f.write(base64.b64decode(resp.b64Tgz))
 
 
+def export_experiment(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    resp = sess.get(
+        f"experiments/{args.experiment_id}/export",
+        params={"include_logs": str(args.include_logs).lower()},
+        stream=True,
+    )
+    output = args.output or pathlib.Path(f"experiment_{args.experiment_id}_bundle.tar.gz")
+    project.write_bundle(resp, output)
+
+
+def export_experiment_metrics(args: argparse.Namespace) -> None:
+    ids = ",".join(str(i) for i in args.experiment_ids)
+    name = "experiments"
+    if len(args.experiment_ids) == 1:
+        name = f"experiment_{args.experiment_ids[0]}"
+    project.export_run_metrics(args, {"experiment_ids": ids}, name)
+
+
+def import_bundle(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    params: Dict[str, Any] = {"dry_run": str(args.dry_run).lower()}
+    if args.project_id is not None:
+        params["project_id"] = args.project_id
+    if args.workspace_map:
+        params["workspace_map"] = ",".join(args.workspace_map)
+    if args.user_map:
+        params["user_map"] = ",".join(args.user_map)
+
+    with args.bundle.open("rb") as f:
+        resp = sess.post("experiments/import", params=params, data=f.read()).json()
+
+    headers = ["Source ID", "Name", "ID", "Owner", "Project ID", "Trials", "Checkpoints", "Notes"]
+    values = [
+        [
+            e["source_id"],
+            e["name"],
+            e.get("id", ""),
+            e["owner"],
+            e["project_id"],
+            e["trials"],
+            e["checkpoints"],
+            "\n".join(
+                [f"error: {msg}" for msg in e.get("errors") or []]
+                + [f"warning: {msg}" for msg in e.get("warnings") or []]
+            ),
+        ]
+        for e in resp["experiments"]
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+    if resp["imported"]:
+        print(f"Imported {len(resp['experiments'])} experiment(s)")
+    elif resp["dry_run"]:
+        print("Dry run; nothing was imported")
+    else:
+        raise cli.CliError("Bundle cannot be imported; see the errors above")
+
+
+def _render_schedules(schedules: Sequence[bindings.v1ExperimentSchedule], as_json: bool) -> None:
+    if as_json:
+        render.print_json([s.to_json() for s in schedules])
+        return
+    headers = ["ID", "Name", "Cron", "Project ID", "Owner", "Paused", "Next Run", "Last Run"]
+    values = [
+        [
+            s.id,
+            s.name,
+            s.cron,
+            s.projectId,
+            s.username,
+            s.paused,
+            render.format_time(s.nextRunTime) if not s.paused else "",
+            f"{render.format_time(s.lastRun.runTime)} ({s.lastRun.status.name.lower()})"
+            if s.lastRun
+            else "",
+        ]
+        for s in schedules
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def create_schedule(args: argparse.Namespace) -> None:
+    if args.config_file is None and args.experiment_id is None:
+        raise cli.CliError(
+            "Please provide a config file, a source experiment with --experiment, or both"
+        )
+    body = bindings.v1PostExperimentScheduleRequest(
+        name=args.name,
+        cron=args.cron,
+        description=args.description,
+        config=args.config_file.read() if args.config_file else None,
+        template=args.template,
+        experimentId=args.experiment_id,
+        projectId=args.project_id,
+        skipIfRunning=args.skip_if_running,
+        paused=args.paused,
+    )
+    resp = bindings.post_PostExperimentSchedule(cli.setup_session(args), body=body)
+    print(f"Created experiment schedule {resp.schedule.id}")
+
+
+def list_schedules(args: argparse.Namespace) -> None:
+    resp = bindings.get_GetExperimentSchedules(cli.setup_session(args), projectId=args.project_id)
+    _render_schedules(resp.schedules, args.json)
+
+
+def describe_schedule(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    s = bindings.get_GetExperimentSchedule(sess, id=args.schedule_id).schedule
+    if args.json:
+        render.print_json(s.to_json())
+        return
+    _render_schedules([s], False)
+    if s.modelDefinitionExperimentId is not None:
+        print(f"Model definition from experiment {s.modelDefinitionExperimentId}")
+    if s.template:
+        print(f"Template: {s.template}")
+    print("Config:")
+    print(s.config)
+
+
+def list_schedule_runs(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    runs = api.read_paginated(
+        lambda offset: bindings.get_GetExperimentScheduleRuns(
+            sess, id=args.schedule_id, offset=offset, limit=args.limit
+        ),
+        offset=args.offset,
+        pages=args.pages,
+    )
+    headers = ["Run Time", "Status", "Experiment ID", "Message"]
+    values = [
+        [
+            render.format_time(r.runTime),
+            r.status.name.lower(),
+            r.experimentId or "",
+            r.message or "",
+        ]
+        for page in runs
+        for r in page.runs
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
+def pause_schedule(args: argparse.Namespace) -> None:
+    bindings.post_PauseExperimentSchedule(cli.setup_session(args), id=args.schedule_id)
+    print(f"Paused experiment schedule {args.schedule_id}")
+
+
+def unpause_schedule(args: argparse.Namespace) -> None:
+    bindings.post_UnpauseExperimentSchedule(cli.setup_session(args), id=args.schedule_id)
+    print(f"Unpaused experiment schedule {args.schedule_id}")
+
+
+def delete_schedule(args: argparse.Namespace) -> None:
+    if not args.yes and not render.yes_or_no(
+        "Deleting a schedule stops it from creating experiments. Experiments it already\n"
+        "created are kept. Do you still want to proceed?"
+    ):
+        raise cli.CliError("Aborting deletion of schedule.")
+    bindings.delete_DeleteExperimentSchedule(cli.setup_session(args), id=args.schedule_id)
+    print(f"Deleted experiment schedule {args.schedule_id}")
+
+
+def diff_experiments(args: argparse.Namespace) -> None:
+    resp = bindings.get_GetExperimentsDiff(
+        cli.setup_session(args),
+        experimentId=args.experiment_id,
+        otherExperimentId=args.other_experiment_id,
+        includeModelDefinition=args.model_definition,
+    )
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+    render.print_value_diffs("Config", resp.config)
+    render.print_value_diffs("Hyperparameters", resp.hyperparameters)
+    if not args.model_definition:
+        return
+    print("Model definition:")
+    if not resp.modelDefinition:
+        print("  (no differences)")
+    for f in resp.modelDefinition:
+        if f.binary:
+            print(f"Binary files a/{f.path} and b/{f.path} differ")
+        else:
+            print(f.unifiedDiff, end="")
+
 def download(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     exp = client.Experiment(args.experiment_id, sess)
@@ -994,6 +1182,21 @@ main_cmd = cli.Cmd(
                 *trial.logs_args_description,
             ],
         ),
+        cli.Cmd(
+            "diff",
+            diff_experiments,
+            "show the differences between the configs of two experiments",
+            [
+                experiment_id_arg("experiment ID"),
+                cli.Arg("other_experiment_id", type=int, help="experiment ID to compare with"),
+                cli.Arg(
+                    "--model-definition",
+                    action="store_true",
+                    help="also show the differences between the model definitions",
+                ),
+                cli.output_format_args["json"],
+            ],
+        ),
         cli.Cmd(
             "download-model-def",
             download_model_def,
@@ -1003,6 +1206,154 @@ main_cmd = cli.Cmd(
                 cli.Arg("--output-dir", type=pathlib.Path, help="output directory", default="."),
             ],
         ),
+        cli.Cmd(
+            "export",
+            export_experiment,
+            "export an experiment as a bundle that can be imported into another cluster",
+            [
+                experiment_id_arg("experiment ID"),
+                cli.Arg("-o", "--output", type=pathlib.Path, help="bundle file to write"),
+                cli.Arg("--include-logs", action="store_true", help="include trial logs"),
+            ],
+        ),
+        cli.Cmd(
+            "export-metrics",
+            export_experiment_metrics,
+            "export the metrics, hyperparameters and metadata of the trials of experiments",
+            [
+                cli.Arg("experiment_ids", type=int, nargs="+", help="experiment IDs"),
+                *project.export_metrics_args,
+            ],
+        ),
+        cli.Cmd(
+            "import",
+            import_bundle,
+            "import the experiments of a bundle",
+            [
+                cli.Arg("bundle", type=pathlib.Path, help="bundle file to import"),
+                cli.Arg(
+                    "--project-id",
+                    type=int,
+                    help="project to import into; by default, experiments are imported into "
+                    "projects with the same names as their original projects",
+                ),
+                cli.Arg(
+                    "--workspace-map",
+                    action="append",
+                    metavar="OLD=NEW",
+                    help="import experiments of workspace OLD into workspace NEW (repeatable)",
+                ),
+                cli.Arg(
+                    "--user-map",
+                    action="append",
+                    metavar="OLD=NEW",
+                    help="make user NEW the owner of experiments owned by OLD (repeatable)",
+                ),
+                cli.Arg(
+                    "--dry-run",
+                    action="store_true",
+                    help="show what would be imported without importing anything",
+                ),
+            ],
+        ),
+        cli.Cmd(
+            "schedule",
+            None,
+            "manage schedules that create experiments on a cron expression",
+            [
+                cli.Cmd(
+                    "create",
+                    create_schedule,
+                    "create an experiment schedule",
+                    [
+                        cli.Arg("name", help="name of the schedule"),
+                        cli.Arg(
+                            "cron",
+                            help="five-field cron expression in UTC, or in the zone of a "
+                            "CRON_TZ=<zone> prefix, e.g. \"CRON_TZ=America/New_York 0 2 * * *\"",
+                        ),
+                        cli.Arg(
+                            "config_file",
+                            type=argparse.FileType("r"),
+                            nargs="?",
+                            help="experiment config file (.yaml); may reference {{.Date}}, "
+                            "{{.Time}} and {{.ScheduleName}}",
+                        ),
+                        cli.Arg(
+                            "--experiment",
+                            dest="experiment_id",
+                            type=int,
+                            help="experiment whose model definition every run uses, and whose "
+                            "config is used if no config file is given",
+                        ),
+                        cli.Arg("--description", help="description of the schedule"),
+                        cli.Arg("--template", help="name of the template to apply"),
+                        cli.Arg("--project-id", type=int, help="project to create runs in"),
+                        cli.Arg(
+                            "--skip-if-running",
+                            action="store_true",
+                            help="skip a run if the experiment of the previous run is active",
+                        ),
+                        cli.Arg("--paused", action="store_true", help="create the schedule paused"),
+                    ],
+                ),
+                cli.Cmd(
+                    "list ls",
+                    list_schedules,
+                    "list experiment schedules",
+                    [
+                        cli.Arg("--project-id", type=int, help="only list schedules of a project"),
+                        cli.output_format_args["json"],
+                    ],
+                    is_default=True,
+                ),
+                cli.Cmd(
+                    "describe",
+                    describe_schedule,
+                    "describe an experiment schedule",
+                    [
+                        cli.Arg("schedule_id", type=int, help="schedule ID"),
+                        cli.output_format_args["json"],
+                    ],
+                ),
+                cli.Cmd(
+                    "runs",
+                    list_schedule_runs,
+                    "list the runs of an experiment schedule",
+                    [
+                        cli.Arg("schedule_id", type=int, help="schedule ID"),
+                        *cli.default_pagination_args,
+                        cli.Arg("--csv", action="store_true", help="print as CSV"),
+                    ],
+                ),
+                cli.Cmd(
+                    "pause",
+                    pause_schedule,
+                    "pause an experiment schedule",
+                    [cli.Arg("schedule_id", type=int, help="schedule ID")],
+                ),
+                cli.Cmd(
+                    "unpause",
+                    unpause_schedule,
+                    "unpause an experiment schedule",
+                    [cli.Arg("schedule_id", type=int, help="schedule ID")],
+                ),
+                cli.Cmd(
+                    "delete",
+                    delete_schedule,
+                    "delete an experiment schedule",
+                    [
+                        cli.Arg("schedule_id", type=int, help="schedule ID"),
+                        cli.Arg(
+                            "--yes",
+                            action="store_true",
+                            default=False,
+                            help="automatically answer yes to prompts",
+                        ),
+                    ],
+                ),
+            ],
+        ),
         cli.Cmd(
             "list-trials lt",
             list_trials,
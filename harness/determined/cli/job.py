@@ -66,6 +66,12 @@ def ls(args: argparse.Namespace) -> None:
         else:
             return job.name
 
+    def slots(job: Union[bindings.v1Job, bindings.v1LimitedJob]) -> str:
+        slots = f"{job.allocatedSlots}/{job.requestedSlots}"
+        if job.targetSlots and job.targetSlots != job.requestedSlots:
+            slots += f" (resizing to {job.targetSlots})"
+        return slots
+
     values = [
         [
             j.summary.jobsAhead if j.summary is not None and j.summary.jobsAhead > -1 else "N/A",
@@ -76,7 +82,7 @@ def ls(args: argparse.Namespace) -> None:
             util.parse_protobuf_timestamp(j.submissionTime).astimezone(datetime.timezone.utc)
             if isinstance(j, bindings.v1Job)
             else render.OMITTED_VALUE,
-            f"{j.allocatedSlots}/{j.requestedSlots}",
+            slots(j),
             j.summary.state.value if j.summary is not None else "N/A",
             j.username if isinstance(j, bindings.v1Job) else render.OMITTED_VALUE,
         ]
@@ -28,21 +28,37 @@ def set_master_config(args: argparse.Namespace) -> None:
         log_config.level = bindings.v1LogLevel[args.log_level]
         field_masks.append("log.level")
 
-    if len(field_masks) == 0:
+    patch = None
+    if "patch" in args:
+        patch = util.yaml_safe_load(args.patch)
+        if not isinstance(patch, dict):
+            raise cli.errors.CliError("--patch must be a YAML or JSON object")
+
+    if len(field_masks) == 0 and patch is None:
         raise cli.errors.CliError(
-            "Please provide at least one argument to set master config. "
-            + "Currently, the supported fields are --log.level and --log.color."
+            "Please provide at least one argument to set master config: "
+            + "--log.level, --log.color, or --patch."
         )
 
     master_config = bindings.v1Config(log=log_config)
     req = bindings.v1PatchMasterConfigRequest(
-        config=master_config, fieldMask=bindings.protobufFieldMask(paths=field_masks)
+        config=master_config,
+        fieldMask=bindings.protobufFieldMask(paths=field_masks),
+        patch=patch,
     )
-    bindings.patch_PatchMasterConfig(sess, body=req)
+    resp = bindings.patch_PatchMasterConfig(sess, body=req)
     cli.warn(
         "This will only make ephermeral changes to the master config, "
         + "that will be lost if the user restarts the cluster."
     )
+    if not resp.changes:
+        print("The master config is unchanged.")
+        return
+    render.tabulate_or_csv(
+        ["Path", "Old Value", "New Value"],
+        [[c.path, c.oldValue, c.newValue] for c in resp.changes],
+        False,
+    )
     print("Successfully made changes to the master config.")
 
 
@@ -140,7 +156,12 @@ args_description = [
                             "--log.color", type=str, default=argparse.SUPPRESS, required=False,
                             help="set log color in the master config", dest="log_color",
                             choices=["on", "off"]
-                        )
+                        ),
+                        cli.Arg(
+                            "--patch", type=str, default=argparse.SUPPRESS, required=False,
+                            help="YAML or JSON master config fields to change, e.g. "
+                            "'{webhooks: {base_url: https://det.example.com}}'",
+                        ),
                     ]
                 ),
                 cli.Group(
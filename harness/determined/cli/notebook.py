@@ -91,6 +91,36 @@ def open_notebook(args: argparse.Namespace) -> None:
     webbrowser.open(f"{args.master}/{nb_path}")
 
 
+def suspend_notebook(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    notebook_id = typing.cast(str, ntsc.expand_uuid_prefixes(sess, args))
+
+    bindings.post_SuspendNotebook(sess, notebookId=notebook_id)
+    print(
+        f"Suspending notebook {notebook_id}; it will release its resources once its working "
+        "directory is archived."
+    )
+
+
+def resume_notebook(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    notebook_id = typing.cast(str, ntsc.expand_uuid_prefixes(sess, args))
+
+    nb = bindings.post_ResumeNotebook(sess, notebookId=notebook_id).notebook
+    print(f"Resumed notebook {nb.id}")
+
+    if args.detach:
+        return
+
+    cli.wait_ntsc_ready(sess, api.NTSC_Kind.notebook, nb.id)
+    assert nb.serviceAddress is not None, "missing Jupyter serviceAddress"
+    print(
+        termcolor.colored(
+            f"Jupyter Notebook is running at: {args.master}{nb.serviceAddress}", "green"
+        )
+    )
+
+
 args_description: cli.ArgsDescription = [
     cli.Cmd(
         "notebook",
@@ -193,6 +223,26 @@ args_description: cli.ArgsDescription = [
                     cli.Arg("-f", "--force", action="store_true", help="ignore errors"),
                 ],
             ),
+            cli.Cmd(
+                "suspend",
+                suspend_notebook,
+                "suspend a notebook, archiving its working directory and releasing its resources",
+                [cli.Arg("notebook_id", help="notebook ID")],
+            ),
+            cli.Cmd(
+                "resume",
+                resume_notebook,
+                "resume a suspended notebook",
+                [
+                    cli.Arg("notebook_id", help="notebook ID"),
+                    cli.Arg(
+                        "-d",
+                        "--detach",
+                        action="store_true",
+                        help="do not wait for the notebook to be ready",
+                    ),
+                ],
+            ),
             cli.Cmd(
                 "set",
                 None,
@@ -243,6 +243,10 @@ def list_tasks(args: argparse.Namespace) -> None:
     for item in res:
         if item["state"].startswith("STATE_"):
             item["state"] = item["state"][6:]
+        # Notebooks report whether they are suspending or suspended apart from their state.
+        suspend_state = item.get("suspendState", "SUSPEND_STATE_UNSPECIFIED")
+        if suspend_state != "SUSPEND_STATE_UNSPECIFIED":
+            item["state"] = suspend_state[len("SUSPEND_STATE_") :]
         if "workspaceId" in item:
             wId = item["workspaceId"]
             item["workspaceName"] = (
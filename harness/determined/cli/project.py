@@ -1,4 +1,5 @@
 import argparse
+import pathlib
 import time
 from typing import Any, Dict, List, Sequence, Tuple
 
@@ -180,6 +181,63 @@ def unarchive_project(args: argparse.Namespace) -> None:
     print(f"Successfully un-archived project {args.project_name}.")
 
 
+def write_stream(resp: Any, output: pathlib.Path) -> None:
+    with output.open("wb") as f:
+        for chunk in resp.iter_content(chunk_size=4096):
+            f.write(chunk)
+
+
+def write_bundle(resp: Any, output: pathlib.Path) -> None:
+    write_stream(resp, output)
+    print(f"Wrote bundle to {output}")
+
+
+def export_run_metrics(args: argparse.Namespace, params: Dict[str, Any], default_name: str) -> None:
+    sess = cli.setup_session(args)
+    params["format"] = args.format
+    if args.filter is not None:
+        params["filter"] = args.filter
+    if args.metric_group is not None:
+        params["metric_group"] = args.metric_group
+    resp = sess.get("runs/metrics/export", params=params, stream=True)
+    output = args.output or pathlib.Path(f"{default_name}_metrics.{args.format}")
+    write_stream(resp, output)
+    print(f"Wrote metrics to {output}")
+
+
+export_metrics_args = [
+    cli.Arg(
+        "--format",
+        choices=["parquet", "csv"],
+        default="parquet",
+        help="file format to write",
+    ),
+    cli.Arg("--filter", type=str, help="only export runs matching this run filter, as JSON"),
+    cli.Arg("--metric-group", type=str, help="only export metrics of this group"),
+    cli.Arg("-o", "--output", type=pathlib.Path, help="file to write"),
+]
+
+
+def export_project(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    (w, p) = project_by_name(sess, args.workspace_name, args.project_name)
+    resp = sess.get(
+        f"projects/{p.id}/export",
+        params={"include_logs": str(args.include_logs).lower()},
+        stream=True,
+    )
+    write_bundle(resp, args.output or pathlib.Path(f"project_{p.id}_bundle.tar.gz"))
+
+
+def export_project_metrics(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    (w, p) = project_by_name(sess, args.workspace_name, args.project_name)
+    params: Dict[str, Any] = {"project_id": p.id}
+    if args.experiment_ids:
+        params["experiment_ids"] = ",".join(str(i) for i in args.experiment_ids)
+    export_run_metrics(args, params, f"project_{p.id}")
+
+
 args_description = [
     cli.Cmd(
         "p|roject",
@@ -254,6 +312,33 @@ args_description = [
                     cli.Arg("--json", action="store_true", help="print as JSON"),
                 ],
             ),
+            cli.Cmd(
+                "export",
+                export_project,
+                "export the experiments of a project as a bundle",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg("project_name", type=str, help="name of the project"),
+                    cli.Arg("-o", "--output", type=pathlib.Path, help="bundle file to write"),
+                    cli.Arg("--include-logs", action="store_true", help="include trial logs"),
+                ],
+            ),
+            cli.Cmd(
+                "export-metrics",
+                export_project_metrics,
+                "export the metrics, hyperparameters and metadata of the runs of a project",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg("project_name", type=str, help="name of the project"),
+                    cli.Arg(
+                        "--experiment-ids",
+                        type=int,
+                        nargs="+",
+                        help="only export runs of these experiments",
+                    ),
+                    *export_metrics_args,
+                ],
+            ),
             cli.Cmd(
                 "delete",
                 delete_project,
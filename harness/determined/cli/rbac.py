@@ -1,6 +1,6 @@
 import argparse
 import collections
-from typing import Any, Dict, List, Set, Tuple
+from typing import Any, Dict, List, Optional, Set, Tuple
 
 from determined import cli
 from determined.cli import render
@@ -399,6 +399,71 @@ def unassign_role(args: argparse.Namespace) -> None:
         )
 
 
+def _permission_types(names: List[str]) -> List[bindings.v1PermissionType]:
+    types = []
+    for name in names:
+        value = name.upper().replace("-", "_")
+        if not value.startswith("PERMISSION_TYPE_"):
+            value = "PERMISSION_TYPE_" + value
+        try:
+            types.append(bindings.v1PermissionType(value))
+        except ValueError:
+            raise api.errors.BadRequestException(
+                f"unknown permission '{name}'; see 'det rbac my-permissions' or the "
+                "documentation for permission names"
+            )
+    return types
+
+
+def _scope_type_mask(args: argparse.Namespace) -> Optional[bindings.v1ScopeTypeMask]:
+    if not args.workspace_assignable:
+        return None
+    return bindings.v1ScopeTypeMask(cluster=True, workspace=True)
+
+
+@cli.require_feature_flag("rbacEnabled", rbac_flag_disabled_message)
+def create_role(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    req = bindings.v1CreateRoleRequest(
+        name=args.role_name,
+        permissionIds=_permission_types(args.permissions),
+        scopeTypeMask=_scope_type_mask(args),
+    )
+    role = bindings.post_CreateRole(sess, body=req).role
+    print(f"created role '{role.name}' with ID {role.roleId}")
+
+
+@cli.require_feature_flag("rbacEnabled", rbac_flag_disabled_message)
+def edit_role(args: argparse.Namespace) -> None:
+    if args.name is None and not args.permissions:
+        raise api.errors.BadRequestException("must provide --name or --permissions")
+
+    sess = cli.setup_session(args)
+    role_id = api.role_name_to_role_id(sess, args.role_name)
+    req = bindings.v1PatchRoleRequest(
+        roleId=role_id,
+        name=args.name,
+        permissionIds=_permission_types(args.permissions or []),
+        scopeTypeMask=_scope_type_mask(args),
+    )
+    role = bindings.patch_PatchRole(sess, body=req, roleId=role_id).role
+    print(f"updated role '{role.name}' with ID {role.roleId}")
+
+
+@cli.require_feature_flag("rbacEnabled", rbac_flag_disabled_message)
+def delete_role(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    role_id = api.role_name_to_role_id(sess, args.role_name)
+    bindings.delete_DeleteRole(sess, roleId=role_id, cascade=args.cascade)
+    print(f"deleted role '{args.role_name}' with ID {role_id}")
+
+
+workspace_assignable_arg = cli.Arg(
+    "--workspace-assignable",
+    action="store_true",
+    help="fail unless the role can be assigned to workspaces, not only cluster-wide",
+)
+
 args_description = [
     cli.Cmd(
         "rbac",
@@ -457,6 +522,49 @@ args_description = [
                     cli.Arg("--json", action="store_true", help="print as JSON"),
                 ],
             ),
+            cli.Cmd(
+                "create-role",
+                create_role,
+                "create a custom role",
+                [
+                    cli.Arg("role_name", help="name of the role to create"),
+                    cli.Arg(
+                        "permissions",
+                        nargs="+",
+                        help="permissions granted by the role, e.g. CREATE_NSC VIEW_NSC",
+                    ),
+                    workspace_assignable_arg,
+                ],
+            ),
+            cli.Cmd(
+                "edit-role",
+                edit_role,
+                "rename a custom role or replace its permissions",
+                [
+                    cli.Arg("role_name", help="name of the role to edit"),
+                    cli.Arg("--name", default=None, help="new name of the role"),
+                    cli.Arg(
+                        "--permissions",
+                        nargs="+",
+                        default=None,
+                        help="permissions granted by the role, replacing the current ones",
+                    ),
+                    workspace_assignable_arg,
+                ],
+            ),
+            cli.Cmd(
+                "delete-role",
+                delete_role,
+                "delete a custom role",
+                [
+                    cli.Arg("role_name", help="name of the role to delete"),
+                    cli.Arg(
+                        "--cascade",
+                        action="store_true",
+                        help="also remove the role from every user and group it is assigned to",
+                    ),
+                ],
+            ),
             cli.Cmd(
                 "assign-role",
                 assign_role,
@@ -26,6 +26,7 @@ from dateutil import parser
 from determined import experimental
 from determined import util as det_util
 from determined.common import util
+from determined.common.api import bindings
 
 # Avoid reporting BrokenPipeError when piping `tabulate` output through
 # a filter like `head`.
@@ -284,6 +285,26 @@ def print_json(data: Union[str, Any]) -> None:
         print(data)
 
 
+def print_value_diffs(title: str, diffs: Sequence[bindings.v1ValueDiff]) -> None:
+    """
+    Print the differences between two documents as one line per changed path.
+    """
+    print(f"{title}:")
+    if not diffs:
+        print("  (no differences)")
+    for d in diffs:
+        if d.kind == bindings.v1DiffKind.ADDED:
+            line = f"+ {d.path}: {json.dumps(d.right)}"
+            color = "green"
+        elif d.kind == bindings.v1DiffKind.REMOVED:
+            line = f"- {d.path}: {json.dumps(d.left)}"
+            color = "red"
+        else:
+            line = f"~ {d.path}: {json.dumps(d.left)} -> {json.dumps(d.right)}"
+            color = "yellow"
+        print("  " + (termcolor.colored(line, color) if _coloring_enabled() else line))
+
+
 def report_job_launched(_type: str, _id: str, name: str) -> None:
     msg = f"Launched {_type} (id: {_id}, name: {name})."
     print(termcolor.colored(msg, "green"))
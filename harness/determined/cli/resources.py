@@ -1,9 +1,12 @@
 import argparse
 import sys
+from typing import List
 
 import requests
 
 from determined import cli
+from determined.cli import render
+from determined.common.api import bindings
 
 
 # Print the body of a response in chunks so we don't have to buffer the whole thing.
@@ -41,6 +44,75 @@ def aggregated(args: argparse.Namespace) -> None:
     print_response(sess.get(path, params=params))
 
 
+def chargeback(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    params = {"timestamp_after": args.timestamp_after, "timestamp_before": args.timestamp_before}
+    if not args.json:
+        if args.group_by:
+            params["group_by"] = args.group_by
+        print_response(sess.get("resources/chargeback-csv", params=params))
+        return
+
+    group_by: List[bindings.v1ChargebackDimension] = []
+    for d in (args.group_by or "").split(","):
+        if not d.strip():
+            continue
+        try:
+            group_by.append(
+                bindings.v1ChargebackDimension("CHARGEBACK_DIMENSION_" + d.strip().upper())
+            )
+        except ValueError:
+            raise cli.CliError(f"unknown dimension {d!r}") from None
+    resp = bindings.get_GetChargebackReport(
+        sess,
+        timestampAfter=args.timestamp_after,
+        timestampBefore=args.timestamp_before,
+        groupBy=group_by,
+    )
+    render.print_json(resp.to_json()["entries"])
+
+
+def list_cost_rates(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    resp = bindings.get_GetCostRates(sess, resourcePool=args.resource_pool)
+    if args.json:
+        render.print_json([r.to_json() for r in resp.costRates])
+        return
+    headers = ["ID", "Resource Pool", "Slot Type", "Rate per Slot-Hour", "Effective From"]
+    values = [
+        [
+            r.id,
+            r.resourcePool,
+            r.slotType.value.replace("TYPE_", "").lower(),
+            r.ratePerSlotHour,
+            render.format_time(r.effectiveFrom),
+        ]
+        for r in resp.costRates
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
+def set_cost_rate(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    body = bindings.v1PostCostRateRequest(
+        resourcePool=args.resource_pool,
+        slotType=bindings.devicev1Type("TYPE_" + args.slot_type.upper()),
+        ratePerSlotHour=args.rate,
+        effectiveFrom=args.effective_from,
+    )
+    rate = bindings.post_PostCostRate(sess, body=body).costRate
+    print(
+        f"Set the cost rate of {args.slot_type} slots in resource pool {rate.resourcePool} to "
+        f"{rate.ratePerSlotHour} per slot-hour from {rate.effectiveFrom} (ID {rate.id})."
+    )
+
+
+def delete_cost_rate(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    bindings.delete_DeleteCostRate(sess, id=args.id)
+    print(f"Deleted cost rate {args.id}.")
+
+
 args_description: cli.ArgsDescription = [
     cli.Cmd(
         "res|ources",
@@ -81,6 +153,64 @@ args_description: cli.ArgsDescription = [
                     ),
                 ],
             ),
+            cli.Cmd(
+                "chargeback",
+                chargeback,
+                "get the cost of the resources allocated in a period",
+                [
+                    cli.Arg("timestamp_after"),
+                    cli.Arg("timestamp_before"),
+                    cli.Arg(
+                        "--group-by",
+                        help="comma-separated dimensions to break costs down by: workspace, "
+                        "project, user, label, resource_pool",
+                    ),
+                    cli.Arg("--json", action="store_true", help="output JSON rather than CSV"),
+                ],
+            ),
+            cli.Cmd(
+                "cost-rates",
+                None,
+                "manage the cost rates of resource pools",
+                [
+                    cli.Cmd(
+                        "list ls",
+                        list_cost_rates,
+                        "list cost rates",
+                        [
+                            cli.Arg("--resource-pool", help="only list the rates of this pool"),
+                            cli.Group(
+                                cli.output_format_args["json"], cli.output_format_args["csv"]
+                            ),
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "set",
+                        set_cost_rate,
+                        "set the cost rate of a resource pool and slot type",
+                        [
+                            cli.Arg("resource_pool", help="resource pool the rate applies to"),
+                            cli.Arg(
+                                "slot_type",
+                                choices=["cpu", "cuda", "rocm"],
+                                help="slot type the rate applies to",
+                            ),
+                            cli.Arg("rate", type=float, help="cost of one slot-hour"),
+                            cli.Arg(
+                                "effective_from",
+                                help="time the rate takes effect (YYYY-MM-DDTHH:MM:SSZ format)",
+                            ),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "delete",
+                        delete_cost_rate,
+                        "delete a cost rate",
+                        [cli.Arg("id", type=int, help="ID of the rate")],
+                    ),
+                ],
+            ),
         ],
     )
 ]
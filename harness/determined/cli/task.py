@@ -193,6 +193,104 @@ def unpause(args: argparse.Namespace) -> None:
     print(f"Unpaused task: {args.task_id}")
 
 
+def create_share_link(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    task_id = cast(str, ntsc.expand_uuid_prefixes(sess, args, args.task_id))
+    req = bindings.v1CreateProxyShareLinkRequest(
+        taskId=task_id,
+        duration=args.duration,
+        readOnly=args.read_only,
+        userIds=api.usernames_to_user_ids(sess, args.users),
+        groupIds=[api.group_name_to_group_id(sess, g) for g in args.groups],
+    )
+    resp = bindings.post_CreateProxyShareLink(sess, taskId=task_id, body=req)
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+
+    service = task_id if args.port is None else f"{task_id}:{args.port}"
+    print(f"Created share link {resp.shareLink.id}, expiring at {resp.shareLink.expiresAt}:")
+    print(f"{sess.master}/proxy/{service}/?share_token={resp.token}")
+
+
+def list_share_links(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    task_id = cast(str, ntsc.expand_uuid_prefixes(sess, args, args.task_id))
+    links = bindings.get_GetProxyShareLinks(sess, taskId=task_id).shareLinks
+    if args.json:
+        render.print_json([link.to_json() for link in links])
+        return
+
+    headers = ["ID", "Created By", "Created At", "Expires At", "Read Only", "Users", "Groups"]
+    values = [
+        [
+            link.id,
+            link.userId,
+            render.format_time(link.createdAt),
+            render.format_time(link.expiresAt),
+            link.readOnly,
+            ",".join(map(str, link.userIds)),
+            ",".join(map(str, link.groupIds)),
+        ]
+        for link in links
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def revoke_share_link(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    bindings.delete_RevokeProxyShareLink(sess, id=args.share_link_id)
+    print(f"Revoked share link {args.share_link_id}")
+
+
+def proxy_logs(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    task_id = cast(str, ntsc.expand_uuid_prefixes(sess, args, args.task_id))
+    user_id = api.usernames_to_user_ids(sess, [args.user])[0] if args.user else None
+    logs = bindings.get_GetProxyAccessLogs(
+        sess,
+        taskId=task_id,
+        userId=user_id,
+        timestampAfter=args.since,
+        timestampBefore=args.until,
+        limit=args.limit,
+    ).accessLogs
+    if args.json:
+        render.print_json([log.to_json() for log in logs])
+        return
+
+    headers = [
+        "Start Time",
+        "End Time",
+        "Service",
+        "User ID",
+        "Remote IP",
+        "Method",
+        "Path",
+        "Status",
+        "Bytes In",
+        "Bytes Out",
+        "WebSocket",
+    ]
+    values = [
+        [
+            render.format_time(log.startTime),
+            render.format_time(log.endTime),
+            log.serviceId,
+            log.userId,
+            log.remoteIp,
+            log.method,
+            log.path,
+            log.status,
+            log.bytesIn,
+            log.bytesOut,
+            log.websocket,
+        ]
+        for log in logs
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
 def cleanup_logs(args: argparse.Namespace) -> None:
     response = bindings.post_CleanupLogs(cli.setup_session(args))
     print(f"Deleted {response.removedCount} rows of log entries.")
@@ -306,6 +404,97 @@ args_description: List[Any] = [
                 ],
             ),
             cli.Cmd("cleanup-logs", cleanup_logs, "cleanup expired task logs", []),
+            cli.Cmd(
+                "proxy-logs",
+                proxy_logs,
+                "fetch the access logs of the proxied services of a task, newest first",
+                [
+                    cli.Arg("task_id", help="task ID"),
+                    cli.Arg("--user", help="only show the requests of this user"),
+                    cli.Arg(
+                        "--since",
+                        help="only show requests started at or after (RFC 3339 format), "
+                        "e.g. '2021-10-26T23:17:12Z'",
+                    ),
+                    cli.Arg(
+                        "--until",
+                        help="only show requests started before (RFC 3339 format), "
+                        "e.g. '2021-10-26T23:17:12Z'",
+                    ),
+                    cli.Arg(
+                        "--limit", type=int, default=1000, help="maximum number of requests to show"
+                    ),
+                    cli.Group(
+                        cli.output_format_args["csv"],
+                        cli.output_format_args["json"],
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "share-link",
+                None,
+                "manage links that share the proxied services of a task",
+                [
+                    cli.Cmd(
+                        "create",
+                        create_share_link,
+                        "create an expiring link to the proxied services of a task",
+                        [
+                            cli.Arg("task_id", help="task ID"),
+                            cli.Arg(
+                                "-d",
+                                "--duration",
+                                default="24h",
+                                help="how long the link is valid for, as a Go duration string "
+                                "(e.g. 90m, 24h); at most 720h",
+                            ),
+                            cli.Arg(
+                                "--read-only",
+                                action="store_true",
+                                help="only allow GET, HEAD and OPTIONS requests through the link",
+                            ),
+                            cli.Arg(
+                                "--user",
+                                dest="users",
+                                action="append",
+                                default=[],
+                                help="only allow this user to use the link "
+                                "(repeat for multiple values)",
+                            ),
+                            cli.Arg(
+                                "--group",
+                                dest="groups",
+                                action="append",
+                                default=[],
+                                help="only allow members of this group to use the link "
+                                "(repeat for multiple values)",
+                            ),
+                            cli.Arg(
+                                "--port",
+                                type=int,
+                                help="print the link for this proxied port of the task",
+                            ),
+                            cli.output_format_args["json"],
+                        ],
+                    ),
+                    cli.Cmd(
+                        "list ls",
+                        list_share_links,
+                        "list the active share links of a task",
+                        [
+                            cli.Arg("task_id", help="task ID"),
+                            cli.output_format_args["json"],
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "revoke",
+                        revoke_share_link,
+                        "revoke a share link",
+                        [cli.Arg("share_link_id", type=int, help="share link ID")],
+                    ),
+                ],
+            ),
             cli.Cmd(
                 "create",
                 create,
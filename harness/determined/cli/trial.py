@@ -148,6 +148,17 @@ def describe_trial(args: argparse.Namespace) -> None:
         render.print_json(trial_response.trial.summaryMetrics)
 
 
+def diff_trials(args: argparse.Namespace) -> None:
+    resp = bindings.get_GetTrialsDiff(
+        cli.setup_session(args), trialId=args.trial_id, otherTrialId=args.other_trial_id
+    )
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+    render.print_value_diffs("Hyperparameters", resp.hparams)
+    render.print_value_diffs("Metadata", resp.metadata)
+    render.print_value_diffs("Environment", resp.environment)
+
 def download(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     det = client.Determined._from_session(sess)
@@ -435,6 +446,17 @@ args_description: cli.ArgsDescription = [
                     *cli.make_pagination_args(limit=1000),
                 ],
             ),
+            cli.Cmd(
+                "diff",
+                diff_trials,
+                "show the differences between the hyperparameters, metadata and environments "
+                "of two trials",
+                [
+                    cli.Arg("trial_id", type=int, help="trial ID"),
+                    cli.Arg("other_trial_id", type=int, help="trial ID to compare with"),
+                    cli.output_format_args["json"],
+                ],
+            ),
             cli.Cmd(
                 "download",
                 download,
@@ -5,7 +5,7 @@ from determined.common.api import bindings
 
 
 def role_name_to_role_id(session: api.Session, role_name: str) -> int:
-    # No need to use read-paginated since the number of roles is fixed and small.
+    # No need to use read-paginated since the number of roles is small.
     req = bindings.v1ListRolesRequest(limit=500, offset=0)
     resp = bindings.post_ListRoles(session=session, body=req)
     for r in resp.roles:
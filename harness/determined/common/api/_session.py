@@ -130,7 +130,7 @@ class BaseSession(metaclass=abc.ABCMeta):
         path: str,
         params: Optional[Dict[str, Any]],
         json: Any,
-        data: Optional[str],
+        data: Optional[Union[str, bytes]],
         headers: Optional[Dict[str, Any]],
         timeout: Optional[int],
         stream: bool,
@@ -205,7 +205,7 @@ class BaseSession(metaclass=abc.ABCMeta):
         *,
         params: Optional[Dict[str, Any]] = None,
         json: Any = None,
-        data: Optional[str] = None,
+        data: Optional[Union[str, bytes]] = None,
         headers: Optional[Dict[str, Any]] = None,
         timeout: Optional[int] = None,
     ) -> requests.Response:
@@ -217,7 +217,7 @@ class BaseSession(metaclass=abc.ABCMeta):
         *,
         params: Optional[Dict[str, Any]] = None,
         json: Any = None,
-        data: Optional[str] = None,
+        data: Optional[Union[str, bytes]] = None,
         headers: Optional[Dict[str, Any]] = None,
         timeout: Optional[int] = None,
     ) -> requests.Response:
@@ -229,7 +229,7 @@ class BaseSession(metaclass=abc.ABCMeta):
         *,
         params: Optional[Dict[str, Any]] = None,
         json: Any = None,
-        data: Optional[str] = None,
+        data: Optional[Union[str, bytes]] = None,
         headers: Optional[Dict[str, Any]] = None,
         timeout: Optional[int] = None,
     ) -> requests.Response:
@@ -0,0 +1,87 @@
+"""
+Fetch the checkpoint a replica of a deployment serves, and report the replica as ready once its
+serving command answers.
+"""
+
+import argparse
+import logging
+import os
+import sys
+import time
+from typing import List
+
+import requests
+
+import determined as det
+from determined.common import api
+from determined.common.api import authentication, certs
+from determined.common.experimental import checkpoint
+
+logger = logging.getLogger("determined")
+
+POLL_SECONDS = 1
+BACKOFF_SECONDS = 5
+
+
+def download(sess: api.Session, checkpoint_uuid: str, path: str) -> None:
+    """Download a checkpoint, unless it is already mounted at the path."""
+    if os.path.isdir(path) and os.listdir(path):
+        logger.info(f"using checkpoint {checkpoint_uuid} mounted at {path}")
+        return
+    ckpt = checkpoint.Checkpoint(sess, checkpoint_uuid)
+    ckpt.reload()
+    ckpt.download(path)
+    logger.info(f"downloaded checkpoint {checkpoint_uuid} to {path}")
+
+
+def wait_ready(sess: api.Session, allocation_id: str, port: int, health_path: str) -> None:
+    """Poll the serving command until it answers, then report the replica as ready."""
+    url = f"http://localhost:{port}{health_path}"
+    while True:
+        try:
+            if requests.get(url, timeout=POLL_SECONDS).status_code < 500:
+                break
+        except requests.RequestException:
+            pass
+        time.sleep(POLL_SECONDS)
+    logger.info(f"replica answers on {url}, reporting it as ready")
+
+    # The replica receives no requests until the call completes, so try until it does.
+    while True:
+        try:
+            sess.post(f"/api/v1/allocations/{allocation_id}/ready")
+            return
+        except requests.RequestException as e:
+            if e.response is not None and e.response.status_code < 500:
+                raise e
+            time.sleep(BACKOFF_SECONDS)
+
+
+def main(argv: List[str]) -> None:
+    parser = argparse.ArgumentParser(description="Prepare a replica of a deployment")
+    subparsers = parser.add_subparsers(dest="action", required=True)
+    download_parser = subparsers.add_parser("download", help="Download the served checkpoint")
+    download_parser.add_argument("--checkpoint", default=os.environ.get("DET_CHECKPOINT_UUID"))
+    download_parser.add_argument("--path", default=os.environ.get("DET_CHECKPOINT_DIR"))
+    ready_parser = subparsers.add_parser("wait-ready", help="Report the replica as ready")
+    ready_parser.add_argument("--port", type=int, default=os.environ.get("DET_DEPLOYMENT_PORT"))
+    ready_parser.add_argument(
+        "--health-path", default=os.environ.get("DET_DEPLOYMENT_HEALTH_PATH", "/")
+    )
+    args = parser.parse_args(argv)
+
+    master_url = api.canonicalize_master_url(os.environ["DET_MASTER"])
+    cert = certs.default_load(master_url)
+    # This only runs on-cluster, so it is expected the username and session token are present in the
+    # environment.
+    sess = authentication.login_from_task(master_url, cert=cert)
+
+    if args.action == "download":
+        download(sess, args.checkpoint, args.path)
+    else:
+        wait_ready(sess, os.environ["DET_ALLOCATION_ID"], args.port, args.health_path)
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    main(sys.argv[1:])
@@ -0,0 +1,64 @@
+"""
+Report the GPU utilization of a task to the master, which terminates notebooks and shells whose
+GPUs stay underused for longer than their gpu_idle_timeout.
+"""
+
+import logging
+import time
+
+import determined as det
+from determined.common import api
+from determined.common.api import authentication, bindings, certs
+from determined.core import _profiler
+
+logger = logging.getLogger("determined")
+
+SAMPLE_INTERVAL = 1  # How many seconds to wait between samples of the GPU utilization.
+REPORT_INTERVAL = 30  # How many seconds of samples to average into each report to the master.
+
+
+def report(sess: api.Session, allocation_id: str, gpus: _profiler._GPU) -> None:
+    """Report the utilization of each GPU, averaged since the previous report."""
+    utilization = {uuid: float(m["gpu_util"]) for uuid, m in gpus.aggregate().items()}
+    gpus.reset()
+    if not utilization:
+        return
+    bindings.post_PostAllocationAcceleratorData(
+        sess,
+        allocationId=allocation_id,
+        body=bindings.v1PostAllocationAcceleratorDataRequest(
+            allocationId=allocation_id, acceleratorUtilization=utilization
+        ),
+    )
+
+
+def sample(gpus: _profiler._GPU, seconds: int) -> None:
+    """Sample the GPU utilization every SAMPLE_INTERVAL for the given number of seconds."""
+    for _ in range(seconds // SAMPLE_INTERVAL):
+        gpus.sample_metrics()
+        time.sleep(SAMPLE_INTERVAL)
+
+
+def main() -> None:
+    info = det.get_cluster_info()
+    assert info is not None, "must be run on-cluster"
+
+    gpus = _profiler._GPU()
+    if not gpus._pynvml_device_handles:
+        logger.warning("no GPU utilization to report; the GPU idle timeout will not apply")
+        return
+
+    cert = certs.default_load(info.master_url)
+    sess = authentication.login_from_task(info.master_url, cert=cert)
+
+    while True:
+        sample(gpus, REPORT_INTERVAL)
+        try:
+            report(sess, info.allocation_id, gpus)
+        except Exception:
+            logger.warning("ignoring error reporting GPU utilization to master", exc_info=True)
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    main()
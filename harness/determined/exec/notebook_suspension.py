@@ -0,0 +1,116 @@
+"""
+Archive the working directory of a notebook to checkpoint storage when it is suspended, restore it
+when the notebook is resumed, and discard it when the suspended notebook is killed.
+"""
+
+import argparse
+import json
+import logging
+import os
+import sys
+import tarfile
+import tempfile
+from typing import List
+
+import determined as det
+from determined.common import api, constants, storage
+from determined.common.api import bindings
+
+logger = logging.getLogger("determined")
+
+STORAGE_CONFIG_PATH = "/run/determined/jupyter/storage_config.json"
+ARCHIVE_NAME = "workdir.tar.gz"
+
+
+def _storage_manager(storage_config_path: str) -> storage.StorageManager:
+    with open(storage_config_path) as f:
+        storage_config = json.load(f)
+    return storage.build(storage_config, container_path=constants.SHARED_FS_CONTAINER_PATH)
+
+
+def suspend(
+    sess: api.Session,
+    notebook_id: str,
+    storage_id: str,
+    work_dir: str,
+    storage_config_path: str = STORAGE_CONFIG_PATH,
+) -> None:
+    """Archive the working directory and report it to the master, which then stops the notebook."""
+    manager = _storage_manager(storage_config_path)
+    with tempfile.TemporaryDirectory() as tmp:
+        with tarfile.open(os.path.join(tmp, ARCHIVE_NAME), "w:gz") as tar:
+            tar.add(work_dir, arcname=".")
+        manager.upload(src=tmp, dst=storage_id)
+    logger.info(f"archived working directory {work_dir} to storage {storage_id}")
+
+    bindings.put_ReportNotebookSuspended(
+        sess,
+        notebookId=notebook_id,
+        body=bindings.v1ReportNotebookSuspendedRequest(
+            notebookId=notebook_id, storageId=storage_id
+        ),
+    )
+
+
+def _extract(tar: tarfile.TarFile, work_dir: str) -> None:
+    if hasattr(tarfile, "data_filter"):
+        tar.extractall(work_dir, filter="data")
+        return
+
+    # Pythons without extraction filters get the gist of the "data" filter: nothing may end up
+    # outside of the working directory, and nothing but regular files and directories is extracted.
+    root = os.path.realpath(work_dir)
+    members = []
+    for member in tar.getmembers():
+        path = os.path.realpath(os.path.join(root, member.name))
+        if os.path.commonpath([root, path]) != root:
+            raise ValueError(f"archive member {member.name} is outside of {work_dir}")
+        if not (member.isfile() or member.isdir()):
+            logger.warning(f"not restoring {member.name}, which is not a file or directory")
+            continue
+        member.mode &= 0o755
+        members.append(member)
+    tar.extractall(root, members=members)
+
+
+def restore(
+    storage_id: str,
+    work_dir: str,
+    storage_config_path: str = STORAGE_CONFIG_PATH,
+) -> None:
+    """Restore the working directory a suspended notebook archived, then delete the archive."""
+    manager = _storage_manager(storage_config_path)
+    with tempfile.TemporaryDirectory() as tmp:
+        manager.download(src=storage_id, dst=tmp)
+        with tarfile.open(os.path.join(tmp, ARCHIVE_NAME), "r:gz") as tar:
+            _extract(tar, work_dir)
+    logger.info(f"restored working directory {work_dir} from storage {storage_id}")
+    discard(storage_id, storage_config_path)
+
+
+def discard(storage_id: str, storage_config_path: str = STORAGE_CONFIG_PATH) -> None:
+    """Delete the archive of a suspended notebook."""
+    manager = _storage_manager(storage_config_path)
+    manager.delete(storage_id, ["**/*"])
+    logger.info(f"deleted archive {storage_id}")
+
+
+def main(argv: List[str]) -> None:
+    parser = argparse.ArgumentParser(description="Restore the working directory of a notebook")
+    parser.add_argument("--storage-id", required=True, help="The storage id to restore from")
+    parser.add_argument("--work-dir", default=os.getcwd(), help="The directory to restore into")
+    parser.add_argument("--storage-config", default=STORAGE_CONFIG_PATH)
+    parser.add_argument(
+        "--discard", action="store_true", help="Delete the archive instead of restoring it"
+    )
+    args = parser.parse_args(argv)
+
+    if args.discard:
+        discard(args.storage_id, args.storage_config)
+        return
+    restore(args.storage_id, args.work_dir, args.storage_config)
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    main(sys.argv[1:])
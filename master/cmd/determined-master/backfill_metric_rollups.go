@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// backfillBatchSize is how many trials are listed at a time while backfilling.
+const backfillBatchSize = 100
+
+func newBackfillMetricRollupsCmd() *cobra.Command {
+	var trialIDs []int
+	cmd := &cobra.Command{
+		Use:   "backfill-metric-rollups",
+		Short: "build metric rollups for trials that reported metrics before rollups existed",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runBackfillMetricRollups(trialIDs); err != nil {
+				log.Error(fmt.Sprintf("%+v", err))
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().IntSliceVar(&trialIDs, "trial-id", nil,
+		"only backfill these trials, rebuilding their rollups even if they are complete")
+	return cmd
+}
+
+func runBackfillMetricRollups(trialIDs []int) error {
+	if err := initializeConfig(); err != nil {
+		return err
+	}
+
+	database, err := db.Connect(&config.GetMasterConfig().DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errd := database.Close(); errd != nil {
+			log.Errorf("error closing pg connection: %s", errd)
+		}
+	}()
+
+	ctx := context.Background()
+	backfill := func(ids []int) error {
+		for _, id := range ids {
+			if err := database.BackfillMetricRollups(ctx, id); err != nil {
+				return errors.Wrapf(err, "backfilling metric rollups of trial %d", id)
+			}
+			log.Infof("backfilled metric rollups of trial %d", id)
+		}
+		return nil
+	}
+	if len(trialIDs) > 0 {
+		return backfill(trialIDs)
+	}
+
+	afterID := 0
+	for {
+		ids, err := db.TrialsWithoutMetricRollups(ctx, afterID, backfillBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := backfill(ids); err != nil {
+			return err
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/determined-ai/determined/master/version"
 )
 
-var v *viper.Viper
+var (
+	v *viper.Viper
+	// configFlags are the command line flags the master config is read from.
+	configFlags *pflag.FlagSet
+)
 
 // viperKeyDelimiter marks nested values in the configuration. For example, with a key delimiter
 // of ".", viper will expect `{ db { host = "something" } }` to be stored and supplied as
@@ -29,6 +33,7 @@ func init() {
 	// because link-time variable assignments are not applied when package-scoped variables
 	// are initialized.
 	rootCmd.Version = version.Version
+	configFlags = rootCmd.PersistentFlags()
 	registerConfig()
 }
 
@@ -47,26 +52,34 @@ func (c configKey) FlagName() string {
 }
 
 func registerString(flags *pflag.FlagSet, name configKey, value string, usage string) {
-	flags.String(name.FlagName(), value, usage)
+	if flags.Lookup(name.FlagName()) == nil {
+		flags.String(name.FlagName(), value, usage)
+	}
 	_ = v.BindEnv(name.AccessPath(), name.EnvName())
 	_ = v.BindPFlag(name.AccessPath(), flags.Lookup(name.FlagName()))
 	v.SetDefault(name.AccessPath(), value)
 }
 
 func registerBool(flags *pflag.FlagSet, name configKey, value bool, usage string) {
-	flags.Bool(name.FlagName(), value, usage)
+	if flags.Lookup(name.FlagName()) == nil {
+		flags.Bool(name.FlagName(), value, usage)
+	}
 	_ = v.BindEnv(name.AccessPath(), name.EnvName())
 	_ = v.BindPFlag(name.AccessPath(), flags.Lookup(name.FlagName()))
 	v.SetDefault(name.AccessPath(), value)
 }
 
 func registerInt(flags *pflag.FlagSet, name configKey, value int, usage string) {
-	flags.Int(name.FlagName(), value, usage)
+	if flags.Lookup(name.FlagName()) == nil {
+		flags.Int(name.FlagName(), value, usage)
+	}
 	_ = v.BindEnv(name.AccessPath(), name.EnvName())
 	_ = v.BindPFlag(name.AccessPath(), flags.Lookup(name.FlagName()))
 	v.SetDefault(name.AccessPath(), value)
 }
 
+// registerConfig binds a new viper instance to the master's flags, environment variables and
+// defaults. Flags are only defined the first time, so it can be called again to reload the config.
 func registerConfig() {
 	// Relies on https://github.com/spf13/viper/pull/794. Once the points in the commentary
 	// are addressed, specifically adding the option `v.AllowDelimiterInKey`, it may be better
@@ -77,11 +90,13 @@ func registerConfig() {
 	defaults := config.DefaultConfig()
 
 	// Register flags and environment variables, and set default values for the flags.
-	flags := rootCmd.PersistentFlags()
+	flags := configFlags
 	name := func(components ...string) configKey { return components }
 
 	registerString(flags, name("config-file"),
 		defaults.ConfigFile, "location of config file")
+	registerBool(flags, name("watch-config-file"),
+		defaults.WatchConfigFile, "reload the config file when it changes")
 
 	registerString(flags, name("log", "level"),
 		defaults.Log.Level, "choose logging level from [trace, debug, info, warn, error, fatal]")
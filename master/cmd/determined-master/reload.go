@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal"
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+// configWatchInterval is how often a watched config file is checked for changes.
+const configWatchInterval = 10 * time.Second
+
+// reloadMu serializes reloads, which replace the global viper instance.
+var reloadMu sync.Mutex
+
+// reloadConfig re-reads the master config and applies the fields that can change at runtime to
+// the running master.
+func reloadConfig(m *internal.Master) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	// Start from a fresh viper instance so settings removed from the config file are dropped.
+	registerConfig()
+	conf, err := loadConfig()
+	if err != nil {
+		log.WithError(err).Error("failed to reload master config")
+		return
+	}
+
+	// A signing key is generated at startup if none is configured; keep it rather than rotating it
+	// on every reload.
+	if v.GetString(configKey{"webhooks", "signing_key"}.AccessPath()) == "" {
+		conf.Webhooks.SigningKey = config.GetMasterConfig().Webhooks.SigningKey
+	}
+
+	changes, err := m.ReloadConfig(conf)
+	if err != nil {
+		log.WithError(err).Error("failed to reload master config")
+		return
+	}
+	log.Infof("reloaded master config with %d changes", len(changes))
+}
+
+// reloadConfigOnSignal reloads the master config whenever the master receives SIGHUP.
+func reloadConfigOnSignal(m *internal.Master) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	for range sigs {
+		log.Info("received SIGHUP, reloading master config")
+		reloadConfig(m)
+	}
+}
+
+// watchConfigFile reloads the master config whenever the contents of the config file change.
+// The file is polled rather than watched for events so that replacing it, as Kubernetes does when
+// updating a mounted ConfigMap, is also noticed.
+func watchConfigFile(m *internal.Master, path string) {
+	if path == "" {
+		path = defaultConfigPath
+	}
+	last, _ := os.ReadFile(path) // #nosec G304
+
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		bs, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			log.WithError(err).Warnf("failed to read master config file %s", path)
+			continue
+		}
+		if bytes.Equal(bs, last) {
+			continue
+		}
+		last = bs
+		log.Infof("master config file %s changed, reloading master config", path)
+		reloadConfig(m)
+	}
+}
@@ -35,6 +35,7 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newPopulateCmd())
+	cmd.AddCommand(newBackfillMetricRollupsCmd())
 	return cmd
 }
 
@@ -63,6 +64,10 @@ func runRoot() error {
 	}
 
 	m := internal.New(logStore, config)
+	go reloadConfigOnSignal(m)
+	if config.WatchConfigFile {
+		go watchConfigFile(m, config.ConfigFile)
+	}
 	return m.Run(context.TODO(), nil)
 }
 
@@ -70,33 +75,43 @@ func runRoot() error {
 // file, environment variables, and command line flags) and also initializes
 // global logging state based on those options.
 func initializeConfig() error {
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	config.SetMasterConfig(conf)
+
+	for _, deprecation := range conf.Deprecations() {
+		log.Warn(deprecation.Error())
+	}
+
+	return nil
+}
+
+// loadConfig reads and validates the master config from the config file, environment variables,
+// and command line flags.
+func loadConfig() (*config.Config, error) {
 	// Fetch an initial config to get the config file path and read its settings into Viper.
 	initialConfig, err := getConfig(v.AllSettings())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	bs, err := readConfigFile(initialConfig.ConfigFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	conf, err := mergeConfigIntoViper(bs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := check.Validate(conf); err != nil {
-		return err
-	}
-
-	config.SetMasterConfig(conf)
-
-	for _, deprecation := range conf.Deprecations() {
-		log.Warn(deprecation.Error())
+		return nil, err
 	}
-
-	return nil
+	return conf, nil
 }
 
 func mergeConfigIntoViper(bs []byte) (*config.Config, error) {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	k8sV1 "k8s.io/api/core/v1"
@@ -238,3 +240,28 @@ func TestApplyBackwardsCompatibility(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigAfterReregistering(t *testing.T) {
+	config.RegisterAuthZType(config.BasicAuthZType)
+
+	path := filepath.Join(t.TempDir(), "master.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte("log:\n  level: debug\n"), 0o600))
+	assert.NilError(t, configFlags.Set("config-file", path))
+	defer func() {
+		assert.NilError(t, configFlags.Set("config-file", ""))
+		registerConfig()
+	}()
+
+	registerConfig()
+	conf, err := loadConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, conf.Log.Level, "debug")
+
+	// Settings removed from the config file are dropped when the config is reloaded.
+	assert.NilError(t, os.WriteFile(path, []byte("log:\n  color: false\n"), 0o600))
+	registerConfig()
+	conf, err = loadConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, conf.Log.Level, "info")
+	assert.Equal(t, conf.Log.Color, false)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -106,9 +107,15 @@ func redirectToLogin(c echo.Context) error {
 }
 
 // processProxyAuthentication is a middleware processing function that attempts
-// to authenticate incoming HTTP requests coming through proxies.
+// to authenticate incoming HTTP requests coming through proxies. Requests to deployment routes
+// are checked against the task of the replica they are forwarded to.
 func processProxyAuthentication(c echo.Context) (done bool, err error) {
-	taskID := model.TaskID(strings.SplitN(c.Param("service"), ":", 2)[0])
+	taskID := model.TaskID(strings.SplitN(proxy.ServiceID(c), ":", 2)[0])
+
+	// Share links let requests in without the usual task permissions.
+	if authenticateProxyShareLink(c, taskID) {
+		return false, nil
+	}
 
 	// Notebooks require special auth token passed as a URL parameter.
 	token := extractNotebookTokenFromRequest(c.Request())
@@ -136,6 +143,7 @@ func processProxyAuthentication(c echo.Context) (done bool, err error) {
 	if !usr.Active {
 		return true, redirectToLogin(c)
 	}
+	proxy.SetUserID(c, usr.ID)
 
 	var ctx context.Context
 
@@ -145,37 +153,46 @@ func processProxyAuthentication(c echo.Context) (done bool, err error) {
 		ctx = c.Request().Context()
 	}
 
-	serviceNotFoundErr := api.NotFoundErrs("service", fmt.Sprint(taskID), false)
+	err = canAccessTaskProxy(ctx, *usr, taskID, false)
+	return err != nil, err
+}
+
+// canAccessTaskProxy checks whether a user may reach the proxied services of a task. Tasks the
+// user may not see are reported as not found.
+func canAccessTaskProxy(
+	ctx context.Context, usr model.User, taskID model.TaskID, statusErr bool,
+) error {
+	serviceNotFoundErr := api.NotFoundErrs("service", fmt.Sprint(taskID), statusErr)
 
 	spec, err := command.IdentifyTask(ctx, taskID)
 	if errors.Is(err, db.ErrNotFound) || errors.Cause(err) == sql.ErrNoRows {
 		// Check if it's an experiment.
 		e, err := db.ExperimentByTaskID(ctx, taskID)
 		if errors.Is(err, db.ErrNotFound) || errors.Cause(err) == sql.ErrNoRows {
-			return true, err
+			return err
 		}
 
 		if err != nil {
-			return true, fmt.Errorf("error looking up task experiment: %w", err)
+			return fmt.Errorf("error looking up task experiment: %w", err)
 		}
 
-		err = expauth.AuthZProvider.Get().CanGetExperiment(ctx, *usr, e)
-		return err != nil, authz.SubIfUnauthorized(err, serviceNotFoundErr)
+		err = expauth.AuthZProvider.Get().CanGetExperiment(ctx, usr, e)
+		return authz.SubIfUnauthorized(err, serviceNotFoundErr)
 	}
 
 	if err != nil {
-		return true, fmt.Errorf("error fetching task metadata: %w", err)
+		return fmt.Errorf("error fetching task metadata: %w", err)
 	}
 
 	// Continue NTSC task checks.
 	if spec.TaskType == model.TaskTypeTensorboard {
 		err = command.AuthZProvider.Get().CanGetTensorboard(
-			ctx, *usr, spec.WorkspaceID, spec.ExperimentIDs, spec.TrialIDs)
+			ctx, usr, spec.WorkspaceID, spec.ExperimentIDs, spec.TrialIDs)
 	} else {
 		err = command.AuthZProvider.Get().CanGetNSC(
-			ctx, *usr, spec.WorkspaceID)
+			ctx, usr, spec.WorkspaceID)
 	}
-	return err != nil, authz.SubIfUnauthorized(err, serviceNotFoundErr)
+	return authz.SubIfUnauthorized(err, serviceNotFoundErr)
 }
 
 // extractNotebookTokenFromRequest looks for auth token for Jupyter notebooks
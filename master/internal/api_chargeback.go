@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/chargeback"
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetChargebackReport(
+	ctx context.Context, req *apiv1.GetChargebackReportRequest,
+) (*apiv1.GetChargebackReportResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.m.canGetUsageDetails(ctx, u); err != nil {
+		return nil, err
+	}
+
+	if req.TimestampAfter == nil {
+		return nil, status.Error(codes.InvalidArgument, "no start time provided")
+	}
+	if req.TimestampBefore == nil {
+		return nil, status.Error(codes.InvalidArgument, "no end time provided")
+	}
+	var groupBy []chargeback.Dimension
+	for _, d := range req.GroupBy {
+		dim, err := chargeback.DimensionFromProto(d)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		groupBy = append(groupBy, dim)
+	}
+
+	entries, err := a.m.chargebackReport(
+		ctx, req.TimestampAfter.AsTime(), req.TimestampBefore.AsTime(), groupBy)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetChargebackReportResponse{Entries: []*apiv1.ChargebackEntry{}}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, e.Proto())
+	}
+	return resp, nil
+}
+
+// chargebackReport prices the allocations of a period with the rates of the current slot type of
+// their resource pools.
+func (m *Master) chargebackReport(
+	ctx context.Context, start, end time.Time, groupBy []chargeback.Dimension,
+) ([]*chargeback.Entry, error) {
+	if start.After(end) {
+		return nil, status.Error(codes.InvalidArgument, "start time cannot be after end time")
+	}
+
+	pools, err := m.rm.GetResourcePools()
+	if err != nil {
+		return nil, err
+	}
+	slotTypes := map[string]device.Type{}
+	for _, p := range pools.ResourcePools {
+		if slotType, err := chargeback.SlotTypeFromProto(p.SlotType); err == nil {
+			slotTypes[p.Name] = slotType
+		}
+	}
+	return chargeback.Report(ctx, start.UTC(), end.UTC(), groupBy, slotTypes)
+}
+
+func (a *apiServer) GetCostRates(
+	ctx context.Context, req *apiv1.GetCostRatesRequest,
+) (*apiv1.GetCostRatesResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.m.canGetUsageDetails(ctx, u); err != nil {
+		return nil, err
+	}
+
+	rates, err := chargeback.Rates(ctx, req.GetResourcePool())
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetCostRatesResponse{CostRates: []*apiv1.CostRate{}}
+	for _, r := range rates {
+		resp.CostRates = append(resp.CostRates, r.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostCostRate(
+	ctx context.Context, req *apiv1.PostCostRateRequest,
+) (*apiv1.PostCostRateResponse, error) {
+	if err := a.canEditCostRates(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.ResourcePool == "" {
+		return nil, status.Error(codes.InvalidArgument, "resource_pool is required")
+	}
+	if req.EffectiveFrom == nil {
+		return nil, status.Error(codes.InvalidArgument, "effective_from is required")
+	}
+	slotType, err := chargeback.SlotTypeFromProto(req.SlotType)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	r := &chargeback.CostRate{
+		ResourcePool:    req.ResourcePool,
+		SlotType:        slotType,
+		RatePerSlotHour: req.RatePerSlotHour,
+		EffectiveFrom:   req.EffectiveFrom.AsTime(),
+	}
+	err = chargeback.SetRate(ctx, r)
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostCostRateResponse{CostRate: r.Proto()}, nil
+}
+
+func (a *apiServer) DeleteCostRate(
+	ctx context.Context, req *apiv1.DeleteCostRateRequest,
+) (*apiv1.DeleteCostRateResponse, error) {
+	if err := a.canEditCostRates(ctx); err != nil {
+		return nil, err
+	}
+
+	err := chargeback.DeleteRate(ctx, int(req.Id))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("cost rate", strconv.Itoa(int(req.Id)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteCostRateResponse{}, nil
+}
+
+// canEditCostRates checks if the user can set cost rates, which is limited to those who can update
+// the configuration of the master.
+func (a *apiServer) canEditCostRates(ctx context.Context) error {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, u)
+	if err != nil {
+		return err
+	} else if permErr != nil {
+		return permErr
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/rbac/audit"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/templates"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/archive"
@@ -158,6 +159,17 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 		return nil, nil, err
 	}
 
+	// Check the pod spec against the resource pool's pod template. The slots were already validated
+	// when the resource pool was resolved, so they are left out here.
+	if config.Environment.PodSpec != nil {
+		if _, err := a.m.rm.ValidateResources(sproto.ValidateResourcesRequest{
+			ResourcePool: poolName.String(),
+			PodSpec:      (*expconf.PodSpec)(config.Environment.PodSpec),
+		}); err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "validating pod spec: %v", err)
+		}
+	}
+
 	// Check submitted config against task config policies.
 	err = configpolicy.CheckNTSCConstraints(ctx, int(cmdSpec.Metadata.WorkspaceID), config, a.m.rm)
 	if err != nil {
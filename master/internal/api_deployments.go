@@ -0,0 +1,348 @@
+package internal
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/api/apiutils"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/deployment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/check"
+	pkgCommand "github.com/determined-ai/determined/master/pkg/command"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+const (
+	deploymentEntrypoint    = "/run/determined/deployment-entrypoint.sh"
+	deploymentCheckpointDir = "/run/determined/checkpoint"
+)
+
+func (a *apiServer) CreateDeployment(
+	ctx context.Context, req *apiv1.CreateDeploymentRequest,
+) (*apiv1.CreateDeploymentResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mv, err := a.deployableModelVersion(ctx, *curUser, req.ModelName, req.ModelVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	launchReq, launchWarnings, err := a.getCommandLaunchParams(ctx, &protoCommandParams{
+		TemplateName: req.TemplateName,
+		WorkspaceID:  req.WorkspaceId,
+		Config:       req.Config,
+	}, curUser)
+	if err != nil {
+		return nil, api.WrapWithFallbackCode(err, codes.InvalidArgument,
+			"failed to prepare launch params")
+	}
+	if err = a.isNTSCPermittedToLaunch(ctx, launchReq.Spec, curUser); err != nil {
+		return nil, err
+	}
+
+	spec := launchReq.Spec
+	if len(spec.Config.Entrypoint) == 0 {
+		return nil, status.Error(codes.InvalidArgument,
+			"the config must set the entrypoint to the serving command")
+	}
+	healthPath := req.HealthPath
+	if healthPath == "" {
+		healthPath = "/"
+	} else if !strings.HasPrefix(healthPath, "/") {
+		return nil, status.Error(codes.InvalidArgument, "health_path must start with /")
+	}
+
+	spec.TaskType = model.TaskTypeDeployment
+	if spec.Config.Description == "" {
+		spec.Config.Description = fmt.Sprintf("Deployment (%s)", req.Name)
+	}
+	spec.Config.Entrypoint = append([]string{deploymentEntrypoint}, spec.Config.Entrypoint...)
+	if spec.Base.ExtraEnvVars == nil {
+		spec.Base.ExtraEnvVars = map[string]string{}
+	}
+	maps.Copy(spec.Base.ExtraEnvVars, map[string]string{
+		"DET_TASK_TYPE":              string(model.TaskTypeDeployment),
+		"DET_DEPLOYMENT_NAME":        req.Name,
+		"DET_DEPLOYMENT_HEALTH_PATH": healthPath,
+		"DET_CHECKPOINT_DIR":         deploymentCheckpointDir,
+	})
+	if err := a.setDeploymentCheckpoint(spec, mv.Checkpoint); err != nil {
+		return nil, err
+	}
+	if err = check.Validate(spec.Config); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid deployment config: %s", err.Error())
+	}
+
+	d := &deployment.Deployment{
+		Name:           req.Name,
+		ModelID:        int(mv.Model.Id),
+		ModelVersion:   int(mv.Version),
+		CheckpointUUID: uuid.MustParse(mv.Checkpoint.Uuid),
+		Replicas:       int(req.Replicas),
+		Spec:           *spec,
+		WorkspaceID:    int(spec.Metadata.WorkspaceID),
+		UserID:         curUser.ID,
+		ModelName:      mv.Model.Name,
+	}
+	if err := deployment.Create(ctx, d); err != nil {
+		return nil, deploymentStatusError(err)
+	}
+	deployment.DefaultManager.Wake()
+
+	return &apiv1.CreateDeploymentResponse{
+		Deployment: d.Proto(nil),
+		Config:     protoutils.ToStruct(spec.Config),
+		Warnings:   pkgCommand.LaunchWarningToProto(launchWarnings),
+	}, nil
+}
+
+func (a *apiServer) GetDeployment(
+	ctx context.Context, req *apiv1.GetDeploymentRequest,
+) (*apiv1.GetDeploymentResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := getDeployment(ctx, *curUser, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := deployment.DefaultManager.ReplicaStatuses(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetDeploymentResponse{Deployment: d.Proto(replicas)}, nil
+}
+
+func (a *apiServer) GetDeployments(
+	ctx context.Context, req *apiv1.GetDeploymentsRequest,
+) (*apiv1.GetDeploymentsResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.WorkspaceId != 0 {
+		// check if the workspace exists.
+		if _, err := a.GetWorkspaceByID(ctx, req.WorkspaceId, *curUser, false); err != nil {
+			return nil, err
+		}
+	}
+
+	limitedScopes, err := command.AuthZProvider.Get().AccessibleScopes(
+		ctx, *curUser, model.AccessScopeID(req.WorkspaceId),
+	)
+	if err != nil {
+		return nil, apiutils.MapAndFilterErrors(err, nil, nil)
+	}
+	ds, err := deployment.List(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetDeploymentsResponse{Deployments: []*apiv1.Deployment{}}
+	for _, d := range ds {
+		if !limitedScopes[model.AccessScopeID(d.WorkspaceID)] {
+			continue
+		}
+		replicas, err := deployment.DefaultManager.ReplicaStatuses(ctx, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Deployments = append(resp.Deployments, d.Proto(replicas))
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PatchDeployment(
+	ctx context.Context, req *apiv1.PatchDeploymentRequest,
+) (*apiv1.PatchDeploymentResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := getDeployment(ctx, *curUser, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	if err := command.AuthZProvider.Get().CanCreateNSC(
+		ctx, *curUser, model.AccessScopeID(d.WorkspaceID),
+	); err != nil {
+		return nil, apiutils.MapAndFilterErrors(err, nil, nil)
+	}
+
+	if req.Replicas != nil {
+		d.Replicas = int(*req.Replicas)
+	}
+	if req.ModelVersion != nil && int(*req.ModelVersion) != d.ModelVersion {
+		mv, err := a.deployableModelVersion(ctx, *curUser, fmt.Sprint(d.ModelID), *req.ModelVersion)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.setDeploymentCheckpoint(&d.Spec, mv.Checkpoint); err != nil {
+			return nil, err
+		}
+		d.ModelVersion = int(mv.Version)
+		d.CheckpointUUID = uuid.MustParse(mv.Checkpoint.Uuid)
+	}
+	if err := deployment.Update(ctx, d); err != nil {
+		return nil, deploymentStatusError(err)
+	}
+	deployment.DefaultManager.Wake()
+
+	replicas, err := deployment.DefaultManager.ReplicaStatuses(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PatchDeploymentResponse{Deployment: d.Proto(replicas)}, nil
+}
+
+func (a *apiServer) DeleteDeployment(
+	ctx context.Context, req *apiv1.DeleteDeploymentRequest,
+) (*apiv1.DeleteDeploymentResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := getDeployment(ctx, *curUser, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	if err := command.AuthZProvider.Get().CanTerminateNSC(
+		ctx, *curUser, model.AccessScopeID(d.WorkspaceID),
+	); err != nil {
+		return nil, apiutils.MapAndFilterErrors(err, nil, nil)
+	}
+
+	if err := deployment.Delete(ctx, d.ID); err != nil {
+		return nil, deploymentStatusError(err)
+	}
+	deployment.DefaultManager.Wake()
+	return &apiv1.DeleteDeploymentResponse{}, nil
+}
+
+// getDeployment returns a deployment the user may see, reporting the others as not found.
+func getDeployment(ctx context.Context, curUser model.User, id int) (*deployment.Deployment, error) {
+	notFoundErr := api.NotFoundErrs("deployment", fmt.Sprint(id), true)
+	d, err := deployment.ByID(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, notFoundErr
+	} else if err != nil {
+		return nil, err
+	}
+	if err := command.AuthZProvider.Get().CanGetNSC(
+		ctx, curUser, model.AccessScopeID(d.WorkspaceID),
+	); err != nil {
+		return nil, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+	return d, nil
+}
+
+// deployableModelVersion returns a model version the user may see, if its checkpoint can be
+// served.
+func (a *apiServer) deployableModelVersion(
+	ctx context.Context, curUser model.User, modelName string, version int32,
+) (*modelv1.ModelVersion, error) {
+	mv, err := a.ModelVersionFromID(modelName, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := modelauth.AuthZProvider.Get().CanGetModel(
+		ctx, curUser, mv.Model, mv.Model.WorkspaceId,
+	); err != nil {
+		return nil, authz.SubIfUnauthorized(err,
+			api.NotFoundErrs("model", modelName, true))
+	}
+	if mv.Checkpoint == nil || mv.Checkpoint.State != checkpointv1.State_STATE_COMPLETED {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"the checkpoint of version %d of model %s is not completed", version, mv.Model.Name)
+	}
+	return mv, nil
+}
+
+// setDeploymentCheckpoint points the replicas launched from a spec at a checkpoint. Checkpoints
+// in shared_fs storage are mounted read-only where replicas expect them, and the others are
+// downloaded there by the replicas when they start.
+func (a *apiServer) setDeploymentCheckpoint(
+	spec *tasks.GenericCommandSpec, ckpt *checkpointv1.Checkpoint,
+) error {
+	spec.Base.ExtraEnvVars["DET_CHECKPOINT_UUID"] = ckpt.Uuid
+	spec.Config.BindMounts = slices.DeleteFunc(spec.Config.BindMounts, func(m model.BindMount) bool {
+		return m.ContainerPath == deploymentCheckpointDir
+	})
+	spec.AdditionalFiles = archive.Archive{
+		spec.Base.AgentUserGroup.OwnedArchiveItem(
+			deploymentEntrypoint,
+			etc.MustStaticFile(etc.DeploymentEntrypointResource),
+			0o700,
+			tar.TypeReg,
+		),
+	}
+
+	var hostPath string
+	if ckpt.Training != nil && ckpt.Training.ExperimentId != nil {
+		conf, err := a.m.db.LegacyExperimentConfigByID(int(*ckpt.Training.ExperimentId))
+		if err != nil {
+			return fmt.Errorf("getting checkpoint storage of checkpoint %s: %w", ckpt.Uuid, err)
+		}
+		if c, ok := conf.CheckpointStorage.GetUnionMember().(expconf.SharedFSConfig); ok {
+			storagePath, err := c.PathInHost()
+			if err != nil {
+				return fmt.Errorf("getting checkpoint storage of checkpoint %s: %w", ckpt.Uuid, err)
+			}
+			hostPath = filepath.Join(storagePath, ckpt.Uuid)
+		}
+	}
+	if hostPath == "" {
+		spec.AdditionalFiles = append(spec.AdditionalFiles,
+			spec.Base.AgentUserGroup.OwnedArchiveItem(deploymentCheckpointDir, nil, 0o700, tar.TypeDir))
+		return nil
+	}
+	spec.Config.BindMounts = append(spec.Config.BindMounts, model.ToModelBindMount(
+		schemas.WithDefaults(expconf.BindMount{
+			RawContainerPath: deploymentCheckpointDir,
+			RawHostPath:      hostPath,
+			RawReadOnly:      ptrs.Ptr(true),
+			RawPropagation:   ptrs.Ptr(expconf.DefaultSharedFSPropagation),
+		})))
+	return nil
+}
+
+// deploymentStatusError converts the errors of changes to deployments to status errors.
+func deploymentStatusError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrDuplicateRecord):
+		return status.Error(codes.AlreadyExists, "a deployment with this name already exists")
+	case errors.Is(err, db.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, deployment.ErrNotActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/diff"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func (a *apiServer) GetExperimentsDiff(
+	ctx context.Context, req *apiv1.GetExperimentsDiffRequest,
+) (*apiv1.GetExperimentsDiffResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	left, err := a.getExperiment(ctx, *curUser, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.getExperiment(ctx, *curUser, int(req.OtherExperimentId))
+	if err != nil {
+		return nil, err
+	}
+
+	leftConfig, rightConfig := left.Config.AsMap(), right.Config.AsMap()
+	leftHPs, rightHPs := leftConfig["hyperparameters"], rightConfig["hyperparameters"]
+	delete(leftConfig, "hyperparameters")
+	delete(rightConfig, "hyperparameters")
+
+	resp := &apiv1.GetExperimentsDiffResponse{
+		ModelDefinition: []*apiv1.FileDiff{},
+	}
+	if resp.Config, err = valueDiffsToProto(diff.Values(leftConfig, rightConfig)); err != nil {
+		return nil, err
+	}
+	if resp.Hyperparameters, err = valueDiffsToProto(diff.Values(leftHPs, rightHPs)); err != nil {
+		return nil, err
+	}
+
+	if req.IncludeModelDefinition {
+		for _, id := range []int32{req.ExperimentId, req.OtherExperimentId} {
+			if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(id),
+				experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+				return nil, err
+			}
+		}
+		if resp.ModelDefinition, err = diffModelDefs(
+			int(req.ExperimentId), int(req.OtherExperimentId),
+		); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetTrialsDiff(
+	ctx context.Context, req *apiv1.GetTrialsDiffRequest,
+) (*apiv1.GetTrialsDiffResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trials share the environment and resources sections of the config of their experiment.
+	var hparams, metadata, environment [2]any
+	for i, id := range []int32{req.TrialId, req.OtherTrialId} {
+		trial, err := a.GetTrial(ctx, &apiv1.GetTrialRequest{TrialId: id})
+		if err != nil {
+			return nil, err
+		}
+		exp, err := a.getExperiment(ctx, *curUser, int(trial.Trial.ExperimentId))
+		if err != nil {
+			return nil, err
+		}
+		config := exp.Config.AsMap()
+		hparams[i] = trial.Trial.Hparams.AsMap()
+		metadata[i] = trial.Trial.Metadata.AsMap()
+		environment[i] = map[string]any{
+			"environment": config["environment"],
+			"resources":   config["resources"],
+		}
+	}
+
+	resp := &apiv1.GetTrialsDiffResponse{}
+	if resp.Hparams, err = valueDiffsToProto(diff.Values(hparams[0], hparams[1])); err != nil {
+		return nil, err
+	}
+	if resp.Metadata, err = valueDiffsToProto(diff.Values(metadata[0], metadata[1])); err != nil {
+		return nil, err
+	}
+	if resp.Environment, err = valueDiffsToProto(
+		diff.Values(environment[0], environment[1]),
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// diffModelDefs compares the files of the model definitions of two experiments.
+func diffModelDefs(leftID, rightID int) ([]*apiv1.FileDiff, error) {
+	modelDefCache := GetModelDefCache()
+	paths := map[string][2]bool{}
+	for i, id := range []int{leftID, rightID} {
+		tree, err := modelDefCache.FileTreeNested(id)
+		if err != nil {
+			return nil, fmt.Errorf("reading model definition of experiment %d: %w", id, err)
+		}
+		walkModelDefFiles(tree, func(path string) {
+			exists := paths[path]
+			exists[i] = true
+			paths[path] = exists
+		})
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	diffs := []*apiv1.FileDiff{}
+	for _, path := range sorted {
+		var contents [2][]byte
+		for i, id := range []int{leftID, rightID} {
+			if !paths[path][i] {
+				continue
+			}
+			content, err := modelDefCache.FileContent(id, path)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s of experiment %d: %w", path, id, err)
+			}
+			contents[i] = content
+		}
+
+		fileDiff := &apiv1.FileDiff{Path: path}
+		switch {
+		case !paths[path][0]:
+			fileDiff.Kind = apiv1.DiffKind_DIFF_KIND_ADDED
+		case !paths[path][1]:
+			fileDiff.Kind = apiv1.DiffKind_DIFF_KIND_REMOVED
+		case bytes.Equal(contents[0], contents[1]):
+			continue
+		default:
+			fileDiff.Kind = apiv1.DiffKind_DIFF_KIND_CHANGED
+		}
+
+		if !diff.IsText(contents[0]) || !diff.IsText(contents[1]) {
+			fileDiff.Binary = true
+		} else {
+			text, err := diff.Text(path, contents[0], contents[1])
+			if err != nil {
+				return nil, err
+			}
+			fileDiff.UnifiedDiff = text
+		}
+		diffs = append(diffs, fileDiff)
+	}
+	return diffs, nil
+}
+
+func walkModelDefFiles(nodes []*experimentv1.FileNode, fn func(path string)) {
+	for _, node := range nodes {
+		if node.IsDir {
+			walkModelDefFiles(node.Files, fn)
+		} else {
+			fn(node.Path)
+		}
+	}
+}
+
+func valueDiffsToProto(diffs []diff.Value) ([]*apiv1.ValueDiff, error) {
+	protoDiffs := make([]*apiv1.ValueDiff, 0, len(diffs))
+	for _, d := range diffs {
+		protoDiff := &apiv1.ValueDiff{Path: d.Path}
+		var err error
+		switch d.Kind {
+		case diff.Added:
+			protoDiff.Kind = apiv1.DiffKind_DIFF_KIND_ADDED
+			protoDiff.Right, err = structpb.NewValue(d.Right)
+		case diff.Removed:
+			protoDiff.Kind = apiv1.DiffKind_DIFF_KIND_REMOVED
+			protoDiff.Left, err = structpb.NewValue(d.Left)
+		case diff.Changed:
+			protoDiff.Kind = apiv1.DiffKind_DIFF_KIND_CHANGED
+			if protoDiff.Left, err = structpb.NewValue(d.Left); err == nil {
+				protoDiff.Right, err = structpb.NewValue(d.Right)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("converting %s: %w", d.Path, err)
+		}
+		protoDiffs = append(protoDiffs, protoDiff)
+	}
+	return protoDiffs, nil
+}
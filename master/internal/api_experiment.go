@@ -25,8 +25,6 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
-	structpbmap "google.golang.org/protobuf/types/known/structpb"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
@@ -2201,17 +2199,11 @@ func (a *apiServer) fetchTrialSample(trialID int32, metricName string, metricGro
 
 	if !seenBefore {
 		for _, in := range metricMeasurements {
-			valueMap, err := structpbmap.NewStruct(in.Values)
+			out, err := toDataPoint(in)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to parse metric values")
 			}
-			out := apiv1.DataPoint{
-				Batches: int32(in.Batches),
-				Values:  valueMap,
-				Time:    timestamppb.New(in.Time),
-				Epoch:   in.Epoch,
-			}
-			trial.Data = append(trial.Data, &out)
+			trial.Data = append(trial.Data, out)
 		}
 	}
 
@@ -2637,8 +2629,7 @@ func (a *apiServer) SearchExperiments(
 	}
 
 	if req.Filter != nil {
-		var efr experimentFilterRoot
-		err := json.Unmarshal([]byte(*req.Filter), &efr)
+		efr, err := parseFilter(*req.Filter, false)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/lineage"
+	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/lineagev1"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+func (a *apiServer) GetLineage(
+	ctx context.Context, req *apiv1.GetLineageRequest,
+) (*apiv1.GetLineageResponse, error) {
+	root := &lineagev1.NodeRef{Type: req.Type, Id: req.Id}
+	if err := lineage.ValidateRef(root); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	depth := int(req.Depth)
+	switch {
+	case depth == 0:
+		depth = lineage.DefaultDepth
+	case depth < 0 || depth > lineage.MaxDepth:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"depth must be between 1 and %d", lineage.MaxDepth)
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := req.Direction != apiv1.GetLineageRequest_DIRECTION_DOWNSTREAM
+	downstream := req.Direction != apiv1.GetLineageRequest_DIRECTION_UPSTREAM
+	g, err := lineage.Traverse(ctx, lineage.PostgresSource{}, root, upstream, downstream, depth,
+		a.lineageAuthorizer(*curUser))
+	if errors.Is(err, db.ErrNotFound) {
+		name := strings.ToLower(strings.TrimPrefix(root.Type.String(), "NODE_TYPE_"))
+		return nil, api.NotFoundErrs(strings.ReplaceAll(name, "_", " "), root.Id, true)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.GetLineageResponse{
+		Nodes:     g.Nodes,
+		Edges:     g.Edges,
+		NumHidden: int32(g.NumHidden),
+	}, nil
+}
+
+// lineageVisible interprets the result of an authorization check of a lineage node. Errors that
+// deny access hide the node rather than failing the request.
+func lineageVisible(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if authz.IsPermissionDenied(err) {
+		return false, nil
+	}
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied:
+		return false, nil
+	}
+	return false, err
+}
+
+// lineageAuthorizer returns a lineage.Authorizer that applies the same checks as the APIs that get
+// each type of entity. Checks of experiments and models are cached for the request.
+func (a *apiServer) lineageAuthorizer(curUser model.User) lineage.Authorizer {
+	experiments := make(map[int]bool)
+	models := make(map[int32]bool)
+
+	return func(ctx context.Context, ref *lineagev1.NodeRef) (bool, error) {
+		switch ref.Type {
+		case lineagev1.NodeType_NODE_TYPE_RUN:
+			var expID int
+			if err := db.Bun().NewSelect().Table("runs").Column("experiment_id").
+				Where("id = ?", ref.Id).Scan(ctx, &expID); err != nil {
+				return false, errors.Wrapf(err, "error getting experiment of run %s", ref.Id)
+			}
+			if ok, cached := experiments[expID]; cached {
+				return ok, nil
+			}
+			exp, err := db.ExperimentByID(ctx, expID)
+			if err != nil {
+				return false, err
+			}
+			ok, err := lineageVisible(expauth.AuthZProvider.Get().CanGetExperiment(ctx, curUser, exp))
+			if err != nil {
+				return false, err
+			}
+			experiments[expID] = ok
+			return ok, nil
+		case lineagev1.NodeType_NODE_TYPE_CHECKPOINT:
+			errE := a.m.canDoActionOnCheckpoint(ctx, curUser, ref.Id,
+				expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
+			if errE == nil {
+				return true, nil
+			}
+			return lineageVisible(a.m.canDoActionOnCheckpointThroughModel(ctx, curUser, ref.Id))
+		case lineagev1.NodeType_NODE_TYPE_MODEL_VERSION:
+			var modelID int32
+			if err := db.Bun().NewSelect().Table("model_versions").Column("model_id").
+				Where("id = ?", ref.Id).Scan(ctx, &modelID); err != nil {
+				return false, errors.Wrapf(err, "error getting model of model version %s", ref.Id)
+			}
+			if ok, cached := models[modelID]; cached {
+				return ok, nil
+			}
+			m := &modelv1.Model{}
+			if err := a.m.db.QueryProto("get_model_by_id", m, modelID); err != nil {
+				return false, err
+			}
+			ok, err := lineageVisible(
+				modelauth.AuthZProvider.Get().CanGetModel(ctx, curUser, m, m.WorkspaceId))
+			if err != nil {
+				return false, err
+			}
+			models[modelID] = ok
+			return ok, nil
+		case lineagev1.NodeType_NODE_TYPE_TASK:
+			_, _, err := a.canDoActionsOnTask(ctx, model.TaskID(ref.Id))
+			return lineageVisible(err)
+		default:
+			return false, nil
+		}
+	}
+}
@@ -5,13 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/api"
@@ -47,7 +48,7 @@ func (a *apiServer) GetMaster(
 		MasterId:              a.m.MasterID,
 		ClusterId:             a.m.ClusterID,
 		ClusterName:           a.m.config.ClusterName,
-		TelemetryEnabled:      a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "",
+		TelemetryEnabled:      a.m.config.Telemetry.Enabled && config.GetMasterConfig().Telemetry.SegmentWebUIKey != "",
 		HasCustomLogo:         a.m.config.UICustomization.HasCustomLogo(),
 		ExternalLoginUri:      a.m.config.InternalConfig.ExternalSessions.LoginURI,
 		ExternalLogoutUri:     a.m.config.InternalConfig.ExternalSessions.LogoutURI,
@@ -77,9 +78,10 @@ func (a *apiServer) GetTelemetry(
 	_ context.Context, _ *apiv1.GetTelemetryRequest,
 ) (*apiv1.GetTelemetryResponse, error) {
 	resp := apiv1.GetTelemetryResponse{}
-	if a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "" {
+	segmentKey := config.GetMasterConfig().Telemetry.SegmentWebUIKey
+	if a.m.config.Telemetry.Enabled && segmentKey != "" {
 		resp.Enabled = true
-		resp.SegmentKey = a.m.config.Telemetry.SegmentWebUIKey
+		resp.SegmentKey = segmentKey
 	}
 	return &resp, nil
 }
@@ -99,19 +101,19 @@ func (a *apiServer) GetMasterConfig(
 		return nil, permErr
 	}
 
-	config, err := a.m.config.Printable()
+	printable, err := config.GetMasterConfig().Printable()
 	if err != nil {
 		return nil, fmt.Errorf("error parsing master config: %w", err)
 	}
 	configStruct := &structpb.Struct{}
-	err = protojson.Unmarshal(config, configStruct)
+	err = protojson.Unmarshal(printable, configStruct)
 	return &apiv1.GetMasterConfigResponse{
 		Config: configStruct,
 	}, err
 }
 
-// This endpoint will only make ephermeral changes to the master config,
-// that will be lost if the user restarts the cluster.
+// PatchMasterConfig changes the running master config. Changes are not persisted to the master
+// config file, so they are lost when the master restarts.
 func (a *apiServer) PatchMasterConfig(
 	ctx context.Context, req *apiv1.PatchMasterConfigRequest,
 ) (*apiv1.PatchMasterConfigResponse, error) {
@@ -127,19 +129,83 @@ func (a *apiServer) PatchMasterConfig(
 		return nil, permErr
 	}
 
-	paths := req.FieldMask.GetPaths()
+	newConf, err := patchedMasterConfig(config.GetMasterConfig(), req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	diff, err := config.GetMasterConfig().Diff(newConf)
+	if err != nil {
+		return nil, err
+	}
+	var restartRequired []string
+	for _, change := range diff {
+		if change.RequiresRestart {
+			restartRequired = append(restartRequired, change.Path)
+		}
+	}
+	if len(restartRequired) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"cannot change %s without restarting the master", strings.Join(restartRequired, ", "))
+	}
+
+	changes, err := a.m.ReloadConfig(newConf)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &apiv1.PatchMasterConfigResponse{}
+	for _, change := range changes {
+		oldValue, err := structpb.NewValue(change.OldValue)
+		if err != nil {
+			return nil, fmt.Errorf("converting %s: %w", change.Path, err)
+		}
+		newValue, err := structpb.NewValue(change.NewValue)
+		if err != nil {
+			return nil, fmt.Errorf("converting %s: %w", change.Path, err)
+		}
+		resp.Changes = append(resp.Changes, &apiv1.MasterConfigChange{
+			Path:     change.Path,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return resp, nil
+}
+
+// patchedMasterConfig returns a copy of the master config with the request's patch and masked
+// fields applied.
+func patchedMasterConfig(
+	conf *config.Config, req *apiv1.PatchMasterConfigRequest,
+) (*config.Config, error) {
+	patch := []byte("{}")
+	if req.Patch != nil {
+		var err error
+		if patch, err = protojson.Marshal(req.Patch); err != nil {
+			return nil, err
+		}
+	}
+	newConf, err := conf.Patch(patch)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, path := range paths {
+	for _, path := range req.FieldMask.GetPaths() {
 		switch path {
 		case "log.level":
-			logger.SetLogrus(a.m.config.Log)
+			level := req.Config.GetLog().GetLevel()
+			if level == logv1.LogLevel_LOG_LEVEL_UNSPECIFIED {
+				return nil, errors.New("log.level must be specified")
+			}
+			newConf.Log.Level = logger.ProtoToLogrusLevel(level).String()
 		case "log.color":
-			logger.SetLogrus(a.m.config.Log)
+			newConf.Log.Color = req.Config.GetLog().GetColor()
 		default:
-			panic(fmt.Sprintf("unsupported or invalid field: %s", path))
+			return nil, fmt.Errorf(
+				"unsupported field %s: use patch to change other fields of the master config", path)
 		}
 	}
-	return &apiv1.PatchMasterConfigResponse{}, err
+	return newConf, nil
 }
 
 func (a *apiServer) MasterLogs(
@@ -769,6 +769,14 @@ func (a *apiServer) PatchModelVersion(
 		currLabels = reqLabels
 	}
 
+	if req.ModelVersion.Stage != modelv1.Stage_STAGE_UNSPECIFIED &&
+		req.ModelVersion.Stage != currModelVersion.Stage {
+		log.Infof("model version (%v) stage changing from %v to %v",
+			modelVersionName, currModelVersion.Stage, req.ModelVersion.Stage)
+		madeChanges = true
+		currModelVersion.Stage = req.ModelVersion.Stage
+	}
+
 	if !madeChanges {
 		return &apiv1.PatchModelVersionResponse{ModelVersion: currModelVersion}, nil
 	}
@@ -776,7 +784,7 @@ func (a *apiServer) PatchModelVersion(
 	finalModelVersion := &modelv1.ModelVersion{}
 	err = a.m.db.QueryProto("update_model_version", finalModelVersion, currModelVersion.Id,
 		parentModel.Id, currModelVersion.Name, currModelVersion.Comment, currModelVersion.Notes,
-		currMeta, currLabels)
+		currMeta, currLabels, strings.TrimPrefix(currModelVersion.Stage.String(), "STAGE_"))
 
 	return &apiv1.PatchModelVersionResponse{ModelVersion: finalModelVersion},
 		errors.Wrapf(err, "error updating model version (%v) in database", modelVersionName)
@@ -817,6 +825,143 @@ func (a *apiServer) DeleteModelVersion(
 		errors.Wrapf(err, "error deleting model version %v", modelVersionName)
 }
 
+// modelAliasPattern restricts aliases to names that cannot be mistaken for version numbers.
+var modelAliasPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]{0,127}$`)
+
+func validateModelAlias(alias string) error {
+	if !modelAliasPattern.MatchString(alias) {
+		return status.Errorf(codes.InvalidArgument,
+			"alias %q must start with a letter and contain at most 128 letters, digits, "+
+				"underscores, dots or dashes", alias)
+	}
+	return nil
+}
+
+func (a *apiServer) PutModelAlias(
+	ctx context.Context, req *apiv1.PutModelAliasRequest,
+) (*apiv1.PutModelAliasResponse, error) {
+	if err := validateModelAlias(req.Alias); err != nil {
+		return nil, err
+	}
+	modelVersion, err := a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := modelauth.AuthZProvider.Get().CanEditModel(ctx, *curUser, modelVersion.Model,
+		modelVersion.Model.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	if err := db.SetModelAlias(
+		ctx, modelVersion.Model.Id, req.Alias, modelVersion, curUser.ID,
+	); err != nil {
+		return nil, err
+	}
+	log.Infof("model %q alias %q points at version %v",
+		req.ModelName, req.Alias, req.ModelVersionNum)
+
+	modelVersion, err = a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutModelAliasResponse{ModelVersion: modelVersion}, nil
+}
+
+func (a *apiServer) DeleteModelAlias(
+	ctx context.Context, req *apiv1.DeleteModelAliasRequest,
+) (*apiv1.DeleteModelAliasResponse, error) {
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := modelauth.AuthZProvider.Get().CanEditModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	switch err := db.DeleteModelAlias(ctx, currModel.Id, req.Alias, curUser.ID); {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound,
+			"alias %q for model %q not found", req.Alias, req.ModelName)
+	case err != nil:
+		return nil, err
+	}
+	log.Infof("model %q alias %q removed", req.ModelName, req.Alias)
+	return &apiv1.DeleteModelAliasResponse{}, nil
+}
+
+func (a *apiServer) GetModelVersionByAlias(
+	ctx context.Context, req *apiv1.GetModelVersionByAliasRequest,
+) (*apiv1.GetModelVersionByAliasResponse, error) {
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = modelauth.AuthZProvider.Get().CanGetModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, authz.SubIfUnauthorized(err,
+			errors.Errorf("current user %q doesn't have permissions to get model %q",
+				curUser.Username, currModel.Name))
+	}
+
+	version, err := db.ModelAliasVersion(ctx, currModel.Id, req.Alias)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound,
+			"alias %q for model %q not found", req.Alias, req.ModelName)
+	case err != nil:
+		return nil, err
+	}
+
+	mv, err := a.ModelVersionFromID(req.ModelName, version)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetModelVersionByAliasResponse{ModelVersion: mv}, nil
+}
+
+func (a *apiServer) GetModelAliasHistory(
+	ctx context.Context, req *apiv1.GetModelAliasHistoryRequest,
+) (*apiv1.GetModelAliasHistoryResponse, error) {
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = modelauth.AuthZProvider.Get().CanGetModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, authz.SubIfUnauthorized(err,
+			errors.Errorf("current user %q doesn't have permissions to get model %q",
+				curUser.Username, currModel.Name))
+	}
+
+	resp := &apiv1.GetModelAliasHistoryResponse{}
+	resp.Changes, err = db.ModelAliasHistory(ctx, currModel.Id, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+	return resp, api.Paginate(&resp.Pagination, &resp.Changes, req.Offset, req.Limit)
+}
+
 // Query for all trials that use a given model_version and return their metrics.
 func (a *apiServer) GetTrialMetricsByModelVersion(
 	ctx context.Context, req *apiv1.GetTrialMetricsByModelVersionRequest,
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -13,17 +14,20 @@ import (
 	"google.golang.org/grpc/status"
 
 	petname "github.com/dustinkirkland/golang-petname"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/api/apiutils"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/rbac/audit"
 	"github.com/determined-ai/determined/master/internal/task/idle"
+	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/check"
 	pkgCommand "github.com/determined-ai/determined/master/pkg/command"
@@ -31,6 +35,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/master/pkg/tasks"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -48,6 +53,11 @@ const (
 	jupyterIdleCheck  = "/run/determined/jupyter/check_idle.py"
 	jupyterCertPath   = "/run/determined/jupyter/jupyterCert.pem"
 	jupyterKeyPath    = "/run/determined/jupyter/jupyterKey.key"
+	// jupyterStorageConfPath is the checkpoint storage suspended notebooks archive to.
+	jupyterStorageConfPath = "/run/determined/jupyter/storage_config.json"
+	// notebookSuspensionsDir is the directory of a shared_fs checkpoint storage the archive
+	// directories of notebooks are made in.
+	notebookSuspensionsDir = "notebook-suspensions"
 	// Agent ports 2600 - 3500 are split between TensorBoards, Notebooks, and Shells.
 	minNotebookPort     = 2900
 	maxNotebookPort     = minNotebookPort + 299
@@ -139,7 +149,16 @@ func (a *apiServer) IdleNotebook(
 	if !req.Idle {
 		idle.RecordActivity(req.NotebookId)
 	}
-	return &apiv1.IdleNotebookResponse{}, nil
+
+	resp := &apiv1.IdleNotebookResponse{}
+	storageID, err := command.DefaultCmdService.PendingNotebookSuspension(req.NotebookId)
+	if err != nil {
+		return nil, err
+	}
+	if storageID != nil {
+		resp.SuspendStorageId = ptrs.Ptr(storageID.String())
+	}
+	return resp, nil
 }
 
 func (a *apiServer) KillNotebook(
@@ -156,6 +175,113 @@ func (a *apiServer) KillNotebook(
 	return &apiv1.KillNotebookResponse{Notebook: cmd.ToV1Notebook()}, nil
 }
 
+func (a *apiServer) SuspendNotebook(
+	ctx context.Context, req *apiv1.SuspendNotebookRequest,
+) (*apiv1.SuspendNotebookResponse, error) {
+	if err := a.validateToKillNotebook(ctx, req.NotebookId); err != nil {
+		return nil, err
+	}
+	cmd, err := command.DefaultCmdService.SuspendNotebook(req.NotebookId)
+	if errors.Is(err, command.ErrNotRunning) || errors.Is(err, command.ErrSuspensionDisabled) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.SuspendNotebookResponse{Notebook: cmd.ToV1Notebook()}, nil
+}
+
+func (a *apiServer) ReportNotebookSuspended(
+	ctx context.Context, req *apiv1.ReportNotebookSuspendedRequest,
+) (*apiv1.ReportNotebookSuspendedResponse, error) {
+	if err := a.validateToKillNotebook(ctx, req.NotebookId); err != nil {
+		return nil, err
+	}
+	storageID, err := uuid.Parse(req.StorageId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid storage id: %s", err)
+	}
+	err = command.DefaultCmdService.CompleteNotebookSuspension(req.NotebookId, storageID)
+	if errors.Is(err, command.ErrNotSuspending) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.ReportNotebookSuspendedResponse{}, nil
+}
+
+func (a *apiServer) ResumeNotebook(
+	ctx context.Context, req *apiv1.ResumeNotebookRequest,
+) (*apiv1.ResumeNotebookResponse, error) {
+	targetNotebook, err := a.GetNotebook(ctx, &apiv1.GetNotebookRequest{NotebookId: req.NotebookId})
+	if err != nil {
+		return nil, err
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = audit.SupplyEntityID(ctx, req.NotebookId)
+	if err := command.AuthZProvider.Get().CanCreateNSC(
+		ctx, *curUser, model.AccessScopeID(targetNotebook.Notebook.WorkspaceId),
+	); err != nil {
+		return nil, apiutils.MapAndFilterErrors(err, nil, nil)
+	}
+
+	// The resumed notebook acts on behalf of its owner, like the one it was launched as.
+	owner, err := user.ByID(ctx, model.UserID(targetNotebook.Notebook.UserId))
+	if err != nil {
+		return nil, err
+	}
+	token, err := getTaskSessionToken(ctx, ptrs.Ptr(owner.ToUser()))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, err := command.DefaultCmdService.ResumeNotebook(ctx, req.NotebookId, token)
+	if errors.Is(err, command.ErrNotSuspended) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.ResumeNotebookResponse{Notebook: cmd.ToV1Notebook()}, nil
+}
+
+// notebookSuspendStorage returns the storage a notebook of a workspace archives its working
+// directory to when it is suspended. It is the checkpoint storage of the workspace, except that a
+// shared_fs is narrowed to a new directory of the notebook's own so that the notebook can't reach
+// the checkpoints of experiments.
+func (a *apiServer) notebookSuspendStorage(
+	ctx context.Context, workspaceID model.AccessScopeID,
+) (expconf.CheckpointStorageConfig, error) {
+	w := &model.Workspace{}
+	if err := db.Bun().NewSelect().Model(w).
+		Where("id = ?", workspaceID).
+		Column("checkpoint_storage_config").
+		Scan(ctx); err != nil {
+		return expconf.CheckpointStorageConfig{}, err
+	}
+	merged := schemas.WithDefaults(*schemas.Merge(w.CheckpointStorageConfig,
+		&a.m.config.CheckpointStorage))
+
+	fs := merged.RawSharedFSConfig
+	if fs == nil {
+		return merged, nil
+	}
+	storagePath, err := fs.PathInHost()
+	if err != nil {
+		return expconf.CheckpointStorageConfig{}, err
+	}
+	return expconf.CheckpointStorageConfig{
+		RawSharedFSConfig: &expconf.SharedFSConfig{
+			RawHostPath: ptrs.Ptr(filepath.Join(
+				storagePath, notebookSuspensionsDir, uuid.NewString(),
+			)),
+			RawPropagation: fs.RawPropagation,
+		},
+	}, nil
+}
+
 func (a *apiServer) SetNotebookPriority(
 	ctx context.Context, req *apiv1.SetNotebookPriorityRequest,
 ) (resp *apiv1.SetNotebookPriorityResponse, err error) {
@@ -265,9 +391,10 @@ func (a *apiServer) LaunchNotebook(
 	launchReq.Spec.WatchRunnerIdleTimeout = true
 
 	// Postprocess the launchReq.Spec.
-	if launchReq.Spec.Config.IdleTimeout == nil && a.m.config.NotebookTimeout != nil {
+	notebookTimeout := config.GetMasterConfig().NotebookTimeout
+	if launchReq.Spec.Config.IdleTimeout == nil && notebookTimeout != nil {
 		launchReq.Spec.Config.IdleTimeout = ptrs.Ptr(model.Duration(
-			time.Second * time.Duration(*a.m.config.NotebookTimeout)))
+			time.Second * time.Duration(*notebookTimeout)))
 	}
 	if launchReq.Spec.Config.Description == "" {
 		petName := petname.Generate(expconf.TaskNameGeneratorWords, expconf.TaskNameGeneratorSep)
@@ -310,6 +437,18 @@ func (a *apiServer) LaunchNotebook(
 
 	launchReq.Spec.Config.Entrypoint = []string{jupyterEntrypoint}
 
+	var storageConfBytes []byte
+	if launchReq.Spec.Config.NotebookIdleAction == model.NotebookIdleActionSuspend {
+		suspendStorage, err := a.notebookSuspendStorage(ctx, launchReq.Spec.Metadata.WorkspaceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting checkpoint storage of notebook")
+		}
+		if storageConfBytes, err = json.Marshal(suspendStorage); err != nil {
+			return nil, errors.Wrap(err, "error marshaling checkpoint_storage")
+		}
+		launchReq.Spec.SuspendStorage = &suspendStorage
+	}
+
 	if err = check.Validate(launchReq.Spec.Config); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid notebook config: %s", err.Error())
 	}
@@ -356,6 +495,15 @@ func (a *apiServer) LaunchNotebook(
 			tar.TypeReg,
 		),
 	}
+	if storageConfBytes != nil {
+		launchReq.Spec.AdditionalFiles = append(launchReq.Spec.AdditionalFiles,
+			launchReq.Spec.Base.AgentUserGroup.OwnedArchiveItem(
+				jupyterStorageConfPath,
+				storageConfBytes,
+				0o600,
+				tar.TypeReg,
+			))
+	}
 
 	// Launch a Notebook.
 	genericCmd, err := command.DefaultCmdService.LaunchNotebookCommand(
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const defaultProxyAccessLogsLimit = 1000
+
+func (a *apiServer) GetProxyAccessLogs(
+	ctx context.Context, req *apiv1.GetProxyAccessLogsRequest,
+) (*apiv1.GetProxyAccessLogsResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Access logs reveal the activity of every user of a task, so they are as sensitive as the
+	// master logs.
+	permErr, err := cluster.AuthZProvider.Get().CanGetMasterLogs(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if permErr != nil {
+		return nil, status.Error(codes.PermissionDenied, permErr.Error())
+	}
+	if err := grpcutil.ValidateRequest(
+		grpcutil.ValidateLimit(req.Limit),
+	); err != nil {
+		return nil, err
+	}
+
+	filter := proxy.AccessLogFilter{Offset: int(req.Offset), Limit: int(req.Limit)}
+	if filter.Limit == 0 {
+		filter.Limit = defaultProxyAccessLogsLimit
+	}
+	if req.UserId != nil {
+		filter.UserID = ptrs.Ptr(model.UserID(*req.UserId))
+	}
+	if req.TimestampAfter != nil {
+		filter.After = ptrs.Ptr(req.TimestampAfter.AsTime())
+	}
+	if req.TimestampBefore != nil {
+		filter.Before = ptrs.Ptr(req.TimestampBefore.AsTime())
+	}
+	if filter.After != nil && filter.Before != nil && !filter.After.Before(*filter.Before) {
+		return nil, status.Error(codes.InvalidArgument,
+			"timestamp_after must be before timestamp_before")
+	}
+
+	logs, err := proxy.AccessLogs(ctx, model.TaskID(req.TaskId), filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetProxyAccessLogsResponse{AccessLogs: []*apiv1.ProxyAccessLog{}}
+	for _, l := range logs {
+		resp.AccessLogs = append(resp.AccessLogs, l.Proto())
+	}
+	return resp, nil
+}
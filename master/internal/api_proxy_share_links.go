@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/internal/sharelink"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/usergroup"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) CreateProxyShareLink(
+	ctx context.Context, req *apiv1.CreateProxyShareLinkRequest,
+) (*apiv1.CreateProxyShareLinkResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID := model.TaskID(req.TaskId)
+	if err := canShareTaskProxy(ctx, *u, taskID); err != nil {
+		return nil, err
+	}
+
+	lifetime, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument,
+			"duration must be a Go-formatted duration string with a positive value")
+	}
+	for _, id := range req.UserIds {
+		if _, err := user.ByID(ctx, model.UserID(id)); errors.Is(err, db.ErrNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "user %d not found", id)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range req.GroupIds {
+		if _, err := usergroup.GroupByIDTx(ctx, nil, int(id)); errors.Is(err, db.ErrNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "group %d not found", id)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	l := &sharelink.ShareLink{
+		TaskID:   taskID,
+		UserID:   u.ID,
+		ReadOnly: req.ReadOnly,
+		UserIDs:  req.UserIds,
+		GroupIDs: req.GroupIds,
+	}
+	token, err := sharelink.Create(ctx, l, lifetime)
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	logProxyShareLinkAudit("created", l, u)
+	return &apiv1.CreateProxyShareLinkResponse{ShareLink: l.Proto(), Token: token}, nil
+}
+
+func (a *apiServer) GetProxyShareLinks(
+	ctx context.Context, req *apiv1.GetProxyShareLinksRequest,
+) (*apiv1.GetProxyShareLinksResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID := model.TaskID(req.TaskId)
+	if err := canShareTaskProxy(ctx, *u, taskID); err != nil {
+		return nil, err
+	}
+
+	links, err := sharelink.ActiveForTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetProxyShareLinksResponse{ShareLinks: []*apiv1.ProxyShareLink{}}
+	for _, l := range links {
+		resp.ShareLinks = append(resp.ShareLinks, l.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) RevokeProxyShareLink(
+	ctx context.Context, req *apiv1.RevokeProxyShareLinkRequest,
+) (*apiv1.RevokeProxyShareLinkResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notFoundErr := api.NotFoundErrs("share link", fmt.Sprint(req.Id), true)
+	l, err := sharelink.ByID(ctx, int(req.Id))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, notFoundErr
+	} else if err != nil {
+		return nil, err
+	}
+	if err := canShareTaskProxy(ctx, *u, l.TaskID); err != nil {
+		return nil, notFoundErr
+	}
+
+	if err := sharelink.Revoke(ctx, l.ID); errors.Is(err, db.ErrNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "share link %d is already revoked", l.ID)
+	} else if err != nil {
+		return nil, err
+	}
+	logProxyShareLinkAudit("revoked", l, u)
+	return &apiv1.RevokeProxyShareLinkResponse{}, nil
+}
+
+// canShareTaskProxy checks whether a user may manage the share links of a task, which requires
+// being able to reach its proxied services.
+func canShareTaskProxy(ctx context.Context, u model.User, taskID model.TaskID) error {
+	err := canAccessTaskProxy(ctx, u, taskID, true)
+	if errors.Is(err, db.ErrNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return api.NotFoundErrs("task", string(taskID), true)
+	}
+	return err
+}
+
+// authenticateProxyShareLink returns whether a proxy request carries a share link that lets it
+// in. A link opened through its URL is remembered in a cookie scoped to the service, so that the
+// requests the page makes afterwards are let in too.
+func authenticateProxyShareLink(c echo.Context, taskID model.TaskID) bool {
+	token, opened := c.QueryParam(sharelink.QueryParam), true
+	if token == "" {
+		cookie, err := c.Cookie(sharelink.CookieName)
+		if err != nil {
+			return false
+		}
+		token, opened = cookie.Value, false
+	}
+
+	// Links limited to users or groups need the requester to be logged in.
+	usr, _, err := user.GetService().UserAndSessionFromRequest(c.Request())
+	if err != nil || !usr.Active {
+		usr = nil
+	}
+
+	l, err := sharelink.Authorize(
+		c.Request().Context(), token, taskID, c.Request().Method, c.IsWebSocket(), usr)
+	if err != nil {
+		if !errors.Is(err, sharelink.ErrInvalidLink) && !errors.Is(err, sharelink.ErrNotAllowed) {
+			log.WithError(err).Warnf("error checking share link for task %s", taskID)
+		}
+		return false
+	}
+
+	if usr != nil {
+		proxy.SetUserID(c, usr.ID)
+	}
+	if opened {
+		c.SetCookie(&http.Cookie{
+			Name:     sharelink.CookieName,
+			Value:    token,
+			Path:     fmt.Sprintf("%s/%s/", proxyPrefix, c.Param("service")),
+			Expires:  l.ExpiresAt,
+			Secure:   c.Scheme() == "https",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		logProxyShareLinkAudit("opened", l, usr)
+	}
+	return true
+}
+
+// logProxyShareLinkAudit records the creation, revocation and use of a share link in the audit
+// log. The user is nil for links opened by someone who is not logged in.
+func logProxyShareLinkAudit(action string, l *sharelink.ShareLink, usr *model.User) {
+	fields := log.Fields{
+		"type":          "proxy_share_link_audit_log",
+		"share_link_id": l.ID,
+		"task_id":       l.TaskID,
+		"action":        action,
+	}
+	if usr != nil {
+		fields["determined_user"] = usr.Username
+	}
+	log.WithFields(fields).Infof("share link %d for task %s %s", l.ID, l.TaskID, action)
+}
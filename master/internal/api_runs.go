@@ -3,7 +3,6 @@ package internal
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -269,8 +268,7 @@ func sortRuns(sortString *string, runQuery *bun.SelectQuery) error {
 }
 
 func filterRunQuery(getQ *bun.SelectQuery, filter *string) (*bun.SelectQuery, error) {
-	var efr experimentFilterRoot
-	err := json.Unmarshal([]byte(*filter), &efr)
+	efr, err := parseFilter(*filter, true)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,250 @@
+package internal
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/db/bunutils"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/internal/schedules"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/schedulev1"
+)
+
+func (a *apiServer) PostExperimentSchedule(
+	ctx context.Context, req *apiv1.PostExperimentScheduleRequest,
+) (*apiv1.PostExperimentScheduleResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := schedules.ValidateCron(req.Cron); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s := &schedules.ExperimentSchedule{
+		Name:          req.Name,
+		Description:   req.Description,
+		Cron:          req.Cron,
+		Config:        req.Config,
+		Template:      req.Template,
+		ProjectID:     int(req.ProjectId),
+		UserID:        curUser.ID,
+		Username:      curUser.Username,
+		SkipIfRunning: req.SkipIfRunning,
+		Paused:        req.Paused,
+	}
+	if req.ExperimentId != nil {
+		exp, err := a.getExperiment(ctx, *curUser, int(*req.ExperimentId))
+		if err != nil {
+			return nil, err
+		}
+		s.ModelDefinitionExperimentID = ptrs.Ptr(int(exp.Id))
+		if s.Config == "" {
+			s.Config = exp.OriginalConfig
+		}
+		if s.ProjectID == 0 {
+			s.ProjectID = int(exp.ProjectId)
+		}
+	}
+	if s.Config == "" {
+		return nil, status.Error(codes.InvalidArgument, "config or experiment_id is required")
+	}
+
+	// Create an experiment of the schedule in validate-only mode now, so that errors in the config,
+	// model definition and permissions surface before the schedule first fires.
+	createReq, err := schedules.CreateRequest(s, time.Now().UTC())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.ProjectID == 0 {
+		config, err := expconf.ParseAnyExperimentConfigYAML([]byte(createReq.Config))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid experiment configuration: %s", err)
+		}
+		p, err := getCreateExperimentsProject(a.m, createReq, curUser, schemas.WithDefaults(config))
+		if err != nil {
+			return nil, err
+		}
+		s.ProjectID = int(p.Id)
+		createReq.ProjectId = p.Id
+	}
+	createReq.ValidateOnly = true
+	if _, err := a.CreateExperiment(ctx, createReq); err != nil {
+		return nil, err
+	}
+
+	if err := schedules.Add(ctx, s); err != nil {
+		return nil, err
+	}
+	protoSchedule, err := s.Proto(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostExperimentScheduleResponse{Schedule: protoSchedule}, nil
+}
+
+func (a *apiServer) GetExperimentSchedules(
+	ctx context.Context, req *apiv1.GetExperimentSchedulesRequest,
+) (*apiv1.GetExperimentSchedulesResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := schedules.List(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	canView := map[int]bool{}
+	resp := &apiv1.GetExperimentSchedulesResponse{Schedules: []*schedulev1.ExperimentSchedule{}}
+	for _, s := range all {
+		if _, ok := canView[s.ProjectID]; !ok {
+			p, err := project.GetProjectByID(ctx, s.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			err = project.AuthZProvider.Get().CanGetProject(ctx, *curUser, p.Proto())
+			if err != nil && !authz.IsPermissionDenied(err) {
+				return nil, err
+			}
+			canView[s.ProjectID] = err == nil
+		}
+		if !canView[s.ProjectID] {
+			continue
+		}
+		protoSchedule, err := s.Proto(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp.Schedules = append(resp.Schedules, protoSchedule)
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetExperimentSchedule(
+	ctx context.Context, req *apiv1.GetExperimentScheduleRequest,
+) (*apiv1.GetExperimentScheduleResponse, error) {
+	s, err := a.getExperimentSchedule(ctx, int(req.Id), false)
+	if err != nil {
+		return nil, err
+	}
+	protoSchedule, err := s.Proto(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetExperimentScheduleResponse{Schedule: protoSchedule}, nil
+}
+
+func (a *apiServer) GetExperimentScheduleRuns(
+	ctx context.Context, req *apiv1.GetExperimentScheduleRunsRequest,
+) (*apiv1.GetExperimentScheduleRunsResponse, error) {
+	if _, err := a.getExperimentSchedule(ctx, int(req.Id), false); err != nil {
+		return nil, err
+	}
+
+	q, pagination, err := bunutils.Paginate(ctx,
+		schedules.Runs(int(req.Id)), int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	var runs []*schedules.Run
+	if err := q.Scan(ctx, &runs); err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetExperimentScheduleRunsResponse{
+		Runs:       []*schedulev1.ExperimentScheduleRun{},
+		Pagination: pagination,
+	}
+	for _, r := range runs {
+		resp.Runs = append(resp.Runs, r.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PauseExperimentSchedule(
+	ctx context.Context, req *apiv1.PauseExperimentScheduleRequest,
+) (*apiv1.PauseExperimentScheduleResponse, error) {
+	if _, err := a.getExperimentSchedule(ctx, int(req.Id), true); err != nil {
+		return nil, err
+	}
+	if err := schedules.SetPaused(ctx, int(req.Id), true); err != nil {
+		return nil, err
+	}
+	return &apiv1.PauseExperimentScheduleResponse{}, nil
+}
+
+func (a *apiServer) UnpauseExperimentSchedule(
+	ctx context.Context, req *apiv1.UnpauseExperimentScheduleRequest,
+) (*apiv1.UnpauseExperimentScheduleResponse, error) {
+	if _, err := a.getExperimentSchedule(ctx, int(req.Id), true); err != nil {
+		return nil, err
+	}
+	if err := schedules.SetPaused(ctx, int(req.Id), false); err != nil {
+		return nil, err
+	}
+	return &apiv1.UnpauseExperimentScheduleResponse{}, nil
+}
+
+func (a *apiServer) DeleteExperimentSchedule(
+	ctx context.Context, req *apiv1.DeleteExperimentScheduleRequest,
+) (*apiv1.DeleteExperimentScheduleResponse, error) {
+	if _, err := a.getExperimentSchedule(ctx, int(req.Id), true); err != nil {
+		return nil, err
+	}
+	if err := schedules.Delete(ctx, int(req.Id)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteExperimentScheduleResponse{}, nil
+}
+
+// getExperimentSchedule returns a schedule the current user can view. If modify is set, the user
+// must also be able to create experiments in the project of the schedule.
+func (a *apiServer) getExperimentSchedule(
+	ctx context.Context, id int, modify bool,
+) (*schedules.ExperimentSchedule, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := api.NotFoundErrs("experiment schedule", strconv.Itoa(id), true)
+	s, err := schedules.Get(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, notFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	p, err := project.GetProjectByID(ctx, s.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	protoProject := p.Proto()
+	if err := project.AuthZProvider.Get().CanGetProject(ctx, *curUser, protoProject); err != nil {
+		return nil, authz.SubIfUnauthorized(err, notFound)
+	}
+	if modify {
+		if err := expauth.AuthZProvider.Get().
+			CanCreateExperiment(ctx, *curUser, protoProject); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	return s, nil
+}
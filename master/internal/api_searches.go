@@ -3,7 +3,6 @@ package internal
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -28,8 +27,7 @@ type searchCandidateResult struct {
 }
 
 func filterSearchQuery(getQ *bun.SelectQuery, filter *string) (*bun.SelectQuery, error) {
-	var efr experimentFilterRoot
-	err := json.Unmarshal([]byte(*filter), &efr)
+	efr, err := parseFilter(*filter, false)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +246,10 @@ func (a *apiServer) LaunchShell(
 	}
 
 	launchReq.Spec.Base.ExtraEnvVars = map[string]string{"DET_TASK_TYPE": string(model.TaskTypeShell)}
+	if launchReq.Spec.Config.GPUIdleTimeout != nil {
+		// Shells have no idleness check loop, so report their GPU utilization from the entrypoint.
+		launchReq.Spec.Base.ExtraEnvVars["DET_REPORT_GPU_UTILIZATION"] = "1"
+	}
 
 	oidcPachydermEnvVars, err := a.getOIDCPachydermEnvVars(session)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/logpattern"
 	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/task/idle"
 	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
@@ -380,6 +381,19 @@ func (a *apiServer) PostAllocationAcceleratorData(
 		return nil, err
 	}
 
+	if len(req.AcceleratorUtilization) > 0 {
+		// The task is in use as long as any of its accelerators is.
+		var utilization float64
+		for _, u := range req.AcceleratorUtilization {
+			utilization = max(utilization, u)
+		}
+		idle.RecordGPUUtilization(
+			string(model.AllocationID(req.AllocationId).ToTaskID()), utilization)
+	}
+	if req.AcceleratorData == nil {
+		return &apiv1.PostAllocationAcceleratorDataResponse{}, nil
+	}
+
 	accData := &model.AcceleratorData{
 		ContainerID:      req.AcceleratorData.ContainerId,
 		AllocationID:     model.AllocationID(req.AllocationId),
@@ -27,6 +27,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/api/apiutils"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	exputil "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
@@ -252,7 +253,7 @@ func (a *apiServer) LaunchTensorboard(
 	// Postprocess the launchReq.Spec.
 	if launchReq.Spec.Config.IdleTimeout == nil {
 		masterTensorBoardIdleTimeout := model.Duration(
-			time.Duration(a.m.config.TensorBoardTimeout) * time.Second)
+			time.Duration(config.GetMasterConfig().TensorBoardTimeout) * time.Second)
 		launchReq.Spec.Config.IdleTimeout = &masterTensorBoardIdleTimeout
 	}
 
@@ -745,6 +745,15 @@ func (a *apiServer) GetTrial(ctx context.Context, req *apiv1.GetTrialRequest) (
 		}
 	}
 
+	restarts, err := db.TrialRestarts(ctx, int(req.TrialId))
+	if err != nil {
+		return nil, err
+	}
+	resp.RestartHistory = make([]*trialv1.TrialRestart, 0, len(restarts))
+	for _, r := range restarts {
+		resp.RestartHistory = append(resp.RestartHistory, r.Proto())
+	}
+
 	return resp, nil
 }
 
@@ -808,21 +817,43 @@ func (a *apiServer) formatMetrics(
 	m *apiv1.DownsampledMetrics, metricMeasurements []db.MetricMeasurements,
 ) error {
 	for _, in := range metricMeasurements {
-		valueMap, err := structpb.NewStruct(in.Values) // in.Value is a map.
+		out, err := toDataPoint(in)
 		if err != nil {
 			return errors.Wrapf(err, "error formatting metrics")
 		}
-		out := apiv1.DataPoint{
-			Time:    timestamppb.New(in.Time),
-			Batches: int32(in.Batches),
-			Values:  valueMap,
-			Epoch:   in.Epoch,
-		}
-		m.Data = append(m.Data, &out)
+		m.Data = append(m.Data, out)
 	}
 	return nil
 }
 
+func toDataPoint(in db.MetricMeasurements) (*apiv1.DataPoint, error) {
+	out := &apiv1.DataPoint{
+		Time:    timestamppb.New(in.Time),
+		Batches: int32(in.Batches),
+		Epoch:   in.Epoch,
+	}
+	var err error
+	if out.Values, err = structpb.NewStruct(in.Values); err != nil {
+		return nil, err
+	}
+	if in.MinValues != nil {
+		if out.MinValues, err = structpb.NewStruct(in.MinValues); err != nil {
+			return nil, err
+		}
+	}
+	if in.MaxValues != nil {
+		if out.MaxValues, err = structpb.NewStruct(in.MaxValues); err != nil {
+			return nil, err
+		}
+	}
+	if in.MeanValues != nil {
+		if out.MeanValues, err = structpb.NewStruct(in.MeanValues); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
 func (a *apiServer) parseMetricGroupArgs(
 	legacyType apiv1.MetricType, newType model.MetricGroup,
 ) (model.MetricGroup, error) {
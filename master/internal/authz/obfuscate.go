@@ -121,6 +121,7 @@ func ObfuscateJob(job *jobv1.Job) jobv1.LimitedJob {
 		JobId:          job.JobId,
 		RequestedSlots: job.RequestedSlots,
 		AllocatedSlots: job.AllocatedSlots,
+		TargetSlots:    job.TargetSlots,
 		Progress:       job.Progress,
 		WorkspaceId:    job.WorkspaceId,
 	}
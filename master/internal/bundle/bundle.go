@@ -0,0 +1,349 @@
+// Package bundle exports experiments to self-contained archives and imports them into another
+// cluster.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Version is the version of the bundle format written by this master.
+const Version = 1
+
+const (
+	manifestFile   = "manifest.json"
+	experimentFile = "experiment.json"
+	modelDefFile   = "model_def.tar.gz"
+	metricsFile    = "metrics.jsonl"
+	logsFile       = "logs.jsonl"
+)
+
+// Manifest describes the contents of a bundle.
+type Manifest struct {
+	Version     int       `json:"version"`
+	ClusterID   string    `json:"cluster_id"`
+	ExportedAt  time.Time `json:"exported_at"`
+	Experiments []int     `json:"experiments"`
+}
+
+// Experiment is the metadata of an exported experiment.
+type Experiment struct {
+	ID                   int             `json:"id"`
+	State                model.State     `json:"state"`
+	Config               json.RawMessage `json:"config"`
+	OriginalConfig       string          `json:"original_config"`
+	Notes                string          `json:"notes"`
+	StartTime            time.Time       `json:"start_time"`
+	EndTime              *time.Time      `json:"end_time"`
+	Archived             bool            `json:"archived"`
+	Unmanaged            bool            `json:"unmanaged"`
+	ExternalExperimentID *string         `json:"external_experiment_id"`
+	Owner                string          `json:"owner"`
+	Workspace            string          `json:"workspace"`
+	Project              string          `json:"project"`
+	Trials               []Trial         `json:"trials"`
+	Checkpoints          []Checkpoint    `json:"checkpoints"`
+}
+
+// Name returns the name of the experiment from its config.
+func (e *Experiment) Name() string {
+	var config struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(e.Config, &config); err != nil {
+		return ""
+	}
+	return config.Name
+}
+
+// Trial is an exported trial.
+type Trial struct {
+	ID              int              `json:"id"`
+	RequestID       *model.RequestID `json:"request_id"`
+	State           model.State      `json:"state"`
+	StartTime       time.Time        `json:"start_time"`
+	EndTime         *time.Time       `json:"end_time"`
+	HParams         map[string]any   `json:"hparams"`
+	Seed            int64            `json:"seed"`
+	Restarts        int              `json:"restarts"`
+	ExternalTrialID *string          `json:"external_trial_id"`
+	Metadata        map[string]any   `json:"metadata"`
+}
+
+// Checkpoint is the metadata of an exported checkpoint. Checkpoint files stay in checkpoint
+// storage and are not part of the bundle.
+type Checkpoint struct {
+	UUID       uuid.UUID        `json:"uuid"`
+	TrialID    int              `json:"trial_id"`
+	ReportTime time.Time        `json:"report_time"`
+	State      model.State      `json:"state"`
+	Resources  map[string]int64 `json:"resources"`
+	Metadata   map[string]any   `json:"metadata"`
+}
+
+// Metrics is one exported metrics report of a trial.
+type Metrics struct {
+	TrialID        int               `json:"trial_id"`
+	Group          model.MetricGroup `json:"group"`
+	StepsCompleted int               `json:"steps_completed"`
+	ReportTime     time.Time         `json:"report_time"`
+	AvgMetrics     map[string]any    `json:"avg_metrics"`
+	BatchMetrics   []map[string]any  `json:"batch_metrics,omitempty"`
+}
+
+// TaskLog is one exported log line of a trial.
+type TaskLog struct {
+	TrialID int `json:"trial_id"`
+	model.TaskLog
+}
+
+// ExperimentData is everything a bundle holds for one experiment.
+type ExperimentData struct {
+	Experiment Experiment
+	ModelDef   []byte
+	Metrics    []Metrics
+	Logs       []TaskLog
+}
+
+// Bundle is a decoded bundle.
+type Bundle struct {
+	Manifest    Manifest
+	Experiments []*ExperimentData
+}
+
+// Writer writes a bundle as a gzipped tar archive.
+type Writer struct {
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	manifest Manifest
+}
+
+// NewWriter returns a Writer that writes a bundle to w.
+func NewWriter(w io.Writer, clusterID string) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+		manifest: Manifest{
+			Version:    Version,
+			ClusterID:  clusterID,
+			ExportedAt: time.Now().UTC(),
+		},
+	}
+}
+
+// WriteExperiment adds an experiment to the bundle.
+func (w *Writer) WriteExperiment(data *ExperimentData) error {
+	dir := experimentDir(data.Experiment.ID)
+
+	bs, err := json.Marshal(data.Experiment)
+	if err != nil {
+		return fmt.Errorf("encoding experiment %d: %w", data.Experiment.ID, err)
+	}
+	if err := w.writeFile(path.Join(dir, experimentFile), bs); err != nil {
+		return err
+	}
+	if err := w.writeFile(path.Join(dir, modelDefFile), data.ModelDef); err != nil {
+		return err
+	}
+
+	var metrics bytes.Buffer
+	enc := json.NewEncoder(&metrics)
+	for _, m := range data.Metrics {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("encoding metrics of trial %d: %w", m.TrialID, err)
+		}
+	}
+	if err := w.writeFile(path.Join(dir, metricsFile), metrics.Bytes()); err != nil {
+		return err
+	}
+
+	if data.Logs != nil {
+		var logs bytes.Buffer
+		enc := json.NewEncoder(&logs)
+		for _, l := range data.Logs {
+			if err := enc.Encode(l); err != nil {
+				return fmt.Errorf("encoding logs of trial %d: %w", l.TrialID, err)
+			}
+		}
+		if err := w.writeFile(path.Join(dir, logsFile), logs.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	w.manifest.Experiments = append(w.manifest.Experiments, data.Experiment.ID)
+	return nil
+}
+
+// Close writes the manifest and flushes the archive.
+func (w *Writer) Close() error {
+	bs, err := json.Marshal(w.manifest)
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := w.writeFile(manifestFile, bs); err != nil {
+		return err
+	}
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+func (w *Writer) writeFile(name string, contents []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(contents)),
+		ModTime: w.manifest.ExportedAt,
+	}); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(contents); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+func experimentDir(id int) string {
+	return path.Join("experiments", strconv.Itoa(id))
+}
+
+// ErrTooLarge is returned by Read for bundles that are larger than allowed.
+var ErrTooLarge = errors.New("bundle is too large")
+
+// Read decodes a bundle. Bundles are decoded into memory, so Read fails with ErrTooLarge once it
+// has read more than maxSize uncompressed bytes.
+func Read(r io.Reader, maxSize int64) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("bundle is not a gzipped archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	experiments := map[int]*ExperimentData{}
+	get := func(id int) *ExperimentData {
+		if _, ok := experiments[id]; !ok {
+			experiments[id] = &ExperimentData{}
+		}
+		return experiments[id]
+	}
+
+	tr := tar.NewReader(&sizeLimitedReader{r: gz, max: maxSize})
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+
+		if hdr.Name == manifestFile {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("decoding manifest: %w", err)
+			}
+			if manifest.Version > Version {
+				return nil, fmt.Errorf(
+					"bundle version %d is newer than the supported version %d", manifest.Version, Version)
+			}
+			continue
+		}
+
+		parts := strings.Split(hdr.Name, "/")
+		if len(parts) != 3 || parts[0] != "experiments" {
+			return nil, fmt.Errorf("unexpected file in bundle: %s", hdr.Name)
+		}
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected file in bundle: %s", hdr.Name)
+		}
+		data := get(id)
+
+		switch parts[2] {
+		case experimentFile:
+			if err := json.NewDecoder(tr).Decode(&data.Experiment); err != nil {
+				return nil, fmt.Errorf("decoding experiment %d: %w", id, err)
+			}
+		case modelDefFile:
+			if data.ModelDef, err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("reading model definition of experiment %d: %w", id, err)
+			}
+		case metricsFile:
+			if data.Metrics, err = readJSONLines[Metrics](tr); err != nil {
+				return nil, fmt.Errorf("decoding metrics of experiment %d: %w", id, err)
+			}
+		case logsFile:
+			if data.Logs, err = readJSONLines[TaskLog](tr); err != nil {
+				return nil, fmt.Errorf("decoding logs of experiment %d: %w", id, err)
+			}
+			if data.Logs == nil {
+				data.Logs = []TaskLog{}
+			}
+		default:
+			return nil, fmt.Errorf("unexpected file in bundle: %s", hdr.Name)
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("bundle has no %s", manifestFile)
+	}
+	b := &Bundle{Manifest: *manifest}
+	for _, id := range manifest.Experiments {
+		data, ok := experiments[id]
+		if !ok || data.Experiment.ID != id {
+			return nil, fmt.Errorf("bundle is missing experiment %d", id)
+		}
+		b.Experiments = append(b.Experiments, data)
+	}
+	sort.Slice(b.Experiments, func(i, j int) bool {
+		return b.Experiments[i].Experiment.ID < b.Experiments[j].Experiment.ID
+	})
+	return b, nil
+}
+
+// sizeLimitedReader fails with ErrTooLarge once more than max bytes have been read from r.
+type sizeLimitedReader struct {
+	r    io.Reader
+	read int64
+	max  int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n, fmt.Errorf("%w: bundles can be at most %d bytes uncompressed", ErrTooLarge, l.max)
+	}
+	return n, err
+}
+
+func readJSONLines[T any](r io.Reader) ([]T, error) {
+	var items []T
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var item T
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
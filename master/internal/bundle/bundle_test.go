@@ -0,0 +1,130 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const maxTestBundleSize = 64 << 20
+
+func TestBundleRoundTrip(t *testing.T) {
+	reportTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	exported := []*ExperimentData{
+		{
+			Experiment: Experiment{
+				ID:        7,
+				State:     model.CompletedState,
+				Config:    json.RawMessage(`{"name":"mnist"}`),
+				Owner:     "alice",
+				Workspace: "research",
+				Project:   "vision",
+				Trials:    []Trial{{ID: 70, State: model.CompletedState, Seed: 1}},
+				Checkpoints: []Checkpoint{{
+					UUID:       uuid.New(),
+					TrialID:    70,
+					ReportTime: reportTime,
+					State:      model.CompletedState,
+					Resources:  map[string]int64{"model.pt": 10},
+				}},
+			},
+			ModelDef: []byte("model definition"),
+			Metrics: []Metrics{{
+				TrialID:        70,
+				Group:          model.TrainingMetricGroup,
+				StepsCompleted: 100,
+				ReportTime:     reportTime,
+				AvgMetrics:     map[string]any{"loss": 0.5},
+			}},
+			Logs: []TaskLog{{TrialID: 70, TaskLog: model.TaskLog{Log: "hello\n"}}},
+		},
+		{
+			Experiment: Experiment{
+				ID:     3,
+				State:  model.ActiveState,
+				Config: json.RawMessage(`{"name":"cifar"}`),
+			},
+			ModelDef: []byte("another model definition"),
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "cluster")
+	for _, data := range exported {
+		require.NoError(t, w.WriteExperiment(data))
+	}
+	require.NoError(t, w.Close())
+
+	b, err := Read(&buf, maxTestBundleSize)
+	require.NoError(t, err)
+	require.Equal(t, Version, b.Manifest.Version)
+	require.Equal(t, "cluster", b.Manifest.ClusterID)
+	require.Len(t, b.Experiments, 2)
+
+	require.Equal(t, 3, b.Experiments[0].Experiment.ID)
+	require.Equal(t, "cifar", b.Experiments[0].Experiment.Name())
+	require.Nil(t, b.Experiments[0].Logs)
+
+	imported := b.Experiments[1]
+	require.Equal(t, exported[0].Experiment.Checkpoints, imported.Experiment.Checkpoints)
+	require.Equal(t, exported[0].Experiment.Trials, imported.Experiment.Trials)
+	require.Equal(t, exported[0].ModelDef, imported.ModelDef)
+	require.Equal(t, exported[0].Metrics, imported.Metrics)
+	require.Equal(t, exported[0].Logs, imported.Logs)
+}
+
+func TestReadRejectsInvalidBundles(t *testing.T) {
+	write := func(files map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, contents := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{
+				Name: name, Mode: 0o600, Size: int64(len(contents)),
+			}))
+			_, err := tw.Write([]byte(contents))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return &buf
+	}
+
+	_, err := Read(bytes.NewBufferString("not a bundle"), maxTestBundleSize)
+	require.ErrorContains(t, err, "not a gzipped archive")
+
+	_, err = Read(write(map[string]string{}), maxTestBundleSize)
+	require.ErrorContains(t, err, "no manifest.json")
+
+	_, err = Read(write(map[string]string{manifestFile: `{"version": 2}`}), maxTestBundleSize)
+	require.ErrorContains(t, err, "newer than the supported version")
+
+	_, err = Read(
+		write(map[string]string{manifestFile: `{"version": 1, "experiments": [1]}`}), maxTestBundleSize)
+	require.ErrorContains(t, err, "missing experiment 1")
+
+	_, err = Read(write(map[string]string{"../etc/passwd": ""}), maxTestBundleSize)
+	require.ErrorContains(t, err, "unexpected file")
+
+	_, err = Read(write(map[string]string{
+		manifestFile:                `{"version": 1, "experiments": [1]}`,
+		"experiments/1/" + logsFile: strings.Repeat("{}\n", 1024),
+	}), 1024)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestImportedState(t *testing.T) {
+	require.Equal(t, model.CompletedState, importedState(model.CompletedState))
+	require.Equal(t, model.ErrorState, importedState(model.ErrorState))
+	require.Equal(t, model.CanceledState, importedState(model.ActiveState))
+	require.Equal(t, model.CanceledState, importedState(model.PausedState))
+}
@@ -0,0 +1,192 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// logBatchSize is how many log lines are read or written at a time.
+const logBatchSize = 1000
+
+// LogBackend reads and writes task logs.
+type LogBackend interface {
+	TaskLogs(
+		taskID model.TaskID, limit int, filters []api.Filter, order apiv1.OrderBy, state interface{},
+	) ([]*model.TaskLog, interface{}, error)
+	AddTaskLogs([]*model.TaskLog) error
+}
+
+// ExportExperiment reads everything a bundle holds for an experiment. Logs are only included if
+// logs is non-nil.
+func ExportExperiment(ctx context.Context, id int, logs LogBackend) (*ExperimentData, error) {
+	exp, err := db.ExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting experiment %d: %w", id, err)
+	}
+	p, err := project.GetProjectByID(ctx, exp.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("getting project of experiment %d: %w", id, err)
+	}
+
+	var config, originalConfig string
+	var modelDef []byte
+	if err := db.Bun().NewSelect().Table("experiments").
+		ColumnExpr("config::text").
+		ColumnExpr("COALESCE(original_config, '')").
+		ColumnExpr("model_definition").
+		Where("id = ?", id).
+		Scan(ctx, &config, &originalConfig, &modelDef); err != nil {
+		return nil, fmt.Errorf("getting config of experiment %d: %w", id, err)
+	}
+
+	data := &ExperimentData{
+		Experiment: Experiment{
+			ID:                   exp.ID,
+			State:                exp.State,
+			Config:               json.RawMessage(config),
+			OriginalConfig:       originalConfig,
+			Notes:                exp.Notes,
+			StartTime:            exp.StartTime,
+			EndTime:              exp.EndTime,
+			Archived:             exp.Archived,
+			Unmanaged:            exp.Unmanaged,
+			ExternalExperimentID: exp.ExternalExperimentID,
+			Owner:                exp.Username,
+			Workspace:            p.WorkspaceName,
+			Project:              p.Name,
+		},
+		ModelDef: modelDef,
+	}
+
+	var trials []model.Trial
+	if err := db.Bun().NewSelect().Model(&trials).
+		Where("experiment_id = ?", id).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting trials of experiment %d: %w", id, err)
+	}
+	for _, t := range trials {
+		data.Experiment.Trials = append(data.Experiment.Trials, Trial{
+			ID:              t.ID,
+			RequestID:       t.RequestID,
+			State:           t.State,
+			StartTime:       t.StartTime,
+			EndTime:         t.EndTime,
+			HParams:         t.HParams,
+			Seed:            t.Seed,
+			Restarts:        t.Restarts,
+			ExternalTrialID: t.ExternalTrialID,
+			Metadata:        t.Metadata,
+		})
+
+		metrics, err := exportMetrics(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		data.Metrics = append(data.Metrics, metrics...)
+	}
+
+	if data.Experiment.Checkpoints, err = exportCheckpoints(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if logs != nil {
+		data.Logs = []TaskLog{}
+		for _, t := range trials {
+			trialLogs, err := exportLogs(ctx, t.ID, logs)
+			if err != nil {
+				return nil, err
+			}
+			data.Logs = append(data.Logs, trialLogs...)
+		}
+	}
+	return data, nil
+}
+
+func exportMetrics(ctx context.Context, trialID int) ([]Metrics, error) {
+	var rows []struct {
+		MetricGroup  model.MetricGroup
+		TotalBatches int
+		EndTime      time.Time
+		Metrics      map[string]any
+	}
+	if err := db.Bun().NewSelect().Table("metrics").
+		Column("metric_group", "total_batches", "end_time", "metrics").
+		Where("trial_id = ?", trialID).
+		Where("archived = false").
+		Order("total_batches", "id").
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting metrics of trial %d: %w", trialID, err)
+	}
+
+	var metrics []Metrics
+	for _, row := range rows {
+		isValidation := row.MetricGroup == model.ValidationMetricGroup
+		avg, _ := row.Metrics[model.TrialMetricsJSONPath(isValidation)].(map[string]any)
+		m := Metrics{
+			TrialID:        trialID,
+			Group:          row.MetricGroup,
+			StepsCompleted: row.TotalBatches,
+			ReportTime:     row.EndTime,
+			AvgMetrics:     avg,
+		}
+		if batches, ok := row.Metrics["batch_metrics"].([]any); ok {
+			for _, batch := range batches {
+				if batch, ok := batch.(map[string]any); ok {
+					m.BatchMetrics = append(m.BatchMetrics, batch)
+				}
+			}
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func exportCheckpoints(ctx context.Context, expID int) ([]Checkpoint, error) {
+	var checkpoints []Checkpoint
+	if err := db.Bun().NewRaw(`
+SELECT c.uuid, rc.run_id AS trial_id, c.report_time, c.state, c.resources, c.metadata
+FROM checkpoints_v2 c
+JOIN run_checkpoints rc ON rc.checkpoint_id = c.uuid
+JOIN runs r ON r.id = rc.run_id
+WHERE r.experiment_id = ?
+ORDER BY c.report_time`, expID).Scan(ctx, &checkpoints); err != nil {
+		return nil, fmt.Errorf("getting checkpoints of experiment %d: %w", expID, err)
+	}
+	return checkpoints, nil
+}
+
+func exportLogs(ctx context.Context, trialID int, backend LogBackend) ([]TaskLog, error) {
+	taskIDs, err := db.TrialTaskIDsByTrialID(ctx, trialID)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []TaskLog
+	for _, taskID := range taskIDs {
+		var state interface{}
+		for {
+			batch, next, err := backend.TaskLogs(
+				taskID.TaskID, logBatchSize, nil, apiv1.OrderBy_ORDER_BY_ASC, state)
+			if err != nil {
+				return nil, fmt.Errorf("getting logs of task %s: %w", taskID.TaskID, err)
+			}
+			for _, l := range batch {
+				logs = append(logs, TaskLog{TrialID: trialID, TaskLog: *l})
+			}
+			if len(batch) < logBatchSize {
+				break
+			}
+			state = next
+		}
+	}
+	return logs, nil
+}
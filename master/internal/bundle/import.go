@@ -0,0 +1,417 @@
+package bundle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// MetricsWriter persists trial metrics.
+type MetricsWriter interface {
+	AddTrialMetrics(ctx context.Context, m *trialv1.TrialMetrics, mGroup model.MetricGroup) error
+}
+
+// ImportOptions configure how a bundle is imported.
+type ImportOptions struct {
+	// ProjectID, if set, is the project every experiment is imported into. Otherwise experiments
+	// are imported into the project with the same name as their original project, in the workspace
+	// WorkspaceMap maps their original workspace to, or one with the same name.
+	ProjectID    int
+	WorkspaceMap map[string]string
+	// UserMap maps the usernames of the original owners to users of this cluster. Owners that are
+	// not mapped keep their username if such a user exists.
+	UserMap map[string]string
+	// Importer is the user importing the bundle. They own any experiment whose owner cannot be
+	// mapped, and every experiment unless MapOwners is set.
+	Importer  model.User
+	MapOwners bool
+	// LogRetentionDays is the log retention of imported trials.
+	LogRetentionDays *int16
+}
+
+// ExperimentPlan describes how an experiment in a bundle is, or would be, imported.
+type ExperimentPlan struct {
+	SourceID    int      `json:"source_id"`
+	Name        string   `json:"name"`
+	ID          int      `json:"id,omitempty"`
+	Owner       string   `json:"owner"`
+	ProjectID   int      `json:"project_id"`
+	Trials      int      `json:"trials"`
+	Checkpoints int      `json:"checkpoints"`
+	Metrics     int      `json:"metrics"`
+	Logs        int      `json:"logs"`
+	Warnings    []string `json:"warnings,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+
+	ownerID model.UserID
+}
+
+// Plan resolves the owners and projects of the experiments in a bundle and checks that they can
+// be imported, without changing anything.
+func Plan(ctx context.Context, b *Bundle, opts ImportOptions) ([]*ExperimentPlan, error) {
+	var uuids []uuid.UUID
+	for _, data := range b.Experiments {
+		for _, ckpt := range data.Experiment.Checkpoints {
+			uuids = append(uuids, ckpt.UUID)
+		}
+	}
+	existing := map[uuid.UUID]bool{}
+	if len(uuids) > 0 {
+		var found []uuid.UUID
+		if err := db.Bun().NewSelect().Table("checkpoints_v2").Column("uuid").
+			Where("uuid IN (?)", bun.In(uuids)).
+			Scan(ctx, &found); err != nil {
+			return nil, fmt.Errorf("checking for existing checkpoints: %w", err)
+		}
+		for _, id := range found {
+			existing[id] = true
+		}
+	}
+
+	var plans []*ExperimentPlan
+	for _, data := range b.Experiments {
+		exp := data.Experiment
+		plan := &ExperimentPlan{
+			SourceID:    exp.ID,
+			Name:        exp.Name(),
+			Trials:      len(exp.Trials),
+			Checkpoints: len(exp.Checkpoints),
+			Metrics:     len(data.Metrics),
+			Logs:        len(data.Logs),
+		}
+
+		if err := planOwner(ctx, plan, exp.Owner, opts); err != nil {
+			return nil, err
+		}
+		if err := planProject(ctx, plan, exp, opts); err != nil {
+			return nil, err
+		}
+
+		var conflicts []string
+		for _, ckpt := range exp.Checkpoints {
+			if existing[ckpt.UUID] {
+				conflicts = append(conflicts, ckpt.UUID.String())
+			}
+		}
+		if len(conflicts) > 0 {
+			plan.Errors = append(plan.Errors, fmt.Sprintf(
+				"checkpoints already exist in this cluster: %s", strings.Join(conflicts, ", ")))
+		}
+
+		if !model.TerminalStates[exp.State] {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"experiment was %s when it was exported and will be imported as %s",
+				exp.State, model.CanceledState))
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func planOwner(ctx context.Context, plan *ExperimentPlan, owner string, opts ImportOptions) error {
+	plan.Owner, plan.ownerID = opts.Importer.Username, opts.Importer.ID
+
+	target, mapped := opts.UserMap[owner]
+	if !mapped {
+		target = owner
+	}
+	if target == opts.Importer.Username {
+		return nil
+	}
+	if !opts.MapOwners {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"only admins can import experiments for other users; the owner will be %s instead of %s",
+			opts.Importer.Username, target))
+		return nil
+	}
+
+	var u model.User
+	err := db.Bun().NewSelect().Model(&u).Where("username = ?", target).Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows) && mapped:
+		plan.Errors = append(plan.Errors, fmt.Sprintf("user %s does not exist", target))
+	case errors.Is(err, sql.ErrNoRows):
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"user %s does not exist; the owner will be %s", target, opts.Importer.Username))
+	case err != nil:
+		return fmt.Errorf("getting user %s: %w", target, err)
+	default:
+		plan.Owner, plan.ownerID = u.Username, u.ID
+	}
+	return nil
+}
+
+func planProject(ctx context.Context, plan *ExperimentPlan, exp Experiment, opts ImportOptions) error {
+	if opts.ProjectID != 0 {
+		plan.ProjectID = opts.ProjectID
+		return nil
+	}
+
+	workspace, ok := opts.WorkspaceMap[exp.Workspace]
+	if !ok {
+		workspace = exp.Workspace
+	}
+	id, err := project.ProjectByName(ctx, workspace, exp.Project)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		plan.Errors = append(plan.Errors, fmt.Sprintf(
+			"project %s does not exist in workspace %s; map the workspace or choose a project",
+			exp.Project, workspace))
+	case err != nil:
+		plan.Errors = append(plan.Errors, fmt.Sprintf(
+			"cannot import into project %s in workspace %s: %s", exp.Project, workspace, err))
+	default:
+		plan.ProjectID = id
+	}
+	return nil
+}
+
+// Import creates the experiments of a bundle as planned by Plan. Plans with errors must not be
+// passed. Either every experiment is imported or, if one fails to import, every experiment created
+// so far is deleted again.
+func Import(
+	ctx context.Context, b *Bundle, plans []*ExperimentPlan, opts ImportOptions,
+	metrics MetricsWriter, logs LogBackend,
+) error {
+	for _, plan := range plans {
+		if len(plan.Errors) > 0 {
+			return fmt.Errorf("experiment %d cannot be imported: %s",
+				plan.SourceID, strings.Join(plan.Errors, "; "))
+		}
+	}
+
+	for i, data := range b.Experiments {
+		plan := plans[i]
+		if err := importExperiment(ctx, data, plan, opts, metrics, logs); err != nil {
+			err = fmt.Errorf("importing experiment %d: %w", plan.SourceID, err)
+			if dErr := deleteImported(ctx, plans); dErr != nil {
+				return fmt.Errorf("%w (cleaning up: %s)", err, dErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteImported deletes the experiments that were created for plans.
+func deleteImported(ctx context.Context, plans []*ExperimentPlan) error {
+	var ids []int
+	for _, plan := range plans {
+		if plan.ID != 0 {
+			ids = append(ids, plan.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := db.SingleDB().DeleteExperiments(ctx, ids); err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		plan.ID = 0
+	}
+	return nil
+}
+
+func importExperiment(
+	ctx context.Context, data *ExperimentData, plan *ExperimentPlan, opts ImportOptions,
+	metrics MetricsWriter, logs LogBackend,
+) error {
+	src := data.Experiment
+
+	exp := &model.Experiment{
+		JobID:                model.NewJobID(),
+		State:                importedState(src.State),
+		Notes:                src.Notes,
+		OriginalConfig:       src.OriginalConfig,
+		StartTime:            src.StartTime,
+		EndTime:              src.EndTime,
+		Archived:             src.Archived,
+		OwnerID:              &plan.ownerID,
+		ProjectID:            plan.ProjectID,
+		Unmanaged:            src.Unmanaged,
+		ExternalExperimentID: src.ExternalExperimentID,
+	}
+	if exp.EndTime == nil && model.TerminalStates[exp.State] {
+		exp.EndTime = ptrs.Ptr(time.Now().UTC())
+	}
+	if err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&model.Job{
+			JobID:   exp.JobID,
+			JobType: model.JobTypeExperiment,
+			OwnerID: exp.OwnerID,
+		}).Exec(ctx); err != nil {
+			return fmt.Errorf("inserting job: %w", err)
+		}
+		_, err := tx.NewInsert().Model(exp).
+			ExcludeColumn("id", "username").
+			Value("progress", "?", 1).
+			Value("config", "?", string(src.Config)).
+			Value("model_definition", "?", data.ModelDef).
+			Returning("id").
+			Exec(ctx)
+		return err
+	}); err != nil {
+		return fmt.Errorf("inserting experiment: %w", err)
+	}
+	plan.ID = exp.ID
+
+	trialIDs := map[int]int{}
+	taskIDs := map[int]model.TaskID{}
+	for _, t := range src.Trials {
+		// Trial task IDs are prefixed by their experiment ID.
+		taskID := model.TaskID(fmt.Sprintf("%d.%s", exp.ID, model.NewTaskID()))
+		state := importedState(t.State)
+		endTime := t.EndTime
+		if endTime == nil && model.TerminalStates[state] {
+			endTime = exp.EndTime
+		}
+
+		if err := db.AddTask(ctx, &model.Task{
+			TaskID:     taskID,
+			JobID:      &exp.JobID,
+			TaskType:   model.TaskTypeTrial,
+			StartTime:  t.StartTime,
+			EndTime:    endTime,
+			LogVersion: model.CurrentTaskLogVersion,
+		}); err != nil {
+			return fmt.Errorf("inserting task of trial %d: %w", t.ID, err)
+		}
+
+		trial := &model.Trial{
+			RequestID:        t.RequestID,
+			ExperimentID:     exp.ID,
+			State:            state,
+			StartTime:        t.StartTime,
+			EndTime:          endTime,
+			HParams:          t.HParams,
+			Seed:             t.Seed,
+			Restarts:         t.Restarts,
+			ExternalTrialID:  t.ExternalTrialID,
+			LogRetentionDays: opts.LogRetentionDays,
+			Metadata:         t.Metadata,
+		}
+		if err := db.AddTrial(ctx, trial, taskID); err != nil {
+			return fmt.Errorf("inserting trial %d: %w", t.ID, err)
+		}
+		trialIDs[t.ID] = trial.ID
+		taskIDs[t.ID] = taskID
+	}
+
+	for _, m := range data.Metrics {
+		trialID, ok := trialIDs[m.TrialID]
+		if !ok {
+			continue
+		}
+		report, err := metricsReport(trialID, m)
+		if err != nil {
+			return fmt.Errorf("converting metrics of trial %d: %w", m.TrialID, err)
+		}
+		if err := metrics.AddTrialMetrics(ctx, report, m.Group); err != nil {
+			return fmt.Errorf("inserting metrics of trial %d: %w", m.TrialID, err)
+		}
+	}
+
+	for _, ckpt := range src.Checkpoints {
+		trialID, ok := trialIDs[ckpt.TrialID]
+		if !ok {
+			continue
+		}
+		if err := db.AddCheckpointMetadata(ctx, &model.CheckpointV2{
+			UUID:       ckpt.UUID,
+			TaskID:     taskIDs[ckpt.TrialID],
+			ReportTime: ckpt.ReportTime,
+			State:      ckpt.State,
+			Resources:  ckpt.Resources,
+			Metadata:   ckpt.Metadata,
+		}, trialID); err != nil {
+			return fmt.Errorf("inserting checkpoint %s: %w", ckpt.UUID, err)
+		}
+	}
+
+	if logs != nil && len(data.Logs) > 0 {
+		if err := importLogs(data.Logs, taskIDs, logs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importedState is the state an experiment or trial is imported in. Experiments that were still
+// running when they were exported cannot continue on this cluster.
+func importedState(state model.State) model.State {
+	if model.TerminalStates[state] {
+		return state
+	}
+	return model.CanceledState
+}
+
+func metricsReport(trialID int, m Metrics) (*trialv1.TrialMetrics, error) {
+	avg, err := structpb.NewStruct(m.AvgMetrics)
+	if err != nil {
+		return nil, err
+	}
+	var batches []*structpb.Struct
+	for _, b := range m.BatchMetrics {
+		batch, err := structpb.NewStruct(b)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return &trialv1.TrialMetrics{
+		TrialId:        int32(trialID),
+		StepsCompleted: ptrs.Ptr(int32(m.StepsCompleted)),
+		ReportTime:     timestamppb.New(m.ReportTime),
+		Metrics:        &commonv1.Metrics{AvgMetrics: avg, BatchMetrics: batches},
+	}, nil
+}
+
+func importLogs(logs []TaskLog, taskIDs map[int]model.TaskID, backend LogBackend) error {
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].TrialID < logs[j].TrialID })
+
+	batch := make([]*model.TaskLog, 0, logBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := backend.AddTaskLogs(batch); err != nil {
+			return fmt.Errorf("inserting logs: %w", err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for _, l := range logs {
+		taskID, ok := taskIDs[l.TrialID]
+		if !ok {
+			continue
+		}
+		taskLog := l.TaskLog
+		taskLog.ID = nil
+		taskLog.StringID = nil
+		taskLog.TaskID = string(taskID)
+		batch = append(batch, &taskLog)
+		if len(batch) == logBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,77 @@
+//go:build integration
+// +build integration
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+type failingMetricsWriter struct{}
+
+func (failingMetricsWriter) AddTrialMetrics(
+	context.Context, *trialv1.TrialMetrics, model.MetricGroup,
+) error {
+	return errors.New("metrics are unavailable")
+}
+
+func TestImportDeletesEveryExperimentOnFailure(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(db.RootFromDB))
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+	ctx := context.Background()
+
+	user := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	projectID, _ := db.RequireMockProjectID(t, pgDB, workspaceID, false)
+
+	experiment := func(id int) Experiment {
+		return Experiment{
+			ID:        id,
+			State:     model.CompletedState,
+			Config:    json.RawMessage(`{"name":"imported"}`),
+			StartTime: time.Now().UTC(),
+		}
+	}
+	// The first experiment imports, and the second fails when its metrics are written.
+	failing := experiment(2)
+	failing.Trials = []Trial{{ID: 20, State: model.CompletedState}}
+	b := &Bundle{Experiments: []*ExperimentData{
+		{Experiment: experiment(1)},
+		{
+			Experiment: failing,
+			Metrics: []Metrics{{
+				TrialID:    20,
+				Group:      model.TrainingMetricGroup,
+				ReportTime: time.Now().UTC(),
+				AvgMetrics: map[string]any{"loss": 1.0},
+			}},
+		},
+	}}
+	opts := ImportOptions{ProjectID: projectID, Importer: user}
+	plans, err := Plan(ctx, b, opts)
+	require.NoError(t, err)
+
+	err = Import(ctx, b, plans, opts, failingMetricsWriter{}, nil)
+	require.ErrorContains(t, err, "metrics are unavailable")
+	for _, plan := range plans {
+		require.Zero(t, plan.ID)
+	}
+	count, err := db.Bun().NewSelect().Table("experiments").
+		Where("project_id = ?", projectID).
+		Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
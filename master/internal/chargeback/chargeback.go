@@ -0,0 +1,317 @@
+// Package chargeback prices the resources allocated to tasks with the cost rates of their resource
+// pools and breaks the cost down by workspace, project, user, label and resource pool.
+package chargeback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/devicev1"
+)
+
+// CostRate is a row of the cost_rates table. A rate is in effect from EffectiveFrom until the next
+// rate of the same resource pool and slot type takes effect.
+type CostRate struct {
+	bun.BaseModel `bun:"table:cost_rates"`
+
+	ID              int         `bun:"id,pk,autoincrement"`
+	ResourcePool    string      `bun:"resource_pool"`
+	SlotType        device.Type `bun:"slot_type"`
+	RatePerSlotHour float64     `bun:"rate_per_slot_hour"`
+	EffectiveFrom   time.Time   `bun:"effective_from"`
+}
+
+// Proto converts a rate to its proto representation.
+func (r *CostRate) Proto() *apiv1.CostRate {
+	return &apiv1.CostRate{
+		Id:              int32(r.ID),
+		ResourcePool:    r.ResourcePool,
+		SlotType:        r.SlotType.Proto(),
+		RatePerSlotHour: r.RatePerSlotHour,
+		EffectiveFrom:   timestamppb.New(r.EffectiveFrom),
+	}
+}
+
+// SlotTypeFromProto converts the proto representation of a slot type that rates can be set for.
+func SlotTypeFromProto(t devicev1.Type) (device.Type, error) {
+	switch t {
+	case devicev1.Type_TYPE_CPU:
+		return device.CPU, nil
+	case devicev1.Type_TYPE_CUDA:
+		return device.CUDA, nil
+	case devicev1.Type_TYPE_ROCM:
+		return device.ROCM, nil
+	default:
+		return "", fmt.Errorf("slot type must be one of %s, %s or %s",
+			devicev1.Type_TYPE_CPU, devicev1.Type_TYPE_CUDA, devicev1.Type_TYPE_ROCM)
+	}
+}
+
+// SetRate sets a rate, replacing the rate of the same resource pool and slot type that takes
+// effect at the same time, if any.
+func SetRate(ctx context.Context, r *CostRate) error {
+	if r.RatePerSlotHour < 0 {
+		return fmt.Errorf("%w: rate per slot-hour cannot be negative", db.ErrInvalidInput)
+	}
+	_, err := db.Bun().NewInsert().Model(r).
+		On("CONFLICT (resource_pool, slot_type, effective_from) DO UPDATE").
+		Set("rate_per_slot_hour = EXCLUDED.rate_per_slot_hour").
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting cost rate of resource pool %s: %w", r.ResourcePool, err)
+	}
+	return nil
+}
+
+// Rates returns the rates of a resource pool, or of all pools if it is empty, ordered by pool,
+// slot type and effective time.
+func Rates(ctx context.Context, resourcePool string) ([]*CostRate, error) {
+	rates := []*CostRate{}
+	q := db.Bun().NewSelect().Model(&rates).Order("resource_pool", "slot_type", "effective_from")
+	if resourcePool != "" {
+		q = q.Where("resource_pool = ?", resourcePool)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting cost rates: %w", err)
+	}
+	return rates, nil
+}
+
+// DeleteRate deletes a rate, returning db.ErrNotFound if it does not exist.
+func DeleteRate(ctx context.Context, id int) error {
+	res, err := db.Bun().NewDelete().Model((*CostRate)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting cost rate %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// Dimension is a dimension that reports can break costs down by.
+type Dimension string
+
+const (
+	// Workspace is the workspace of an allocation.
+	Workspace Dimension = "workspace"
+	// Project is the project of the experiment of an allocation.
+	Project Dimension = "project"
+	// User is the owner of the job of an allocation.
+	User Dimension = "user"
+	// Label is a label of the experiment of an allocation.
+	Label Dimension = "label"
+	// ResourcePool is the resource pool of an allocation.
+	ResourcePool Dimension = "resource_pool"
+)
+
+// Dimensions are all dimensions, in the order reports are sorted by.
+var Dimensions = []Dimension{Workspace, Project, User, Label, ResourcePool}
+
+var dimensionExprs = map[Dimension]string{
+	Workspace:    "coalesce(al.workspace_name, '')",
+	Project:      "coalesce(al.project_name, '')",
+	User:         "coalesce(al.username, '')",
+	Label:        "coalesce(l.label, '')",
+	ResourcePool: "al.resource_pool",
+}
+
+var dimensionsFromProto = map[apiv1.ChargebackDimension]Dimension{
+	apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_WORKSPACE:     Workspace,
+	apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_PROJECT:       Project,
+	apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_USER:          User,
+	apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_LABEL:         Label,
+	apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_RESOURCE_POOL: ResourcePool,
+}
+
+// ParseDimension parses the name of a dimension.
+func ParseDimension(s string) (Dimension, error) {
+	d := Dimension(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := dimensionExprs[d]; !ok {
+		return "", fmt.Errorf("unknown dimension %q, expected one of %v", s, Dimensions)
+	}
+	return d, nil
+}
+
+// DimensionFromProto converts the proto representation of a dimension.
+func DimensionFromProto(d apiv1.ChargebackDimension) (Dimension, error) {
+	if dim, ok := dimensionsFromProto[d]; ok {
+		return dim, nil
+	}
+	return "", fmt.Errorf("unknown dimension %s", d)
+}
+
+// Entry is the cost of the resources allocated to one group of a report. The fields of dimensions
+// the report is not broken down by are nil.
+type Entry struct {
+	Workspace         *string `bun:"workspace"`
+	Project           *string `bun:"project"`
+	User              *string `bun:"user"`
+	Label             *string `bun:"label"`
+	ResourcePool      *string `bun:"resource_pool"`
+	SlotHours         float64 `bun:"slot_hours"`
+	UnpricedSlotHours float64 `bun:"unpriced_slot_hours"`
+	Cost              float64 `bun:"cost"`
+}
+
+// Value returns the value of a dimension of the entry.
+func (e *Entry) Value(d Dimension) *string {
+	switch d {
+	case Workspace:
+		return e.Workspace
+	case Project:
+		return e.Project
+	case User:
+		return e.User
+	case Label:
+		return e.Label
+	case ResourcePool:
+		return e.ResourcePool
+	default:
+		return nil
+	}
+}
+
+// Proto converts an entry to its proto representation.
+func (e *Entry) Proto() *apiv1.ChargebackEntry {
+	return &apiv1.ChargebackEntry{
+		Workspace:         e.Workspace,
+		Project:           e.Project,
+		User:              e.User,
+		Label:             e.Label,
+		ResourcePool:      e.ResourcePool,
+		SlotHours:         e.SlotHours,
+		UnpricedSlotHours: e.UnpricedSlotHours,
+		Cost:              e.Cost,
+	}
+}
+
+// Report returns the cost of the resources allocated in [start, end), broken down by the given
+// dimensions. Allocations are clipped to the period, so those that only partially overlap it are
+// charged for the overlap. Each allocation is priced with the rates of its own resource pool and
+// the slot type of that pool in slotTypes, split at the times rates change; slot-hours allocated
+// when no rate was in effect are counted as unpriced.
+func Report(
+	ctx context.Context, start, end time.Time, groupBy []Dimension,
+	slotTypes map[string]device.Type,
+) ([]*Entry, error) {
+	type poolSlotType struct {
+		ResourcePool string      `json:"resource_pool"`
+		SlotType     device.Type `json:"slot_type"`
+	}
+	pools := []poolSlotType{}
+	for pool, slotType := range slotTypes {
+		pools = append(pools, poolSlotType{ResourcePool: pool, SlotType: slotType})
+	}
+	poolsJSON, err := json.Marshal(pools)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := map[Dimension]bool{}
+	for _, d := range groupBy {
+		if _, ok := dimensionExprs[d]; !ok {
+			return nil, fmt.Errorf("unknown dimension %q", d)
+		}
+		grouped[d] = true
+	}
+	var columns, groups []string
+	for _, d := range Dimensions {
+		if grouped[d] {
+			columns = append(columns, fmt.Sprintf(`%s AS "%s"`, dimensionExprs[d], d))
+			groups = append(groups, dimensionExprs[d])
+		} else {
+			columns = append(columns, fmt.Sprintf(`NULL::text AS "%s"`, d))
+		}
+	}
+	labels := ""
+	if grouped[Label] {
+		labels = `LEFT JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(al.labels) = 'array' THEN al.labels ELSE '[]'::jsonb END
+		) AS l(label) ON true`
+	}
+	groupOrder := ""
+	if len(groups) > 0 {
+		groupOrder = fmt.Sprintf("GROUP BY %[1]s ORDER BY %[1]s", strings.Join(groups, ", "))
+	}
+
+	entries := []*Entry{}
+	err = db.Bun().NewRaw(fmt.Sprintf(`
+		WITH pool_slot_types AS (
+			SELECT * FROM jsonb_to_recordset(?::jsonb) AS p(resource_pool text, slot_type text)
+		),
+		rates AS (
+			SELECT resource_pool, slot_type, rate_per_slot_hour, effective_from,
+				lead(effective_from, 1, 'infinity') OVER (
+					PARTITION BY resource_pool, slot_type ORDER BY effective_from
+				) AS effective_to
+			FROM cost_rates
+		),
+		allocs AS (
+			SELECT a.allocation_id, a.resource_pool, a.slots, p.slot_type,
+				GREATEST(a.start_time, ?::timestamptz) AS start_time,
+				LEAST(coalesce(a.end_time, now()), ?::timestamptz) AS end_time,
+				awi.workspace_name, pr.name AS project_name, u.username,
+				e.config->'labels' AS labels
+			FROM allocations a
+			LEFT JOIN pool_slot_types p ON a.resource_pool = p.resource_pool
+			LEFT JOIN allocation_workspace_info awi ON a.allocation_id = awi.allocation_id
+			LEFT JOIN experiments e ON awi.experiment_id = e.id
+			LEFT JOIN projects pr ON e.project_id = pr.id
+			LEFT JOIN tasks t ON a.task_id = t.task_id
+			LEFT JOIN jobs j ON t.job_id = j.job_id
+			LEFT JOIN users u ON j.owner_id = u.id
+			WHERE a.start_time IS NOT NULL
+				AND a.start_time < ?::timestamptz
+				AND coalesce(a.end_time, now()) > ?::timestamptz
+				AND coalesce(a.end_time, now()) > a.start_time
+		),
+		priced AS (
+			SELECT al.allocation_id,
+				sum(al.slots * extract(epoch FROM
+					LEAST(al.end_time, r.effective_to) - GREATEST(al.start_time, r.effective_from)
+				) / 3600) AS slot_hours,
+				sum(al.slots * r.rate_per_slot_hour * extract(epoch FROM
+					LEAST(al.end_time, r.effective_to) - GREATEST(al.start_time, r.effective_from)
+				) / 3600) AS cost
+			FROM allocs al
+			JOIN rates r ON al.resource_pool = r.resource_pool
+				AND al.slot_type = r.slot_type
+				AND r.effective_from < al.end_time
+				AND r.effective_to > al.start_time
+			GROUP BY al.allocation_id
+		),
+		costs AS (
+			SELECT al.*,
+				al.slots * extract(epoch FROM al.end_time - al.start_time) / 3600 AS slot_hours,
+				coalesce(pc.slot_hours, 0) AS priced_slot_hours,
+				coalesce(pc.cost, 0) AS cost
+			FROM allocs al
+			LEFT JOIN priced pc ON al.allocation_id = pc.allocation_id
+		)
+		SELECT %s,
+			coalesce(sum(al.slot_hours), 0)::float8 AS slot_hours,
+			coalesce(sum(al.slot_hours - al.priced_slot_hours), 0)::float8 AS unpriced_slot_hours,
+			coalesce(sum(al.cost), 0)::float8 AS cost
+		FROM costs al
+		%s
+		%s`, strings.Join(columns, ", "), labels, groupOrder),
+		string(poolsJSON), start, end, end, start,
+	).Scan(ctx, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("building chargeback report: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package chargeback
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+var pgDB *db.PgDB
+
+func TestMain(m *testing.M) {
+	var err error
+	pgDB, _, err = db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+	if err := db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up"); err != nil {
+		log.Panicln(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	pricedPool, unpricedPool := uuid.NewString(), uuid.NewString()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	user := db.RequireMockUser(t, pgDB)
+	for _, pool := range []string{pricedPool, unpricedPool} {
+		task := db.RequireMockTask(t, pgDB, &user.ID)
+		require.NoError(t, db.AddAllocation(ctx, &model.Allocation{
+			AllocationID: model.AllocationID(task.TaskID + ".1"),
+			TaskID:       task.TaskID,
+			Slots:        2,
+			ResourcePool: pool,
+			StartTime:    ptrs.Ptr(start),
+			EndTime:      ptrs.Ptr(start.Add(3 * time.Hour)),
+			State:        ptrs.Ptr(model.AllocationStateTerminated),
+		}))
+	}
+
+	for _, r := range []*CostRate{
+		{RatePerSlotHour: 3, EffectiveFrom: start.Add(-24 * time.Hour)},
+		{RatePerSlotHour: 4, EffectiveFrom: start.Add(90 * time.Minute)},
+		// Replaces the first rate, since it takes effect at the same time.
+		{RatePerSlotHour: 1, EffectiveFrom: start.Add(-24 * time.Hour)},
+	} {
+		r.ResourcePool, r.SlotType = pricedPool, device.CUDA
+		require.NoError(t, SetRate(ctx, r))
+	}
+	// Rates of other slot types do not apply to the pool.
+	require.NoError(t, SetRate(ctx, &CostRate{
+		ResourcePool: pricedPool, SlotType: device.CPU, RatePerSlotHour: 100, EffectiveFrom: start,
+	}))
+
+	rates, err := Rates(ctx, pricedPool)
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	entries, err := Report(ctx, start.Add(30*time.Minute), start.Add(10*time.Hour),
+		[]Dimension{ResourcePool, User}, map[string]device.Type{pricedPool: device.CUDA})
+	require.NoError(t, err)
+	actual := map[string]Entry{}
+	for _, e := range entries {
+		if *e.ResourcePool == pricedPool || *e.ResourcePool == unpricedPool {
+			require.Equal(t, user.Username, *e.User)
+			require.Nil(t, e.Workspace)
+			actual[*e.ResourcePool] = *e
+		}
+	}
+	require.Len(t, actual, 2)
+
+	// The allocation is clipped to 2.5 hours of 2 slots, an hour at the first rate and 1.5 hours
+	// at the second one.
+	require.InDelta(t, 5, actual[pricedPool].SlotHours, 1e-9)
+	require.InDelta(t, 0, actual[pricedPool].UnpricedSlotHours, 1e-9)
+	require.InDelta(t, 2*1+3*4, actual[pricedPool].Cost, 1e-9)
+
+	require.InDelta(t, 5, actual[unpricedPool].SlotHours, 1e-9)
+	require.InDelta(t, 5, actual[unpricedPool].UnpricedSlotHours, 1e-9)
+	require.InDelta(t, 0, actual[unpricedPool].Cost, 1e-9)
+
+	for _, r := range rates {
+		require.NoError(t, DeleteRate(ctx, r.ID))
+	}
+	require.ErrorIs(t, DeleteRate(ctx, rates[0].ID), db.ErrNotFound)
+}
@@ -0,0 +1,36 @@
+package chargeback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestParseDimension(t *testing.T) {
+	for s, expected := range map[string]Dimension{
+		"workspace":      Workspace,
+		" Project":       Project,
+		"user":           User,
+		"label":          Label,
+		"RESOURCE_POOL ": ResourcePool,
+	} {
+		actual, err := ParseDimension(s)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	_, err := ParseDimension("team")
+	require.ErrorContains(t, err, `unknown dimension "team"`)
+}
+
+func TestDimensionFromProto(t *testing.T) {
+	for p, expected := range dimensionsFromProto {
+		actual, err := DimensionFromProto(p)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	require.Len(t, dimensionsFromProto, len(Dimensions))
+	_, err := DimensionFromProto(apiv1.ChargebackDimension_CHARGEBACK_DIMENSION_UNSPECIFIED)
+	require.Error(t, err)
+}
@@ -113,7 +113,7 @@ func runCheckpointGCTask(
 	}
 	taskSpec.TaskContainerDefaults = tcd
 
-	userSessionToken, err := user.StartSession(context.TODO(), owner)
+	userSessionToken, err := user.StartSession(context.TODO(), owner, user.WithTaskSession())
 	if err != nil {
 		return errors.Wrapf(err, "unable to create user session for checkpoint gc")
 	}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
@@ -38,6 +39,23 @@ import (
 // terminated state in the master before garbage collecting.
 const terminatedDuration = 24 * time.Hour
 
+// suspendTimeout is how long a notebook has to archive its working directory once it is asked to
+// suspend before the suspension is abandoned.
+const suspendTimeout = 15 * time.Minute
+
+var (
+	// ErrSuspensionDisabled is returned when suspending a notebook that wasn't launched with
+	// notebook_idle_action: suspend.
+	ErrSuspensionDisabled = errors.New(
+		"notebook was not launched with notebook_idle_action: suspend and can't be suspended")
+	// ErrNotRunning is returned when suspending a notebook that already exited.
+	ErrNotRunning = errors.New("notebook is not running")
+	// ErrNotSuspending is returned when a notebook reports a suspension it wasn't asked for.
+	ErrNotSuspending = errors.New("notebook is not suspending")
+	// ErrNotSuspended is returned when resuming a notebook that isn't suspended.
+	ErrNotSuspended = errors.New("notebook is not suspended")
+)
+
 // queueStates are allocation states which the API and UI will show as "Queued".
 var queueStates = []model.AllocationState{
 	model.AllocationStatePending,
@@ -65,6 +83,13 @@ type Command struct {
 	restored         bool
 	contextDirectory []byte // Don't rely on this being set outsides of PreStart non restore case.
 
+	// suspendStorageID is where a notebook archives its working directory to while it is suspending
+	// or suspended.
+	suspendStorageID *uuid.UUID
+	suspended        bool
+	// resuming is set while a suspended notebook starts a new allocation.
+	resuming bool
+
 	logCtx logger.Context
 	syslog *logrus.Entry
 }
@@ -99,32 +124,77 @@ func commandFromSnapshot(
 		taskType:           taskType,
 		jobType:            snapshot.Task.Job.JobType,
 		jobID:              jobID,
+		allocationID:       snapshot.AllocationID,
 		restored:           true,
+		suspendStorageID:   snapshot.SuspendStorageID,
 		logCtx:             logCtx,
 		syslog:             logrus.WithFields(logrus.Fields{"component": "command"}).WithFields(logCtx.Fields()),
 	}
 	return cmd, cmd.Start(context.TODO())
 }
 
+// suspendedCommandFromSnapshot restores a suspended notebook without starting an allocation.
+func suspendedCommandFromSnapshot(
+	db *internaldb.PgDB,
+	rm rm.ResourceManager,
+	snapshot *CommandSnapshot,
+) *Command {
+	taskID := snapshot.TaskID
+	taskType := snapshot.Task.TaskType
+	jobID := snapshot.Task.Job.JobID
+
+	logCtx := logger.Context{
+		"job-id":    jobID,
+		"task-id":   taskID,
+		"task-type": taskType,
+	}
+
+	return &Command{
+		db:                 db,
+		rm:                 rm,
+		registeredTime:     snapshot.RegisteredTime,
+		GenericCommandSpec: snapshot.GenericCommandSpec,
+		taskID:             taskID,
+		taskType:           taskType,
+		jobType:            snapshot.Task.Job.JobType,
+		jobID:              jobID,
+		allocationID:       snapshot.AllocationID,
+		lastState:          task.AllocationState{State: model.AllocationStateTerminated},
+		suspendStorageID:   snapshot.SuspendStorageID,
+		suspended:          true,
+		logCtx:             logCtx,
+		syslog:             logrus.WithFields(logrus.Fields{"component": "command"}).WithFields(logCtx.Fields()),
+	}
+}
+
 // Start starts the command & its respective allocation. Once started, it persists to the db.
 func (c *Command) Start(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	return c.start(ctx)
+}
+
+func (c *Command) start(ctx context.Context) error {
 	priorityChange := func(priority int) error {
 		return c.setNTSCPriority(priority, false)
 	}
 	if err := tasklist.GroupPriorityChangeRegistry.Add(c.jobID, priorityChange); err != nil {
 		return err
 	}
-	c.allocationID = model.AllocationID(fmt.Sprintf("%s.%d", c.taskID, 1))
+	if c.allocationID == "" {
+		c.allocationID = model.AllocationID(fmt.Sprintf("%s.%d", c.taskID, 1))
+	}
 
 	if !c.restored {
-		if err := internaldb.Bun().RunInTx(ctx, nil, c.registerJobAndTask); err != nil {
-			return err
-		}
-		if err := c.persistAndEvictContextDirectoryFromMemory(); err != nil {
-			return err
+		// A resumed notebook keeps the job, task and context directory it was launched with.
+		if !c.resuming {
+			if err := internaldb.Bun().RunInTx(ctx, nil, c.registerJobAndTask); err != nil {
+				return err
+			}
+			if err := c.persistAndEvictContextDirectoryFromMemory(); err != nil {
+				return err
+			}
 		}
 		err := task.InsertNTSCAllocationWorkspaceRecord(
 			ctx,
@@ -147,6 +217,14 @@ func (c *Command) Start(ctx context.Context) error {
 		}
 	}
 
+	// A killed suspended notebook only starts to delete its archive, which needs no slots, nor
+	// idle watching or proxying.
+	_, discarding := c.Base.ExtraEnvVars[model.NotebookDiscardStorageIDEnvVar]
+	slots, proxyPorts := c.Config.Resources.Slots, c.GenericCommandSpec.ProxyPorts()
+	if discarding {
+		slots, proxyPorts = 0, nil
+	}
+
 	var idleWatcherConfig *sproto.IdleTimeoutConfig
 	if c.Config.IdleTimeout != nil && (c.WatchProxyIdleTimeout || c.WatchRunnerIdleTimeout) {
 		idleWatcherConfig = &sproto.IdleTimeoutConfig{
@@ -157,6 +235,26 @@ func (c *Command) Start(ctx context.Context) error {
 			Debug:           c.Config.Debug,
 		}
 	}
+	// Only notebooks and shells report their GPU utilization.
+	if c.Config.GPUIdleTimeout != nil &&
+		(c.TaskType == model.TaskTypeNotebook || c.TaskType == model.TaskTypeShell) {
+		if idleWatcherConfig == nil {
+			idleWatcherConfig = &sproto.IdleTimeoutConfig{
+				ServiceID: string(c.taskID),
+				Debug:     c.Config.Debug,
+			}
+		}
+		idleWatcherConfig.GPU = c.Config.GPUIdleTimeout
+	}
+	if idleWatcherConfig != nil && c.TaskType == model.TaskTypeNotebook &&
+		c.Config.NotebookIdleAction == model.NotebookIdleActionSuspend {
+		idleWatcherConfig.Suspend = func() error {
+			return c.suspend(true)
+		}
+	}
+	if discarding {
+		idleWatcherConfig = nil
+	}
 
 	err := task.DefaultService.StartAllocation(c.logCtx,
 		sproto.AllocateRequest{
@@ -166,10 +264,10 @@ func (c *Command) Start(ctx context.Context) error {
 			JobSubmissionTime:   c.registeredTime,
 			IsUserVisible:       true,
 			Name:                c.Config.Description,
-			SlotsNeeded:         c.Config.Resources.Slots,
+			SlotsNeeded:         slots,
 			ResourcePool:        c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{SingleAgent: true},
-			ProxyPorts:          sproto.NewProxyPortConfig(c.GenericCommandSpec.ProxyPorts(), c.taskID),
+			ProxyPorts:          sproto.NewProxyPortConfig(proxyPorts, c.taskID),
 			IdleTimeout:         idleWatcherConfig,
 			Restore:             c.restored,
 			ProxyTLS:            c.TaskType == model.TaskTypeNotebook,
@@ -227,6 +325,8 @@ func (c *Command) persist() error {
 		RegisteredTime:     c.registeredTime,
 		AllocationID:       c.allocationID,
 		GenericCommandSpec: c.GenericCommandSpec,
+		SuspendStorageID:   c.suspendStorageID,
+		Suspended:          c.suspended,
 	}
 	_, err := internaldb.Bun().NewInsert().Model(snapshot).
 		On("CONFLICT (task_id) DO UPDATE").
@@ -242,6 +342,20 @@ func (c *Command) OnExit(ae *task.AllocationExited) {
 
 	c.exitStatus = ae
 
+	if c.suspended {
+		// A suspended notebook keeps its task and notebook session until it is resumed or killed.
+		if err := user.DeleteSessionByToken(context.TODO(), c.GenericCommandSpec.Base.UserSessionToken); err != nil {
+			c.syslog.WithError(err).Errorf(
+				"failure to delete user session for task: %v", c.taskID)
+		}
+		if err := tasklist.GroupPriorityChangeRegistry.Delete(c.jobID); err != nil {
+			c.syslog.WithError(err).Error("deleting command from GroupPriorityChangeRegistry")
+		}
+		go jobservice.DefaultService.UnregisterJob(c.jobID)
+		return
+	}
+	c.suspendStorageID = nil
+
 	if err := internaldb.CompleteTask(context.TODO(), c.taskID, time.Now().UTC()); err != nil {
 		c.syslog.WithError(err).Error("marking task complete")
 	}
@@ -262,6 +376,182 @@ func (c *Command) OnExit(ae *task.AllocationExited) {
 	}()
 }
 
+// suspend asks a notebook to archive its working directory so it can release its resources. If the
+// notebook doesn't report the archive within suspendTimeout, the suspension is abandoned and, if
+// killOnTimeout is set, the notebook is killed.
+func (c *Command) suspend(killOnTimeout bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.SuspendStorage == nil:
+		return ErrSuspensionDisabled
+	case c.exitStatus != nil:
+		return ErrNotRunning
+	case c.suspendStorageID != nil:
+		return nil
+	}
+
+	storageID := uuid.New()
+	c.suspendStorageID = &storageID
+	if err := c.persist(); err != nil {
+		c.suspendStorageID = nil
+		return fmt.Errorf("persisting suspension of %s: %w", c.taskID, err)
+	}
+	c.syslog.Infof("suspending notebook to storage %s", storageID)
+
+	time.AfterFunc(suspendTimeout, func() {
+		c.abandonSuspension(storageID, killOnTimeout)
+	})
+	return nil
+}
+
+func (c *Command) abandonSuspension(storageID uuid.UUID, kill bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended || c.suspendStorageID == nil || *c.suspendStorageID != storageID {
+		return
+	}
+	c.syslog.Warnf("notebook did not archive its working directory within %s", suspendTimeout)
+	c.suspendStorageID = nil
+	if err := c.persist(); err != nil {
+		c.syslog.WithError(err).Warn("command persist failure")
+	}
+	if !kill {
+		return
+	}
+	err := task.DefaultService.Signal(
+		c.allocationID, task.TerminateAllocation, "failed to suspend idle notebook",
+	)
+	if err != nil {
+		c.syslog.WithError(err).Warn("failed to kill notebook after failing to suspend it")
+	}
+}
+
+// pendingSuspension returns the storage ID a suspending notebook should archive its working
+// directory to, or nil if it isn't suspending.
+func (c *Command) pendingSuspension() *uuid.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended || c.exitStatus != nil {
+		return nil
+	}
+	return c.suspendStorageID
+}
+
+// completeSuspension releases the resources of a notebook once it archived its working directory.
+func (c *Command) completeSuspension(storageID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended || c.suspendStorageID == nil || *c.suspendStorageID != storageID {
+		return fmt.Errorf("%w to storage %s", ErrNotSuspending, storageID)
+	}
+	c.suspended = true
+	if err := c.persist(); err != nil {
+		c.suspended = false
+		return fmt.Errorf("persisting suspension of %s: %w", c.taskID, err)
+	}
+
+	err := task.DefaultService.Signal(c.allocationID, task.TerminateAllocation, "notebook suspended")
+	if err != nil {
+		c.syslog.WithError(err).Warn("failed to terminate suspended notebook")
+	}
+	return nil
+}
+
+// resume starts a new allocation for a suspended notebook, which restores its working directory,
+// deletes the archive, and keeps its ID, proxy address and notebook session.
+func (c *Command) resume(ctx context.Context, userSessionToken string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.suspended {
+		return ErrNotSuspended
+	}
+	return c.restart(ctx, userSessionToken, model.NotebookRestoreStorageIDEnvVar)
+}
+
+// restart starts a new allocation for a suspended notebook, passing it the storage ID of its
+// archive in the given environment variable.
+func (c *Command) restart(ctx context.Context, userSessionToken string, storageIDEnvVar string) error {
+	specifier, err := c.allocationID.GetAllocationSpecifier()
+	if err != nil {
+		return err
+	}
+
+	prevAllocationID, prevToken := c.allocationID, c.Base.UserSessionToken
+	c.allocationID = model.AllocationID(fmt.Sprintf("%s.%d", c.taskID, specifier+1))
+	c.Base.UserSessionToken = userSessionToken
+	delete(c.Base.ExtraEnvVars, model.NotebookRestoreStorageIDEnvVar)
+	delete(c.Base.ExtraEnvVars, model.NotebookDiscardStorageIDEnvVar)
+	c.Base.ExtraEnvVars[storageIDEnvVar] = c.suspendStorageID.String()
+	c.suspended = false
+	c.restored = false
+	c.resuming = true
+	defer func() { c.resuming = false }()
+
+	if err := c.start(ctx); err != nil {
+		c.allocationID, c.Base.UserSessionToken = prevAllocationID, prevToken
+		delete(c.Base.ExtraEnvVars, storageIDEnvVar)
+		c.suspended = true
+		if err := c.persist(); err != nil {
+			c.syslog.WithError(err).Warn("command persist failure")
+		}
+		return err
+	}
+	c.suspendStorageID = nil
+	c.exitStatus = nil
+	c.lastState = task.AllocationState{}
+	if err := c.persist(); err != nil {
+		c.syslog.WithError(err).Warn("command persist failure")
+	}
+	return nil
+}
+
+// discardSuspension deletes the archive of a suspended notebook that is killed instead of resumed,
+// by starting it one last time to delete the archive and exit, after which its task completes. It
+// returns whether the notebook was suspended.
+func (c *Command) discardSuspension() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.suspended {
+		return false
+	}
+	storageID := *c.suspendStorageID
+	token, err := user.StartSession(context.TODO(), c.Base.Owner, user.WithTaskSession())
+	if err == nil {
+		err = c.restart(context.TODO(), token, model.NotebookDiscardStorageIDEnvVar)
+	}
+	if err == nil {
+		c.syslog.Infof("discarding archive %s of suspended notebook", storageID)
+		return true
+	}
+	c.syslog.WithError(err).Errorf(
+		"failed to discard archive %s of suspended notebook, leaving it in place", storageID)
+
+	c.suspended = false
+	if err := c.persist(); err != nil {
+		c.syslog.WithError(err).Warn("command persist failure")
+	}
+	if err := internaldb.CompleteTask(context.TODO(), c.taskID, time.Now().UTC()); err != nil {
+		c.syslog.WithError(err).Error("marking task complete")
+	}
+	if err := internaldb.DeleteNotebookSessionByTask(context.TODO(), c.taskID); err != nil {
+		c.syslog.WithError(err).Errorf(
+			"failure to delete notebook session for task: %v", c.taskID)
+	}
+
+	go func() {
+		time.Sleep(terminatedDuration)
+		c.garbageCollect()
+	}()
+	return true
+}
+
 // gc garbage collects the exited command.
 func (c *Command) garbageCollect() {
 	if err := tasklist.GroupPriorityChangeRegistry.Delete(c.jobID); err != nil {
@@ -368,6 +658,18 @@ func (c *Command) ToV1Notebook() *notebookv1.Notebook {
 		ExitStatus:     c.exitStatus.String(),
 		JobId:          c.jobID.String(),
 		WorkspaceId:    int32(c.GenericCommandSpec.Metadata.WorkspaceID),
+		SuspendState:   c.suspendState(),
+	}
+}
+
+func (c *Command) suspendState() notebookv1.SuspendState {
+	switch {
+	case c.suspended:
+		return notebookv1.SuspendState_SUSPEND_STATE_SUSPENDED
+	case c.suspendStorageID != nil:
+		return notebookv1.SuspendState_SUSPEND_STATE_SUSPENDING
+	default:
+		return notebookv1.SuspendState_SUSPEND_STATE_UNSPECIFIED
 	}
 }
 
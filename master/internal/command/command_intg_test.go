@@ -4,8 +4,10 @@
 package command
 
 import (
+	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/determined-ai/determined/master/internal/user"
 
@@ -17,7 +19,10 @@ import (
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/mocks"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/master/pkg/syncx/queue"
 	"github.com/determined-ai/determined/master/pkg/tasks"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -111,6 +116,99 @@ func TestNotebookManagerLifecycle(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNotebookSuspendResume(t *testing.T) {
+	pgDB := setupTest(t)
+	user.InitService(pgDB, &model.ExternalSessions{})
+	ctx := context.Background()
+
+	// Only notebooks launched with suspension enabled can be suspended.
+	_, err := DefaultCmdService.SuspendNotebook(launchNotebook(t, pgDB).Id)
+	require.ErrorIs(t, err, ErrSuspensionDisabled)
+
+	nb := launchSuspendableNotebook(t, pgDB)
+
+	// Resuming a running notebook fails.
+	_, err = DefaultCmdService.ResumeNotebook(ctx, nb.Id, "token")
+	require.ErrorIs(t, err, ErrNotSuspended)
+
+	// Suspending asks the notebook to archive its working directory.
+	cmd, err := DefaultCmdService.SuspendNotebook(nb.Id)
+	require.NoError(t, err)
+	require.Equal(t, notebookv1.SuspendState_SUSPEND_STATE_SUSPENDING, cmd.ToV1Notebook().SuspendState)
+	storageID, err := DefaultCmdService.PendingNotebookSuspension(nb.Id)
+	require.NoError(t, err)
+	require.NotNil(t, storageID)
+
+	// Only the requested archive completes the suspension.
+	err = DefaultCmdService.CompleteNotebookSuspension(nb.Id, uuid.New())
+	require.ErrorIs(t, err, ErrNotSuspending)
+	require.NoError(t, DefaultCmdService.CompleteNotebookSuspension(nb.Id, *storageID))
+	require.Equal(t, notebookv1.SuspendState_SUSPEND_STATE_SUSPENDED, cmd.ToV1Notebook().SuspendState)
+	require.Eventually(t, func() bool {
+		cmd.mu.Lock()
+		defer cmd.mu.Unlock()
+		return cmd.exitStatus != nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The suspended notebook keeps its task.
+	completed, err := db.TaskCompleted(ctx, model.TaskID(nb.Id))
+	require.NoError(t, err)
+	require.False(t, completed)
+
+	// A restarted master restores the suspended notebook.
+	cs, err := NewService(pgDB, DefaultCmdService.rm)
+	require.NoError(t, err)
+	require.NoError(t, cs.RestoreAllCommands(ctx))
+	restored, ok := cs.commands[model.TaskID(nb.Id)]
+	require.True(t, ok)
+	require.True(t, restored.suspended)
+	require.Equal(t, *storageID, *restored.suspendStorageID)
+
+	// Resuming starts a new allocation of the same task that restores the archive.
+	cmd, err = DefaultCmdService.ResumeNotebook(ctx, nb.Id, "token")
+	require.NoError(t, err)
+	resumed := cmd.ToV1Notebook()
+	require.Equal(t, notebookv1.SuspendState_SUSPEND_STATE_UNSPECIFIED, resumed.SuspendState)
+	require.Equal(t, nb.ServiceAddress, resumed.ServiceAddress)
+	require.Equal(t, model.AllocationID(nb.Id+".2"), cmd.allocationID)
+	require.Equal(t, storageID.String(), cmd.Base.ExtraEnvVars[model.NotebookRestoreStorageIDEnvVar])
+}
+
+func TestKillSuspendedNotebookDiscardsArchive(t *testing.T) {
+	pgDB := setupTest(t)
+	user.InitService(pgDB, &model.ExternalSessions{})
+	ctx := context.Background()
+	nb := launchSuspendableNotebook(t, pgDB)
+
+	cmd, err := DefaultCmdService.SuspendNotebook(nb.Id)
+	require.NoError(t, err)
+	storageID, err := DefaultCmdService.PendingNotebookSuspension(nb.Id)
+	require.NoError(t, err)
+	require.NoError(t, DefaultCmdService.CompleteNotebookSuspension(nb.Id, *storageID))
+	require.Eventually(t, func() bool {
+		cmd.mu.Lock()
+		defer cmd.mu.Unlock()
+		return cmd.exitStatus != nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Killing the suspended notebook starts it once more, without slots, to delete the archive.
+	cmd, err = DefaultCmdService.KillNTSC(nb.Id, model.TaskTypeNotebook)
+	require.NoError(t, err)
+	require.False(t, cmd.suspended)
+	require.Equal(t, model.AllocationID(nb.Id+".2"), cmd.allocationID)
+	require.Equal(t, storageID.String(), cmd.Base.ExtraEnvVars[model.NotebookDiscardStorageIDEnvVar])
+	require.NotContains(t, cmd.Base.ExtraEnvVars, model.NotebookRestoreStorageIDEnvVar)
+
+	// Once it exits, the task completes.
+	require.NoError(t, task.DefaultService.Signal(
+		cmd.allocationID, task.KillAllocation, "test",
+	))
+	require.Eventually(t, func() bool {
+		completed, err := db.TaskCompleted(ctx, model.TaskID(nb.Id))
+		return err == nil && completed
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
 func TestShellManagerLifecycle(t *testing.T) {
 	db := setupTest(t)
 
@@ -239,6 +337,17 @@ func launchNotebook(t *testing.T, db *db.PgDB) *notebookv1.Notebook {
 	return v1nb
 }
 
+func launchSuspendableNotebook(t *testing.T, db *db.PgDB) *notebookv1.Notebook {
+	mockReq := CreateMockGenericReq(t, db)
+	mockReq.Spec.Config.NotebookIdleAction = model.NotebookIdleActionSuspend
+	mockReq.Spec.SuspendStorage = &expconf.CheckpointStorageConfig{
+		RawSharedFSConfig: &expconf.SharedFSConfig{RawHostPath: ptrs.Ptr("/tmp/" + uuid.NewString())},
+	}
+	cmd, err := DefaultCmdService.LaunchNotebookCommand(mockReq, mockReq.Spec.Base.Owner)
+	require.NoError(t, err)
+	return cmd.ToV1Notebook()
+}
+
 func launchShell(t *testing.T, db *db.PgDB) *shellv1.Shell {
 	cmd, err := DefaultCmdService.LaunchGenericCommand(
 		model.TaskTypeShell,
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/internal/api"
@@ -83,6 +84,23 @@ func (cs *CommandService) RestoreAllCommands(
 		cs.commands[cmd.taskID] = cmd
 		cs.syslog.Debugf("restored & started generic command %s", cmd.taskID)
 	}
+
+	suspended := []CommandSnapshot{}
+	err = db.Bun().NewSelect().Model(&suspended).
+		Relation("Task").
+		Relation("Task.Job").
+		Where("command_snapshot.suspended").
+		Where("task.end_time IS NULL").
+		Scan(ctx)
+	if err != nil {
+		cs.syslog.Errorf("failed to remake suspended notebooks: %s", err)
+		return nil
+	}
+	for i := range suspended {
+		cmd := suspendedCommandFromSnapshot(cs.db, cs.rm, &suspended[i])
+		cs.commands[cmd.taskID] = cmd
+		cs.syslog.Debugf("restored suspended notebook %s", cmd.taskID)
+	}
 	return nil
 }
 
@@ -277,6 +295,10 @@ func (cs *CommandService) KillNTSC(id string, taskType model.TaskType) (*Command
 	if err != nil {
 		return nil, err
 	}
+	if c.discardSuspension() {
+		// The notebook is either gone already or only starting to delete its archive.
+		return c, nil
+	}
 
 	completed, err := db.TaskCompleted(context.TODO(), tID)
 	if err != nil {
@@ -293,6 +315,80 @@ func (cs *CommandService) KillNTSC(id string, taskType model.TaskType) (*Command
 	return c, nil
 }
 
+// NTSCState returns the state of the allocation of a command, and false if the command exited and
+// is no longer tracked.
+func (cs *CommandService) NTSCState(id model.TaskID) (task.AllocationState, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, ok := cs.commands[id]
+	if !ok {
+		return task.AllocationState{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshAllocationState(), true
+}
+
+// SuspendNotebook asks a notebook to archive its working directory so it can release its resources.
+func (cs *CommandService) SuspendNotebook(id string) (*Command, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, err := cs.getNTSC(model.TaskID(id), model.TaskTypeNotebook)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.suspend(false); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// PendingNotebookSuspension returns the storage ID a suspending notebook should archive its
+// working directory to, or nil if it isn't suspending.
+func (cs *CommandService) PendingNotebookSuspension(id string) (*uuid.UUID, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, err := cs.getNTSC(model.TaskID(id), model.TaskTypeNotebook)
+	if err != nil {
+		return nil, err
+	}
+	return c.pendingSuspension(), nil
+}
+
+// CompleteNotebookSuspension releases the resources of a notebook that archived its working
+// directory to the given storage ID.
+func (cs *CommandService) CompleteNotebookSuspension(id string, storageID uuid.UUID) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, err := cs.getNTSC(model.TaskID(id), model.TaskTypeNotebook)
+	if err != nil {
+		return err
+	}
+	return c.completeSuspension(storageID)
+}
+
+// ResumeNotebook starts a suspended notebook again, restoring its working directory. The task
+// of the new allocation uses the given user session token.
+func (cs *CommandService) ResumeNotebook(
+	ctx context.Context, id string, userSessionToken string,
+) (*Command, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, err := cs.getNTSC(model.TaskID(id), model.TaskTypeNotebook)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resume(ctx, userSessionToken); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // GetCommands returns all commands in the command service registry matching the workspace ID.
 func (cs *CommandService) GetCommands(req *apiv1.GetCommandsRequest) (*apiv1.GetCommandsResponse, error) {
 	cs.mu.Lock()
@@ -3,6 +3,7 @@ package command
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -26,6 +27,12 @@ type CommandSnapshot struct {
 	// GenericTaskSpec
 	GenericTaskSpec *tasks.GenericTaskSpec `bun:"generic_task_spec"`
 
+	// SuspendStorageID is where a notebook archives its working directory to while it is
+	// suspending or suspended.
+	SuspendStorageID *uuid.UUID `bun:"suspend_storage_id"`
+	// Suspended is set once the working directory is archived until the notebook is resumed.
+	Suspended bool `bun:"suspended"`
+
 	// Relations
 	Task       model.Task       `bun:"rel:belongs-to,join:task_id=task_id"`
 	Allocation model.Allocation `bun:"rel:belongs-to,join:allocation_id=allocation_id"`
@@ -9,7 +9,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
-	"sync"
+	"sync/atomic"
 
 	"github.com/jinzhu/copier"
 	log "github.com/sirupsen/logrus"
@@ -30,10 +30,9 @@ var (
 	DefaultSegmentWebUIKey  = ""
 )
 
-var (
-	once         sync.Once
-	masterConfig *Config
-)
+// masterConfig is the master config singleton. It is replaced as a whole when the config is
+// reloaded, so a config read from it is never modified.
+var masterConfig atomic.Pointer[Config]
 
 // KubernetesDefaultPriority is the default K8 resource manager priority.
 const (
@@ -81,6 +80,40 @@ type WebhooksConfig struct {
 	SigningKey string `json:"signing_key"`
 }
 
+// DefaultMaxBundleImportSize is the default limit on the uncompressed size of imported bundles.
+const DefaultMaxBundleImportSize = 1 << 30
+
+// BundlesConfig hosts configuration fields for experiment export and import bundles.
+type BundlesConfig struct {
+	// MaxImportSize is the largest bundle, in uncompressed bytes, that the master imports.
+	MaxImportSize int64 `json:"max_import_size"`
+}
+
+// Validate implements the check.Validatable interface.
+func (b *BundlesConfig) Validate() []error {
+	if b.MaxImportSize <= 0 {
+		return []error{errors.New("bundles.max_import_size must be positive")}
+	}
+	return nil
+}
+
+// DefaultProxyAccessLogRetentionDays is the default number of days proxy access logs are kept.
+const DefaultProxyAccessLogRetentionDays = 30
+
+// ProxyConfig hosts configuration fields for the proxy to the services of tasks.
+type ProxyConfig struct {
+	// AccessLogRetentionDays is the number of days access logs are kept, or -1 to keep them forever.
+	AccessLogRetentionDays int `json:"access_log_retention_days"`
+}
+
+// Validate implements the check.Validatable interface.
+func (p *ProxyConfig) Validate() []error {
+	if p.AccessLogRetentionDays < -1 {
+		return []error{errors.New("proxy.access_log_retention_days must be at least -1")}
+	}
+	return nil
+}
+
 // IntegrationsConfig stores configs related to integrations like pachyderm.
 type IntegrationsConfig struct {
 	Pachyderm PachydermConfig `json:"pachyderm"`
@@ -130,6 +163,8 @@ func DefaultConfig() *Config {
 		Cache: CacheConfig{
 			CacheDir: "/var/cache/determined",
 		},
+		Bundles:         BundlesConfig{MaxImportSize: DefaultMaxBundleImportSize},
+		Proxy:           ProxyConfig{AccessLogRetentionDays: DefaultProxyAccessLogRetentionDays},
 		FeatureSwitches: []string{},
 		ResourceConfig:  *DefaultResourceConfig(),
 		Observability: ObservabilityConfig{
@@ -149,6 +184,7 @@ func DefaultConfig() *Config {
 // environment variables and command line arguments.
 type Config struct {
 	ConfigFile            string                            `json:"config_file"`
+	WatchConfigFile       bool                              `json:"watch_config_file"`
 	Log                   logger.Config                     `json:"log"`
 	DB                    DBConfig                          `json:"db"`
 	TensorBoardTimeout    int                               `json:"tensorboard_timeout"`
@@ -168,6 +204,8 @@ type Config struct {
 	Observability         ObservabilityConfig               `json:"observability"`
 	Cache                 CacheConfig                       `json:"cache"`
 	Webhooks              WebhooksConfig                    `json:"webhooks"`
+	Bundles               BundlesConfig                     `json:"bundles"`
+	Proxy                 ProxyConfig                       `json:"proxy"`
 	FeatureSwitches       []string                          `json:"feature_switches"`
 	ReservedPorts         []int                             `json:"reserved_ports"`
 	ResourceConfig
@@ -182,24 +220,24 @@ type Config struct {
 	Integrations IntegrationsConfig `json:"integrations"`
 }
 
-// GetMasterConfig returns reference to the master config singleton.
+// GetMasterConfig returns reference to the master config singleton. Reloading the master config
+// replaces the singleton, so callers should not hold on to it if they want reloaded values.
 func GetMasterConfig() *Config {
-	once.Do(func() {
-		masterConfig = DefaultConfig()
-	})
-	return masterConfig
+	if c := masterConfig.Load(); c != nil {
+		return c
+	}
+	masterConfig.CompareAndSwap(nil, DefaultConfig())
+	return masterConfig.Load()
 }
 
 // SetMasterConfig sets the master config singleton.
 func SetMasterConfig(aConfig *Config) {
-	if masterConfig != nil {
-		panic("master config is already set")
-	}
 	if aConfig == nil {
 		panic("passed in config is nil")
 	}
-	config := GetMasterConfig()
-	*config = *aConfig
+	if !masterConfig.CompareAndSwap(nil, aConfig) {
+		panic("master config is already set")
+	}
 }
 
 // Printable returns a printable string.
@@ -224,6 +262,9 @@ func (c Config) Printable() ([]byte, error) {
 	if configCopy.Telemetry.SegmentWebUIKey != "" {
 		configCopy.Telemetry.SegmentWebUIKey = hiddenValue
 	}
+	if configCopy.Webhooks.SigningKey != "" {
+		configCopy.Webhooks.SigningKey = hiddenValue
+	}
 	if configCopy.TaskContainerDefaults.RegistryAuth != nil {
 		if configCopy.TaskContainerDefaults.RegistryAuth.Password != "" {
 			// RegistryAuth is a pointer, so if we need to hide the password we need to be very
@@ -661,6 +702,32 @@ func DefaultPriorityForPool(rpName string) int {
 	return DefaultSchedulingPriority
 }
 
+// TaskContainerDefaultsForPool returns the task container defaults configured for a resource pool,
+// or nil if the pool does not configure any.
+func TaskContainerDefaultsForPool(rpName string) *model.TaskContainerDefaultsConfig {
+	for _, rm := range GetMasterConfig().ResourceManagers() {
+		for _, rpConfig := range rm.ResourcePools {
+			if rpConfig.PoolName == rpName {
+				return rpConfig.TaskContainerDefaults
+			}
+		}
+	}
+	return nil
+}
+
+// MergePoolTaskContainerDefaults merges the task container defaults configured for a resource pool
+// into tcd. Pool settings can be reloaded, so they are read from the master config singleton rather
+// than from the copy a resource manager was started with.
+func MergePoolTaskContainerDefaults(
+	rpName string, tcd model.TaskContainerDefaultsConfig,
+) (model.TaskContainerDefaultsConfig, error) {
+	overrides := TaskContainerDefaultsForPool(rpName)
+	if overrides == nil {
+		return tcd, nil
+	}
+	return tcd.Merge(*overrides)
+}
+
 // ReadWeight resolves the weight value for a job.
 func ReadWeight(rpName string, jobConf interface{}) float64 {
 	var weight float64
@@ -2,6 +2,7 @@ package config
 
 import (
 	"net/url"
+	"regexp"
 )
 
 // OIDCConfig holds the parameters for the OIDC provider.
@@ -23,6 +24,10 @@ type OIDCConfig struct {
 	AgentGroupNameAttributeName string `json:"agent_group_name_attribute_name"`
 	AlwaysRedirect              bool   `json:"always_redirect"`
 	ExcludeGroupsScope          bool   `json:"exclude_groups_scope"`
+	// GroupsSyncPrefix and GroupsSyncRegex limit the groups whose membership is synced from the
+	// groups attribute to the ones with matching names.
+	GroupsSyncPrefix string `json:"groups_sync_prefix"`
+	GroupsSyncRegex  string `json:"groups_sync_regex"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -32,5 +37,6 @@ func (c OIDCConfig) Validate() []error {
 	}
 
 	_, err := url.Parse(c.IDPRecipientURL)
-	return []error{err}
+	_, regexErr := regexp.Compile(c.GroupsSyncRegex)
+	return []error{err, regexErr}
 }
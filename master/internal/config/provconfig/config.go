@@ -28,19 +28,21 @@ type Config struct {
 	AgentDockerRuntime     string `json:"agent_docker_runtime"`
 	AgentDockerImage       string `json:"agent_docker_image"`
 	// deprecated, no longer in use.
-	AgentFluentImage        string            `json:"agent_fluent_image"`
-	AgentReconnectAttempts  int               `json:"agent_reconnect_attempts"`
-	AgentReconnectBackoff   int               `json:"agent_reconnect_backoff"`
-	AgentConfigFileContents json.RawMessage   `json:"agent_config_file_contents"`
-	AWS                     *AWSClusterConfig `union:"type,aws" json:"-"`
-	GCP                     *GCPClusterConfig `union:"type,gcp" json:"-"`
-	HPC                     *HpcClusterConfig `union:"type,hpc" json:"-"`
-	MaxIdleAgentPeriod      model.Duration    `json:"max_idle_agent_period"`
-	MaxAgentStartingPeriod  model.Duration    `json:"max_agent_starting_period"`
-	MinInstances            int               `json:"min_instances"`
-	MaxInstances            int               `json:"max_instances"`
-	LaunchErrorTimeout      *model.Duration   `json:"launch_error_timeout"`
-	LaunchErrorRetries      int               `json:"launch_error_retries"`
+	AgentFluentImage        string             `json:"agent_fluent_image"`
+	AgentReconnectAttempts  int                `json:"agent_reconnect_attempts"`
+	AgentReconnectBackoff   int                `json:"agent_reconnect_backoff"`
+	AgentConfigFileContents json.RawMessage    `json:"agent_config_file_contents"`
+	AWS                     *AWSClusterConfig  `union:"type,aws" json:"-"`
+	GCP                     *GCPClusterConfig  `union:"type,gcp" json:"-"`
+	Exec                    *ExecClusterConfig `union:"type,exec" json:"-"`
+	HPC                     *HpcClusterConfig  `union:"type,hpc" json:"-"`
+	MaxIdleAgentPeriod      model.Duration     `json:"max_idle_agent_period"`
+	MaxAgentStartingPeriod  model.Duration     `json:"max_agent_starting_period"`
+	MinInstances            int                `json:"min_instances"`
+	MaxInstances            int                `json:"max_instances"`
+	LaunchErrorTimeout      *model.Duration    `json:"launch_error_timeout"`
+	LaunchErrorRetries      int                `json:"launch_error_retries"`
+	Schedules               []ScalingSchedule  `json:"schedules"`
 }
 
 // HpcClusterConfig describes the configuration for a HPC cluster managed by Determined.
@@ -95,9 +97,9 @@ func (c Config) Validate() []error {
 	errs = append(errs, []error{
 		masterURLErr,
 		check.NotEmpty(c.AgentDockerImage, "must configure an agent docker image"),
-		check.False(c.AWS != nil && c.GCP != nil, "must configure only one cluster"),
-		check.False(c.AWS == nil && c.GCP == nil && c.HPC == nil,
-			"must configure aws or gcp or hpc cluster"),
+		check.LessThanOrEqualTo(c.numClusters(), 1, "must configure only one cluster"),
+		check.False(c.numClusters() == 0 && c.HPC == nil,
+			"must configure aws, gcp, exec or hpc cluster"),
 		check.GreaterThan(
 			int64(c.MaxIdleAgentPeriod), int64(0), "max idle agent period must be greater than 0"),
 		check.GreaterThan(
@@ -108,9 +110,25 @@ func (c Config) Validate() []error {
 		check.GreaterThanOrEqualTo(int64(c.MaxInstances), int64(c.MinInstances),
 			"max instance must be greater than or equal to min instance"),
 	}...)
+	names := make(map[string]bool, len(c.Schedules))
+	for _, s := range c.Schedules {
+		errs = append(errs, check.False(names[s.Name],
+			fmt.Sprintf("duplicate scaling schedule name %q", s.Name)))
+		names[s.Name] = true
+	}
 	return errs
 }
 
+func (c Config) numClusters() int {
+	n := 0
+	for _, configured := range []bool{c.AWS != nil, c.GCP != nil, c.Exec != nil} {
+		if configured {
+			n++
+		}
+	}
+	return n
+}
+
 func (c Config) mustParseMasterURL() url.URL {
 	masterURL, err := url.Parse(c.MasterURL)
 	if err != nil {
@@ -163,6 +181,14 @@ func (c Config) Printable() Config {
 	if len(c.ContainerStartupScript) > 0 {
 		c.ContainerStartupScript = hiddenValue
 	}
+	if c.Exec != nil && len(c.Exec.Env) > 0 {
+		execConfig := *c.Exec
+		execConfig.Env = make(map[string]string, len(c.Exec.Env))
+		for k := range c.Exec.Env {
+			execConfig.Env[k] = hiddenValue
+		}
+		c.Exec = &execConfig
+	}
 
 	return c
 }
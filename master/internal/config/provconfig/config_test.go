@@ -22,7 +22,7 @@ func TestProvisionerConfigMissingFields(t *testing.T) {
 	err := json.Unmarshal([]byte(`{}`), &config)
 	assert.NilError(t, err)
 	err = check.Validate(&config)
-	require.ErrorContains(t, err, "must configure aws or gcp or hpc cluster")
+	require.ErrorContains(t, err, "must configure aws, gcp, exec or hpc cluster")
 	expected := Config{
 		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
 		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
@@ -237,3 +237,75 @@ partition: tesla_queue
 
 	assert.Equal(t, unmarshaled.HPC.Partition, "tesla_queue")
 }
+
+func TestUnmarshalProvisionerConfigWithSchedules(t *testing.T) {
+	configRaw := `
+master_url: http://test.master
+agent_docker_image: test_image
+type: hpc
+partition: tesla_queue
+max_instances: 4
+schedules:
+  - name: business-hours
+    cron: "CRON_TZ=America/New_York 0 8 * * 1-5"
+    duration: 11h
+    min_instances: 8
+    max_instances: 8
+  - name: maintenance
+    cron: "0 2 * * 0"
+    duration: 4h
+    max_instances: 0
+`
+	unmarshaled := Config{}
+	err := yaml.Unmarshal([]byte(configRaw), &unmarshaled, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	err = check.Validate(&unmarshaled)
+	assert.NilError(t, err)
+
+	require.Len(t, unmarshaled.Schedules, 2)
+	require.Equal(t, model.Duration(11*time.Hour), unmarshaled.Schedules[0].Duration)
+	require.Equal(t, 8, *unmarshaled.Schedules[0].MinInstances)
+	require.Nil(t, unmarshaled.Schedules[1].MinInstances)
+	require.Equal(t, 0, *unmarshaled.Schedules[1].MaxInstances)
+}
+
+func TestProvisionerConfigInvalidSchedules(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tcs := []struct {
+		name     string
+		schedule ScalingSchedule
+		errMsg   string
+	}{
+		{
+			name: "bad cron",
+			schedule: ScalingSchedule{
+				Name: "s", Cron: "every day", Duration: model.Duration(time.Hour),
+				MinInstances: intPtr(1),
+			},
+			errMsg: "invalid cron expression",
+		},
+		{
+			name:     "no bounds",
+			schedule: ScalingSchedule{Name: "s", Cron: "0 8 * * *", Duration: model.Duration(time.Hour)},
+			errMsg:   "must set min_instances or max_instances",
+		},
+		{
+			name:     "no duration",
+			schedule: ScalingSchedule{Name: "s", Cron: "0 8 * * *", MinInstances: intPtr(1)},
+			errMsg:   "duration must be greater than 0",
+		},
+		{
+			name: "min above max",
+			schedule: ScalingSchedule{
+				Name: "s", Cron: "0 8 * * *", Duration: model.Duration(time.Hour),
+				MinInstances: intPtr(3), MaxInstances: intPtr(2),
+			},
+			errMsg: "max instance must be greater than or equal to min instance",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, check.Validate(tc.schedule), tc.errMsg)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package provconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ExecClusterConfig describes the configuration for a cluster whose instances are managed by an
+// external executable. The executable is invoked as `<command...> <verb>` where verb is one of
+// `launch`, `list` or `terminate`; the request is written to its stdin and the response is read
+// from its stdout, both as JSON.
+type ExecClusterConfig struct {
+	Command      []string          `json:"command"`
+	Env          map[string]string `json:"env"`
+	Timeout      model.Duration    `json:"timeout"`
+	InstanceType execInstanceType  `json:"instance_type"`
+	// AgentID is a shell expression evaluated on the instance to name its agent. It must expand
+	// to the agent name reported for the instance by the `list` verb.
+	AgentID string `json:"agent_id"`
+}
+
+// DefaultExecClusterConfig returns the default configuration of the exec cluster.
+func DefaultExecClusterConfig() *ExecClusterConfig {
+	return &ExecClusterConfig{
+		Timeout: model.Duration(5 * time.Minute),
+		AgentID: "$(hostname)",
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ExecClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = *DefaultExecClusterConfig()
+	type DefaultParser *ExecClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c ExecClusterConfig) Validate() []error {
+	errs := []error{
+		check.GreaterThan(len(c.Command), 0, "exec provider command must be non-empty"),
+		check.GreaterThan(int64(c.Timeout), int64(0), "exec provider timeout must be greater than 0"),
+		check.NotEmpty(c.AgentID, "exec provider agent_id must be non-empty"),
+	}
+	if len(c.Command) > 0 {
+		errs = append(errs, check.NotEmpty(c.Command[0], "exec provider executable must be non-empty"))
+	}
+	return errs
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c ExecClusterConfig) SlotsPerInstance() int {
+	return c.InstanceType.Slots()
+}
+
+// SlotType returns the type of the slot.
+func (c ExecClusterConfig) SlotType() device.Type {
+	if c.InstanceType.SlotType != "" {
+		return c.InstanceType.SlotType
+	}
+	if c.InstanceType.Slots() > 0 {
+		return device.CUDA
+	}
+	return device.ZeroSlot
+}
+
+// Accelerator returns the accelerator for the instance.
+func (c ExecClusterConfig) Accelerator() string {
+	if c.InstanceType.Accelerator == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d x %s", c.InstanceType.SlotsPerInstance, c.InstanceType.Accelerator)
+}
+
+type execInstanceType struct {
+	MachineType      string      `json:"machine_type"`
+	SlotsPerInstance int         `json:"slots"`
+	SlotType         device.Type `json:"slot_type"`
+	Accelerator      string      `json:"accelerator"`
+}
+
+func (t execInstanceType) Name() string {
+	return t.MachineType
+}
+
+func (t execInstanceType) Slots() int {
+	return t.SlotsPerInstance
+}
+
+func (t execInstanceType) Validate() []error {
+	return []error{
+		check.NotEmpty(t.MachineType, "exec provider instance type must have a machine_type"),
+		check.GreaterThanOrEqualTo(t.SlotsPerInstance, 0,
+			"exec provider instance type slots must be greater than or equal to 0"),
+		check.In(string(t.SlotType), []string{
+			string(device.ZeroSlot), string(device.CPU), string(device.CUDA), string(device.ROCM),
+		}, "exec provider instance type slot_type must be within [cpu, cuda, rocm]"),
+	}
+}
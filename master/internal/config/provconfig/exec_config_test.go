@@ -0,0 +1,39 @@
+package provconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestExecClusterConfigValidate(t *testing.T) {
+	config := *DefaultExecClusterConfig()
+	config.Command = []string{"/usr/local/bin/provider", "--cloud", "internal"}
+	config.InstanceType = execInstanceType{MachineType: "bm.gpu8", SlotsPerInstance: 8}
+	require.NoError(t, check.Validate(config))
+	require.Equal(t, device.CUDA, config.SlotType())
+	require.Equal(t, 8, config.SlotsPerInstance())
+
+	config.InstanceType = execInstanceType{MachineType: "bm.cpu", SlotsPerInstance: 1, SlotType: "cpu"}
+	require.NoError(t, check.Validate(config))
+	require.Equal(t, device.CPU, config.SlotType())
+
+	require.ErrorContains(t, check.Validate(ExecClusterConfig{
+		Timeout:      config.Timeout,
+		AgentID:      config.AgentID,
+		InstanceType: config.InstanceType,
+	}), "exec provider command must be non-empty")
+
+	config.InstanceType = execInstanceType{MachineType: "bm.tpu", SlotType: "tpu"}
+	require.ErrorContains(t, check.Validate(config), "slot_type must be within")
+}
+
+func TestProvisionerConfigOnlyOneCluster(t *testing.T) {
+	config := DefaultConfig()
+	config.GCP = DefaultGCPClusterConfig()
+	config.Exec = DefaultExecClusterConfig()
+	require.ErrorContains(t, check.Validate(config), "must configure only one cluster")
+}
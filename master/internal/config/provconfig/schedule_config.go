@@ -0,0 +1,60 @@
+package provconfig
+
+import (
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ScalingSchedule overrides the instance bounds of a provisioner during a recurring window. The
+// window opens at every activation of Cron and stays open for Duration. When several schedules
+// are active at once, the largest minimum and the smallest maximum win, and a maximum always takes
+// precedence over a minimum so that a schedule can be used to cap spend (e.g. during maintenance).
+type ScalingSchedule struct {
+	Name string `json:"name"`
+	// Cron is a standard five-field cron expression. It may be prefixed with CRON_TZ=<zone> to
+	// evaluate it in a timezone other than the master's.
+	Cron         string         `json:"cron"`
+	Duration     model.Duration `json:"duration"`
+	MinInstances *int           `json:"min_instances"`
+	MaxInstances *int           `json:"max_instances"`
+}
+
+// Validate implements the check.Validatable interface.
+func (s ScalingSchedule) Validate() []error {
+	errs := []error{
+		check.NotEmpty(s.Name, "scaling schedule must have a name"),
+		check.GreaterThan(int64(s.Duration), int64(0),
+			"scaling schedule duration must be greater than 0"),
+		check.False(s.MinInstances == nil && s.MaxInstances == nil,
+			"scaling schedule must set min_instances or max_instances"),
+	}
+	if _, err := s.ParseCron(); err != nil {
+		errs = append(errs, err)
+	}
+	if s.MinInstances != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(int64(*s.MinInstances), int64(0),
+			"scaling schedule min instance must be greater than or equal to 0"))
+	}
+	if s.MaxInstances != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(int64(*s.MaxInstances), int64(0),
+			"scaling schedule max instance must be greater than or equal to 0"))
+	}
+	if s.MinInstances != nil && s.MaxInstances != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			int64(*s.MaxInstances), int64(*s.MinInstances),
+			"scaling schedule max instance must be greater than or equal to min instance"))
+	}
+	return errs
+}
+
+// ParseCron parses the cron expression of the schedule.
+func (s ScalingSchedule) ParseCron() (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression for scaling schedule %q", s.Name)
+	}
+	return sched, nil
+}
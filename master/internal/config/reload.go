@@ -0,0 +1,267 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/jinzhu/copier"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+// reloadMu serializes master config reloads.
+var reloadMu sync.Mutex
+
+// ConfigChange describes a field that differs between the running master config and a reloaded
+// one. Values are taken from the printable form of the config, so secrets are masked.
+type ConfigChange struct {
+	Path            string `json:"path"`
+	OldValue        any    `json:"old_value"`
+	NewValue        any    `json:"new_value"`
+	RequiresRestart bool   `json:"requires_restart"`
+}
+
+// copyReloadable copies the fields of the master config that can change while the master is
+// running from src into dst. Resource managers look up the reloadable settings of their pools in
+// the master config singleton when they use them, so they see the reloaded values once dst
+// replaces it.
+func copyReloadable(dst, src *Config) {
+	dst.Log = src.Log
+	dst.LaunchError = src.LaunchError
+	dst.NotebookTimeout = src.NotebookTimeout
+	dst.TensorBoardTimeout = src.TensorBoardTimeout
+	dst.TaskContainerDefaults = src.TaskContainerDefaults
+	dst.Webhooks.BaseURL = src.Webhooks.BaseURL
+	dst.Webhooks.SigningKey = src.Webhooks.SigningKey
+	dst.Telemetry.SegmentWebUIKey = src.Telemetry.SegmentWebUIKey
+
+	dstRMs, srcRMs := dst.ResourceManagers(), src.ResourceManagers()
+	if len(dstRMs) != len(srcRMs) {
+		return
+	}
+	for i, dstRM := range dstRMs {
+		srcRM := srcRMs[i]
+		if dstRM.ResourceManager.AgentRM != nil && srcRM.ResourceManager.AgentRM != nil {
+			copyDefaultPriority(
+				dstRM.ResourceManager.AgentRM.Scheduler, srcRM.ResourceManager.AgentRM.Scheduler,
+			)
+		}
+
+		srcPools := map[string]ResourcePoolConfig{}
+		for _, pool := range srcRM.ResourcePools {
+			srcPools[pool.PoolName] = pool
+		}
+		for j := range dstRM.ResourcePools {
+			dstPool := &dstRM.ResourcePools[j]
+			srcPool, ok := srcPools[dstPool.PoolName]
+			if !ok {
+				continue
+			}
+			dstPool.TaskContainerDefaults = srcPool.TaskContainerDefaults
+			copyDefaultPriority(dstPool.Scheduler, srcPool.Scheduler)
+		}
+	}
+}
+
+func copyDefaultPriority(dst, src *SchedulerConfig) {
+	if dst == nil || src == nil || dst.Priority == nil || src.Priority == nil {
+		return
+	}
+	dst.Priority.DefaultPriority = src.Priority.DefaultPriority
+}
+
+// Diff returns the fields that differ between c and newConf, marking those that cannot be
+// applied without restarting the master.
+func (c *Config) Diff(newConf *Config) ([]ConfigChange, error) {
+	reloaded, err := c.reloaded(newConf)
+	if err != nil {
+		return nil, err
+	}
+	return c.diff(reloaded, newConf)
+}
+
+// Patch returns a copy of c with patch, a partial master config in the format of the master config
+// file, merged on top. Lists in the patch replace the existing lists.
+func (c *Config) Patch(patch []byte) (*Config, error) {
+	var patched Config
+	if err := copier.CopyWithOption(&patched, c, copier.Option{DeepCopy: true}); err != nil {
+		return nil, fmt.Errorf("copying config: %w", err)
+	}
+	if err := yaml.Unmarshal(patch, &patched, yaml.DisallowUnknownFields); err != nil {
+		return nil, fmt.Errorf("invalid master config patch: %w", err)
+	}
+	return &patched, nil
+}
+
+// Reload validates newConf and returns a copy of c with the fields of newConf that can change at
+// runtime, along with how the config changed. Changes to other fields are returned with
+// RequiresRestart set and are not part of the copy. c is not modified.
+func (c *Config) Reload(newConf *Config) (*Config, []ConfigChange, error) {
+	if err := check.Validate(newConf); err != nil {
+		return nil, nil, err
+	}
+	reloaded, err := c.reloaded(newConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := c.diff(reloaded, newConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reloaded, changes, nil
+}
+
+// ReloadMasterConfig reloads the master config singleton from newConf and swaps the result in, so
+// readers of the singleton see either the old or the new config and never a mix of both. onApply
+// is then called with the new config to propagate changes that are not read from the singleton.
+func ReloadMasterConfig(newConf *Config, onApply func(*Config)) ([]ConfigChange, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	reloaded, changes, err := GetMasterConfig().Reload(newConf)
+	if err != nil {
+		return nil, err
+	}
+	masterConfig.Store(reloaded)
+	if onApply != nil {
+		onApply(reloaded)
+	}
+	return changes, nil
+}
+
+// reloaded returns a deep copy of c with the reloadable fields of newConf.
+func (c *Config) reloaded(newConf *Config) (*Config, error) {
+	var reloaded Config
+	if err := copier.CopyWithOption(&reloaded, c, copier.Option{DeepCopy: true}); err != nil {
+		return nil, fmt.Errorf("copying config: %w", err)
+	}
+	copyReloadable(&reloaded, newConf)
+	return &reloaded, nil
+}
+
+// diff returns the changes from c to newConf, marking those that reloaded does not pick up.
+func (c *Config) diff(reloaded, newConf *Config) ([]ConfigChange, error) {
+	changes, err := diffConfigs(c, newConf)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := diffConfigs(reloaded, newConf)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]ConfigChange, 0, len(changes))
+	for path, change := range changes {
+		_, change.RequiresRestart = pending[path]
+		sorted = append(sorted, change)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted, nil
+}
+
+// diffConfigs returns the leaf fields that differ between two configs, keyed by path.
+func diffConfigs(oldConf, newConf *Config) (map[string]ConfigChange, error) {
+	oldFields, err := flattenConfig(oldConf)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenConfig(newConf)
+	if err != nil {
+		return nil, err
+	}
+	oldPrintable, err := flattenPrintable(oldConf)
+	if err != nil {
+		return nil, err
+	}
+	newPrintable, err := flattenPrintable(newConf)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]ConfigChange{}
+	for path := range unionKeys(oldFields, newFields) {
+		if reflect.DeepEqual(oldFields[path], newFields[path]) {
+			continue
+		}
+		changes[path] = ConfigChange{
+			Path:     path,
+			OldValue: oldPrintable[path],
+			NewValue: newPrintable[path],
+		}
+	}
+	return changes, nil
+}
+
+func unionKeys(a, b map[string]any) map[string]bool {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+func flattenConfig(c *Config) (map[string]any, error) {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return flattenJSON(bytes)
+}
+
+func flattenPrintable(c *Config) (map[string]any, error) {
+	bytes, err := c.Printable()
+	if err != nil {
+		return nil, err
+	}
+	return flattenJSON(bytes)
+}
+
+func flattenJSON(bytes []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := json.Unmarshal(bytes, &m); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	fields := map[string]any{}
+	flatten(fields, nil, m)
+	return fields, nil
+}
+
+// flatten records the leaves of a decoded JSON value by dotted path. Lists of resource pools are
+// keyed by pool name so that changes to one pool are reported against that pool; other lists are
+// leaves.
+func flatten(fields map[string]any, path []string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			flatten(fields, append(path[:len(path):len(path)], k), child)
+		}
+	case []any:
+		pools := map[string]any{}
+		for _, item := range v {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				break
+			}
+			name, ok := obj["pool_name"].(string)
+			if !ok {
+				break
+			}
+			pools[name] = obj
+		}
+		if len(v) > 0 && len(pools) == len(v) {
+			flatten(fields, path, pools)
+			return
+		}
+		fields[strings.Join(path, ".")] = v
+	default:
+		fields[strings.Join(path, ".")] = v
+	}
+}
@@ -0,0 +1,139 @@
+//nolint:exhaustruct
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func reloadTestConfig(t *testing.T) *Config {
+	RegisterAuthZType("basic")
+
+	c := DefaultConfig()
+	c.RootManagerInternal = &ResourceManagerConfig{AgentRM: &AgentResourceManagerConfig{
+		DefaultAuxResourcePool:     "default",
+		DefaultComputeResourcePool: "gpu",
+	}}
+	c.RootPoolsInternal = []ResourcePoolConfig{
+		{PoolName: "default", MaxAuxContainersPerAgent: 100},
+		{
+			PoolName:                 "gpu",
+			MaxAuxContainersPerAgent: 100,
+			Scheduler: &SchedulerConfig{
+				FittingPolicy: best,
+				Priority:      &PrioritySchedulerConfig{DefaultPriority: ptrs.Ptr(42)},
+			},
+		},
+	}
+	require.NoError(t, c.Resolve())
+	return c
+}
+
+func TestConfigDiff(t *testing.T) {
+	c := reloadTestConfig(t)
+	newConf := reloadTestConfig(t)
+	newConf.Webhooks.SigningKey = c.Webhooks.SigningKey
+
+	changes, err := c.Diff(newConf)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	newConf.Log.Level = "debug"
+	newConf.Webhooks.SigningKey = "rotated"
+	newConf.RootPoolsInternal[1].Scheduler.Priority.DefaultPriority = ptrs.Ptr(10)
+	newConf.RootPoolsInternal[1].MaxAuxContainersPerAgent = 5
+	newConf.Port = 9090
+
+	changes, err = c.Diff(newConf)
+	require.NoError(t, err)
+	require.Equal(t, []ConfigChange{
+		{Path: "log.level", OldValue: "info", NewValue: "debug"},
+		{Path: "port", OldValue: 8080.0, NewValue: 9090.0, RequiresRestart: true},
+		{
+			Path:            "resource_pools.gpu.max_aux_containers_per_agent",
+			OldValue:        100.0,
+			NewValue:        5.0,
+			RequiresRestart: true,
+		},
+		{Path: "resource_pools.gpu.scheduler.default_priority", OldValue: 42.0, NewValue: 10.0},
+		{Path: "webhooks.signing_key", OldValue: "********", NewValue: "********"},
+	}, changes)
+}
+
+func TestConfigReload(t *testing.T) {
+	c := reloadTestConfig(t)
+
+	newConf := reloadTestConfig(t)
+	newConf.Log.Level = "debug"
+	newConf.TaskContainerDefaults.ShmSizeBytes = 1 << 20
+	newConf.RootPoolsInternal[1].Scheduler.Priority.DefaultPriority = ptrs.Ptr(10)
+	newConf.Telemetry.Enabled = !c.Telemetry.Enabled
+	newConf.Port = 9090
+
+	reloaded, changes, err := c.Reload(newConf)
+	require.NoError(t, err)
+	require.Contains(t, changes, ConfigChange{
+		Path:            "telemetry.enabled",
+		OldValue:        c.Telemetry.Enabled,
+		NewValue:        newConf.Telemetry.Enabled,
+		RequiresRestart: true,
+	})
+	require.Equal(t, "debug", reloaded.Log.Level)
+	require.Equal(t, int64(1<<20), reloaded.TaskContainerDefaults.ShmSizeBytes)
+	require.Equal(t, newConf.Webhooks.SigningKey, reloaded.Webhooks.SigningKey)
+	require.Equal(t, 10, *reloaded.RootPoolsInternal[1].Scheduler.Priority.DefaultPriority)
+	// Fields that require a restart are left alone.
+	require.Equal(t, 8080, reloaded.Port)
+	require.Equal(t, c.Telemetry.Enabled, reloaded.Telemetry.Enabled)
+	// The running config is not modified.
+	require.Equal(t, "info", c.Log.Level)
+	require.Equal(t, 42, *c.RootPoolsInternal[1].Scheduler.Priority.DefaultPriority)
+
+	// Invalid configs are rejected.
+	invalid := reloadTestConfig(t)
+	invalid.TaskContainerDefaults = model.TaskContainerDefaultsConfig{ShmSizeBytes: -1}
+	invalid.RootPoolsInternal[0].PoolName = ""
+	_, _, err = c.Reload(invalid)
+	require.Error(t, err)
+}
+
+func TestReloadMasterConfig(t *testing.T) {
+	previous := masterConfig.Load()
+	defer masterConfig.Store(previous)
+
+	c := reloadTestConfig(t)
+	masterConfig.Store(c)
+
+	newConf := reloadTestConfig(t)
+	newConf.Log.Level = "debug"
+	newConf.RootPoolsInternal[1].Scheduler.Priority.DefaultPriority = ptrs.Ptr(10)
+	tcd := model.DefaultTaskContainerDefaults()
+	tcd.ShmSizeBytes = 1 << 20
+	newConf.RootPoolsInternal[1].TaskContainerDefaults = tcd
+
+	var applied *Config
+	_, err := ReloadMasterConfig(newConf, func(c *Config) { applied = c })
+	require.NoError(t, err)
+	// The reloaded config replaces the singleton instead of being copied into it.
+	require.Same(t, applied, GetMasterConfig())
+	require.NotSame(t, c, GetMasterConfig())
+	require.Equal(t, "info", c.Log.Level)
+	require.Equal(t, "debug", GetMasterConfig().Log.Level)
+	// Resource pool settings are read from the singleton, so they are reloaded too.
+	require.Equal(t, 10, DefaultPriorityForPool("gpu"))
+	require.Equal(t, int64(1<<20), TaskContainerDefaultsForPool("gpu").ShmSizeBytes)
+	require.Nil(t, TaskContainerDefaultsForPool("default"))
+
+	// Invalid configs are rejected before anything is applied.
+	invalid := reloadTestConfig(t)
+	invalid.RootPoolsInternal[0].PoolName = ""
+	applied = nil
+	_, err = ReloadMasterConfig(invalid, func(c *Config) { applied = c })
+	require.Error(t, err)
+	require.Nil(t, applied)
+	require.Equal(t, "debug", GetMasterConfig().Log.Level)
+}
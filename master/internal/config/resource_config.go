@@ -153,6 +153,17 @@ func (r ResourceConfig) Validate() []error {
 
 			rmPoolNames[rp.PoolName] = true
 			poolNames[rp.PoolName] = true
+
+			if rp.Kueue != nil && r.ResourceManager.KubernetesRM == nil {
+				errs = append(errs, fmt.Errorf(
+					"resource pool %s: kueue is only supported by the kubernetes resource manager",
+					rp.PoolName))
+			}
+			if rp.PodTemplate != nil && r.ResourceManager.KubernetesRM == nil {
+				errs = append(errs, fmt.Errorf(
+					"resource pool %s: pod_template is only supported by the kubernetes resource manager",
+					rp.PoolName))
+			}
 		}
 	}
 
@@ -257,6 +257,62 @@ additional_resource_managers:
 			"root.ResourceConfig.AdditionalResourceManagersInternal[0]." +
 			"ResourceManager.KubernetesRM: cluster_name is required:  must be non-empty"},
 
+		{"kueue on agent pool", `
+resource_manager:
+  type: agent
+  name: a
+  cluster_name: a
+resource_pools:
+  - pool_name: a
+    kueue:
+      queue_name: team-a`, nil, "Check Failed! 2 errors found:\n\terror found at " +
+			"root.ResourceConfig: resource pool a: kueue is only supported by the kubernetes " +
+			"resource manager\n\terror found at root: resource pool a: kueue is only supported " +
+			"by the kubernetes resource manager"},
+
+		{"kueue without queue name", `
+resource_manager:
+  type: kubernetes
+  name: a
+  cluster_name: a
+  max_slots_per_pod: 2
+resource_pools:
+  - pool_name: a
+    kueue: {}`, nil, "Check Failed! 1 errors found:\n\terror found at " +
+			"root.ResourceConfig.RootPoolsInternal[0].Kueue: kueue queue_name cannot be empty: " +
+			"expected true, got false"},
+
+		{"pod template without template", `
+resource_manager:
+  type: kubernetes
+  name: a
+  cluster_name: a
+  max_slots_per_pod: 2
+resource_pools:
+  - pool_name: a
+    pod_template:
+      locked_fields: [tolerations]`, nil, "Check Failed! 1 errors found:\n\terror found at " +
+			"root.ResourceConfig.RootPoolsInternal[0].PodTemplate: pod_template template is " +
+			"required: expected true, got false"},
+
+		{"pod template locked field", `
+resource_manager:
+  type: kubernetes
+  name: a
+  cluster_name: a
+  max_slots_per_pod: 2
+resource_pools:
+  - pool_name: a
+    pod_template:
+      template:
+        spec:
+          hostNetwork: true
+      locked_fields: [tolerations]`, nil, "Check Failed! 2 errors found:\n\terror found at " +
+			"root.ResourceConfig.RootPoolsInternal[0].PodTemplate: pod_template cannot enable host " +
+			"networking: expected false, got true\n\terror found at " +
+			"root.ResourceConfig.RootPoolsInternal[0].PodTemplate: pod_template locked field " +
+			"tolerations must start with metadata. or spec.: expected true, got false"},
+
 		{"additional rm not giving pools", `
 resource_manager:
   type: agent
@@ -2,6 +2,9 @@ package config
 
 import (
 	"encoding/json"
+	"strings"
+
+	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/pkg/aproto"
@@ -32,6 +35,12 @@ type ResourcePoolConfig struct {
 	// AgentReconnectWait define the time master will wait for agent
 	// before abandoning it.
 	AgentReconnectWait model.Duration `json:"agent_reconnect_wait"`
+	// Kueue, if set, defers admission of the pool's jobs to Kueue. Only supported by the
+	// Kubernetes resource manager.
+	Kueue *KueueConfig `json:"kueue,omitempty"`
+	// PodTemplate is merged into every pod launched in the pool. Only supported by the Kubernetes
+	// resource manager.
+	PodTemplate *PodTemplateConfig `json:"pod_template,omitempty"`
 
 	// Deprecated: Use MaxAuxContainersPerAgent instead.
 	MaxCPUContainersPerAgent int `json:"max_cpu_containers_per_agent,omitempty"`
@@ -72,3 +81,47 @@ func (r ResourcePoolConfig) Printable() ResourcePoolConfig {
 
 	return r
 }
+
+// KueueConfig configures submitting a Kubernetes resource pool's jobs through Kueue. Jobs are
+// created suspended and labeled with the queue name; Kueue unsuspends them once they are admitted.
+type KueueConfig struct {
+	// QueueName is the name of the Kueue LocalQueue in the job's namespace.
+	QueueName string `json:"queue_name"`
+}
+
+// Validate implements the check.Validatable interface.
+func (k KueueConfig) Validate() []error {
+	return []error{
+		check.True(len(k.QueueName) != 0, "kueue queue_name cannot be empty"),
+	}
+}
+
+// PodTemplateConfig configures a pod spec overlay for a Kubernetes resource pool. Tasks' pod specs
+// are strategically merged on top of the template, so the template's sidecars, init containers and
+// tolerations are added to every pod unless a task overrides them.
+type PodTemplateConfig struct {
+	Template *k8sV1.Pod `json:"template"`
+	// LockedFields are dot-separated paths into the template, e.g. "spec.tolerations", that tasks'
+	// pod specs may not set.
+	LockedFields []string `json:"locked_fields"`
+}
+
+// Validate implements the check.Validatable interface.
+func (p PodTemplateConfig) Validate() []error {
+	if p.Template == nil {
+		return []error{check.True(p.Template != nil, "pod_template template is required")}
+	}
+
+	errs := []error{
+		check.Equal(p.Template.Name, "", "pod_template name is not a configurable option"),
+		check.Equal(p.Template.Namespace, "", "pod_template namespace is not a configurable option"),
+		check.False(p.Template.Spec.HostNetwork, "pod_template cannot enable host networking"),
+	}
+	for _, field := range p.LockedFields {
+		errs = append(errs, check.True(
+			strings.HasPrefix(field, "metadata.") || strings.HasPrefix(field, "spec."),
+			"pod_template locked field %s must start with metadata. or spec.", field,
+		))
+	}
+	return errs
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"net/url"
+	"regexp"
 
 	"github.com/determined-ai/determined/master/pkg/check"
 )
@@ -22,6 +23,10 @@ type SAMLConfig struct {
 	AgentUserNameAttributeName  string `json:"agent_user_name_attribute_name"`
 	AgentGroupNameAttributeName string `json:"agent_group_name_attribute_name"`
 	AlwaysRedirect              bool   `json:"always_redirect"`
+	// GroupsSyncPrefix and GroupsSyncRegex limit the groups whose membership is synced from the
+	// groups attribute to the ones with matching names.
+	GroupsSyncPrefix string `json:"groups_sync_prefix"`
+	GroupsSyncRegex  string `json:"groups_sync_regex"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -31,9 +36,11 @@ func (c SAMLConfig) Validate() []error {
 	}
 
 	_, urlErr := url.Parse(c.IDPRecipientURL)
+	_, regexErr := regexp.Compile(c.GroupsSyncRegex)
 
 	return []error{
 		urlErr,
+		regexErr,
 		check.NotEmpty(c.Provider, "saml_provider must be specified"),
 		check.NotEmpty(c.IDPRecipientURL, "saml_idp_recipient_url must be specified"),
 		check.NotEmpty(c.IDPSSOURL, "saml_idp_sso_url must be specified"),
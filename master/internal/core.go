@@ -46,6 +46,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/connsave"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/deployment"
 	"github.com/determined-ai/determined/master/internal/elastic"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
@@ -63,6 +64,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/rm/multirm"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/saas/saasprovisioner"
+	"github.com/determined-ai/determined/master/internal/schedules"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/stream"
 	"github.com/determined-ai/determined/master/internal/task"
@@ -124,11 +126,33 @@ func New(logStore *logger.LogBuffer, config *config.Config) *Master {
 	}
 }
 
+// ReloadConfig applies the fields of newConf that can change while the master is running and
+// returns how the config changed. Changed fields that require a restart are reported but not
+// applied. Reloadable fields are read from the master config singleton rather than m.config.
+func (m *Master) ReloadConfig(newConf *config.Config) ([]config.ConfigChange, error) {
+	changes, err := config.ReloadMasterConfig(newConf, func(c *config.Config) {
+		logger.SetLogrus(c.Log)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if change.RequiresRestart {
+			log.Warnf("master config %s changed but requires a restart to take effect", change.Path)
+		} else {
+			log.Infof("master config %s changed from %v to %v",
+				change.Path, change.OldValue, change.NewValue)
+		}
+	}
+	return changes, nil
+}
+
 // Info returns this master's information.
 func (m *Master) Info() aproto.MasterInfo {
 	telemetryInfo := aproto.TelemetryInfo{}
-	if m.config.Telemetry.SegmentWebUIKey != "" {
-		telemetryInfo.SegmentKey = m.config.Telemetry.SegmentWebUIKey
+	if segmentKey := config.GetMasterConfig().Telemetry.SegmentWebUIKey; segmentKey != "" {
+		telemetryInfo.SegmentKey = segmentKey
 	}
 
 	if m.config.Telemetry.Enabled {
@@ -1275,6 +1299,9 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	userService := user.GetService()
 
 	proxy.InitProxy(processProxyAuthentication)
+	if days := m.config.Proxy.AccessLogRetentionDays; days >= 0 {
+		go proxy.PruneAccessLogs(time.Duration(days) * 24 * time.Hour)
+	}
 	portregistry.InitPortRegistry(config.GetMasterConfig().ReservedPorts)
 
 	go periodicallyAggregateResourceAllocation(m.db)
@@ -1409,6 +1436,10 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		return err
 	}
 
+	// Keep deployments running, starting from the replicas restored with the commands.
+	deployment.SetDefaultManager(deployment.NewManager(command.DefaultCmdService, proxy.DefaultProxy))
+	go deployment.DefaultManager.Run(ctx)
+
 	if err = m.closeOpenAllocations(ctx); err != nil {
 		return err
 	}
@@ -1423,6 +1454,10 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	go updateClusterHeartbeat(ctx, m.db)
 	go trials.MarkLostTrialsWorker(ctx)
 
+	if err = schedules.Start(ctx, (&apiServer{m: m}).CreateExperiment); err != nil {
+		return errors.Wrap(err, "restoring experiment schedules")
+	}
+
 	// Docs and WebUI.
 	webuiRoot := filepath.Join(m.config.Root, "webui")
 	reactRoot := filepath.Join(webuiRoot, "react")
@@ -1499,6 +1534,14 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	experimentsGroup.GET("/:experiment_id/model_def", m.getExperimentModelDefinition)
 	experimentsGroup.GET("/:experiment_id/file/download", m.getExperimentModelFile)
 	experimentsGroup.GET("/:experiment_id/preview_gc", api.Route(m.getExperimentCheckpointsToGC))
+	experimentsGroup.GET("/:experiment_id/export", m.exportExperiment)
+	experimentsGroup.POST("/import", api.Route(m.importBundle))
+
+	projectsGroup := m.echo.Group("/projects")
+	projectsGroup.GET("/:project_id/export", m.exportProject)
+
+	runsGroup := m.echo.Group("/runs")
+	runsGroup.GET("/metrics/export", m.exportRunMetrics)
 
 	checkpointsGroup := m.echo.Group("/checkpoints")
 	checkpointsGroup.GET("/:checkpoint_uuid", m.getCheckpoint)
@@ -1510,8 +1553,10 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	resourcesGroup.GET("/allocation/raw", m.getRawResourceAllocation)
 	resourcesGroup.GET("/allocation/allocations-csv", m.getResourceAllocations)
 	resourcesGroup.GET("/allocation/aggregated", m.getAggregatedResourceAllocation)
+	resourcesGroup.GET("/chargeback-csv", m.getChargebackReport)
 
 	m.echo.POST("/task-logs", api.Route(m.postTaskLogs))
+	m.echo.POST("/otlp/v1/metrics", m.postOTLPMetrics)
 
 	m.echo.Any("/debug/pprof/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
 	m.echo.Any(
@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/bundle"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+// importBundleResponse is the response of importing a bundle.
+type importBundleResponse struct {
+	DryRun      bool                     `json:"dry_run"`
+	Imported    bool                     `json:"imported"`
+	Experiments []*bundle.ExperimentPlan `json:"experiments"`
+}
+
+func (m *Master) exportExperiment(c echo.Context) error {
+	args := struct {
+		ExperimentID int   `path:"experiment_id"`
+		IncludeLogs  *bool `query:"include_logs"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	if _, _, err := echoGetExperimentAndCheckCanDoActions(
+		c.Request().Context(), c, args.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts,
+	); err != nil {
+		return err
+	}
+
+	return m.writeBundle(c, fmt.Sprintf("exp%d", args.ExperimentID),
+		[]int{args.ExperimentID}, args.IncludeLogs != nil && *args.IncludeLogs)
+}
+
+func (m *Master) exportProject(c echo.Context) error {
+	args := struct {
+		ProjectID   int   `path:"project_id"`
+		IncludeLogs *bool `query:"include_logs"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	user := c.(*detContext.DetContext).MustGetUser()
+
+	if _, err := m.getProjectForUser(ctx, user, args.ProjectID); err != nil {
+		return err
+	}
+
+	var ids []int
+	if err := db.Bun().NewSelect().Table("experiments").Column("id").
+		Where("project_id = ?", args.ProjectID).
+		Order("id").
+		Scan(ctx, &ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		e, err := db.ExperimentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := expauth.AuthZProvider.Get().CanGetExperimentArtifacts(ctx, user, e); err != nil {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+	}
+
+	return m.writeBundle(c, fmt.Sprintf("project%d", args.ProjectID),
+		ids, args.IncludeLogs != nil && *args.IncludeLogs)
+}
+
+// writeBundle streams a bundle of the given experiments. Callers must check that the user can read
+// every experiment first, since errors after the first experiment is written truncate the bundle.
+func (m *Master) writeBundle(c echo.Context, name string, ids []int, includeLogs bool) error {
+	var logs bundle.LogBackend
+	if includeLogs {
+		logs = m.taskLogBackend
+	}
+
+	c.Response().Header().Set(
+		"Content-Disposition", fmt.Sprintf(`attachment; filename="%s_bundle.tar.gz"`, name))
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-gtar")
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := bundle.NewWriter(c.Response(), m.ClusterID)
+	for _, id := range ids {
+		data, err := bundle.ExportExperiment(c.Request().Context(), id, logs)
+		if err != nil {
+			return err
+		}
+		if err := w.WriteExperiment(data); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func (m *Master) importBundle(c echo.Context) (interface{}, error) {
+	args := struct {
+		ProjectID    *int    `query:"project_id"`
+		WorkspaceMap *string `query:"workspace_map"`
+		UserMap      *string `query:"user_map"`
+		DryRun       *bool   `query:"dry_run"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	ctx := c.Request().Context()
+	user := c.(*detContext.DetContext).MustGetUser()
+
+	opts := bundle.ImportOptions{
+		Importer:         user,
+		MapOwners:        user.Admin,
+		LogRetentionDays: m.taskSpec.LogRetentionDays,
+	}
+	if args.ProjectID != nil {
+		opts.ProjectID = *args.ProjectID
+	}
+	var err error
+	if opts.WorkspaceMap, err = parseNameMap(args.WorkspaceMap); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "workspace_map: "+err.Error())
+	}
+	if opts.UserMap, err = parseNameMap(args.UserMap); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "user_map: "+err.Error())
+	}
+
+	b, err := bundle.Read(c.Request().Body, m.config.Bundles.MaxImportSize)
+	if errors.Is(err, bundle.ErrTooLarge) {
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	} else if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	plans, err := bundle.Plan(ctx, b, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	canCreate := map[int]error{}
+	valid := true
+	for _, plan := range plans {
+		if plan.ProjectID != 0 {
+			if _, ok := canCreate[plan.ProjectID]; !ok {
+				canCreate[plan.ProjectID] = m.canImportInto(ctx, user, plan.ProjectID)
+			}
+			if err := canCreate[plan.ProjectID]; err != nil {
+				plan.Errors = append(plan.Errors, err.Error())
+			}
+		}
+		valid = valid && len(plan.Errors) == 0
+	}
+
+	resp := importBundleResponse{
+		DryRun:      args.DryRun != nil && *args.DryRun,
+		Experiments: plans,
+	}
+	if resp.DryRun || !valid {
+		return resp, nil
+	}
+	if err := bundle.Import(ctx, b, plans, opts, m.db, m.taskLogBackend); err != nil {
+		return nil, err
+	}
+	resp.Imported = true
+	return resp, nil
+}
+
+func (m *Master) canImportInto(ctx context.Context, user model.User, projectID int) error {
+	p, err := m.getProjectForUser(ctx, user, projectID)
+	if err != nil {
+		return err
+	}
+	if p.Archived {
+		return fmt.Errorf("project %d is archived", projectID)
+	}
+	return expauth.AuthZProvider.Get().CanCreateExperiment(ctx, user, p)
+}
+
+func (m *Master) getProjectForUser(
+	ctx context.Context, user model.User, projectID int,
+) (*projectv1.Project, error) {
+	errProjectNotFound := api.NotFoundErrs("project", strconv.Itoa(projectID), false)
+	p := &projectv1.Project{}
+	if err := m.db.QueryProto("get_project", p, projectID); errors.Is(err, db.ErrNotFound) {
+		return nil, errProjectNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if err := project.AuthZProvider.Get().CanGetProject(ctx, user, p); err != nil {
+		return nil, authz.SubIfUnauthorized(err, errProjectNotFound)
+	}
+	return p, nil
+}
+
+// parseNameMap parses a comma-separated list of old=new name pairs.
+func parseNameMap(s *string) (map[string]string, error) {
+	names := map[string]string{}
+	if s == nil || *s == "" {
+		return names, nil
+	}
+	for _, pair := range strings.Split(*s, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("expected old=new, got %q", pair)
+		}
+		names[from] = to
+	}
+	return names, nil
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/chargeback"
+)
+
+//	@Summary	Get the cost of the resources allocated during the given time period (CSV).
+//	@Tags		Cluster
+//	@ID			get-chargeback-report-csv
+//	@Produce	text/csv
+//
+// nolint:lll
+//
+//	@Param		timestamp_after		query	string	true	"Start time to get costs for (YYYY-MM-DDTHH:MM:SSZ format)"
+//
+// nolint:lll
+//
+//	@Param		timestamp_before	query	string	true	"End time to get costs for (YYYY-MM-DDTHH:MM:SSZ format)"
+//
+// nolint:lll
+//
+//	@Param		group_by			query	string	false	"Comma-separated dimensions to break costs down by (workspace, project, user, label, resource_pool)"
+//	@Success	200					{}		string	"A CSV file containing the dimensions of group_by and the fields slot_hours, unpriced_slot_hours, cost"
+//	@Router		/resources/chargeback-csv [get]
+func (m *Master) getChargebackReport(c echo.Context) error {
+	args := struct {
+		Start   string  `query:"timestamp_after"`
+		End     string  `query:"timestamp_before"`
+		GroupBy *string `query:"group_by"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+
+	start, err := time.Parse(time.RFC3339, args.Start)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid start time")
+	}
+	end, err := time.Parse(time.RFC3339, args.End)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid end time")
+	}
+	if start.After(end) {
+		return echo.NewHTTPError(http.StatusBadRequest, "start time cannot be after end time")
+	}
+	var groupBy []chargeback.Dimension
+	if args.GroupBy != nil && *args.GroupBy != "" {
+		for _, s := range strings.Split(*args.GroupBy, ",") {
+			d, err := chargeback.ParseDimension(s)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			groupBy = append(groupBy, d)
+		}
+	}
+
+	entries, err := m.chargebackReport(c.Request().Context(), start, end, groupBy)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+	c.Response().WriteHeader(http.StatusOK)
+	var header []string
+	for _, d := range groupBy {
+		header = append(header, string(d))
+	}
+	header = append(header, "slot_hours", "unpriced_slot_hours", "cost")
+
+	csvWriter := csv.NewWriter(c.Response())
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	formatFloat := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	for _, e := range entries {
+		var fields []string
+		for _, d := range groupBy {
+			if v := e.Value(d); v != nil {
+				fields = append(fields, *v)
+			} else {
+				fields = append(fields, "")
+			}
+		}
+		fields = append(fields,
+			formatFloat(e.SlotHours), formatFloat(e.UnpricedSlotHours), formatFloat(e.Cost))
+		if err := csvWriter.Write(fields); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -319,7 +319,7 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 
 	taskContainerDefaults, err := m.rm.TaskContainerDefaults(
 		poolName,
-		m.config.TaskContainerDefaults,
+		masterConfig.GetMasterConfig().TaskContainerDefaults,
 	)
 	if err != nil {
 		return nil, nil, config, nil, nil, errors.Wrapf(err, "error getting TaskContainerDefaults")
@@ -377,6 +377,9 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 	if err = config.Searcher().AssertCurrent(); err != nil {
 		return nil, nil, config, nil, nil, errors.Wrap(err, "invalid experiment configuration")
 	}
+	if err = config.Resources().ValidateElastic(); err != nil {
+		return nil, nil, config, nil, nil, errors.Wrap(err, "invalid experiment configuration")
+	}
 
 	modelBytes := []byte{}
 	var parentID *int
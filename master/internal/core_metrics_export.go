@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/metricsexport"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+// exportRunMetrics streams the metrics of the runs matching a SearchRuns filter as a single file.
+// Only runs whose metrics the user can view are included.
+func (m *Master) exportRunMetrics(c echo.Context) error {
+	args := struct {
+		ProjectID     *int    `query:"project_id"`
+		ExperimentIDs *string `query:"experiment_ids"`
+		Filter        *string `query:"filter"`
+		Format        *string `query:"format"`
+		MetricGroup   *string `query:"metric_group"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	user := c.(*detContext.DetContext).MustGetUser()
+
+	format := ""
+	if args.Format != nil {
+		format = *args.Format
+	}
+	var err error
+	opts := metricsexport.Options{}
+	if opts.Format, err = metricsexport.ParseFormat(format); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if args.MetricGroup != nil && *args.MetricGroup != "" {
+		opts.MetricGroup = model.MetricGroup(*args.MetricGroup)
+		if err := opts.MetricGroup.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	runIDs := db.Bun().NewSelect().
+		ModelTableExpr("runs AS r").
+		Column("r.id").
+		Join("LEFT JOIN experiments AS e ON r.experiment_id=e.id").
+		Join("LEFT JOIN runs_metadata AS rm ON r.id=rm.run_id").
+		Join("LEFT JOIN users u ON e.owner_id = u.id").
+		Join("LEFT JOIN projects p ON r.project_id = p.id").
+		Join("LEFT JOIN workspaces w ON p.workspace_id = w.id")
+
+	var proj *projectv1.Project
+	name := "runs"
+	if args.ProjectID != nil {
+		if proj, err = m.getProjectForUser(ctx, user, *args.ProjectID); err != nil {
+			return err
+		}
+		runIDs = runIDs.Where("r.project_id = ?", *args.ProjectID)
+		name = fmt.Sprintf("project%d", *args.ProjectID)
+	}
+	if args.ExperimentIDs != nil && *args.ExperimentIDs != "" {
+		var expIDs []int
+		for _, s := range strings.Split(*args.ExperimentIDs, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest,
+					fmt.Sprintf("invalid experiment ID %q", s))
+			}
+			expIDs = append(expIDs, id)
+		}
+		runIDs = runIDs.Where("r.experiment_id IN (?)", bun.In(expIDs))
+	}
+	if runIDs, err = expauth.AuthZProvider.Get().FilterExperimentsQuery(ctx, user, proj, runIDs,
+		[]rbacv1.PermissionType{
+			rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_METADATA,
+			rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_ARTIFACTS,
+		},
+	); err != nil {
+		return err
+	}
+	if args.Filter != nil && *args.Filter != "" {
+		if runIDs, err = filterRunQuery(runIDs, args.Filter); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	c.Response().Header().Set(
+		"Content-Disposition", fmt.Sprintf(`attachment; filename="%s_metrics.%s"`, name, opts.Format))
+	c.Response().Header().Set(echo.HeaderContentType, opts.Format.ContentType())
+	c.Response().WriteHeader(http.StatusOK)
+
+	return metricsexport.Export(ctx, c.Response(), runIDs, opts)
+}
@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/otlp"
+	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+const (
+	otlpProtobufContentType = "application/x-protobuf"
+	otlpJSONContentType     = "application/json"
+	// maxOTLPRequestSize is the largest uncompressed OTLP export the master accepts.
+	maxOTLPRequestSize = 16 << 20
+)
+
+// postOTLPMetrics accepts OTLP/HTTP metric exports from trials, authenticated with the allocation
+// token of the trial, and stores their gauges and sums as trial metrics.
+func (m *Master) postOTLPMetrics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	contentType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (contentType != otlpProtobufContentType && contentType != otlpJSONContentType) {
+		return c.String(http.StatusUnsupportedMediaType, fmt.Sprintf(
+			"expected a content type of %s or %s", otlpProtobufContentType, otlpJSONContentType))
+	}
+
+	session, err := grpcutil.AllocationSessionFromRequest(c.Request())
+	if err != nil {
+		return writeOTLPStatus(c, contentType, http.StatusUnauthorized, status.Convert(err))
+	}
+	trial, err := db.TrialByTaskID(ctx, session.AllocationID.ToTaskID())
+	if errors.Is(err, sql.ErrNoRows) {
+		return writeOTLPStatus(c, contentType, http.StatusForbidden, status.New(
+			codes.PermissionDenied, "only trials can report metrics"))
+	} else if err != nil {
+		return err
+	}
+	if session.OwnerID == nil {
+		return writeOTLPStatus(c, contentType, http.StatusForbidden, status.New(
+			codes.PermissionDenied, "allocation session has no associated user"))
+	}
+	owner, err := user.ByID(ctx, *session.OwnerID)
+	if err != nil {
+		return err
+	}
+	if err := trials.CanGetTrialsExperimentAndCheckCanDoAction(ctx, trial.ID,
+		ptrs.Ptr(owner.ToUser()), expauth.AuthZProvider.Get().CanEditExperiment); err != nil {
+		return writeOTLPStatus(c, contentType, http.StatusForbidden, status.Convert(err))
+	}
+
+	req, err := readOTLPMetrics(c.Request(), contentType)
+	if err != nil {
+		return writeOTLPStatus(c, contentType, http.StatusBadRequest,
+			status.New(codes.InvalidArgument, err.Error()))
+	}
+	reports, err := otlp.TrialMetrics(req, int32(trial.ID), int32(trial.RunID))
+	if err != nil {
+		return writeOTLPStatus(c, contentType, http.StatusBadRequest,
+			status.New(codes.InvalidArgument, err.Error()))
+	}
+	if err := m.db.AddTrialMetricsReports(ctx, reports); err != nil {
+		return writeOTLPStatus(c, contentType, http.StatusInternalServerError,
+			status.Newf(codes.Internal, "adding metrics of trial %d: %s", trial.ID, err))
+	}
+
+	return writeOTLP(c, contentType, http.StatusOK, &colmetricspb.ExportMetricsServiceResponse{})
+}
+
+func readOTLPMetrics(
+	r *http.Request, contentType string,
+) (*colmetricspb.ExportMetricsServiceRequest, error) {
+	body := r.Body
+	switch r.Header.Get(echo.HeaderContentEncoding) {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("reading gzip body: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q",
+			r.Header.Get(echo.HeaderContentEncoding))
+	}
+
+	bs, err := io.ReadAll(io.LimitReader(body, maxOTLPRequestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	if len(bs) > maxOTLPRequestSize {
+		return nil, fmt.Errorf("exports can be at most %d bytes", maxOTLPRequestSize)
+	}
+
+	req := &colmetricspb.ExportMetricsServiceRequest{}
+	if contentType == otlpJSONContentType {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(bs, req)
+	} else {
+		err = proto.Unmarshal(bs, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding export: %w", err)
+	}
+	return req, nil
+}
+
+// writeOTLPStatus writes an OTLP error response, which is a google.rpc.Status.
+func writeOTLPStatus(c echo.Context, contentType string, code int, s *status.Status) error {
+	return writeOTLP(c, contentType, code, s.Proto())
+}
+
+func writeOTLP(c echo.Context, contentType string, code int, msg proto.Message) error {
+	var bs []byte
+	var err error
+	if contentType == otlpJSONContentType {
+		bs, err = protojson.Marshal(msg)
+	} else {
+		bs, err = proto.Marshal(msg)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, contentType, bs)
+}
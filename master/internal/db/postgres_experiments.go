@@ -360,6 +360,11 @@ type MetricMeasurements struct {
 	Time    time.Time
 	Epoch   *float64 `json:"epoch,omitempty"`
 	TrialID int32
+	// MinValues, MaxValues and MeanValues are only set for measurements read from metric rollups,
+	// in which case Values holds the last values reported in the bucket.
+	MinValues  map[string]interface{} `json:"min_values,omitempty"`
+	MaxValues  map[string]interface{} `json:"max_values,omitempty"`
+	MeanValues map[string]interface{} `json:"mean_values,omitempty"`
 }
 
 // ExperimentBestSearcherValidation returns the best searcher validation for an experiment.
@@ -1046,7 +1051,7 @@ func InsertModelVersion(ctx context.Context, id int32, ckptID string, name strin
 	modVer := modelv1.ModelVersion{}
 	mv := Bun().NewInsert().
 		Model(&modVer).
-		ExcludeColumn("model", "checkpoint", "username", "id").
+		ExcludeColumn("model", "checkpoint", "username", "id", "aliases", "stage").
 		Value("model_id", "?", id).
 		Value("version", "(SELECT COALESCE(MAX(version), 0) + 1 FROM model_versions WHERE model_id = ?)", id).
 		Value("checkpoint_uuid", "?::uuid", ckptID).
@@ -1117,6 +1122,9 @@ func InsertModelVersion(ctx context.Context, id int32, ckptID string, name strin
 		Column("mv.comment").
 		Column("mv.metadata").
 		Column("u.username").
+		ColumnExpr("ARRAY_TO_JSON(mv.aliases) AS aliases").
+		ColumnExpr(bunutils.ProtoStateDBCaseString(modelv1.Stage_value, "mv.stage", "stage",
+			"STAGE_")).
 		Where("c.uuid = mv.checkpoint_uuid").Scan(ctx, &modVer)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// MetricRollupResolutions are the bucket widths, in batches, that metric rollups are kept at.
+var MetricRollupResolutions = []int{100, 1_000, 10_000, 100_000, 1_000_000}
+
+// metricRollupResolutionsSQL is MetricRollupResolutions as a Postgres array literal.
+var metricRollupResolutionsSQL = func() string {
+	resolutions := make([]string, 0, len(MetricRollupResolutions))
+	for _, r := range MetricRollupResolutions {
+		resolutions = append(resolutions, strconv.Itoa(r))
+	}
+	return "ARRAY[" + strings.Join(resolutions, ",") + "]::int[]"
+}()
+
+// MetricRollupResolution returns the finest rollup resolution that summarizes the batches
+// [startBatches, endBatches] in at most maxDatapoints buckets, or 0 if raw metrics should be read
+// because the range needs no more than maxDatapoints buckets of the finest resolution.
+func MetricRollupResolution(startBatches, endBatches, maxDatapoints int) int {
+	if maxDatapoints <= 0 || endBatches < startBatches {
+		return 0
+	}
+	span := endBatches - startBatches + 1
+	if span <= maxDatapoints*MetricRollupResolutions[0] {
+		return 0
+	}
+	for _, r := range MetricRollupResolutions {
+		if span <= maxDatapoints*r {
+			return r
+		}
+	}
+	return MetricRollupResolutions[len(MetricRollupResolutions)-1]
+}
+
+// MetricRollup is a summary of the values of a metric reported in a bucket of batches.
+type MetricRollup struct {
+	MetricName  string    `bun:"metric_name"`
+	Bucket      int       `bun:"bucket"`
+	Count       int       `bun:"count"`
+	Min         float64   `bun:"min"`
+	Max         float64   `bun:"max"`
+	Sum         float64   `bun:"sum"`
+	Last        float64   `bun:"last"`
+	LastBatches int       `bun:"last_batches"`
+	LastEndTime time.Time `bun:"last_end_time"`
+}
+
+// addMetricRollups adds the numeric metrics of a single report to the rollups of its trial. A
+// metric that was already rolled up at the same batch is not counted again, so reporting the same
+// metrics twice does not skew the rollups.
+func addMetricRollups(
+	ctx context.Context, tx *sqlx.Tx, trialID int32, mGroup model.MetricGroup, batches int32,
+	reportTime *time.Time, metrics *structpb.Struct,
+) error {
+	// protojson encodes NaN and infinities as strings, which are left out of rollups.
+	metricsJSON, err := protojson.Marshal(metrics)
+	if err != nil {
+		return errors.Wrap(err, "encoding metrics for rollups")
+	}
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO metric_rollups AS r (trial_id, metric_group, metric_name, resolution, bucket,
+	count, min, max, sum, last, last_batches, last_end_time)
+SELECT $1::int, $2::text, kv.key, l.resolution, ($3::int / l.resolution) * l.resolution,
+	1, v.value, v.value, v.value, v.value, $3::int, COALESCE($4::timestamptz, now())
+FROM jsonb_each($5::jsonb) kv
+CROSS JOIN LATERAL (SELECT kv.value::float8 AS value) v
+CROSS JOIN unnest(`+metricRollupResolutionsSQL+`) l(resolution)
+WHERE jsonb_typeof(kv.value) = 'number'
+ON CONFLICT (trial_id, metric_group, resolution, metric_name, bucket) DO UPDATE SET
+	count = r.count + 1,
+	min = LEAST(r.min, EXCLUDED.min),
+	max = GREATEST(r.max, EXCLUDED.max),
+	sum = r.sum + EXCLUDED.sum,
+	last = CASE WHEN EXCLUDED.last_batches > r.last_batches THEN EXCLUDED.last ELSE r.last END,
+	last_end_time = CASE WHEN EXCLUDED.last_batches > r.last_batches
+		THEN EXCLUDED.last_end_time ELSE r.last_end_time END,
+	last_batches = GREATEST(r.last_batches, EXCLUDED.last_batches)
+WHERE r.last_batches <> EXCLUDED.last_batches`,
+		trialID, mGroup, batches, reportTime, string(metricsJSON),
+	); err != nil {
+		return errors.Wrap(err, "updating metric rollups")
+	}
+	return nil
+}
+
+// rebuildMetricRollups recomputes the rollups of every metric group of a trial from its raw,
+// unarchived metrics, starting from the buckets that contain fromBatches.
+func rebuildMetricRollups(ctx context.Context, tx *sqlx.Tx, trialID int32, fromBatches int32) error {
+	if _, err := tx.ExecContext(ctx, `
+DELETE FROM metric_rollups
+WHERE trial_id = $1 AND bucket >= ($2::int / resolution) * resolution`, trialID, fromBatches,
+	); err != nil {
+		return errors.Wrap(err, "deleting metric rollups")
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO metric_rollups (trial_id, metric_group, metric_name, resolution, bucket,
+	count, min, max, sum, last, last_batches, last_end_time)
+SELECT m.trial_id, m.metric_group, kv.key, l.resolution,
+	(m.total_batches / l.resolution) * l.resolution AS bucket,
+	count(*), min(v.value), max(v.value), sum(v.value),
+	(array_agg(v.value ORDER BY m.total_batches DESC, m.id DESC))[1],
+	max(m.total_batches),
+	(array_agg(m.end_time ORDER BY m.total_batches DESC, m.id DESC))[1]
+FROM metrics m
+CROSS JOIN unnest(`+metricRollupResolutionsSQL+`) l(resolution)
+CROSS JOIN LATERAL jsonb_each(m.metrics->(
+	CASE WHEN m.partition_type = $3 THEN 'validation_metrics' ELSE 'avg_metrics' END)) kv
+CROSS JOIN LATERAL (SELECT kv.value::float8 AS value) v
+WHERE m.trial_id = $1
+AND NOT m.archived
+AND m.partition_type != $4
+AND m.total_batches >= ($2::int / l.resolution) * l.resolution
+AND jsonb_typeof(kv.value) = 'number'
+GROUP BY m.trial_id, m.metric_group, kv.key, l.resolution, bucket`,
+		trialID, fromBatches, ValidationMetric, ProfilingMetric,
+	); err != nil {
+		return errors.Wrap(err, "computing metric rollups")
+	}
+	return nil
+}
+
+// BackfillMetricRollups rebuilds the rollups of a trial from its raw metrics and marks them as
+// complete, so that sampling APIs read the trial's metrics from its rollups.
+func (db *PgDB) BackfillMetricRollups(ctx context.Context, trialID int) error {
+	return db.withTransaction(fmt.Sprintf("backfill metric rollups %d", trialID),
+		func(tx *sqlx.Tx) error {
+			// Lock the run the same way reporting metrics does, so no report lands mid-rebuild.
+			if _, err := tx.ExecContext(ctx,
+				`SELECT id FROM runs WHERE id = $1 FOR UPDATE`, trialID); err != nil {
+				return errors.Wrap(err, "locking run")
+			}
+			if err := rebuildMetricRollups(ctx, tx, int32(trialID), 0); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE runs SET metric_rollups_complete = true WHERE id = $1`, trialID); err != nil {
+				return errors.Wrap(err, "marking metric rollups complete")
+			}
+			return nil
+		})
+}
+
+// TrialsWithoutMetricRollups returns the ids of up to limit trials whose rollups are incomplete,
+// in ascending order, starting after the trial afterID.
+func TrialsWithoutMetricRollups(ctx context.Context, afterID, limit int) ([]int, error) {
+	var ids []int
+	if err := Bun().NewSelect().Table("runs").Column("id").
+		Where("NOT metric_rollups_complete").
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Scan(ctx, &ids); err != nil {
+		return nil, errors.Wrap(err, "listing trials without metric rollups")
+	}
+	return ids, nil
+}
+
+// MetricRollups returns the rollups of the given metrics of a trial at a resolution, for the
+// buckets whose last reported batch is in [startBatches, endBatches], ordered by bucket.
+func MetricRollups(
+	ctx context.Context, trialID int32, mGroup model.MetricGroup, metricNames []string,
+	resolution, startBatches, endBatches int,
+) ([]MetricRollup, error) {
+	var rollups []MetricRollup
+	if err := Bun().NewSelect().Table("metric_rollups").
+		Column("metric_name", "bucket", "count", "min", "max", "sum", "last", "last_batches",
+			"last_end_time").
+		Where("trial_id = ?", trialID).
+		Where("metric_group = ?", mGroup).
+		Where("resolution = ?", resolution).
+		Where("metric_name IN (?)", bun.In(metricNames)).
+		Where("last_batches >= ?", startBatches).
+		Where("last_batches <= ?", endBatches).
+		Order("bucket").
+		Scan(ctx, &rollups); err != nil {
+		return nil, errors.Wrapf(err, "getting metric rollups of trial %d", trialID)
+	}
+	return rollups, nil
+}
@@ -0,0 +1,85 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func TestMetricRollups(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	trialID := RequireMockTrialID(t, db, exp)
+
+	report := func(runID int, batches int, metricsJSON string) {
+		step := int32(batches)
+		require.NoError(t, db.AddTrainingMetrics(ctx, &trialv1.TrialMetrics{
+			TrialId:        int32(trialID),
+			TrialRunId:     int32(runID),
+			StepsCompleted: &step,
+			Metrics:        &commonv1.Metrics{AvgMetrics: jsonToStruct(t, metricsJSON)},
+		}))
+	}
+	rollups := func() []MetricRollup {
+		rollups, err := MetricRollups(ctx, int32(trialID), model.TrainingMetricGroup,
+			[]string{"loss"}, 100, 0, 1_000_000)
+		require.NoError(t, err)
+		return rollups
+	}
+
+	for b := 1; b <= 250; b++ {
+		report(0, b, fmt.Sprintf(`{"loss": %d, "note": "text"}`, b))
+	}
+	// Reporting the same metrics at the same batch again is not counted twice.
+	report(0, 250, `{"loss": 250}`)
+
+	type rollup struct{ bucket, count, min, max, last, lastBatches int }
+	expected := []rollup{
+		{0, 99, 1, 99, 99, 99},
+		{100, 100, 100, 199, 199, 199},
+		{200, 51, 200, 250, 250, 250},
+	}
+	check := func() {
+		actual := rollups()
+		require.Len(t, actual, len(expected))
+		for i, e := range expected {
+			require.Equal(t, "loss", actual[i].MetricName)
+			require.Equal(t, e.bucket, actual[i].Bucket)
+			require.Equal(t, e.count, actual[i].Count)
+			require.Equal(t, float64(e.min), actual[i].Min)
+			require.Equal(t, float64(e.max), actual[i].Max)
+			require.Equal(t, float64(e.last), actual[i].Last)
+			require.Equal(t, e.lastBatches, actual[i].LastBatches)
+		}
+	}
+	check()
+
+	// Backfilling rebuilds the same rollups from raw metrics.
+	require.NoError(t, db.BackfillMetricRollups(ctx, trialID))
+	check()
+
+	// Restarting from batch 150 drops everything reported after it from the rollups.
+	require.NoError(t, db.UpdateTrialFields(trialID, nil, 1, 0))
+	report(1, 150, `{"loss": -1}`)
+	expected = []rollup{
+		{0, 99, 1, 99, 99, 99},
+		{100, 51, -1, 149, -1, 150},
+	}
+	check()
+}
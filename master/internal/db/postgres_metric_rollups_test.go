@@ -0,0 +1,29 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricRollupResolution(t *testing.T) {
+	cases := []struct {
+		start, end, maxDatapoints int
+		resolution                int
+	}{
+		{0, 50_000, 1000, 0},
+		{0, 99_999, 1000, 0},
+		{0, 100_000, 1000, 1_000},
+		{0, 999_999, 1000, 1_000},
+		{0, 1_000_000, 1000, 10_000},
+		{900_000, 999_999, 1000, 0},
+		{0, 50_000_000, 200, 1_000_000},
+		{0, 10_000_000_000, 1000, 1_000_000},
+		{100, 50, 1000, 0},
+		{0, 1_000_000, 0, 0},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.resolution, MetricRollupResolution(c.start, c.end, c.maxDatapoints),
+			"%+v", c)
+	}
+}
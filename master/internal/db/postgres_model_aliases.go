@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+type modelAliasChange struct {
+	bun.BaseModel `bun:"table:model_alias_history"`
+
+	ID              int32     `bun:"id,pk,autoincrement"`
+	ModelID         int32     `bun:"model_id"`
+	Alias           string    `bun:"alias"`
+	PreviousVersion *int32    `bun:"previous_version"`
+	Version         *int32    `bun:"version"`
+	UserID          *int32    `bun:"user_id"`
+	Username        string    `bun:"username,scanonly"`
+	ChangeTime      time.Time `bun:"change_time,nullzero,default:current_timestamp"`
+}
+
+func (c modelAliasChange) Proto() *modelv1.ModelAliasChange {
+	return &modelv1.ModelAliasChange{
+		Id:              c.ID,
+		Alias:           c.Alias,
+		PreviousVersion: c.PreviousVersion,
+		Version:         c.Version,
+		Username:        c.Username,
+		ChangeTime:      timestamppb.New(c.ChangeTime),
+	}
+}
+
+// modelAliasVersion returns the number of the version an alias of a model points at, or nil if
+// the alias does not exist.
+func modelAliasVersion(
+	ctx context.Context, idb bun.IDB, modelID int32, alias string,
+) (*int32, error) {
+	var version int32
+	err := idb.NewSelect().
+		TableExpr("model_aliases AS a").
+		ColumnExpr("mv.version").
+		Join("JOIN model_versions AS mv ON mv.id = a.model_version_id").
+		Where("a.model_id = ?", modelID).
+		Where("a.alias = ?", alias).
+		Scan(ctx, &version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "error getting version of alias %q", alias)
+	}
+	return &version, nil
+}
+
+// lockModelAliases serializes changes to the aliases of a model for the rest of the transaction.
+func lockModelAliases(ctx context.Context, tx bun.Tx, modelID int32) error {
+	_, err := tx.NewSelect().
+		Table("models").
+		Column("id").
+		Where("id = ?", modelID).
+		For("UPDATE").
+		Exec(ctx)
+	return errors.Wrapf(err, "error locking aliases of model %d", modelID)
+}
+
+// SetModelAlias points an alias of a model at a model version, moving it if it already points at
+// another version, and records the move in the alias history.
+func SetModelAlias(
+	ctx context.Context, modelID int32, alias string, modelVersion *modelv1.ModelVersion,
+	userID model.UserID,
+) error {
+	return Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockModelAliases(ctx, tx, modelID); err != nil {
+			return err
+		}
+		previous, err := modelAliasVersion(ctx, tx, modelID, alias)
+		if err != nil {
+			return err
+		}
+		if previous != nil && *previous == modelVersion.Version {
+			return nil
+		}
+
+		if _, err := tx.NewInsert().
+			Table("model_aliases").
+			Value("model_id", "?", modelID).
+			Value("alias", "?", alias).
+			Value("model_version_id", "?", modelVersion.Id).
+			On("CONFLICT (model_id, alias) DO UPDATE").
+			Set("model_version_id = EXCLUDED.model_version_id").
+			Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error setting alias %q", alias)
+		}
+
+		uid := int32(userID)
+		if _, err := tx.NewInsert().Model(&modelAliasChange{
+			ModelID:         modelID,
+			Alias:           alias,
+			PreviousVersion: previous,
+			Version:         &modelVersion.Version,
+			UserID:          &uid,
+		}).Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error recording change of alias %q", alias)
+		}
+		return nil
+	})
+}
+
+// DeleteModelAlias removes an alias of a model and records the removal in the alias history. It
+// returns ErrNotFound if the alias does not exist.
+func DeleteModelAlias(
+	ctx context.Context, modelID int32, alias string, userID model.UserID,
+) error {
+	return Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockModelAliases(ctx, tx, modelID); err != nil {
+			return err
+		}
+		previous, err := modelAliasVersion(ctx, tx, modelID, alias)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return ErrNotFound
+		}
+
+		if _, err := tx.NewDelete().
+			Table("model_aliases").
+			Where("model_id = ?", modelID).
+			Where("alias = ?", alias).
+			Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error deleting alias %q", alias)
+		}
+
+		uid := int32(userID)
+		if _, err := tx.NewInsert().Model(&modelAliasChange{
+			ModelID:         modelID,
+			Alias:           alias,
+			PreviousVersion: previous,
+			UserID:          &uid,
+		}).Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error recording removal of alias %q", alias)
+		}
+		return nil
+	})
+}
+
+// ModelAliasVersion returns the number of the version an alias of a model points at. It returns
+// ErrNotFound if the alias does not exist.
+func ModelAliasVersion(ctx context.Context, modelID int32, alias string) (int32, error) {
+	version, err := modelAliasVersion(ctx, Bun(), modelID, alias)
+	if err != nil {
+		return 0, err
+	}
+	if version == nil {
+		return 0, ErrNotFound
+	}
+	return *version, nil
+}
+
+// ModelAliasHistory returns the changes to the aliases of a model, most recent first. If alias is
+// not empty, only changes to that alias are returned.
+func ModelAliasHistory(
+	ctx context.Context, modelID int32, alias string,
+) ([]*modelv1.ModelAliasChange, error) {
+	var changes []modelAliasChange
+	q := Bun().NewSelect().
+		Model(&changes).
+		ColumnExpr("model_alias_change.*").
+		ColumnExpr("COALESCE(u.username, '') AS username").
+		Join("LEFT JOIN users AS u ON u.id = model_alias_change.user_id").
+		Where("model_alias_change.model_id = ?", modelID).
+		OrderExpr("model_alias_change.id DESC")
+	if alias != "" {
+		q.Where("model_alias_change.alias = ?", alias)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, errors.Wrapf(err, "error getting alias history of model %d", modelID)
+	}
+
+	out := make([]*modelv1.ModelAliasChange, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, c.Proto())
+	}
+	return out, nil
+}
@@ -158,3 +158,92 @@ func TestModels(t *testing.T) {
 		})
 	}
 }
+
+func TestModelAliases(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	tr, task := RequireMockTrial(t, db, exp)
+	a := RequireMockAllocation(t, db, task.TaskID)
+
+	pmdl, err := InsertModel(ctx, uuid.NewString(), "", emptyMetadata, "", "", user.ID, 1)
+	require.NoError(t, err)
+
+	var versions []*modelv1.ModelVersion
+	for i := 0; i < 2; i++ {
+		ckpt := &model.CheckpointV2{
+			UUID:         uuid.New(),
+			TaskID:       task.TaskID,
+			AllocationID: &a.AllocationID,
+			ReportTime:   time.Now().UTC(),
+			State:        model.CompletedState,
+			Resources:    map[string]int64{"ok": 1.0},
+			Metadata:     map[string]interface{}{"steps_completed": i},
+		}
+		require.NoError(t, AddCheckpointMetadata(ctx, ckpt, tr.ID))
+		mv, err := InsertModelVersion(ctx, pmdl.Id, ckpt.UUID.String(), "", "",
+			emptyMetadata, "", "", user.ID)
+		require.NoError(t, err)
+		require.Equal(t, modelv1.Stage_STAGE_NONE, mv.Stage)
+		require.Empty(t, mv.Aliases)
+		versions = append(versions, mv)
+	}
+
+	requireAliases := func(version int32, expected []string) {
+		var mv modelv1.ModelVersion
+		require.NoError(t, db.QueryProto("get_model_version", &mv, pmdl.Id, version))
+		require.ElementsMatch(t, expected, mv.Aliases)
+	}
+
+	_, err = ModelAliasVersion(ctx, pmdl.Id, "production")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Create aliases, then move one of them to the other version.
+	require.NoError(t, SetModelAlias(ctx, pmdl.Id, "production", versions[0], user.ID))
+	require.NoError(t, SetModelAlias(ctx, pmdl.Id, "staging", versions[0], user.ID))
+	requireAliases(1, []string{"production", "staging"})
+
+	require.NoError(t, SetModelAlias(ctx, pmdl.Id, "production", versions[1], user.ID))
+	// Setting an alias to the version it already points at is not recorded.
+	require.NoError(t, SetModelAlias(ctx, pmdl.Id, "production", versions[1], user.ID))
+	requireAliases(1, []string{"staging"})
+	requireAliases(2, []string{"production"})
+
+	version, err := ModelAliasVersion(ctx, pmdl.Id, "production")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), version)
+
+	require.NoError(t, DeleteModelAlias(ctx, pmdl.Id, "staging", user.ID))
+	require.ErrorIs(t, DeleteModelAlias(ctx, pmdl.Id, "staging", user.ID), ErrNotFound)
+	requireAliases(1, nil)
+
+	history, err := ModelAliasHistory(ctx, pmdl.Id, "")
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	require.Equal(t, "staging", history[0].Alias)
+	require.Equal(t, int32(1), history[0].GetPreviousVersion())
+	require.Nil(t, history[0].Version)
+	require.Equal(t, user.Username, history[0].Username)
+
+	history, err = ModelAliasHistory(ctx, pmdl.Id, "production")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, int32(1), history[0].GetPreviousVersion())
+	require.Equal(t, int32(2), history[0].GetVersion())
+	require.Nil(t, history[1].PreviousVersion)
+	require.Equal(t, int32(1), history[1].GetVersion())
+
+	// Deleting a version removes the aliases that point at it but keeps their history.
+	var deleted modelv1.ModelVersion
+	require.NoError(t, db.QueryProto("delete_model_version", &deleted, versions[1].Id))
+	_, err = ModelAliasVersion(ctx, pmdl.Id, "production")
+	require.ErrorIs(t, err, ErrNotFound)
+	history, err = ModelAliasHistory(ctx, pmdl.Id, "production")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+}
@@ -294,6 +294,13 @@ func UpdateAllocationPorts(ctx context.Context, a model.Allocation) error {
 	return err
 }
 
+// UpdateAllocationSlots stores the number of slots the allocation was placed with.
+func UpdateAllocationSlots(ctx context.Context, a model.Allocation) error {
+	_, err := Bun().NewUpdate().Table("allocations").
+		Set("slots = ?", a.Slots).Where("allocation_id = ?", a.AllocationID).Exec(ctx)
+	return err
+}
+
 // UpdateAllocationStartTime stores the latest start time.
 func UpdateAllocationStartTime(ctx context.Context, a model.Allocation) error {
 	_, err := Bun().NewUpdate().Table("allocations").
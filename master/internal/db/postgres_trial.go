@@ -405,6 +405,26 @@ WHERE id = $1`, trialID).Scan(&runID, &restart); err != nil {
 	return runID, restart, nil
 }
 
+// AddTrialRestart records an automatic restart of a trial.
+func AddTrialRestart(ctx context.Context, r *model.RunRestart) error {
+	if _, err := Bun().NewInsert().Model(r).Exec(ctx); err != nil {
+		return fmt.Errorf("error recording restart of trial %d: %w", r.RunID, err)
+	}
+	return nil
+}
+
+// TrialRestarts returns the automatic restarts of a trial, oldest first.
+func TrialRestarts(ctx context.Context, trialID int) ([]*model.RunRestart, error) {
+	var restarts []*model.RunRestart
+	if err := Bun().NewSelect().Model(&restarts).
+		Where("run_id = ?", trialID).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting restarts for trial %d: %w", trialID, err)
+	}
+	return restarts, nil
+}
+
 // fullTrialSummaryMetricsRecompute recomputes all summary metrics for a given trial.
 func (db *PgDB) fullTrialSummaryMetricsRecompute(
 	ctx context.Context, tx *sqlx.Tx, trialID int,
@@ -646,6 +666,17 @@ WHERE id = $1;
 		}
 	}
 
+	switch {
+	case rollbacks != 0:
+		err = rebuildMetricRollups(ctx, tx, m.TrialId, m.GetStepsCompleted())
+	case m.StepsCompleted != nil:
+		err = addMetricRollups(ctx, tx, m.TrialId, mGroup, *m.StepsCompleted,
+			tryAsTime(m.ReportTime), m.Metrics.AvgMetrics)
+	}
+	if err != nil {
+		return rollbacks, err
+	}
+
 	if isValidation {
 		if err := setTrialBestValidation(
 			tx, int(m.TrialId),
@@ -657,27 +688,51 @@ WHERE id = $1;
 	return rollbacks, nil
 }
 
-// addTrialMetrics inserts a set of trial metrics to the database.
-func (db *PgDB) addTrialMetrics(
-	ctx context.Context, m *trialv1.TrialMetrics, mGroup model.MetricGroup,
+func (db *PgDB) addTrialMetricsTx(
+	ctx context.Context, tx *sqlx.Tx, m *trialv1.TrialMetrics, mGroup model.MetricGroup,
 ) (rollbacks int, err error) {
 	switch v := m.Metrics.AvgMetrics.Fields["epoch"].AsInterface().(type) {
 	case float64, nil:
 	default:
 		return 0, fmt.Errorf("cannot add metric with non numeric 'epoch' value got %v", v)
 	}
+	if slices.Contains(model.ProfilingMetricGroups, mGroup) {
+		return 0, db._addTrialProfilingMetricsTx(ctx, tx, m, mGroup)
+	}
+	return db._addTrialMetricsTx(ctx, tx, m, mGroup)
+}
+
+// addTrialMetrics inserts a set of trial metrics to the database.
+func (db *PgDB) addTrialMetrics(
+	ctx context.Context, m *trialv1.TrialMetrics, mGroup model.MetricGroup,
+) (rollbacks int, err error) {
 	return rollbacks, db.withTransaction(fmt.Sprintf("add trial metrics %s", mGroup),
 		func(tx *sqlx.Tx) error {
-			switch {
-			case slices.Contains(model.ProfilingMetricGroups, mGroup):
-				err = db._addTrialProfilingMetricsTx(ctx, tx, m, mGroup)
-			default:
-				rollbacks, err = db._addTrialMetricsTx(ctx, tx, m, mGroup)
-			}
+			rollbacks, err = db.addTrialMetricsTx(ctx, tx, m, mGroup)
 			return err
 		})
 }
 
+// TrialMetricsReport is a report of trial metrics of a metric group.
+type TrialMetricsReport struct {
+	Group   model.MetricGroup
+	Metrics *trialv1.TrialMetrics
+}
+
+// AddTrialMetricsReports persists several reports of trial metrics in a single transaction, so
+// either all of them are added or none are.
+func (db *PgDB) AddTrialMetricsReports(ctx context.Context, reports []TrialMetricsReport) error {
+	return db.withTransaction("add trial metrics reports", func(tx *sqlx.Tx) error {
+		for _, r := range reports {
+			if _, err := db.addTrialMetricsTx(ctx, tx, r.Metrics, r.Group); err != nil {
+				return fmt.Errorf("adding %s metrics at step %d: %w",
+					r.Group, r.Metrics.GetStepsCompleted(), err)
+			}
+		}
+		return nil
+	})
+}
+
 const (
 	// InfPostgresString how we store infinity in JSONB in postgres.
 	InfPostgresString = "Infinity"
@@ -736,6 +736,50 @@ func TestMetricMergeFail(t *testing.T) {
 	}
 }
 
+func TestAddTrialMetricsReportsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	trialID := RequireMockTrialID(t, db, exp)
+
+	report := func(step int32, metricsJSON string, group model.MetricGroup) TrialMetricsReport {
+		return TrialMetricsReport{
+			Group: group,
+			Metrics: &trialv1.TrialMetrics{
+				TrialId:        int32(trialID),
+				StepsCompleted: &step,
+				Metrics:        &commonv1.Metrics{AvgMetrics: jsonToStruct(t, metricsJSON)},
+			},
+		}
+	}
+
+	require.NoError(t, db.AddTrialMetricsReports(ctx, []TrialMetricsReport{
+		report(1, `{"a":1.0}`, model.TrainingMetricGroup),
+		report(1, `{"b":1.0}`, model.ValidationMetricGroup),
+	}))
+	metrics, err := GetMetrics(ctx, trialID, 0, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	// The second report overwrites a metric, so the first one is not added either.
+	err = db.AddTrialMetricsReports(ctx, []TrialMetricsReport{
+		report(2, `{"a":2.0}`, model.TrainingMetricGroup),
+		report(1, `{"b":2.0}`, model.ValidationMetricGroup),
+	})
+	require.ErrorContains(t, err, "adding validation metrics at step 1")
+	metrics, err = GetMetrics(ctx, trialID, 0, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	for _, m := range metrics {
+		require.Equal(t, int32(1), m.TotalBatches)
+	}
+}
+
 func TestLatestMetricID(t *testing.T) {
 	ctx := context.Background()
 	require.NoError(t, etc.SetRootPath(RootFromDB))
@@ -0,0 +1,255 @@
+// Package deployment serves model versions with replicas that run as NTSC tasks behind a stable,
+// load-balanced proxy route, and keeps the replicas in line with the replica count and model
+// version of their deployment.
+package deployment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// State is the state of a deployment.
+type State string
+
+const (
+	// StateActive is the state of deployments that keep their replicas running.
+	StateActive State = "ACTIVE"
+	// StateDeleting is the state of deployments whose replicas are being stopped.
+	StateDeleting State = "DELETING"
+	// StateDeleted is the state of deployments all replicas of which stopped.
+	StateDeleted State = "DELETED"
+)
+
+// Proto converts a state to its proto representation.
+func (s State) Proto() apiv1.DeploymentState {
+	switch s {
+	case StateActive:
+		return apiv1.DeploymentState_DEPLOYMENT_STATE_ACTIVE
+	case StateDeleting:
+		return apiv1.DeploymentState_DEPLOYMENT_STATE_DELETING
+	case StateDeleted:
+		return apiv1.DeploymentState_DEPLOYMENT_STATE_DELETED
+	default:
+		return apiv1.DeploymentState_DEPLOYMENT_STATE_UNSPECIFIED
+	}
+}
+
+const (
+	// MaxReplicas is the most replicas a deployment can run.
+	MaxReplicas = 100
+	// routePrefix distinguishes the proxy routes of deployments from the IDs of services.
+	routePrefix = "deployment-"
+)
+
+// ErrNotActive is returned when changing a deployment that is being or was deleted.
+var ErrNotActive = errors.New("deployment is deleted")
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Deployment is a row of the deployments table.
+type Deployment struct {
+	bun.BaseModel `bun:"table:deployments,alias:d"`
+
+	ID             int                      `bun:"id,pk,autoincrement"`
+	Name           string                   `bun:"name"`
+	ModelID        int                      `bun:"model_id"`
+	ModelVersion   int                      `bun:"model_version"`
+	CheckpointUUID uuid.UUID                `bun:"checkpoint_uuid"`
+	Replicas       int                      `bun:"replicas"`
+	Spec           tasks.GenericCommandSpec `bun:"spec"`
+	State          State                    `bun:"state"`
+	WorkspaceID    int                      `bun:"workspace_id"`
+	UserID         model.UserID             `bun:"user_id"`
+	CreatedAt      time.Time                `bun:"created_at"`
+	UpdatedAt      time.Time                `bun:"updated_at"`
+
+	ModelName string `bun:"model_name,scanonly"`
+}
+
+// RouteID returns the ID of the proxy route of a deployment.
+func RouteID(name string) string {
+	return routePrefix + name
+}
+
+// Proto converts a deployment and the statuses of its replicas to its proto representation.
+func (d *Deployment) Proto(replicas []*apiv1.DeploymentReplica) *apiv1.Deployment {
+	if replicas == nil {
+		replicas = []*apiv1.DeploymentReplica{}
+	}
+	return &apiv1.Deployment{
+		Id:             int32(d.ID),
+		Name:           d.Name,
+		ModelId:        int32(d.ModelID),
+		ModelName:      d.ModelName,
+		ModelVersion:   int32(d.ModelVersion),
+		CheckpointUuid: d.CheckpointUUID.String(),
+		TargetReplicas: int32(d.Replicas),
+		State:          d.State.Proto(),
+		WorkspaceId:    int32(d.WorkspaceID),
+		UserId:         int32(d.UserID),
+		Route:          fmt.Sprintf("/proxy/%s/", RouteID(d.Name)),
+		CreatedAt:      timestamppb.New(d.CreatedAt),
+		UpdatedAt:      timestamppb.New(d.UpdatedAt),
+		Replicas:       replicas,
+	}
+}
+
+// Replica is a row of the deployment_replicas table, a replica that has not exited yet.
+type Replica struct {
+	bun.BaseModel `bun:"table:deployment_replicas"`
+
+	TaskID       model.TaskID `bun:"task_id,pk"`
+	DeploymentID int          `bun:"deployment_id"`
+	ModelVersion int          `bun:"model_version"`
+	Stopping     bool         `bun:"stopping"`
+	CreatedAt    time.Time    `bun:"created_at,nullzero,default:now()"`
+}
+
+func validate(d *Deployment) error {
+	if !nameRegex.MatchString(d.Name) {
+		return fmt.Errorf("%w: deployment names must be at most 63 lowercase letters, digits and "+
+			"dashes, and start and end with a letter or digit", db.ErrInvalidInput)
+	}
+	if d.Replicas < 0 || d.Replicas > MaxReplicas {
+		return fmt.Errorf("%w: deployments must run between 0 and %d replicas",
+			db.ErrInvalidInput, MaxReplicas)
+	}
+	return nil
+}
+
+// Create persists a new, active deployment, returning db.ErrDuplicateRecord if another deployment
+// that is not deleted has the same name.
+func Create(ctx context.Context, d *Deployment) error {
+	if err := validate(d); err != nil {
+		return err
+	}
+	d.State = StateActive
+	d.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	d.UpdatedAt = d.CreatedAt
+	if _, err := db.Bun().NewInsert().Model(d).Returning("id").Exec(ctx); err != nil {
+		return fmt.Errorf("creating deployment %s: %w", d.Name, db.MatchSentinelError(err))
+	}
+	return nil
+}
+
+func query(ds any) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(ds).
+		ColumnExpr("d.*").
+		ColumnExpr("m.name AS model_name").
+		Join("JOIN models m ON m.id = d.model_id")
+}
+
+// ByID returns a deployment, returning db.ErrNotFound if it does not exist.
+func ByID(ctx context.Context, id int) (*Deployment, error) {
+	var d Deployment
+	if err := query(&d).Where("d.id = ?", id).Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &d, nil
+}
+
+// List returns the deployments that are not deleted, of a workspace or of all workspaces if it is
+// 0, ordered by ID.
+func List(ctx context.Context, workspaceID int) ([]*Deployment, error) {
+	ds := []*Deployment{}
+	q := query(&ds).Where("d.state != ?", StateDeleted).Order("d.id")
+	if workspaceID != 0 {
+		q = q.Where("d.workspace_id = ?", workspaceID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting deployments: %w", err)
+	}
+	return ds, nil
+}
+
+// Update persists the replica count, model version and spec of an active deployment, returning
+// ErrNotActive if it is being or was deleted.
+func Update(ctx context.Context, d *Deployment) error {
+	if err := validate(d); err != nil {
+		return err
+	}
+	d.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	res, err := db.Bun().NewUpdate().Model(d).
+		Column("replicas", "model_version", "checkpoint_uuid", "spec", "updated_at").
+		WherePK().
+		Where("state = ?", StateActive).
+		Exec(ctx)
+	if err := db.MustHaveAffectedRows(res, err); errors.Is(err, db.ErrNotFound) {
+		return ErrNotActive
+	} else if err != nil {
+		return fmt.Errorf("updating deployment %d: %w", d.ID, err)
+	}
+	return nil
+}
+
+// Delete marks an active deployment for deletion, returning ErrNotActive if it is being or was
+// already deleted. The deployment is deleted once its replicas stopped.
+func Delete(ctx context.Context, id int) error {
+	return setState(ctx, id, StateActive, StateDeleting)
+}
+
+func setState(ctx context.Context, id int, from, to State) error {
+	res, err := db.Bun().NewUpdate().Model((*Deployment)(nil)).
+		Set("state = ?", to).
+		Set("updated_at = now()").
+		Where("id = ?", id).
+		Where("state = ?", from).
+		Exec(ctx)
+	if err := db.MustHaveAffectedRows(res, err); errors.Is(err, db.ErrNotFound) {
+		return ErrNotActive
+	} else if err != nil {
+		return fmt.Errorf("setting state of deployment %d to %s: %w", id, to, err)
+	}
+	return nil
+}
+
+// Replicas returns the replicas of a deployment, oldest first.
+func Replicas(ctx context.Context, deploymentID int) ([]*Replica, error) {
+	rs := []*Replica{}
+	err := db.Bun().NewSelect().Model(&rs).
+		Where("deployment_id = ?", deploymentID).
+		Order("created_at", "task_id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting replicas of deployment %d: %w", deploymentID, err)
+	}
+	return rs, nil
+}
+
+func addReplica(ctx context.Context, r *Replica) error {
+	if _, err := db.Bun().NewInsert().Model(r).Returning("created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("adding replica %s: %w", r.TaskID, err)
+	}
+	return nil
+}
+
+func markStopping(ctx context.Context, taskID model.TaskID) error {
+	_, err := db.Bun().NewUpdate().Model((*Replica)(nil)).
+		Set("stopping = true").
+		Where("task_id = ?", taskID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("stopping replica %s: %w", taskID, err)
+	}
+	return nil
+}
+
+func removeReplica(ctx context.Context, taskID model.TaskID) error {
+	_, err := db.Bun().NewDelete().Model((*Replica)(nil)).Where("task_id = ?", taskID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("removing replica %s: %w", taskID, err)
+	}
+	return nil
+}
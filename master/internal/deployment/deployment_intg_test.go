@@ -0,0 +1,112 @@
+//go:build integration
+
+package deployment
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+var pgDB *db.PgDB
+
+func TestMain(m *testing.M) {
+	var err error
+	pgDB, _, err = db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+	if err := db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up"); err != nil {
+		log.Panicln(err)
+	}
+	os.Exit(m.Run())
+}
+
+func requireMockModel(t *testing.T, user model.User) (int, string) {
+	name := uuid.NewString()
+	var id int
+	err := db.Bun().NewRaw(`
+INSERT INTO models (name, workspace_id, creation_time, user_id)
+VALUES (?, 1, NOW(), ?) RETURNING id`, name, user.ID).Scan(context.Background(), &id)
+	require.NoError(t, err)
+	return id, name
+}
+
+func TestDeploymentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, pgDB)
+	modelID, modelName := requireMockModel(t, user)
+
+	d := &Deployment{
+		Name:           "d" + uuid.NewString()[:8],
+		ModelID:        modelID,
+		ModelVersion:   1,
+		CheckpointUUID: uuid.New(),
+		Replicas:       2,
+		WorkspaceID:    1,
+		UserID:         user.ID,
+	}
+	require.NoError(t, Create(ctx, d))
+	require.NotZero(t, d.ID)
+
+	dup := *d
+	require.ErrorIs(t, Create(ctx, &dup), db.ErrDuplicateRecord)
+
+	actual, err := ByID(ctx, d.ID)
+	require.NoError(t, err)
+	require.Equal(t, modelName, actual.ModelName)
+	require.Equal(t, StateActive, actual.State)
+	require.Equal(t, d.CheckpointUUID, actual.CheckpointUUID)
+
+	ds, err := List(ctx, 1)
+	require.NoError(t, err)
+	require.Contains(t, ids(ds), d.ID)
+
+	actual.Replicas = 3
+	actual.ModelVersion = 2
+	require.NoError(t, Update(ctx, actual))
+	actual, err = ByID(ctx, d.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, actual.Replicas)
+	require.Equal(t, 2, actual.ModelVersion)
+
+	// Replicas are tracked until they exit.
+	task := db.RequireMockTask(t, pgDB, &user.ID)
+	require.NoError(t, addReplica(ctx, &Replica{
+		TaskID: task.TaskID, DeploymentID: d.ID, ModelVersion: 2,
+	}))
+	require.NoError(t, markStopping(ctx, task.TaskID))
+	replicas, err := Replicas(ctx, d.ID)
+	require.NoError(t, err)
+	require.Len(t, replicas, 1)
+	require.True(t, replicas[0].Stopping)
+	require.NoError(t, removeReplica(ctx, task.TaskID))
+	replicas, err = Replicas(ctx, d.ID)
+	require.NoError(t, err)
+	require.Empty(t, replicas)
+
+	// Deleted deployments cannot change, and free their names once their replicas stopped.
+	require.NoError(t, Delete(ctx, d.ID))
+	require.ErrorIs(t, Delete(ctx, d.ID), ErrNotActive)
+	require.ErrorIs(t, Update(ctx, actual), ErrNotActive)
+	require.NoError(t, setState(ctx, d.ID, StateDeleting, StateDeleted))
+	ds, err = List(ctx, 1)
+	require.NoError(t, err)
+	require.NotContains(t, ids(ds), d.ID)
+	require.NoError(t, Create(ctx, &dup))
+}
+
+func ids(ds []*Deployment) []int {
+	var res []int
+	for _, d := range ds {
+		res = append(res, d.ID)
+	}
+	return res
+}
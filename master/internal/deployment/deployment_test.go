@@ -0,0 +1,87 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func replica(id string, version int, ready bool) replicaStatus {
+	return replicaStatus{
+		Replica: &Replica{TaskID: model.TaskID(id), ModelVersion: version},
+		ready:   ready,
+	}
+}
+
+func TestPlanScales(t *testing.T) {
+	d := &Deployment{ModelVersion: 1, Replicas: 3, State: StateActive}
+
+	launch, stop, route := plan(d, nil)
+	require.Equal(t, 3, launch)
+	require.Empty(t, stop)
+	require.Empty(t, route)
+
+	launch, stop, route = plan(d, []replicaStatus{replica("a", 1, true), replica("b", 1, false)})
+	require.Equal(t, 1, launch)
+	require.Empty(t, stop)
+	require.Equal(t, []string{"a"}, route)
+
+	// Scaling down stops replicas that are not ready first, then the newest.
+	d.Replicas = 1
+	launch, stop, route = plan(d, []replicaStatus{
+		replica("a", 1, true), replica("b", 1, true), replica("c", 1, false),
+	})
+	require.Equal(t, 0, launch)
+	require.Equal(t, []model.TaskID{"c", "b"}, stop)
+	require.Equal(t, []string{"a"}, route)
+}
+
+func TestPlanRollsVersions(t *testing.T) {
+	d := &Deployment{ModelVersion: 2, Replicas: 2, State: StateActive}
+
+	// Previous replicas keep serving until the new ones are ready.
+	launch, stop, route := plan(d, []replicaStatus{replica("a", 1, true), replica("b", 1, true)})
+	require.Equal(t, 2, launch)
+	require.Empty(t, stop)
+	require.Equal(t, []string{"a", "b"}, route)
+
+	launch, stop, route = plan(d, []replicaStatus{
+		replica("a", 1, true), replica("b", 1, true), replica("c", 2, true), replica("d", 2, false),
+	})
+	require.Equal(t, 0, launch)
+	require.Equal(t, []model.TaskID{"b"}, stop)
+	require.Equal(t, []string{"a", "c"}, route)
+
+	launch, stop, route = plan(d, []replicaStatus{
+		replica("a", 1, true), replica("c", 2, true), replica("d", 2, true),
+	})
+	require.Equal(t, 0, launch)
+	require.Equal(t, []model.TaskID{"a"}, stop)
+	require.Equal(t, []string{"c", "d"}, route)
+}
+
+func TestPlanStopsDeleted(t *testing.T) {
+	d := &Deployment{ModelVersion: 2, Replicas: 2, State: StateDeleting}
+	stopping := replica("c", 2, false)
+	stopping.Stopping = true
+
+	launch, stop, route := plan(d, []replicaStatus{
+		replica("a", 1, true), replica("b", 2, true), stopping,
+	})
+	require.Equal(t, 0, launch)
+	require.ElementsMatch(t, []model.TaskID{"a", "b"}, stop)
+	require.Empty(t, route)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, validate(&Deployment{Name: "bert-base-2", Replicas: 1}))
+	for _, name := range []string{"", "Bert", "-bert", "bert-", "bert_base", string(make([]byte, 64))} {
+		require.ErrorIs(t, validate(&Deployment{Name: name, Replicas: 1}), db.ErrInvalidInput, name)
+	}
+	require.ErrorIs(t, validate(&Deployment{Name: "bert", Replicas: -1}), db.ErrInvalidInput)
+	require.ErrorIs(t, validate(&Deployment{Name: "bert", Replicas: MaxReplicas + 1}),
+		db.ErrInvalidInput)
+}
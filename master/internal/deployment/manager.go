@@ -0,0 +1,355 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	reconcileInterval = 5 * time.Second
+	minRestartDelay   = 10 * time.Second
+	maxRestartDelay   = 5 * time.Minute
+	// Agent ports 2600 - 3500 are split between TensorBoards, Notebooks, and Shells, and replicas
+	// of deployments serve on the ones above.
+	minPort = 3500
+	maxPort = minPort + 299
+	// PortEnvVar is the environment variable holding the port replicas must serve on.
+	PortEnvVar = "DET_DEPLOYMENT_PORT"
+)
+
+// DefaultManager is the global deployment manager singleton.
+var DefaultManager *Manager
+
+// SetDefaultManager sets the global deployment manager.
+func SetDefaultManager(m *Manager) {
+	if DefaultManager != nil {
+		logrus.Warn(
+			"detected re-initialization of the deployment manager that should never occur outside of tests",
+		)
+	}
+	DefaultManager = m
+}
+
+// restartBackoff delays the replacement of replicas that exited on their own, so that replicas
+// that crash on startup are not relaunched in a tight loop.
+type restartBackoff struct {
+	delay time.Duration
+	until time.Time
+}
+
+// Manager keeps the replicas of deployments in line with their replica count and model version,
+// and the proxy routes of deployments pointed at their ready replicas.
+//
+// Replicas of the current model version are launched until there are as many as the deployment
+// asks for. Replicas of previous versions are stopped as replicas of the current version become
+// ready, so that a rolling update never serves with fewer ready replicas than before.
+type Manager struct {
+	cs     *command.CommandService
+	proxy  *proxy.Proxy
+	wake   chan struct{}
+	syslog *logrus.Entry
+
+	// backoffs is only accessed by the reconcile loop.
+	backoffs map[int]*restartBackoff
+}
+
+// NewManager returns a manager that launches replicas through a command service and routes to
+// them through a proxy.
+func NewManager(cs *command.CommandService, p *proxy.Proxy) *Manager {
+	return &Manager{
+		cs:       cs,
+		proxy:    p,
+		wake:     make(chan struct{}, 1),
+		syslog:   logrus.WithField("component", "deployment-manager"),
+		backoffs: make(map[int]*restartBackoff),
+	}
+}
+
+// Run reconciles deployments periodically, and whenever Wake is called, until the context is
+// canceled. Replicas restored by the command service are picked up again on the first pass.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		m.reconcileAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+	}
+}
+
+// Wake makes the manager reconcile deployments without waiting for the next pass, after a
+// deployment was created or changed.
+func (m *Manager) Wake() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ReplicaStatuses returns the statuses of the replicas of a deployment.
+func (m *Manager) ReplicaStatuses(
+	ctx context.Context, deploymentID int,
+) ([]*apiv1.DeploymentReplica, error) {
+	replicas, err := Replicas(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []*apiv1.DeploymentReplica{}
+	for _, r := range replicas {
+		state, ok := m.cs.NTSCState(r.TaskID)
+		if !ok {
+			continue
+		}
+		statuses = append(statuses, &apiv1.DeploymentReplica{
+			TaskId:       string(r.TaskID),
+			ModelVersion: int32(r.ModelVersion),
+			State:        state.State.Proto(),
+			Ready:        isReady(state),
+			Stopping:     r.Stopping,
+		})
+	}
+	return statuses, nil
+}
+
+func (m *Manager) reconcileAll(ctx context.Context) {
+	ds := []*Deployment{}
+	if err := query(&ds).Where("d.state != ?", StateDeleted).Scan(ctx); err != nil {
+		m.syslog.WithError(err).Error("failed to get deployments")
+		return
+	}
+	for _, d := range ds {
+		if err := m.reconcile(ctx, d); err != nil {
+			m.syslog.WithError(err).Errorf("failed to reconcile deployment %s", d.Name)
+		}
+	}
+}
+
+func (m *Manager) reconcile(ctx context.Context, d *Deployment) error {
+	replicas, err := Replicas(ctx, d.ID)
+	if err != nil {
+		return err
+	}
+	var statuses []replicaStatus
+	for _, r := range replicas {
+		state, ok := m.cs.NTSCState(r.TaskID)
+		if !ok {
+			if err := removeReplica(ctx, r.TaskID); err != nil {
+				return err
+			}
+			if !r.Stopping && d.State == StateActive {
+				m.backOff(d)
+			}
+			continue
+		}
+		statuses = append(statuses, replicaStatus{Replica: r, ready: isReady(state)})
+	}
+
+	launch, stop, route := plan(d, statuses)
+	for _, id := range stop {
+		if err := markStopping(ctx, id); err != nil {
+			return err
+		}
+		if _, err := m.cs.KillNTSC(string(id), model.TaskTypeDeployment); err != nil {
+			m.syslog.WithError(err).Warnf("failed to stop replica %s of deployment %s", id, d.Name)
+		}
+	}
+
+	if d.State != StateActive {
+		m.proxy.UnregisterRoute(RouteID(d.Name))
+		if len(statuses) == 0 {
+			m.syslog.Infof("deleted deployment %s", d.Name)
+			delete(m.backoffs, d.ID)
+			return setState(ctx, d.ID, StateDeleting, StateDeleted)
+		}
+		return nil
+	}
+	m.proxy.RegisterRoute(RouteID(d.Name), route)
+
+	if b, ok := m.backoffs[d.ID]; ok {
+		// The delay is reset once the deployment is back to full strength.
+		if launch == 0 && len(route) >= d.Replicas {
+			delete(m.backoffs, d.ID)
+		} else if time.Now().Before(b.until) {
+			return nil
+		}
+	}
+	for i := 0; i < launch; i++ {
+		if err := m.launch(ctx, d); err != nil {
+			m.backOff(d)
+			return err
+		}
+	}
+	return nil
+}
+
+// backOff delays the next replica launches of a deployment, doubling the delay every time.
+func (m *Manager) backOff(d *Deployment) {
+	b, ok := m.backoffs[d.ID]
+	if !ok {
+		b = &restartBackoff{delay: minRestartDelay / 2}
+		m.backoffs[d.ID] = b
+	}
+	b.delay = min(2*b.delay, maxRestartDelay)
+	b.until = time.Now().Add(b.delay)
+	m.syslog.Warnf("delaying new replicas of deployment %s for %s", d.Name, b.delay)
+}
+
+// launch starts a replica of the current model version of a deployment.
+func (m *Manager) launch(ctx context.Context, d *Deployment) error {
+	owner, err := user.ByID(ctx, d.UserID)
+	if err != nil {
+		return fmt.Errorf("getting owner of deployment %s: %w", d.Name, err)
+	}
+	if !owner.Active {
+		return fmt.Errorf("owner %s of deployment %s is deactivated", owner.Username, d.Name)
+	}
+
+	// Every replica gets its own copy of the spec, since commands keep theirs.
+	var spec tasks.GenericCommandSpec
+	bs, err := json.Marshal(d.Spec)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bs, &spec); err != nil {
+		return err
+	}
+	// Replicas outlive the sessions of the requests that created them, so each gets a new one
+	// on behalf of the owner, unless sessions are managed outside the master. The command deletes
+	// the session when the replica exits; one that never launches must delete it here.
+	var token string
+	if !config.GetMasterConfig().InternalConfig.ExternalSessions.Enabled() {
+		if token, err = user.StartSession(ctx, ptrs.Ptr(owner.ToUser()), user.WithTaskSession()); err != nil {
+			return fmt.Errorf("starting session for replica of deployment %s: %w", d.Name, err)
+		}
+		spec.Base.UserSessionToken = token
+	}
+	// Selecting a random port mitigates the risk of replicas on the same agent binding the same
+	// port in host mode.
+	//nolint:gosec // Weak RNG doesn't matter here.
+	port := rand.Intn(maxPort-minPort) + minPort
+	if spec.Base.ExtraEnvVars == nil {
+		spec.Base.ExtraEnvVars = map[string]string{}
+	}
+	spec.Base.ExtraEnvVars[PortEnvVar] = strconv.Itoa(port)
+	spec.Base.ExtraProxyPorts = append(spec.Base.ExtraProxyPorts, expconf.ProxyPort{
+		RawProxyPort:        port,
+		RawDefaultServiceID: ptrs.Ptr(true),
+	})
+
+	if _, err := m.cs.LaunchGenericCommand(
+		model.TaskTypeDeployment, model.JobTypeDeployment, &command.CreateGeneric{Spec: &spec},
+	); err != nil {
+		if token != "" {
+			if dErr := user.DeleteSessionByToken(ctx, token); dErr != nil {
+				m.syslog.WithError(dErr).Errorf(
+					"failed to delete session of unlaunched replica of deployment %s", d.Name)
+			}
+		}
+		return fmt.Errorf("launching replica of deployment %s: %w", d.Name, err)
+	}
+	taskID := model.TaskID(spec.CommandID)
+	m.syslog.Infof("launched replica %s of deployment %s (version %d)",
+		taskID, d.Name, d.ModelVersion)
+	err = addReplica(ctx, &Replica{
+		TaskID:       taskID,
+		DeploymentID: d.ID,
+		ModelVersion: d.ModelVersion,
+	})
+	if err != nil {
+		// A replica the deployment does not know about would never be stopped.
+		if _, kErr := m.cs.KillNTSC(string(taskID), model.TaskTypeDeployment); kErr != nil {
+			m.syslog.WithError(kErr).Errorf("failed to stop untracked replica %s", taskID)
+		}
+		return err
+	}
+	return nil
+}
+
+func isReady(state task.AllocationState) bool {
+	return state.State == model.AllocationStateRunning && state.Ready
+}
+
+// replicaStatus is a replica that is still running, or starting.
+type replicaStatus struct {
+	*Replica
+	ready bool
+}
+
+// plan returns how many replicas of the current model version of a deployment to launch, which
+// replicas to stop, and the replicas to route requests to. Replicas are given oldest first.
+func plan(d *Deployment, replicas []replicaStatus) (launch int, stop []model.TaskID, route []string) {
+	var current, previous []replicaStatus
+	for _, r := range replicas {
+		switch {
+		case r.Stopping:
+		case r.ModelVersion == d.ModelVersion:
+			current = append(current, r)
+		default:
+			previous = append(previous, r)
+		}
+	}
+
+	if d.State != StateActive {
+		for _, r := range append(current, previous...) {
+			stop = append(stop, r.TaskID)
+		}
+		return 0, stop, nil
+	}
+
+	// Replicas that are not ready are stopped first, then the newest ones.
+	byStopOrder := func(rs []replicaStatus) {
+		slices.Reverse(rs)
+		sort.SliceStable(rs, func(i, j int) bool { return !rs[i].ready && rs[j].ready })
+	}
+	readyCurrent := 0
+	for _, r := range current {
+		if r.ready {
+			readyCurrent++
+		}
+	}
+
+	keep := func(rs []replicaStatus, n int) []replicaStatus {
+		if len(rs) <= n {
+			return rs
+		}
+		byStopOrder(rs)
+		for _, r := range rs[:len(rs)-n] {
+			stop = append(stop, r.TaskID)
+		}
+		return rs[len(rs)-n:]
+	}
+	current = keep(current, d.Replicas)
+	previous = keep(previous, max(0, d.Replicas-readyCurrent))
+	launch = max(0, d.Replicas-len(current))
+
+	for _, r := range append(previous, current...) {
+		if r.ready {
+			route = append(route, string(r.TaskID))
+		}
+	}
+	sort.Strings(route)
+	return launch, stop, route
+}
@@ -121,10 +121,11 @@ func newExperiment(
 			ResourcePool: poolName.String(),
 			Slots:        resources.SlotsPerTrial(),
 			IsSingleNode: resources.IsSingleNode() != nil && *resources.IsSingleNode(),
+			PodSpec:      activeConfig.Environment().PodSpec(),
 		}); err != nil {
 			return nil, nil, fmt.Errorf("validating resources: %v", err)
 		}
-		if m.config.LaunchError && len(launchWarnings) > 0 {
+		if config.GetMasterConfig().LaunchError && len(launchWarnings) > 0 {
 			return nil, nil, errors.New("slots requested exceeds cluster capacity")
 		}
 	}
@@ -0,0 +1,398 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+// parseFilter parses a filter given either as the JSON tree sent by the web UI or as a query string
+// such as `hp.lr > 1e-4 AND validation.loss.min < 0.3 AND state = COMPLETED`. runs selects
+// whether columns refer to runs or to experiments.
+func parseFilter(filter string, runs bool) (experimentFilterRoot, error) {
+	var efr experimentFilterRoot
+	if strings.HasPrefix(strings.TrimSpace(filter), "{") {
+		if err := json.Unmarshal([]byte(filter), &efr); err != nil {
+			return efr, err
+		}
+		return efr, nil
+	}
+
+	group, err := parseFilterQuery(filter, runs)
+	if err != nil {
+		return efr, status.Errorf(codes.InvalidArgument, "invalid filter: %s", err)
+	}
+	efr.FilterGroup = *group
+	return efr, nil
+}
+
+type filterTokenKind int
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	// col is the 1-based column the token starts at.
+	col int
+	// quoted is set for identifiers written in backticks, which are never keywords.
+	quoted bool
+}
+
+// keyword returns the upper-cased keyword the token spells, or "" if it is not a keyword.
+func (t filterToken) keyword() string {
+	if t.kind != tokenIdent || t.quoted {
+		return ""
+	}
+	switch k := strings.ToUpper(t.text); k {
+	case "AND", "OR", "NOT", "CONTAINS", "IS", "EMPTY", "TRUE", "FALSE":
+		return k
+	}
+	return ""
+}
+
+func (t filterToken) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// filterQueryError is a syntax or semantic error in a filter query.
+type filterQueryError struct {
+	col int
+	msg string
+}
+
+func (e *filterQueryError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.col, e.msg)
+}
+
+func filterQueryErrorf(col int, format string, args ...any) error {
+	return &filterQueryError{col: col, msg: fmt.Sprintf(format, args...)}
+}
+
+func isFilterIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isFilterIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-/", r)
+}
+
+func lexFilterQuery(query string) ([]filterToken, error) {
+	var tokens []filterToken
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: tokenLParen, text: "(", col: start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: tokenRParen, text: ")", col: start + 1})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			i++
+			if i < len(rs) && rs[i] == '=' {
+				i++
+			}
+			op := string(rs[start:i])
+			if op == "!" {
+				return nil, filterQueryErrorf(start+1, `unexpected "!", did you mean "!="`)
+			}
+			if op == "==" {
+				op = "="
+			}
+			tokens = append(tokens, filterToken{kind: tokenOperator, text: op, col: start + 1})
+		case r == '"' || r == '\'' || r == '`':
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(rs) {
+					return nil, filterQueryErrorf(start+1, "unterminated %c", r)
+				}
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+					sb.WriteRune(rs[i])
+					continue
+				}
+				if rs[i] == r {
+					i++
+					break
+				}
+				sb.WriteRune(rs[i])
+			}
+			if r == '`' {
+				tokens = append(tokens, filterToken{
+					kind: tokenIdent, text: sb.String(), col: start + 1, quoted: true,
+				})
+			} else {
+				tokens = append(tokens, filterToken{kind: tokenString, text: sb.String(), col: start + 1})
+			}
+		case unicode.IsDigit(r) || r == '.' || r == '-' || r == '+':
+			for i++; i < len(rs); i++ {
+				c := rs[i]
+				exponentSign := (c == '-' || c == '+') && (rs[i-1] == 'e' || rs[i-1] == 'E')
+				if !unicode.IsDigit(c) && c != '.' && c != 'e' && c != 'E' && !exponentSign {
+					break
+				}
+			}
+			text := string(rs[start:i])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, filterQueryErrorf(start+1, "invalid number %q", text)
+			}
+			tokens = append(tokens, filterToken{kind: tokenNumber, text: text, col: start + 1})
+		case isFilterIdentStart(r):
+			for i++; i < len(rs) && isFilterIdentPart(rs[i]); i++ {
+			}
+			tokens = append(tokens, filterToken{kind: tokenIdent, text: string(rs[start:i]), col: start + 1})
+		default:
+			return nil, filterQueryErrorf(start+1, "unexpected character %q", r)
+		}
+	}
+	return append(tokens, filterToken{kind: tokenEOF, col: len(rs) + 1}), nil
+}
+
+type filterQueryParser struct {
+	tokens []filterToken
+	pos    int
+	runs   bool
+}
+
+// parseFilterQuery parses a filter query into the same tree the web UI sends as JSON.
+//
+// A query is made of comparisons joined by AND and OR, where AND binds tighter, and grouped with
+// parentheses. A comparison is a column, an operator (=, !=, <, <=, >, >=, contains or
+// not contains) and a value, or a column followed by IS EMPTY or IS NOT EMPTY. Values are numbers,
+// quoted strings, true, false or bare words, which are taken as strings. Columns are:
+//   - hp.<name> for hyperparameters,
+//   - <group>.<metric>.<min|max|mean|last> for summary metrics, where group is training, validation
+//     or the group of a custom metric,
+//   - metadata.<flat key> for run metadata, when filtering runs,
+//   - any other experiment or run column, such as state or searcherMetricsVal.
+//
+// Columns containing other characters may be quoted in backticks.
+func parseFilterQuery(query string, runs bool) (*experimentFilter, error) {
+	tokens, err := lexFilterQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterQueryParser{tokens: tokens, runs: runs}
+	if p.peek().kind == tokenEOF {
+		return &experimentFilter{Kind: group, Conjunction: ptrs.Ptr(and)}, nil
+	}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, filterQueryErrorf(t.col, "expected AND, OR or end of query, found %s", t)
+	}
+	if f.Kind != group {
+		f = &experimentFilter{Kind: group, Conjunction: ptrs.Ptr(and), Children: []*experimentFilter{f}}
+	}
+	return f, nil
+}
+
+func (p *filterQueryParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterQueryParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterQueryParser) parseOr() (*experimentFilter, error) {
+	return p.parseConjunction(or, "OR", p.parseAnd)
+}
+
+func (p *filterQueryParser) parseAnd() (*experimentFilter, error) {
+	return p.parseConjunction(and, "AND", p.parseTerm)
+}
+
+// parseConjunction parses operands joined by the given keyword into a single group.
+func (p *filterQueryParser) parseConjunction(
+	conjunction filterConjunction, keyword string, operand func() (*experimentFilter, error),
+) (*experimentFilter, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().keyword() != keyword {
+		return first, nil
+	}
+
+	g := &experimentFilter{Kind: group, Conjunction: ptrs.Ptr(conjunction)}
+	add := func(f *experimentFilter) {
+		if f.Kind == group && *f.Conjunction == conjunction {
+			g.Children = append(g.Children, f.Children...)
+			return
+		}
+		g.Children = append(g.Children, f)
+	}
+	add(first)
+	for p.peek().keyword() == keyword {
+		p.next()
+		f, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		add(f)
+	}
+	return g, nil
+}
+
+func (p *filterQueryParser) parseTerm() (*experimentFilter, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, filterQueryErrorf(closing.col, `expected ")", found %s`, closing)
+		}
+		return f, nil
+	case t.kind == tokenIdent && t.keyword() == "":
+		return p.parseComparison(t)
+	default:
+		return nil, filterQueryErrorf(t.col, "expected a column or \"(\", found %s", t)
+	}
+}
+
+func (p *filterQueryParser) parseComparison(column filterToken) (*experimentFilter, error) {
+	f, err := p.newField(column)
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+	var op operator
+	switch {
+	case t.kind == tokenOperator:
+		op = operator(t.text)
+	case t.keyword() == "CONTAINS":
+		op = contains
+	case t.keyword() == "NOT":
+		if n := p.next(); n.keyword() != "CONTAINS" {
+			return nil, filterQueryErrorf(n.col, "expected CONTAINS after NOT, found %s", n)
+		}
+		op = doesNotContain
+	case t.keyword() == "IS":
+		op = empty
+		n := p.next()
+		if n.keyword() == "NOT" {
+			op = notEmpty
+			n = p.next()
+		}
+		if n.keyword() != "EMPTY" {
+			return nil, filterQueryErrorf(n.col, "expected EMPTY, found %s", n)
+		}
+		f.Operator = &op
+		return f, nil
+	default:
+		return nil, filterQueryErrorf(t.col, "expected an operator after %s, found %s", column, t)
+	}
+	f.Operator = &op
+
+	t = p.next()
+	var value any
+	switch {
+	case t.kind == tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, filterQueryErrorf(t.col, "invalid number %q", t.text)
+		}
+		value = n
+		f.Type = ptrs.Ptr(projectv1.ColumnType_COLUMN_TYPE_NUMBER.String())
+	case t.keyword() == "TRUE", t.keyword() == "FALSE":
+		value = t.keyword() == "TRUE"
+	case t.kind == tokenString, t.kind == tokenIdent && t.keyword() == "":
+		value = t.text
+		f.Type = ptrs.Ptr(projectv1.ColumnType_COLUMN_TYPE_TEXT.String())
+	default:
+		return nil, filterQueryErrorf(t.col, "expected a value after %s, found %s", op, t)
+	}
+	if op == contains || op == doesNotContain {
+		_, isString := value.(string)
+		if !isString && *f.Location != projectv1.LocationType_LOCATION_TYPE_HYPERPARAMETERS.String() {
+			return nil, filterQueryErrorf(t.col, "%s must be followed by a string", op)
+		}
+	}
+	f.Value = &value
+	return f, nil
+}
+
+// newField returns a field filter on the column, with its location worked out from its name.
+func (p *filterQueryParser) newField(column filterToken) (*experimentFilter, error) {
+	name := column.text
+	var location projectv1.LocationType
+	switch {
+	case strings.HasPrefix(name, "hp."):
+		location = projectv1.LocationType_LOCATION_TYPE_HYPERPARAMETERS
+		if p.runs {
+			location = projectv1.LocationType_LOCATION_TYPE_RUN_HYPERPARAMETERS
+		}
+	case strings.HasPrefix(name, "metadata."):
+		if !p.runs {
+			return nil, filterQueryErrorf(column.col, "run metadata can only be used to filter runs")
+		}
+		location = projectv1.LocationType_LOCATION_TYPE_RUN_METADATA
+	case strings.Contains(name, "."):
+		matches := metricIDTemplate.FindStringSubmatch(name)
+		if len(matches) < 4 || matches[0] != name {
+			return nil, filterQueryErrorf(column.col,
+				"%s is not a valid metric, expected <group>.<metric>.<min|max|mean|last>", column)
+		}
+		switch matches[1] {
+		case metricIDTraining:
+			location = projectv1.LocationType_LOCATION_TYPE_TRAINING
+		case metricIDValidation:
+			location = projectv1.LocationType_LOCATION_TYPE_VALIDATIONS
+		default:
+			location = projectv1.LocationType_LOCATION_TYPE_CUSTOM_METRIC
+		}
+	case p.runs:
+		if _, err := runColumnNameToSQL(name); err != nil {
+			return nil, filterQueryErrorf(column.col, "unknown run column %s", column)
+		}
+		location = projectv1.LocationType_LOCATION_TYPE_RUN
+	default:
+		if _, err := expColumnNameToSQL(name); err != nil {
+			return nil, filterQueryErrorf(column.col, "unknown experiment column %s", column)
+		}
+		location = projectv1.LocationType_LOCATION_TYPE_EXPERIMENT
+	}
+
+	return &experimentFilter{
+		Kind:       field,
+		ColumnName: name,
+		Location:   ptrs.Ptr(location.String()),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilterQuery(t *testing.T) {
+	f, err := parseFilterQuery(
+		`hp.lr > 1e-4 AND validation.loss.min < 0.3 AND state = COMPLETED `+
+			`AND metadata.dataset contains "imagenet"`, true)
+	require.NoError(t, err)
+
+	expected := `{
+		"Kind": "group",
+		"Conjunction": "and",
+		"Children": [
+			{"Kind": "field", "ColumnName": "hp.lr", "Location": "LOCATION_TYPE_RUN_HYPERPARAMETERS",
+				"Operator": ">", "Value": 0.0001, "Type": "COLUMN_TYPE_NUMBER"},
+			{"Kind": "field", "ColumnName": "validation.loss.min", "Location": "LOCATION_TYPE_VALIDATIONS",
+				"Operator": "<", "Value": 0.3, "Type": "COLUMN_TYPE_NUMBER"},
+			{"Kind": "field", "ColumnName": "state", "Location": "LOCATION_TYPE_RUN",
+				"Operator": "=", "Value": "COMPLETED", "Type": "COLUMN_TYPE_TEXT"},
+			{"Kind": "field", "ColumnName": "metadata.dataset", "Location": "LOCATION_TYPE_RUN_METADATA",
+				"Operator": "contains", "Value": "imagenet", "Type": "COLUMN_TYPE_TEXT"}
+		]
+	}`
+	var want experimentFilter
+	require.NoError(t, json.Unmarshal([]byte(expected), &want))
+	require.Equal(t, want, *f)
+}
+
+func TestParseFilterQueryGrouping(t *testing.T) {
+	f, err := parseFilterQuery(
+		`(training.loss.last <= 1 OR my_group.acc.max >= 0.9) and hp.optimizer != "sgd" `+
+			`and description is not empty`, false)
+	require.NoError(t, err)
+
+	require.Equal(t, group, f.Kind)
+	require.Equal(t, and, *f.Conjunction)
+	require.Len(t, f.Children, 3)
+
+	orGroup := f.Children[0]
+	require.Equal(t, or, *orGroup.Conjunction)
+	require.Len(t, orGroup.Children, 2)
+	require.Equal(t, "LOCATION_TYPE_TRAINING", *orGroup.Children[0].Location)
+	require.Equal(t, "LOCATION_TYPE_CUSTOM_METRIC", *orGroup.Children[1].Location)
+
+	require.Equal(t, "LOCATION_TYPE_HYPERPARAMETERS", *f.Children[1].Location)
+	require.Equal(t, notEqual, *f.Children[1].Operator)
+
+	require.Equal(t, "LOCATION_TYPE_EXPERIMENT", *f.Children[2].Location)
+	require.Equal(t, notEmpty, *f.Children[2].Operator)
+	require.Nil(t, f.Children[2].Value)
+}
+
+func TestParseFilterQueryErrors(t *testing.T) {
+	cases := map[string]string{
+		`hp.lr >`:                       "column 8: expected a value after >, found end of query",
+		`hp.lr > 1 AND (state = ACTIVE`: `column 30: expected ")", found end of query`,
+		`validation.loss < 1`:           `column 1: "validation.loss" is not a valid metric`,
+		`metadata.dataset = "x"`:        "column 1: run metadata can only be used to filter runs",
+		`nope = 1`:                      `column 1: unknown experiment column "nope"`,
+		`state = "COMPLETED`:            "column 9: unterminated \"",
+		`hp.lr 1`:                       `column 7: expected an operator after "hp.lr", found "1"`,
+		`state = ACTIVE state = PAUSED`: `column 16: expected AND, OR or end of query, found "state"`,
+		`description is empty or`:       `column 24: expected a column or "(", found end of query`,
+		`searcherMetricsVal contains 1`: "column 29: contains must be followed by a string",
+		`hp.lr ! 1`:                     `column 7: unexpected "!"`,
+		`description not like "x"`:      `column 17: expected CONTAINS after NOT, found "like"`,
+		`description is "x"`:            `column 16: expected EMPTY, found "x"`,
+		`numTrials > 1.2.3`:             `column 13: invalid number "1.2.3"`,
+		`description = "a" AND # = 1`:   `column 23: unexpected character '#'`,
+	}
+	for query, expected := range cases {
+		_, err := parseFilterQuery(query, false)
+		require.ErrorContains(t, err, expected, query)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	efr, err := parseFilter(`{"filterGroup": {"kind": "group", "conjunction": "or", "children": []}, `+
+		`"showArchived": true}`, true)
+	require.NoError(t, err)
+	require.True(t, efr.ShowArchived)
+	require.Equal(t, or, *efr.FilterGroup.Conjunction)
+
+	efr, err = parseFilter(`state = ACTIVE`, true)
+	require.NoError(t, err)
+	require.False(t, efr.ShowArchived)
+	require.Len(t, efr.FilterGroup.Children, 1)
+
+	_, err = parseFilter(`state =`, true)
+	require.ErrorContains(t, err, "invalid filter: column 8")
+}
@@ -94,7 +94,24 @@ func getAllocationSessionBun(ctx context.Context) (*model.AllocationSession, err
 		return nil, ErrTokenMissing
 	}
 
-	token := tokens[0]
+	return allocationSessionByBearerToken(tokens[0])
+}
+
+// AllocationSessionFromRequest returns the allocation session of the allocation token of an HTTP
+// request, which is read from the x-allocation-token header or, failing that, the Authorization
+// header.
+func AllocationSessionFromRequest(r *http.Request) (*model.AllocationSession, error) {
+	token := r.Header.Get(AllocationTokenHeader)
+	if token == "" {
+		token = r.Header.Get("Authorization")
+	}
+	if token == "" {
+		return nil, ErrTokenMissing
+	}
+	return allocationSessionByBearerToken(token)
+}
+
+func allocationSessionByBearerToken(token string) (*model.AllocationSession, error) {
 	if !strings.HasPrefix(token, "Bearer ") {
 		return nil, ErrInvalidCredentials
 	}
@@ -110,6 +127,12 @@ func getAllocationSessionBun(ctx context.Context) (*model.AllocationSession, err
 	}
 }
 
+// WithUser returns a context that is authenticated as user, for requests the master makes on behalf
+// of a user.
+func WithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
 // GetUser returns the currently logged in user.
 func GetUser(ctx context.Context) (*model.User, *model.UserSession, error) {
 	if user, ok := ctx.Value(userContextKey{}).(*model.User); ok {
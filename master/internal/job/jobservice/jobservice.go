@@ -243,11 +243,13 @@ func updateJobQInfo(job *jobv1.Job, rmInfo *sproto.RMJobInfo) {
 		job.Summary = nil
 		job.RequestedSlots = 0
 		job.AllocatedSlots = 0
+		job.TargetSlots = 0
 		return
 	}
 
 	job.RequestedSlots = int32(rmInfo.RequestedSlots)
 	job.AllocatedSlots = int32(rmInfo.AllocatedSlots)
+	job.TargetSlots = int32(rmInfo.TargetSlots)
 	if job.Summary == nil {
 		job.Summary = &jobv1.JobSummary{}
 	}
@@ -0,0 +1,178 @@
+// Package lineage traverses the graph of relationships between runs, checkpoints, model versions
+// and tasks.
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/lineagev1"
+)
+
+const (
+	// DefaultDepth is the depth of a graph when none is requested.
+	DefaultDepth = 3
+	// MaxDepth bounds the depth of a graph.
+	MaxDepth = 10
+)
+
+// Source looks up entities and the relationships between them.
+type Source interface {
+	// Nodes returns the nodes of the entities that exist among refs.
+	Nodes(ctx context.Context, refs []*lineagev1.NodeRef) ([]*lineagev1.Node, error)
+	// Edges returns the edges into the given entities if upstream is set, and out of them
+	// otherwise.
+	Edges(ctx context.Context, refs []*lineagev1.NodeRef, upstream bool) ([]*lineagev1.Edge, error)
+}
+
+// Authorizer returns whether the caller may view an entity.
+type Authorizer func(ctx context.Context, ref *lineagev1.NodeRef) (bool, error)
+
+// Graph is the lineage graph of an entity.
+type Graph struct {
+	Nodes []*lineagev1.Node
+	Edges []*lineagev1.Edge
+	// NumHidden is the number of entities adjacent to the graph that the caller may not view.
+	NumHidden int
+}
+
+type nodeKey struct {
+	typ lineagev1.NodeType
+	id  string
+}
+
+func keyOf(ref *lineagev1.NodeRef) nodeKey {
+	return nodeKey{typ: ref.Type, id: ref.Id}
+}
+
+type edgeKey struct {
+	source, target nodeKey
+	typ            lineagev1.EdgeType
+}
+
+// ValidateRef checks that the id of a reference is well-formed for its type.
+func ValidateRef(ref *lineagev1.NodeRef) error {
+	switch ref.Type {
+	case lineagev1.NodeType_NODE_TYPE_RUN, lineagev1.NodeType_NODE_TYPE_MODEL_VERSION:
+		if _, err := strconv.Atoi(ref.Id); err != nil {
+			return fmt.Errorf("%s id must be an integer: %q", ref.Type, ref.Id)
+		}
+	case lineagev1.NodeType_NODE_TYPE_CHECKPOINT:
+		if _, err := uuid.Parse(ref.Id); err != nil {
+			return fmt.Errorf("checkpoint id must be a uuid: %q", ref.Id)
+		}
+	case lineagev1.NodeType_NODE_TYPE_TASK:
+		if ref.Id == "" {
+			return errors.New("task id must be non-empty")
+		}
+	default:
+		return fmt.Errorf("unsupported node type %s", ref.Type)
+	}
+	return nil
+}
+
+// Traverse returns the lineage graph of root, containing the entities at most depth edges
+// upstream and/or downstream of it. Entities that canView rejects are counted but omitted along
+// with their edges, and the graph is not traversed past them. It returns db.ErrNotFound if root
+// does not exist or may not be viewed.
+func Traverse(
+	ctx context.Context, src Source, root *lineagev1.NodeRef, upstream, downstream bool, depth int,
+	canView Authorizer,
+) (*Graph, error) {
+	rootNodes, err := src.Nodes(ctx, []*lineagev1.NodeRef{root})
+	if err != nil {
+		return nil, err
+	}
+	if len(rootNodes) == 0 {
+		return nil, db.ErrNotFound
+	}
+	switch ok, err := canView(ctx, root); {
+	case err != nil:
+		return nil, err
+	case !ok:
+		return nil, db.ErrNotFound
+	}
+
+	g := &Graph{Nodes: rootNodes[:1]}
+	g.Nodes[0].Depth = 0
+	visible := map[nodeKey]bool{keyOf(root): true}
+	hidden := map[nodeKey]bool{}
+	edges := map[edgeKey]bool{}
+
+	addEdge := func(e *lineagev1.Edge) {
+		k := edgeKey{source: keyOf(e.Source), target: keyOf(e.Target), typ: e.Type}
+		if !edges[k] {
+			edges[k] = true
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	var directions []bool
+	if upstream {
+		directions = append(directions, true)
+	}
+	if downstream {
+		directions = append(directions, false)
+	}
+	for _, up := range directions {
+		frontier := []*lineagev1.NodeRef{root}
+		for d := 1; d <= depth && len(frontier) > 0; d++ {
+			found, err := src.Edges(ctx, frontier, up)
+			if err != nil {
+				return nil, err
+			}
+
+			var unseen []*lineagev1.NodeRef
+			pending := map[nodeKey]bool{}
+			for _, e := range found {
+				far := e.Target
+				if up {
+					far = e.Source
+				}
+				k := keyOf(far)
+				if !visible[k] && !hidden[k] && !pending[k] {
+					pending[k] = true
+					unseen = append(unseen, far)
+				}
+			}
+
+			var nodes []*lineagev1.Node
+			if len(unseen) > 0 {
+				if nodes, err = src.Nodes(ctx, unseen); err != nil {
+					return nil, err
+				}
+			}
+			frontier = nil
+			for _, n := range nodes {
+				ok, err := canView(ctx, n.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					hidden[keyOf(n.Ref)] = true
+					g.NumHidden++
+					continue
+				}
+				visible[keyOf(n.Ref)] = true
+				n.Depth = int32(d)
+				if up {
+					n.Depth = -n.Depth
+				}
+				g.Nodes = append(g.Nodes, n)
+				frontier = append(frontier, n.Ref)
+			}
+
+			for _, e := range found {
+				if visible[keyOf(e.Source)] && visible[keyOf(e.Target)] {
+					addEdge(e)
+				}
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,172 @@
+package lineage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/lineagev1"
+)
+
+const (
+	run   = lineagev1.NodeType_NODE_TYPE_RUN
+	ckpt  = lineagev1.NodeType_NODE_TYPE_CHECKPOINT
+	mv    = lineagev1.NodeType_NODE_TYPE_MODEL_VERSION
+	task  = lineagev1.NodeType_NODE_TYPE_TASK
+	prod  = lineagev1.EdgeType_EDGE_TYPE_PRODUCED
+	warm  = lineagev1.EdgeType_EDGE_TYPE_WARM_STARTED
+	reg   = lineagev1.EdgeType_EDGE_TYPE_REGISTERED
+	exec  = lineagev1.EdgeType_EDGE_TYPE_EXECUTED
+	spawn = lineagev1.EdgeType_EDGE_TYPE_SPAWNED
+)
+
+type fakeSource struct {
+	edges []*lineagev1.Edge
+}
+
+func ref(typ lineagev1.NodeType, id string) *lineagev1.NodeRef {
+	return &lineagev1.NodeRef{Type: typ, Id: id}
+}
+
+func (s *fakeSource) add(src *lineagev1.NodeRef, typ lineagev1.EdgeType, dst *lineagev1.NodeRef) {
+	s.edges = append(s.edges, &lineagev1.Edge{Source: src, Target: dst, Type: typ})
+}
+
+func (s *fakeSource) Nodes(
+	_ context.Context, refs []*lineagev1.NodeRef,
+) ([]*lineagev1.Node, error) {
+	var nodes []*lineagev1.Node
+	for _, r := range refs {
+		if r.Id == "missing" {
+			continue
+		}
+		nodes = append(nodes, &lineagev1.Node{Ref: r, Name: r.Type.String() + " " + r.Id})
+	}
+	return nodes, nil
+}
+
+func (s *fakeSource) Edges(
+	_ context.Context, refs []*lineagev1.NodeRef, upstream bool,
+) ([]*lineagev1.Edge, error) {
+	var out []*lineagev1.Edge
+	for _, e := range s.edges {
+		end := e.Source
+		if upstream {
+			end = e.Target
+		}
+		for _, r := range refs {
+			if keyOf(r) == keyOf(end) {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+func canViewAll(context.Context, *lineagev1.NodeRef) (bool, error) {
+	return true, nil
+}
+
+type depths map[nodeKey]int32
+
+func nodeDepths(g *Graph) depths {
+	out := depths{}
+	for _, n := range g.Nodes {
+		out[keyOf(n.Ref)] = n.Depth
+	}
+	return out
+}
+
+// newFakeSource returns a source with the graph
+//
+//	run 1 -> ckpt a -> run 2 -> ckpt b -> mv 7
+//	            run 2 -> task t2 -> task t3 (spawned)
+//	                     run 1 -> task t1.
+func newFakeSource() *fakeSource {
+	s := &fakeSource{}
+	s.add(ref(run, "1"), prod, ref(ckpt, "a"))
+	s.add(ref(run, "1"), exec, ref(task, "t1"))
+	s.add(ref(ckpt, "a"), warm, ref(run, "2"))
+	s.add(ref(run, "2"), exec, ref(task, "t2"))
+	s.add(ref(run, "2"), prod, ref(ckpt, "b"))
+	s.add(ref(ckpt, "b"), reg, ref(mv, "7"))
+	s.add(ref(task, "t2"), spawn, ref(task, "t3"))
+	return s
+}
+
+func TestTraverseBothDirections(t *testing.T) {
+	g, err := Traverse(context.Background(), newFakeSource(), ref(run, "2"), true, true, 2,
+		canViewAll)
+	require.NoError(t, err)
+
+	require.Equal(t, keyOf(ref(run, "2")), keyOf(g.Nodes[0].Ref))
+	require.Equal(t, depths{
+		{run, "2"}:   0,
+		{ckpt, "a"}:  -1,
+		{run, "1"}:   -2,
+		{task, "t2"}: 1,
+		{ckpt, "b"}:  1,
+		{task, "t3"}: 2,
+		{mv, "7"}:    2,
+	}, nodeDepths(g))
+	// Siblings such as task t1 are neither upstream nor downstream of the root.
+	require.Len(t, g.Edges, 6)
+	require.Zero(t, g.NumHidden)
+}
+
+func TestTraverseDepthAndDirection(t *testing.T) {
+	g, err := Traverse(context.Background(), newFakeSource(), ref(mv, "7"), true, false, 3,
+		canViewAll)
+	require.NoError(t, err)
+	require.Equal(t, depths{
+		{mv, "7"}:   0,
+		{ckpt, "b"}: -1,
+		{run, "2"}:  -2,
+		{ckpt, "a"}: -3,
+	}, nodeDepths(g))
+
+	g, err = Traverse(context.Background(), newFakeSource(), ref(mv, "7"), false, true, 3,
+		canViewAll)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 1)
+	require.Empty(t, g.Edges)
+}
+
+func TestTraverseHidden(t *testing.T) {
+	canView := func(_ context.Context, r *lineagev1.NodeRef) (bool, error) {
+		return keyOf(r) != keyOf(ref(run, "2")), nil
+	}
+	g, err := Traverse(context.Background(), newFakeSource(), ref(run, "1"), true, true, 5,
+		canView)
+	require.NoError(t, err)
+	// The graph is not traversed past run 2, and its edges are omitted.
+	require.Equal(t, depths{
+		{run, "1"}:   0,
+		{ckpt, "a"}:  1,
+		{task, "t1"}: 1,
+	}, nodeDepths(g))
+	require.Len(t, g.Edges, 2)
+	require.Equal(t, 1, g.NumHidden)
+
+	_, err = Traverse(context.Background(), newFakeSource(), ref(run, "2"), true, true, 5, canView)
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
+
+func TestTraverseMissingRoot(t *testing.T) {
+	_, err := Traverse(context.Background(), newFakeSource(), ref(run, "missing"), true, true, 1,
+		canViewAll)
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
+
+func TestValidateRef(t *testing.T) {
+	require.NoError(t, ValidateRef(ref(run, "12")))
+	require.Error(t, ValidateRef(ref(run, "abc")))
+	require.NoError(t, ValidateRef(ref(ckpt, "7e0bad2c-5b8e-4a3a-9a44-7c3fb1a0e8a5")))
+	require.Error(t, ValidateRef(ref(ckpt, "12")))
+	require.NoError(t, ValidateRef(ref(mv, "3")))
+	require.NoError(t, ValidateRef(ref(task, "abc.1")))
+	require.Error(t, ValidateRef(ref(task, "")))
+	require.Error(t, ValidateRef(ref(lineagev1.NodeType_NODE_TYPE_UNSPECIFIED, "1")))
+}
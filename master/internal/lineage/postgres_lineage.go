@@ -0,0 +1,273 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/lineagev1"
+)
+
+// edgeSpec describes how to query the edges of one type.
+type edgeSpec struct {
+	typ    lineagev1.EdgeType
+	source lineagev1.NodeType
+	target lineagev1.NodeType
+	// query selects the ids of the source and target of every edge as source_id and target_id.
+	query func() *bun.SelectQuery
+	// sourceCol and targetCol are the columns to filter on to select edges by source or target.
+	sourceCol string
+	targetCol string
+}
+
+// Checkpoints of trial tasks are attributed to the run rather than to its task, so that the
+// lineage of a run does not depend on how many times it was continued.
+var edgeSpecs = []edgeSpec{
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_PRODUCED,
+		source: lineagev1.NodeType_NODE_TYPE_RUN,
+		target: lineagev1.NodeType_NODE_TYPE_CHECKPOINT,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("checkpoints_v2 AS c").
+				ColumnExpr("rt.run_id::text AS source_id").
+				ColumnExpr("c.uuid::text AS target_id").
+				Join("JOIN run_id_task_id AS rt ON rt.task_id = c.task_id")
+		},
+		sourceCol: "rt.run_id",
+		targetCol: "c.uuid",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_PRODUCED,
+		source: lineagev1.NodeType_NODE_TYPE_TASK,
+		target: lineagev1.NodeType_NODE_TYPE_CHECKPOINT,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("checkpoints_v2 AS c").
+				ColumnExpr("c.task_id AS source_id").
+				ColumnExpr("c.uuid::text AS target_id").
+				Where("NOT EXISTS (SELECT 1 FROM run_id_task_id AS rt WHERE rt.task_id = c.task_id)")
+		},
+		sourceCol: "c.task_id",
+		targetCol: "c.uuid",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_EXECUTED,
+		source: lineagev1.NodeType_NODE_TYPE_RUN,
+		target: lineagev1.NodeType_NODE_TYPE_TASK,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("run_id_task_id AS rt").
+				ColumnExpr("rt.run_id::text AS source_id").
+				ColumnExpr("rt.task_id AS target_id")
+		},
+		sourceCol: "rt.run_id",
+		targetCol: "rt.task_id",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_WARM_STARTED,
+		source: lineagev1.NodeType_NODE_TYPE_CHECKPOINT,
+		target: lineagev1.NodeType_NODE_TYPE_RUN,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("runs AS r").
+				ColumnExpr("c.uuid::text AS source_id").
+				ColumnExpr("r.id::text AS target_id").
+				Join("JOIN checkpoints_v2 AS c ON c.id = r.warm_start_checkpoint_id")
+		},
+		sourceCol: "c.uuid",
+		targetCol: "r.id",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_REGISTERED,
+		source: lineagev1.NodeType_NODE_TYPE_CHECKPOINT,
+		target: lineagev1.NodeType_NODE_TYPE_MODEL_VERSION,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("model_versions AS mv").
+				ColumnExpr("mv.checkpoint_uuid::text AS source_id").
+				ColumnExpr("mv.id::text AS target_id")
+		},
+		sourceCol: "mv.checkpoint_uuid",
+		targetCol: "mv.id",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_SPAWNED,
+		source: lineagev1.NodeType_NODE_TYPE_TASK,
+		target: lineagev1.NodeType_NODE_TYPE_TASK,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("tasks AS t").
+				ColumnExpr("t.parent_id AS source_id").
+				ColumnExpr("t.task_id AS target_id").
+				Where("t.parent_id IS NOT NULL")
+		},
+		sourceCol: "t.parent_id",
+		targetCol: "t.task_id",
+	},
+	{
+		typ:    lineagev1.EdgeType_EDGE_TYPE_FORKED,
+		source: lineagev1.NodeType_NODE_TYPE_TASK,
+		target: lineagev1.NodeType_NODE_TYPE_TASK,
+		query: func() *bun.SelectQuery {
+			return db.Bun().NewSelect().
+				TableExpr("tasks AS t").
+				ColumnExpr("t.forked_from AS source_id").
+				ColumnExpr("t.task_id AS target_id").
+				Where("t.forked_from IS NOT NULL")
+		},
+		sourceCol: "t.forked_from",
+		targetCol: "t.task_id",
+	},
+}
+
+// nodeTypes orders the nodes returned by PostgresSource.Nodes.
+var nodeTypes = []lineagev1.NodeType{
+	lineagev1.NodeType_NODE_TYPE_RUN,
+	lineagev1.NodeType_NODE_TYPE_CHECKPOINT,
+	lineagev1.NodeType_NODE_TYPE_MODEL_VERSION,
+	lineagev1.NodeType_NODE_TYPE_TASK,
+}
+
+// PostgresSource is a Source backed by the database.
+type PostgresSource struct{}
+
+func groupIDs(refs []*lineagev1.NodeRef) map[lineagev1.NodeType][]string {
+	ids := make(map[lineagev1.NodeType][]string)
+	for _, ref := range refs {
+		ids[ref.Type] = append(ids[ref.Type], ref.Id)
+	}
+	return ids
+}
+
+// Edges implements Source.
+func (PostgresSource) Edges(
+	ctx context.Context, refs []*lineagev1.NodeRef, upstream bool,
+) ([]*lineagev1.Edge, error) {
+	ids := groupIDs(refs)
+
+	var edges []*lineagev1.Edge
+	for _, spec := range edgeSpecs {
+		nodeType, col := spec.source, spec.sourceCol
+		if upstream {
+			nodeType, col = spec.target, spec.targetCol
+		}
+		if len(ids[nodeType]) == 0 {
+			continue
+		}
+
+		var rows []struct {
+			SourceID string `bun:"source_id"`
+			TargetID string `bun:"target_id"`
+		}
+		if err := spec.query().
+			Where("? IN (?)", bun.Safe(col), bun.In(ids[nodeType])).
+			OrderExpr("source_id, target_id").
+			Scan(ctx, &rows); err != nil {
+			return nil, errors.Wrapf(err, "error querying %s edges", spec.typ)
+		}
+		for _, row := range rows {
+			edges = append(edges, &lineagev1.Edge{
+				Source: &lineagev1.NodeRef{Type: spec.source, Id: row.SourceID},
+				Target: &lineagev1.NodeRef{Type: spec.target, Id: row.TargetID},
+				Type:   spec.typ,
+			})
+		}
+	}
+	return edges, nil
+}
+
+// Nodes implements Source.
+func (PostgresSource) Nodes(
+	ctx context.Context, refs []*lineagev1.NodeRef,
+) ([]*lineagev1.Node, error) {
+	var nodes []*lineagev1.Node
+	add := func(typ lineagev1.NodeType, id, name string) {
+		nodes = append(nodes, &lineagev1.Node{
+			Ref:  &lineagev1.NodeRef{Type: typ, Id: id},
+			Name: name,
+		})
+	}
+
+	idsByType := groupIDs(refs)
+	for _, typ := range nodeTypes {
+		ids := idsByType[typ]
+		if len(ids) == 0 {
+			continue
+		}
+		switch typ {
+		case lineagev1.NodeType_NODE_TYPE_RUN:
+			var rows []struct {
+				ID           string
+				ExperimentID int
+			}
+			if err := db.Bun().NewSelect().
+				TableExpr("runs AS r").
+				ColumnExpr("r.id::text AS id").
+				Column("r.experiment_id").
+				Where("r.id IN (?)", bun.In(ids)).
+				Order("r.id").
+				Scan(ctx, &rows); err != nil {
+				return nil, errors.Wrap(err, "error querying runs")
+			}
+			for _, row := range rows {
+				add(typ, row.ID, fmt.Sprintf("Trial %s (Experiment %d)", row.ID, row.ExperimentID))
+			}
+		case lineagev1.NodeType_NODE_TYPE_CHECKPOINT:
+			var rows []struct {
+				ID string
+			}
+			if err := db.Bun().NewSelect().
+				TableExpr("checkpoints_v2 AS c").
+				ColumnExpr("c.uuid::text AS id").
+				Where("c.uuid IN (?)", bun.In(ids)).
+				Order("c.report_time").
+				Scan(ctx, &rows); err != nil {
+				return nil, errors.Wrap(err, "error querying checkpoints")
+			}
+			for _, row := range rows {
+				add(typ, row.ID, fmt.Sprintf("Checkpoint %s", row.ID))
+			}
+		case lineagev1.NodeType_NODE_TYPE_MODEL_VERSION:
+			var rows []struct {
+				ID        string
+				ModelName string
+				Version   int
+			}
+			if err := db.Bun().NewSelect().
+				TableExpr("model_versions AS mv").
+				ColumnExpr("mv.id::text AS id").
+				ColumnExpr("m.name AS model_name").
+				Column("mv.version").
+				Join("JOIN models AS m ON m.id = mv.model_id").
+				Where("mv.id IN (?)", bun.In(ids)).
+				Order("mv.id").
+				Scan(ctx, &rows); err != nil {
+				return nil, errors.Wrap(err, "error querying model versions")
+			}
+			for _, row := range rows {
+				add(typ, row.ID, fmt.Sprintf("%s version %d", row.ModelName, row.Version))
+			}
+		case lineagev1.NodeType_NODE_TYPE_TASK:
+			var rows []struct {
+				TaskID   string
+				TaskType string
+			}
+			if err := db.Bun().NewSelect().
+				TableExpr("tasks AS t").
+				Column("t.task_id", "t.task_type").
+				Where("t.task_id IN (?)", bun.In(ids)).
+				Order("t.start_time").
+				Scan(ctx, &rows); err != nil {
+				return nil, errors.Wrap(err, "error querying tasks")
+			}
+			for _, row := range rows {
+				add(typ, row.TaskID, fmt.Sprintf("%s task %s", row.TaskType, row.TaskID))
+			}
+		}
+	}
+	return nodes, nil
+}
@@ -19,10 +19,10 @@ const retainForever = -1
 var (
 	log = logrus.WithField("component", "log-retention")
 
-	schedulerDefaultOpts = []gocron.SchedulerOption{
-		gocron.WithLimitConcurrentJobs(1, gocron.LimitModeReschedule),
-	}
-	scheduler gocron.Scheduler
+	schedulerDefaultOpts = []gocron.SchedulerOption{}
+	schedulerMu          sync.Mutex
+	scheduler            gocron.Scheduler
+	schedulerStarted     bool
 
 	// TestingOnlySynchronizationHelper is used for testing purposes to wait for the log retention scheduler to finish.
 	TestingOnlySynchronizationHelper *sync.WaitGroup
@@ -42,7 +42,22 @@ func SetupScheduler(opts ...gocron.SchedulerOption) {
 	if err != nil {
 		panic(errors.Wrapf(err, "failed to create logretention scheduler"))
 	}
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
 	scheduler = newScheduler
+	schedulerStarted = false
+}
+
+// Scheduler returns the scheduler that runs log retention, starting it if it is not running yet.
+// Other periodic jobs of the master, such as experiment schedules, run on it too.
+func Scheduler() gocron.Scheduler {
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+	if !schedulerStarted {
+		scheduler.Start()
+		schedulerStarted = true
+	}
+	return scheduler
 }
 
 // Schedule begins a log deletion schedule according to the provided LogRetentionPolicy.
@@ -61,26 +76,26 @@ func Schedule(config model.LogRetentionPolicy) error {
 			log.WithField("count", count).Info("deleted expired task logs")
 		}
 	})
+	// Cleanups never overlap; one that is due while another is running is skipped.
+	opts := gocron.WithSingletonMode(gocron.LimitModeReschedule)
 	// If a cleanup schedule is set, schedule the cleanup task.
 	if config.Schedule != nil {
 		if d, err := time.ParseDuration(*config.Schedule); err == nil {
 			// Try to parse out a duration.
 			log.WithField("duration", d).Debug("running task log cleanup with duration")
-			_, err := scheduler.NewJob(gocron.DurationJob(d), task)
+			_, err := Scheduler().NewJob(gocron.DurationJob(d), task, opts)
 			if err != nil {
 				return errors.Wrapf(err, "failed to schedule duration task log cleanup")
 			}
 		} else {
 			// Otherwise, use a cron.
 			log.WithField("cron", *config.Schedule).Debug("running task log cleanup with cron")
-			_, err := scheduler.NewJob(gocron.CronJob(*config.Schedule, false), task)
+			_, err := Scheduler().NewJob(gocron.CronJob(*config.Schedule, false), task, opts)
 			if err != nil {
 				return errors.Wrapf(err, "failed to schedule cron task log cleanup")
 			}
 		}
 	}
-	// Start the scheduler.
-	scheduler.Start()
 	return nil
 }
 
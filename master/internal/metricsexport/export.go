@@ -0,0 +1,352 @@
+package metricsexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	hparamPrefix   = "hparams."
+	metadataPrefix = "metadata."
+	metricPrefix   = "metrics."
+)
+
+// fixedColumns are the leading columns of every export.
+var fixedColumns = []Column{
+	{Name: "run_id", Type: IntColumn},
+	{Name: "experiment_id", Type: IntColumn},
+	{Name: "project_id", Type: IntColumn},
+	{Name: "metric_group", Type: StringColumn},
+	{Name: "steps_completed", Type: IntColumn},
+	{Name: "end_time", Type: TimeColumn},
+}
+
+// runsPageSize is how many runs are read at a time. Tests lower it to page through a few runs.
+var runsPageSize = 100
+
+// Options configure an export.
+type Options struct {
+	Format Format
+	// MetricGroup limits the export to a metric group. All groups but profiling ones are exported
+	// if it is empty.
+	MetricGroup model.MetricGroup
+}
+
+// Export writes a row for every metrics report of the runs whose IDs runIDs selects. Each row
+// carries the run's flattened hyperparameters and metadata, so the export only holds one report
+// and the metadata of one run in memory at a time. runIDs must select a single column of run IDs
+// and is the only place access control is applied.
+func Export(ctx context.Context, w io.Writer, runIDs *bun.SelectQuery, opts Options) error {
+	columns, err := Columns(ctx, runIDs, opts.MetricGroup)
+	if err != nil {
+		return err
+	}
+	indexes := make(map[string]int, len(columns))
+	for i, c := range columns {
+		indexes[c.Name] = i
+	}
+
+	writer, err := NewWriter(w, opts.Format, columns)
+	if err != nil {
+		return err
+	}
+
+	afterID := 0
+	for {
+		var runs []struct {
+			ID           int            `bun:"id"`
+			ExperimentID *int           `bun:"experiment_id"`
+			ProjectID    int            `bun:"project_id"`
+			HParams      map[string]any `bun:"hparams"`
+		}
+		if err := db.Bun().NewSelect().TableExpr("runs AS r").
+			Column("r.id", "r.experiment_id", "r.project_id", "r.hparams").
+			Where("r.id IN (?)", runIDs).
+			Where("r.id > ?", afterID).
+			Order("r.id").
+			Limit(runsPageSize).
+			Scan(ctx, &runs); err != nil {
+			return fmt.Errorf("listing runs to export: %w", err)
+		}
+		if len(runs) == 0 {
+			break
+		}
+		afterID = runs[len(runs)-1].ID
+
+		for _, r := range runs {
+			row := make([]any, len(columns))
+			row[indexes["run_id"]] = r.ID
+			if r.ExperimentID != nil {
+				row[indexes["experiment_id"]] = *r.ExperimentID
+			}
+			row[indexes["project_id"]] = r.ProjectID
+			if err := fillHParams(row, indexes, r.ID, r.ProjectID, r.HParams); err != nil {
+				return err
+			}
+			if err := fillMetadata(ctx, row, indexes, r.ID); err != nil {
+				return err
+			}
+			if err := writeMetrics(ctx, writer, row, indexes, r.ID, opts.MetricGroup); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// Columns returns the columns of an export of the runs whose IDs runIDs selects: the fixed
+// columns, then the hyperparameters, metadata and metrics of the runs, each sorted by name.
+func Columns(
+	ctx context.Context, runIDs *bun.SelectQuery, mGroup model.MetricGroup,
+) ([]Column, error) {
+	hparams, err := hparamTypes(ctx, runIDs)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := metadataTypes(ctx, runIDs)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := metricTypes(ctx, runIDs, mGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := slices.Clone(fixedColumns)
+	for _, types := range []map[string]ColumnType{hparams, metadata, metrics} {
+		names := make([]string, 0, len(types))
+		for name := range types {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			columns = append(columns, Column{Name: name, Type: types[name]})
+		}
+	}
+	return columns, nil
+}
+
+func addType(types map[string]ColumnType, name string, t ColumnType) {
+	if existing, ok := types[name]; ok {
+		t = existing.merge(t)
+	}
+	types[name] = t
+}
+
+func hparamTypes(ctx context.Context, runIDs *bun.SelectQuery) (map[string]ColumnType, error) {
+	var rows []struct {
+		HParam string `bun:"hparam"`
+		Type   string `bun:"type"`
+	}
+	if err := db.Bun().NewSelect().TableExpr("run_hparams").
+		ColumnExpr("DISTINCT hparam").
+		ColumnExpr(`CASE
+			WHEN number_val IS NOT NULL THEN ?
+			WHEN bool_val IS NOT NULL THEN ?
+			ELSE ? END AS type`, db.MetricTypeNumber, db.MetricTypeBool, db.MetricTypeString).
+		Where("run_id IN (?)", runIDs).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting hyperparameters of runs to export: %w", err)
+	}
+	types := map[string]ColumnType{}
+	for _, r := range rows {
+		addType(types, hparamPrefix+r.HParam, columnTypeOf(r.Type))
+	}
+	return types, nil
+}
+
+func metadataTypes(ctx context.Context, runIDs *bun.SelectQuery) (map[string]ColumnType, error) {
+	var rows []struct {
+		FlatKey  string `bun:"flat_key"`
+		IsArray  bool   `bun:"is_array"`
+		IsNumber bool   `bun:"is_number"`
+		IsBool   bool   `bun:"is_bool"`
+	}
+	if err := db.Bun().NewSelect().TableExpr("runs_metadata_index").
+		Column("flat_key").
+		ColumnExpr("bool_or(COALESCE(is_array_element, false)) AS is_array").
+		ColumnExpr("bool_and(integer_value IS NOT NULL OR float_value IS NOT NULL) AS is_number").
+		ColumnExpr("bool_and(boolean_value IS NOT NULL) AS is_bool").
+		Where("run_id IN (?)", runIDs).
+		Group("flat_key").
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting metadata of runs to export: %w", err)
+	}
+	types := map[string]ColumnType{}
+	for _, r := range rows {
+		t := StringColumn
+		switch {
+		case r.IsArray:
+		case r.IsNumber:
+			t = NumberColumn
+		case r.IsBool:
+			t = BoolColumn
+		}
+		types[metadataPrefix+r.FlatKey] = t
+	}
+	return types, nil
+}
+
+func metricTypes(
+	ctx context.Context, runIDs *bun.SelectQuery, mGroup model.MetricGroup,
+) (map[string]ColumnType, error) {
+	var rows []struct {
+		SummaryKey string `bun:"summary_key"`
+		Name       string `bun:"name"`
+		Type       string `bun:"type"`
+	}
+	q := db.Bun().NewSelect().
+		TableExpr(`runs AS r,
+			jsonb_each(CASE WHEN jsonb_typeof(r.summary_metrics) = 'object'
+				THEN r.summary_metrics ELSE '{}' END) g,
+			jsonb_each(CASE WHEN jsonb_typeof(g.value) = 'object' THEN g.value ELSE '{}' END) m`).
+		ColumnExpr("DISTINCT g.key AS summary_key, m.key AS name, m.value->>'type' AS type").
+		Where("r.id IN (?)", runIDs)
+	if mGroup != "" {
+		q = q.Where("g.key = ?", model.TrialSummaryMetricsJSONPath(mGroup))
+	}
+	if err := q.Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting metric names of runs to export: %w", err)
+	}
+	types := map[string]ColumnType{}
+	for _, r := range rows {
+		if slices.Contains(model.ProfilingMetricGroups, model.TrialSummaryMetricGroup(r.SummaryKey)) {
+			continue
+		}
+		addType(types, metricPrefix+r.Name, columnTypeOf(r.Type))
+	}
+	return types, nil
+}
+
+// columnTypeOf returns the column type of a hyperparameter or summary metric type.
+func columnTypeOf(t string) ColumnType {
+	switch t {
+	case db.MetricTypeNumber:
+		return NumberColumn
+	case db.MetricTypeBool:
+		return BoolColumn
+	default:
+		return StringColumn
+	}
+}
+
+func fillHParams(
+	row []any, indexes map[string]int, runID, projectID int, hparams map[string]any,
+) error {
+	runHParams, _, err := db.BuildRunHParams(runID, projectID, hparams, "")
+	if err != nil {
+		return fmt.Errorf("flattening hyperparameters of run %d: %w", runID, err)
+	}
+	for _, hp := range runHParams {
+		i, ok := indexes[hparamPrefix+hp.HParam]
+		if !ok {
+			continue
+		}
+		switch {
+		case hp.NumberVal != nil:
+			row[i] = *hp.NumberVal
+		case hp.BoolVal != nil:
+			row[i] = *hp.BoolVal
+		case hp.TextVal != nil:
+			row[i] = *hp.TextVal
+		}
+	}
+	return nil
+}
+
+func fillMetadata(ctx context.Context, row []any, indexes map[string]int, runID int) error {
+	var entries []model.RunMetadataIndex
+	if err := db.Bun().NewSelect().Model(&entries).
+		Where("run_id = ?", runID).
+		Order("id").
+		Scan(ctx); err != nil {
+		return fmt.Errorf("getting metadata of run %d: %w", runID, err)
+	}
+
+	arrays := map[string][]any{}
+	for _, e := range entries {
+		i, ok := indexes[metadataPrefix+e.FlatKey]
+		if !ok {
+			continue
+		}
+		var value any
+		switch {
+		case e.IntegerValue != nil:
+			value = *e.IntegerValue
+		case e.FloatValue != nil:
+			value = *e.FloatValue
+		case e.BooleanValue != nil:
+			value = *e.BooleanValue
+		case e.TimestampValue != nil:
+			value = *e.TimestampValue
+		case e.StringValue != nil:
+			value = *e.StringValue
+		}
+		if e.IsArrayElement {
+			arrays[e.FlatKey] = append(arrays[e.FlatKey], value)
+			row[i] = arrays[e.FlatKey]
+		} else {
+			row[i] = value
+		}
+	}
+	return nil
+}
+
+// writeMetrics writes a row for every report of a run, on top of the run's row.
+func writeMetrics(
+	ctx context.Context, writer Writer, runRow []any, indexes map[string]int, runID int,
+	mGroup model.MetricGroup,
+) error {
+	q := db.Bun().NewSelect().TableExpr("metrics AS m").
+		Column("m.metric_group", "m.total_batches", "m.end_time").
+		ColumnExpr(`m.metrics->(CASE WHEN m.partition_type = ?
+			THEN 'validation_metrics' ELSE 'avg_metrics' END) AS values`, db.ValidationMetric).
+		Where("m.trial_id = ?", runID).
+		Where("NOT m.archived").
+		Where("m.partition_type != ?", db.ProfilingMetric).
+		Order("m.metric_group", "m.total_batches", "m.id")
+	if mGroup != "" {
+		q = q.Where("m.metric_group = ?", mGroup)
+	}
+	rows, err := q.Rows(ctx)
+	if err != nil {
+		return fmt.Errorf("getting metrics of run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	row := make([]any, len(runRow))
+	for rows.Next() {
+		var report struct {
+			MetricGroup  string         `bun:"metric_group"`
+			TotalBatches int            `bun:"total_batches"`
+			EndTime      *time.Time     `bun:"end_time"`
+			Values       map[string]any `bun:"values"`
+		}
+		if err := db.Bun().ScanRow(ctx, rows, &report); err != nil {
+			return fmt.Errorf("reading metrics of run %d: %w", runID, err)
+		}
+
+		copy(row, runRow)
+		row[indexes["metric_group"]] = report.MetricGroup
+		row[indexes["steps_completed"]] = report.TotalBatches
+		row[indexes["end_time"]] = report.EndTime
+		for name, value := range report.Values {
+			if i, ok := indexes[metricPrefix+name]; ok {
+				row[i] = value
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
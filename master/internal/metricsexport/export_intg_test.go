@@ -0,0 +1,127 @@
+//go:build integration
+// +build integration
+
+package metricsexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"log"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/rbac"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// viewerRoleID is the ID of the Viewer role, which can view the experiments of a workspace.
+const viewerRoleID = 4
+
+var pgDB *db.PgDB
+
+func TestMain(m *testing.M) {
+	var err error
+	pgDB, _, err = db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// requireMockRun adds a run to a project with a training report of the given metrics.
+func requireMockRun(
+	t *testing.T, owner model.User, projectID int, metrics map[string]any,
+) *model.Trial {
+	exp := db.RequireMockExperimentProject(t, pgDB, owner, projectID)
+	trial, _ := db.RequireMockTrial(t, pgDB, exp)
+
+	values, err := structpb.NewStruct(metrics)
+	require.NoError(t, err)
+	require.NoError(t, pgDB.AddTrainingMetrics(context.Background(), &trialv1.TrialMetrics{
+		TrialId:        int32(trial.ID),
+		StepsCompleted: ptrs.Ptr(int32(1)),
+		Metrics:        &commonv1.Metrics{AvgMetrics: values},
+	}))
+	return trial
+}
+
+func TestExportExcludesRunsTheUserCannotView(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, pgDB)
+	viewer := db.RequireMockUser(t, pgDB)
+
+	visibleWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	visibleProjectID, _ := db.RequireMockProjectID(t, pgDB, visibleWorkspaceID, false)
+	hiddenWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	hiddenProjectID, _ := db.RequireMockProjectID(t, pgDB, hiddenWorkspaceID, false)
+
+	workspaceID := int32(visibleWorkspaceID)
+	require.NoError(t, rbac.AddRoleAssignments(ctx, nil, []*rbacv1.UserRoleAssignment{{
+		UserId: int32(viewer.ID),
+		RoleAssignment: &rbacv1.RoleAssignment{
+			Role:             &rbacv1.Role{RoleId: viewerRoleID},
+			ScopeWorkspaceId: &workspaceID,
+		},
+	}}))
+
+	// The hidden run sits between the visible ones, so that pages skip over it.
+	first := requireMockRun(t, owner, visibleProjectID, map[string]any{"loss": 0.5})
+	hidden := requireMockRun(t, owner, hiddenProjectID, map[string]any{"loss": 0.1, "secret": 1.0})
+	last := requireMockRun(t, owner, visibleProjectID, map[string]any{"loss": 0.25})
+
+	// Select the runs the same way the export endpoint does.
+	runIDs := db.Bun().NewSelect().
+		ModelTableExpr("runs AS r").
+		Column("r.id").
+		Join("LEFT JOIN experiments AS e ON r.experiment_id=e.id").
+		Join("LEFT JOIN projects p ON r.project_id = p.id").
+		Where("r.project_id IN (?, ?)", visibleProjectID, hiddenProjectID)
+	runIDs, err := (&experiment.ExperimentAuthZRBAC{}).FilterExperimentsQuery(ctx, viewer, nil, runIDs,
+		[]rbacv1.PermissionType{
+			rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_METADATA,
+			rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_ARTIFACTS,
+		})
+	require.NoError(t, err)
+
+	defer func(pageSize int) { runsPageSize = pageSize }(runsPageSize)
+	runsPageSize = 1
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(ctx, &buf, runIDs, Options{Format: FormatCSV}))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+
+	header := records[0]
+	require.Contains(t, header, metricPrefix+"loss")
+	require.NotContains(t, header, metricPrefix+"secret", "columns of hidden runs are exported")
+
+	var exported []string
+	for _, record := range records[1:] {
+		exported = append(exported, record[0])
+	}
+	require.Equal(t, []string{strconv.Itoa(first.ID), strconv.Itoa(last.ID)}, exported)
+	require.NotContains(t, exported, strconv.Itoa(hidden.ID))
+}
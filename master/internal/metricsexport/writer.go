@@ -0,0 +1,278 @@
+// Package metricsexport streams the metrics of many runs, along with their hyperparameters and
+// metadata, as a single Parquet or CSV file.
+package metricsexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	// FormatParquet writes a Parquet file.
+	FormatParquet Format = "parquet"
+	// FormatCSV writes a CSV file with a header row.
+	FormatCSV Format = "csv"
+)
+
+// ParseFormat parses a format name, defaulting to Parquet.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatParquet:
+		return FormatParquet, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, expected parquet or csv", s)
+	}
+}
+
+// ContentType is the MIME type of files of the format.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/vnd.apache.parquet"
+}
+
+// ColumnType is the type of the values of a column.
+type ColumnType int
+
+const (
+	// IntColumn holds 64-bit integers.
+	IntColumn ColumnType = iota
+	// NumberColumn holds doubles, including NaN and infinities.
+	NumberColumn
+	// BoolColumn holds booleans.
+	BoolColumn
+	// StringColumn holds strings. Values of other types are JSON-encoded.
+	StringColumn
+	// TimeColumn holds timestamps.
+	TimeColumn
+)
+
+// merge returns the narrowest type that holds the values of both types.
+func (t ColumnType) merge(other ColumnType) ColumnType {
+	switch {
+	case t == other:
+		return t
+	case (t == IntColumn && other == NumberColumn) || (t == NumberColumn && other == IntColumn):
+		return NumberColumn
+	default:
+		return StringColumn
+	}
+}
+
+// convert converts a value to the type of the column, or nil if the value does not fit it.
+func (t ColumnType) convert(v any) any {
+	switch t {
+	case IntColumn:
+		switch v := v.(type) {
+		case int:
+			return int64(v)
+		case int32:
+			return int64(v)
+		case int64:
+			return v
+		}
+	case NumberColumn:
+		switch v := v.(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		case string:
+			// Metrics store non-finite floats as strings.
+			switch v {
+			case db.NaNPostgresString, db.InfPostgresString, db.NegInfPostgresString:
+				f, _ := strconv.ParseFloat(v, 64)
+				return f
+			}
+		}
+	case BoolColumn:
+		if v, ok := v.(bool); ok {
+			return v
+		}
+	case StringColumn:
+		switch v := v.(type) {
+		case nil:
+			return nil
+		case string:
+			return v
+		default:
+			bs, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Sprint(v)
+			}
+			return string(bs)
+		}
+	case TimeColumn:
+		switch v := v.(type) {
+		case time.Time:
+			return v
+		case *time.Time:
+			if v != nil {
+				return *v
+			}
+		}
+	}
+	return nil
+}
+
+// Column is a column of an export.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Writer writes the rows of an export. Rows hold a value, or nil, for every column, in order.
+type Writer interface {
+	Write(row []any) error
+	Close() error
+}
+
+// NewWriter returns a writer of a file of the format with the given columns.
+func NewWriter(w io.Writer, format Format, columns []Column) (Writer, error) {
+	switch format {
+	case FormatParquet:
+		return newParquetWriter(w, columns), nil
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.Name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (w *csvWriter) Write(row []any) error {
+	for i, c := range w.columns {
+		switch v := c.Type.convert(row[i]).(type) {
+		case nil:
+			w.record[i] = ""
+		case int64:
+			w.record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			w.record[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		case bool:
+			w.record[i] = strconv.FormatBool(v)
+		case string:
+			w.record[i] = v
+		case time.Time:
+			w.record[i] = v.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// parquetRowGroupSize bounds the rows the Parquet writer buffers before flushing a row group, and
+// so the memory an export uses.
+const parquetRowGroupSize = 16_384
+
+type parquetWriter struct {
+	w       *parquet.Writer
+	columns []Column
+	// leaves maps the position of a column to its index in the Parquet schema, which orders
+	// columns by name.
+	leaves []int
+	row    parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []Column) *parquetWriter {
+	group := parquet.Group{}
+	for _, c := range columns {
+		var node parquet.Node
+		switch c.Type {
+		case IntColumn:
+			node = parquet.Int(64)
+		case NumberColumn:
+			node = parquet.Leaf(parquet.DoubleType)
+		case BoolColumn:
+			node = parquet.Leaf(parquet.BooleanType)
+		case TimeColumn:
+			node = parquet.Timestamp(parquet.Microsecond)
+		default:
+			node = parquet.String()
+		}
+		group[c.Name] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("metrics", group)
+
+	indexes := map[string]int{}
+	for i, path := range schema.Columns() {
+		indexes[path[0]] = i
+	}
+	leaves := make([]int, len(columns))
+	for i, c := range columns {
+		leaves[i] = indexes[c.Name]
+	}
+
+	return &parquetWriter{
+		w: parquet.NewWriter(w, schema,
+			parquet.Compression(&parquet.Snappy),
+			parquet.MaxRowsPerRowGroup(parquetRowGroupSize)),
+		columns: columns,
+		leaves:  leaves,
+		row:     make(parquet.Row, len(columns)),
+	}
+}
+
+func (w *parquetWriter) Write(row []any) error {
+	for i, c := range w.columns {
+		var value parquet.Value
+		switch v := c.Type.convert(row[i]).(type) {
+		case nil:
+			w.row[w.leaves[i]] = parquet.NullValue().Level(0, 0, w.leaves[i])
+			continue
+		case int64:
+			value = parquet.Int64Value(v)
+		case float64:
+			value = parquet.DoubleValue(v)
+		case bool:
+			value = parquet.BooleanValue(v)
+		case string:
+			value = parquet.ByteArrayValue([]byte(v))
+		case time.Time:
+			value = parquet.Int64Value(v.UnixMicro())
+		}
+		w.row[w.leaves[i]] = value.Level(0, 1, w.leaves[i])
+	}
+	_, err := w.w.WriteRows([]parquet.Row{w.row})
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.w.Close()
+}
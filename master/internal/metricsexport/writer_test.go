@@ -0,0 +1,110 @@
+package metricsexport
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = []Column{
+	{Name: "run_id", Type: IntColumn},
+	{Name: "metrics.loss", Type: NumberColumn},
+	{Name: "hparams.global_batch_size", Type: NumberColumn},
+	{Name: "hparams.optimizer", Type: StringColumn},
+	{Name: "metadata.tags", Type: StringColumn},
+	{Name: "metrics.done", Type: BoolColumn},
+	{Name: "end_time", Type: TimeColumn},
+}
+
+var testTime = time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+
+var testRows = [][]any{
+	{1, 0.5, 32, "adam", []any{"a", "b"}, false, &testTime},
+	{2, "NaN", nil, nil, nil, "not a bool", nil},
+}
+
+func TestParseFormat(t *testing.T) {
+	for s, expected := range map[string]Format{
+		"":        FormatParquet,
+		"parquet": FormatParquet,
+		"csv":     FormatCSV,
+	} {
+		actual, err := ParseFormat(s)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	_, err := ParseFormat("xlsx")
+	require.ErrorContains(t, err, "unknown export format")
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatCSV, testColumns)
+	require.NoError(t, err)
+	for _, row := range testRows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+
+	require.Equal(t, `run_id,metrics.loss,hparams.global_batch_size,hparams.optimizer,`+
+		`metadata.tags,metrics.done,end_time
+1,0.5,32,adam,"[""a"",""b""]",false,2024-10-10T12:00:00Z
+2,NaN,,,,,
+`, buf.String())
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatParquet, testColumns)
+	require.NoError(t, err)
+	for _, row := range testRows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+
+	r := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+	defer r.Close()
+	require.Equal(t, int64(len(testRows)), r.NumRows())
+
+	indexes := map[string]int{}
+	for i, path := range r.Schema().Columns() {
+		indexes[path[0]] = i
+	}
+	rows := make([]parquet.Row, len(testRows))
+	n, err := r.ReadRows(rows)
+	require.Equal(t, len(testRows), n)
+	if err != nil {
+		require.ErrorContains(t, err, "EOF")
+	}
+
+	value := func(row int, column string) parquet.Value {
+		return rows[row][indexes[column]]
+	}
+	require.Equal(t, int64(1), value(0, "run_id").Int64())
+	require.Equal(t, 0.5, value(0, "metrics.loss").Double())
+	require.Equal(t, 32.0, value(0, "hparams.global_batch_size").Double())
+	require.Equal(t, "adam", value(0, "hparams.optimizer").String())
+	require.Equal(t, `["a","b"]`, value(0, "metadata.tags").String())
+	require.False(t, value(0, "metrics.done").Boolean())
+	require.False(t, value(0, "metrics.done").IsNull())
+	require.Equal(t, testTime.UnixMicro(), value(0, "end_time").Int64())
+
+	require.Equal(t, int64(2), value(1, "run_id").Int64())
+	require.True(t, math.IsNaN(value(1, "metrics.loss").Double()))
+	for _, column := range []string{
+		"hparams.global_batch_size", "hparams.optimizer", "metadata.tags", "metrics.done", "end_time",
+	} {
+		require.True(t, value(1, column).IsNull(), column)
+	}
+}
+
+func TestColumnTypeMerge(t *testing.T) {
+	require.Equal(t, NumberColumn, IntColumn.merge(NumberColumn))
+	require.Equal(t, NumberColumn, NumberColumn.merge(IntColumn))
+	require.Equal(t, BoolColumn, BoolColumn.merge(BoolColumn))
+	require.Equal(t, StringColumn, BoolColumn.merge(NumberColumn))
+}
@@ -0,0 +1,245 @@
+// Package otlp maps OpenTelemetry (OTLP) metric exports onto trial metrics.
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+const (
+	// TrialIDAttribute is the resource or data point attribute holding the ID of the trial that
+	// metrics belong to. It is optional, since the allocation token identifies the trial, but
+	// exports for any other trial are rejected.
+	TrialIDAttribute = "determined.trial_id"
+	// MetricGroupAttribute is the resource or data point attribute holding the metric group of
+	// metrics. Metrics are training metrics if it is not set.
+	MetricGroupAttribute = "determined.metric_group"
+	// StepsCompletedAttribute is the resource or data point attribute holding the number of
+	// batches the trial completed when the metrics were recorded. It is required.
+	StepsCompletedAttribute = "determined.steps_completed"
+)
+
+// MappingError is returned for data that cannot be mapped onto trial metrics.
+type MappingError struct {
+	Metric string
+	Reason string
+}
+
+func (e *MappingError) Error() string {
+	if e.Metric == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("metric %q: %s", e.Metric, e.Reason)
+}
+
+// labels are the Determined attributes of a resource or data point.
+type labels struct {
+	group *model.MetricGroup
+	steps *int32
+}
+
+type reportKey struct {
+	group model.MetricGroup
+	steps int32
+}
+
+// TrialMetrics maps the gauges and sums of an export onto metrics reports of a trial, one for
+// every metric group and step in the export, ordered by step. Each data point becomes the value
+// of its metric in the report of its group and step. The whole export is rejected with a
+// MappingError if any of it cannot be mapped, so no data is dropped.
+func TrialMetrics(
+	req *colmetricspb.ExportMetricsServiceRequest, trialID, trialRunID int32,
+) ([]db.TrialMetricsReport, error) {
+	reports := map[reportKey]*db.TrialMetricsReport{}
+	for _, rm := range req.ResourceMetrics {
+		resource, err := parseLabels("", rm.GetResource().GetAttributes(), trialID, labels{})
+		if err != nil {
+			return nil, err
+		}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				var points []*metricspb.NumberDataPoint
+				switch data := m.Data.(type) {
+				case *metricspb.Metric_Gauge:
+					points = data.Gauge.GetDataPoints()
+				case *metricspb.Metric_Sum:
+					points = data.Sum.GetDataPoints()
+				case *metricspb.Metric_Histogram, *metricspb.Metric_ExponentialHistogram,
+					*metricspb.Metric_Summary:
+					return nil, &MappingError{
+						Metric: m.Name,
+						Reason: "only gauges and sums can be reported as trial metrics",
+					}
+				default:
+					return nil, &MappingError{Metric: m.Name, Reason: "metric has no data"}
+				}
+				if m.Name == "" {
+					return nil, &MappingError{Reason: "metrics must have a name"}
+				}
+
+				for _, p := range points {
+					if err := addPoint(reports, m.Name, p, resource, trialID, trialRunID); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	sorted := make([]db.TrialMetricsReport, 0, len(reports))
+	for _, r := range reports {
+		sorted = append(sorted, *r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		si, sj := sorted[i].Metrics.GetStepsCompleted(), sorted[j].Metrics.GetStepsCompleted()
+		if si != sj {
+			return si < sj
+		}
+		return sorted[i].Group < sorted[j].Group
+	})
+	return sorted, nil
+}
+
+func addPoint(
+	reports map[reportKey]*db.TrialMetricsReport, name string, p *metricspb.NumberDataPoint, resource labels,
+	trialID, trialRunID int32,
+) error {
+	point, err := parseLabels(name, p.Attributes, trialID, resource)
+	if err != nil {
+		return err
+	}
+	if point.steps == nil {
+		return &MappingError{
+			Metric: name,
+			Reason: fmt.Sprintf("data point has no %s attribute", StepsCompletedAttribute),
+		}
+	}
+	group := model.TrainingMetricGroup
+	if point.group != nil {
+		group = *point.group
+	}
+
+	var value float64
+	switch v := p.Value.(type) {
+	case *metricspb.NumberDataPoint_AsDouble:
+		value = v.AsDouble
+	case *metricspb.NumberDataPoint_AsInt:
+		value = float64(v.AsInt)
+	default:
+		return &MappingError{Metric: name, Reason: "data point has no value"}
+	}
+
+	key := reportKey{group: group, steps: *point.steps}
+	r, ok := reports[key]
+	if !ok {
+		r = &db.TrialMetricsReport{
+			Group: group,
+			Metrics: &trialv1.TrialMetrics{
+				TrialId:        trialID,
+				TrialRunId:     trialRunID,
+				StepsCompleted: point.steps,
+				Metrics: &commonv1.Metrics{
+					AvgMetrics: &structpb.Struct{Fields: map[string]*structpb.Value{}},
+				},
+			},
+		}
+		reports[key] = r
+	}
+	fields := r.Metrics.Metrics.AvgMetrics.Fields
+	if _, ok := fields[name]; ok {
+		return &MappingError{
+			Metric: name,
+			Reason: fmt.Sprintf("metric is reported more than once for %s %d of group %q",
+				StepsCompletedAttribute, *point.steps, group),
+		}
+	}
+	fields[name] = structpb.NewNumberValue(value)
+
+	if p.TimeUnixNano != 0 {
+		t := time.Unix(0, int64(p.TimeUnixNano))
+		if r.Metrics.ReportTime == nil || t.After(r.Metrics.ReportTime.AsTime()) {
+			r.Metrics.ReportTime = timestamppb.New(t)
+		}
+	}
+	return nil
+}
+
+// parseLabels reads the Determined attributes of a resource or data point on top of the labels
+// of its parent. Any other attribute of a data point is rejected, since it would make metrics of
+// the same name indistinguishable.
+func parseLabels(
+	metric string, attrs []*commonpb.KeyValue, trialID int32, parent labels,
+) (labels, error) {
+	l := parent
+	for _, kv := range attrs {
+		switch kv.Key {
+		case TrialIDAttribute:
+			id, err := intValue(kv.Value)
+			if err != nil {
+				return l, attributeError(metric, kv.Key, err)
+			}
+			if id != int64(trialID) {
+				return l, &MappingError{
+					Metric: metric,
+					Reason: fmt.Sprintf("%s is %d, but the allocation token belongs to trial %d",
+						kv.Key, id, trialID),
+				}
+			}
+		case MetricGroupAttribute:
+			group := model.MetricGroup(kv.Value.GetStringValue())
+			if group.Validate() != nil {
+				return l, attributeError(metric, kv.Key,
+					fmt.Errorf("%q is not a valid metric group", group))
+			}
+			l.group = &group
+		case StepsCompletedAttribute:
+			steps, err := intValue(kv.Value)
+			if err == nil && (steps < 0 || steps > math.MaxInt32) {
+				err = fmt.Errorf("%d is out of range", steps)
+			}
+			if err != nil {
+				return l, attributeError(metric, kv.Key, err)
+			}
+			l.steps = ptrs.Ptr(int32(steps))
+		default:
+			if metric != "" {
+				return l, &MappingError{
+					Metric: metric,
+					Reason: fmt.Sprintf("data point attribute %q cannot be mapped onto trial metrics",
+						kv.Key),
+				}
+			}
+		}
+	}
+	return l, nil
+}
+
+func intValue(v *commonpb.AnyValue) (int64, error) {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_IntValue:
+		return v.IntValue, nil
+	case *commonpb.AnyValue_StringValue:
+		return strconv.ParseInt(v.StringValue, 10, 64)
+	default:
+		return 0, fmt.Errorf("expected an integer")
+	}
+}
+
+func attributeError(metric, key string, err error) error {
+	return &MappingError{Metric: metric, Reason: fmt.Sprintf("invalid %s: %s", key, err)}
+}
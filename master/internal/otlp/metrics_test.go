@@ -0,0 +1,170 @@
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	testTrialID    = 7
+	testTrialRunID = 2
+)
+
+func intAttr(key string, v int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}},
+	}
+}
+
+func stringAttr(key, v string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}},
+	}
+}
+
+func gauge(name string, points ...*metricspb.NumberDataPoint) *metricspb.Metric {
+	return &metricspb.Metric{
+		Name: name,
+		Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: points}},
+	}
+}
+
+func point(steps int64, value float64, attrs ...*commonpb.KeyValue) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:   append([]*commonpb.KeyValue{intAttr(StepsCompletedAttribute, steps)}, attrs...),
+		TimeUnixNano: uint64(steps) * 1e9,
+		Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+func export(
+	resource []*commonpb.KeyValue, metrics ...*metricspb.Metric,
+) *colmetricspb.ExportMetricsServiceRequest {
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource:     &resourcepb.Resource{Attributes: resource},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{Metrics: metrics}},
+		}},
+	}
+}
+
+func TestTrialMetrics(t *testing.T) {
+	req := export(
+		[]*commonpb.KeyValue{intAttr(TrialIDAttribute, testTrialID), stringAttr("service.name", "x")},
+		gauge("loss", point(200, 0.5), point(100, 0.7)),
+		&metricspb.Metric{
+			Name: "tokens",
+			Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{DataPoints: []*metricspb.NumberDataPoint{{
+				Attributes: []*commonpb.KeyValue{stringAttr(StepsCompletedAttribute, "100")},
+				Value:      &metricspb.NumberDataPoint_AsInt{AsInt: 4096},
+			}}}},
+		},
+		gauge("accuracy", point(100, 0.9, stringAttr(MetricGroupAttribute, "validation"))),
+	)
+
+	reports, err := TrialMetrics(req, testTrialID, testTrialRunID)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+
+	type summary struct {
+		group   model.MetricGroup
+		steps   int32
+		metrics map[string]any
+		seconds int64
+	}
+	var actual []summary
+	for _, r := range reports {
+		require.Equal(t, int32(testTrialID), r.Metrics.TrialId)
+		require.Equal(t, int32(testTrialRunID), r.Metrics.TrialRunId)
+		actual = append(actual, summary{
+			group:   r.Group,
+			steps:   r.Metrics.GetStepsCompleted(),
+			metrics: r.Metrics.Metrics.AvgMetrics.AsMap(),
+			seconds: r.Metrics.ReportTime.GetSeconds(),
+		})
+	}
+	require.Equal(t, []summary{
+		{model.TrainingMetricGroup, 100, map[string]any{"loss": 0.7, "tokens": 4096.0}, 100},
+		{model.ValidationMetricGroup, 100, map[string]any{"accuracy": 0.9}, 100},
+		{model.TrainingMetricGroup, 200, map[string]any{"loss": 0.5}, 200},
+	}, actual)
+}
+
+func TestTrialMetricsResourceLabels(t *testing.T) {
+	req := export(
+		[]*commonpb.KeyValue{
+			stringAttr(MetricGroupAttribute, "inference"), intAttr(StepsCompletedAttribute, 5),
+		},
+		gauge("latency", &metricspb.NumberDataPoint{
+			Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: 1.5},
+		}),
+	)
+	reports, err := TrialMetrics(req, testTrialID, testTrialRunID)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, model.InferenceMetricGroup, reports[0].Group)
+	require.Equal(t, int32(5), reports[0].Metrics.GetStepsCompleted())
+	require.Nil(t, reports[0].Metrics.ReportTime)
+}
+
+func TestTrialMetricsRejects(t *testing.T) {
+	cases := map[string]struct {
+		req      *colmetricspb.ExportMetricsServiceRequest
+		expected string
+	}{
+		"other trial": {
+			export([]*commonpb.KeyValue{intAttr(TrialIDAttribute, 8)}, gauge("loss", point(1, 1))),
+			"determined.trial_id is 8, but the allocation token belongs to trial 7",
+		},
+		"no steps": {
+			export(nil, gauge("loss", &metricspb.NumberDataPoint{
+				Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: 1},
+			})),
+			`metric "loss": data point has no determined.steps_completed attribute`,
+		},
+		"negative steps": {
+			export(nil, gauge("loss", point(-1, 1))),
+			"-1 is out of range",
+		},
+		"invalid group": {
+			export(nil, gauge("loss", point(1, 1, stringAttr(MetricGroupAttribute, "a.b")))),
+			`"a.b" is not a valid metric group`,
+		},
+		"other attributes": {
+			export(nil, gauge("loss", point(1, 1, stringAttr("layer", "1")))),
+			`data point attribute "layer" cannot be mapped`,
+		},
+		"duplicate": {
+			export(nil, gauge("loss", point(1, 1), point(1, 2))),
+			"metric is reported more than once",
+		},
+		"histogram": {
+			export(nil, &metricspb.Metric{
+				Name: "latency",
+				Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{}},
+			}),
+			`metric "latency": only gauges and sums can be reported as trial metrics`,
+		},
+		"no value": {
+			export(nil, gauge("loss", &metricspb.NumberDataPoint{
+				Attributes: []*commonpb.KeyValue{intAttr(StepsCompletedAttribute, 1)},
+			})),
+			"data point has no value",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := TrialMetrics(c.req, testTrialID, testTrialRunID)
+			require.ErrorContains(t, err, c.expected)
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+		})
+	}
+}
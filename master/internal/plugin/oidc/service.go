@@ -46,6 +46,7 @@ type Service struct {
 	db           *db.PgDB
 	provider     *oidc.Provider
 	oauth2Config oauth2.Config
+	groupsFilter usergroup.GroupSyncFilter
 }
 
 // IDTokenClaims represents the set of claims in an OIDC ID token that we're concerned with.
@@ -67,6 +68,11 @@ var errNotProvisioned = echo.NewHTTPError(http.StatusNotFound, "user has not bee
 func New(db *db.PgDB, config config.OIDCConfig, pachEnabled bool) (*Service, error) {
 	ctx := context.Background()
 
+	groupsFilter, err := usergroup.NewGroupSyncFilter(config.GroupsSyncPrefix, config.GroupsSyncRegex)
+	if err != nil {
+		return nil, err
+	}
+
 	provider, err := oidc.NewProvider(ctx, config.IDPSSOURL)
 	if err != nil {
 		return nil, err
@@ -96,9 +102,10 @@ func New(db *db.PgDB, config config.OIDCConfig, pachEnabled bool) (*Service, err
 	}
 
 	return &Service{
-		config:   config,
-		db:       db,
-		provider: provider,
+		config:       config,
+		db:           db,
+		provider:     provider,
+		groupsFilter: groupsFilter,
 		oauth2Config: oauth2.Config{
 			ClientID:     config.ClientID,
 			ClientSecret: secret,
@@ -365,8 +372,8 @@ func (s *Service) syncUser(ctx context.Context, u *model.User, claims *IDTokenCl
 				}
 			}
 			if s.config.GroupsAttributeName != "" {
-				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, u, claims.Groups); err != nil {
-					return fmt.Errorf("could not update user group membership: %s", err)
+				if err := s.syncGroups(ctx, tx, u, claims.Groups); err != nil {
+					return err
 				}
 			}
 			return nil
@@ -376,6 +383,31 @@ func (s *Service) syncUser(ctx context.Context, u *model.User, claims *IDTokenCl
 	return user.ByUsername(ctx, u.Username)
 }
 
+// syncGroups makes the groups a user is a member of, among the ones matching the groups filter,
+// the ones the IdP claims. Roles assigned to the groups follow, and if the user leaves any group,
+// their existing sessions are revoked.
+func (s *Service) syncGroups(ctx context.Context, tx bun.IDB, u *model.User, groups []string) error {
+	added, removed, err := usergroup.SyncUserGroupMembershipTx(ctx, tx, u, groups, s.groupsFilter)
+	if err != nil {
+		return fmt.Errorf("could not update user group membership: %s", err)
+	}
+	if len(added) != 0 || len(removed) != 0 {
+		logrus.WithFields(logrus.Fields{
+			"user":           u.Username,
+			"groups-added":   added,
+			"groups-removed": removed,
+		}).Info("synced user group membership from OIDC claims")
+	}
+	if len(removed) != 0 {
+		// Sessions started while the user was in the removed groups would keep their access until
+		// they expire, so the user has to log in again.
+		if err := user.DeleteLoginSessionsByUserIDTx(ctx, tx, u.ID); err != nil {
+			return fmt.Errorf("could not revoke sessions of %q: %s", u.Username, err)
+		}
+	}
+	return nil
+}
+
 // provisionUser: If we get forwarded an ID token for an unknown user from the IdP,
 // create a remote user with no password in the user table.
 func (s *Service) provisionUser(
@@ -396,8 +428,8 @@ func (s *Service) provisionUser(
 				return errNotProvisioned
 			}
 			if s.config.GroupsAttributeName != "" {
-				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, &u, groups); err != nil {
-					return fmt.Errorf("could not update user group membership: %s", err)
+				if err := s.syncGroups(ctx, tx, &u, groups); err != nil {
+					return err
 				}
 			}
 			return nil
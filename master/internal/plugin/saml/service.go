@@ -53,11 +53,16 @@ type userConfig struct {
 	agentGIDAttributeName       string
 	agentUserNameAttributeName  string
 	agentGroupNameAttributeName string
+	groupsFilter                usergroup.GroupSyncFilter
 }
 
 // New constructs a new SAML service that is capable of sending SAML requests and consuming
 // responses.
 func New(db *db.PgDB, c config.SAMLConfig) (*Service, error) {
+	groupsFilter, err := usergroup.NewGroupSyncFilter(c.GroupsSyncPrefix, c.GroupsSyncRegex)
+	if err != nil {
+		return nil, err
+	}
 	uc := userConfig{
 		autoProvisionUsers:          c.AutoProvisionUsers,
 		groupsAttributeName:         c.GroupsAttributeName,
@@ -66,6 +71,7 @@ func New(db *db.PgDB, c config.SAMLConfig) (*Service, error) {
 		agentGIDAttributeName:       c.AgentGIDAttributeName,
 		agentUserNameAttributeName:  c.AgentUserNameAttributeName,
 		agentGroupNameAttributeName: c.AgentGroupNameAttributeName,
+		groupsFilter:                groupsFilter,
 	}
 
 	key, cert, err := proxy.GenSignedCert()
@@ -347,8 +353,8 @@ func (s *Service) syncUser(ctx context.Context, u *model.User, uAttr *userAttrib
 				}
 			}
 			if s.userConfig.groupsAttributeName != "" {
-				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, u, uAttr.groups); err != nil {
-					return fmt.Errorf("could not update user group membership: %s", err)
+				if err := s.syncGroups(ctx, tx, u, uAttr.groups); err != nil {
+					return err
 				}
 			}
 			return nil
@@ -359,6 +365,32 @@ func (s *Service) syncUser(ctx context.Context, u *model.User, uAttr *userAttrib
 	return user.ByUsername(ctx, u.Username)
 }
 
+// syncGroups makes the groups a user is a member of, among the ones matching the groups filter,
+// the ones the IdP asserts. Roles assigned to the groups follow, and if the user leaves any group,
+// their existing sessions are revoked.
+func (s *Service) syncGroups(ctx context.Context, tx bun.IDB, u *model.User, groups []string) error {
+	added, removed, err := usergroup.SyncUserGroupMembershipTx(
+		ctx, tx, u, groups, s.userConfig.groupsFilter)
+	if err != nil {
+		return fmt.Errorf("could not update user group membership: %w", err)
+	}
+	if len(added) != 0 || len(removed) != 0 {
+		logrus.WithFields(logrus.Fields{
+			"user":           u.Username,
+			"groups-added":   added,
+			"groups-removed": removed,
+		}).Info("synced user group membership from SAML attributes")
+	}
+	if len(removed) != 0 {
+		// Sessions started while the user was in the removed groups would keep their access until
+		// they expire, so the user has to log in again.
+		if err := user.DeleteLoginSessionsByUserIDTx(ctx, tx, u.ID); err != nil {
+			return fmt.Errorf("could not revoke sessions of %q: %w", u.Username, err)
+		}
+	}
+	return nil
+}
+
 // provisionUser: If we get forwarded an identity for an unknown user from the IdP,
 // create a remote user with no password in the user table.
 func (s *Service) provisionUser(
@@ -379,8 +411,8 @@ func (s *Service) provisionUser(
 				return err
 			}
 			if s.userConfig.groupsAttributeName != "" {
-				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, &u, groups); err != nil {
-					return fmt.Errorf("could not update user group membership: %w", err)
+				if err := s.syncGroups(ctx, tx, &u, groups); err != nil {
+					return err
 				}
 			}
 			return nil
@@ -144,6 +144,34 @@ func TestSAMLWorkflowUserProvisioned(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSAMLGroupRemovalRevokesLoginSessions(t *testing.T) {
+	s := mockService(true)
+	ctx := context.Background()
+
+	username := uuid.NewString()
+	resp := getUserResponse(username, username, 42, 37, username+"group", []string{"abc"})
+	u := processResponseUnprovisioned(ctx, t, resp.Assertion, username, username, 42, 37,
+		username+"group", s)
+	token, err := user.StartSession(ctx, u)
+	require.NoError(t, err)
+	taskToken, err := user.StartSession(ctx, u, user.WithTaskSession())
+	require.NoError(t, err)
+
+	// Joining a group keeps existing sessions.
+	resp = getUserResponse(username, username, 42, 37, username+"group", []string{"abc", "bcd"})
+	processResponseProvisioned(ctx, t, resp.Assertion, username, username, 42, 37, username+"group", s)
+	_, _, err = user.ByToken(ctx, token, &model.ExternalSessions{})
+	require.NoError(t, err)
+
+	// Leaving one revokes the sessions the user logged in with, but not those of their tasks.
+	resp = getUserResponse(username, username, 42, 37, username+"group", []string{"bcd"})
+	processResponseProvisioned(ctx, t, resp.Assertion, username, username, 42, 37, username+"group", s)
+	_, _, err = user.ByToken(ctx, token, &model.ExternalSessions{})
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, _, err = user.ByToken(ctx, taskToken, &model.ExternalSessions{})
+	require.NoError(t, err)
+}
+
 func mockService(autoProvision bool) *Service {
 	service := &Service{
 		db:           db.SingleDB(),
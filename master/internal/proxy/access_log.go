@@ -0,0 +1,295 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	// userIDKey is the echo context key HTTPAuth stores the ID of the requesting user under.
+	userIDKey = "proxy-user-id"
+
+	accessLogBufferSize    = 4096
+	accessLogBatchSize     = 500
+	accessLogFlushInterval = time.Second
+	accessLogPruneInterval = time.Hour
+)
+
+var (
+	// accessLogs queues access logs for the single writeAccessLogs goroutine to persist.
+	accessLogs           = make(chan *AccessLog, accessLogBufferSize)
+	startAccessLogWriter sync.Once
+)
+
+var (
+	proxyRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "det",
+		Name:      "proxy_requests_total",
+		Help:      "Requests proxied to services, counting WebSocket and TCP sessions once",
+	}, []string{"service_id", "websocket", "code"})
+
+	proxyBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "det",
+		Name:      "proxy_bytes_total",
+		Help:      "Bytes proxied to (in) and from (out) services",
+	}, []string{"service_id", "direction"})
+
+	proxyWebSocketSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "det",
+		Name:      "proxy_websocket_session_seconds_total",
+		Help:      "Total length of the WebSocket and TCP sessions proxied to services",
+	}, []string{"service_id"})
+
+	proxyAccessLogsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Subsystem: "det",
+		Name:      "proxy_access_logs_dropped_total",
+		Help:      "Access logs of proxied requests dropped because too many were waiting to be persisted",
+	})
+)
+
+// AccessLog is a row of the proxy_access_logs table, recording a request proxied to a service.
+// WebSocket and TCP sessions are recorded once, when they end.
+type AccessLog struct {
+	bun.BaseModel `bun:"table:proxy_access_logs"`
+
+	ID        int64         `bun:"id,pk,autoincrement"`
+	TaskID    model.TaskID  `bun:"task_id"`
+	ServiceID string        `bun:"service_id"`
+	UserID    *model.UserID `bun:"user_id"`
+	RemoteIP  string        `bun:"remote_ip"`
+	Method    string        `bun:"method"`
+	Path      string        `bun:"path"`
+	Status    int           `bun:"status"`
+	BytesIn   int64         `bun:"bytes_in"`
+	BytesOut  int64         `bun:"bytes_out"`
+	WebSocket bool          `bun:"websocket"`
+	StartTime time.Time     `bun:"start_time"`
+	EndTime   time.Time     `bun:"end_time"`
+}
+
+// Proto converts an access log to its proto representation.
+func (l *AccessLog) Proto() *apiv1.ProxyAccessLog {
+	pb := &apiv1.ProxyAccessLog{
+		Id:        l.ID,
+		TaskId:    string(l.TaskID),
+		ServiceId: l.ServiceID,
+		RemoteIp:  l.RemoteIP,
+		Method:    l.Method,
+		Path:      l.Path,
+		Status:    int32(l.Status),
+		BytesIn:   l.BytesIn,
+		BytesOut:  l.BytesOut,
+		Websocket: l.WebSocket,
+		StartTime: timestamppb.New(l.StartTime),
+		EndTime:   timestamppb.New(l.EndTime),
+	}
+	if l.UserID != nil {
+		pb.UserId = ptrs.Ptr(int32(*l.UserID))
+	}
+	return pb
+}
+
+// AccessLogFilter limits the access logs AccessLogs returns.
+type AccessLogFilter struct {
+	UserID *model.UserID
+	After  *time.Time
+	Before *time.Time
+	Offset int
+	Limit  int
+}
+
+// AccessLogs returns the access logs of the services of a task, newest first.
+func AccessLogs(
+	ctx context.Context, taskID model.TaskID, filter AccessLogFilter,
+) ([]*AccessLog, error) {
+	logs := []*AccessLog{}
+	q := db.Bun().NewSelect().Model(&logs).Where("task_id = ?", taskID)
+	if filter.UserID != nil {
+		q = q.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.After != nil {
+		q = q.Where("start_time >= ?", *filter.After)
+	}
+	if filter.Before != nil {
+		q = q.Where("start_time < ?", *filter.Before)
+	}
+	q = db.PaginateBun(q, "id", db.SortDirectionDesc, filter.Offset, filter.Limit)
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// SetUserID records the user a proxy request was authenticated as, for the access logs.
+func SetUserID(c echo.Context, id model.UserID) {
+	c.Set(userIDKey, id)
+}
+
+// accessRecorder accumulates the access log of a request while it is proxied.
+type accessRecorder struct {
+	log      AccessLog
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+}
+
+func newAccessRecorder(c echo.Context, serviceID string, webSocket bool) *accessRecorder {
+	return &accessRecorder{log: AccessLog{
+		TaskID:    model.TaskID(strings.SplitN(serviceID, ":", 2)[0]),
+		ServiceID: serviceID,
+		RemoteIP:  c.RealIP(),
+		Method:    c.Request().Method,
+		Path:      c.Request().URL.Path,
+		WebSocket: webSocket,
+		StartTime: time.Now().UTC(),
+	}}
+}
+
+// record completes the access log of a request once it has been proxied, or refused with err,
+// counts it in the metrics and queues it to be persisted.
+func (p *Proxy) record(c echo.Context, rec *accessRecorder, err error) {
+	l := &rec.log
+	l.EndTime = time.Now().UTC()
+	if id, ok := c.Get(userIDKey).(model.UserID); ok {
+		l.UserID = &id
+	}
+
+	var httpErr *echo.HTTPError
+	res := c.Response()
+	switch {
+	case errors.As(err, &httpErr):
+		l.Status = httpErr.Code
+	case err != nil:
+		l.Status = http.StatusInternalServerError
+	case l.WebSocket && !res.Committed:
+		// Hijacked connections bypass the response, which is only written to on errors.
+		l.Status = http.StatusSwitchingProtocols
+	default:
+		l.Status = res.Status
+	}
+	l.BytesIn = rec.bytesIn.Load()
+	l.BytesOut = rec.bytesOut.Load()
+	if !l.WebSocket {
+		l.BytesOut = res.Size
+	}
+
+	proxyRequests.WithLabelValues(
+		l.ServiceID, strconv.FormatBool(l.WebSocket), strconv.Itoa(l.Status)).Inc()
+	proxyBytes.WithLabelValues(l.ServiceID, "in").Add(float64(l.BytesIn))
+	proxyBytes.WithLabelValues(l.ServiceID, "out").Add(float64(l.BytesOut))
+	if l.WebSocket {
+		proxyWebSocketSeconds.WithLabelValues(l.ServiceID).Add(l.EndTime.Sub(l.StartTime).Seconds())
+	}
+
+	select {
+	case accessLogs <- l:
+	default:
+		proxyAccessLogsDropped.Inc()
+		p.syslog.Warnf("dropping access log of %s %s: too many pending", l.Method, l.Path)
+	}
+}
+
+// writeAccessLogs persists queued access logs in batches.
+func writeAccessLogs() {
+	syslog := logrus.WithField("component", "proxy")
+	ticker := time.NewTicker(accessLogFlushInterval)
+	defer ticker.Stop()
+
+	var batch []*AccessLog
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if _, err := db.Bun().NewInsert().Model(&batch).Exec(context.Background()); err != nil {
+			syslog.WithError(err).Errorf("failed to persist %d access logs", len(batch))
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case l := <-accessLogs:
+			batch = append(batch, l)
+			if len(batch) >= accessLogBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// PruneAccessLogs deletes access logs older than retention every accessLogPruneInterval. It never
+// returns.
+func PruneAccessLogs(retention time.Duration) {
+	syslog := logrus.WithField("component", "proxy")
+	ticker := time.NewTicker(accessLogPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := deleteAccessLogsBefore(
+			context.Background(), time.Now().Add(-retention),
+		); err != nil {
+			syslog.WithError(err).Warn("failed to prune access logs")
+		}
+		<-ticker.C
+	}
+}
+
+func deleteAccessLogsBefore(ctx context.Context, t time.Time) error {
+	if _, err := db.Bun().NewDelete().Model((*AccessLog)(nil)).
+		Where("end_time < ?", t).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting access logs before %s: %w", t, err)
+	}
+	return nil
+}
+
+// deleteServiceMetrics drops the metrics of an unregistered service.
+func deleteServiceMetrics(serviceID string) {
+	labels := prometheus.Labels{"service_id": serviceID}
+	proxyRequests.DeletePartialMatch(labels)
+	proxyBytes.DeletePartialMatch(labels)
+	proxyWebSocketSeconds.DeletePartialMatch(labels)
+}
+
+// countingReadCloser counts the bytes read through it.
+type countingReadCloser struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	io.Writer
+	n *atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
@@ -46,6 +46,7 @@ type Proxy struct {
 	lock     sync.RWMutex
 	HTTPAuth ProxyHTTPAuth
 	services map[string]*Service
+	routes   map[string]*route
 	syslog   *logrus.Entry
 }
 
@@ -62,8 +63,10 @@ func InitProxy(httpAuth ProxyHTTPAuth) {
 	DefaultProxy = &Proxy{
 		HTTPAuth: httpAuth,
 		services: make(map[string]*Service),
+		routes:   make(map[string]*route),
 		syslog:   logrus.WithField("component", "proxy"),
 	}
+	startAccessLogWriter.Do(func() { go writeAccessLogs() })
 	err := LoadOrGenCA()
 	if err != nil {
 		logrus.Errorf("error generating key and cert: %t", err)
@@ -99,13 +102,15 @@ func (p *Proxy) Unregister(serviceID string) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	delete(p.services, serviceID)
+	deleteServiceMetrics(serviceID)
 }
 
-// ClearProxy erases all services from the proxy in case any handlers are still active.
+// ClearProxy erases all services and routes from the proxy in case any handlers are still active.
 func (p *Proxy) ClearProxy() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	p.services = make(map[string]*Service)
+	p.routes = make(map[string]*route)
 }
 
 // GetService returns the Service, if any, given the serviceID key.
@@ -124,17 +129,27 @@ func (p *Proxy) GetService(serviceID string) *Service {
 }
 
 // NewProxyHandler returns a middleware function for proxying HTTP-like traffic to services
-// running in the cluster. Services an HTTP request through the /proxy/:service/* route.
+// running in the cluster. Services an HTTP request through the /proxy/:service/* route, where
+// :service is a service or a route balancing across services. Every request to a registered
+// service is recorded in the access logs.
 func (p *Proxy) NewProxyHandler(serviceID string) echo.HandlerFunc {
-	return func(c echo.Context) error {
+	return func(c echo.Context) (err error) {
 		// Look up the service name in the url path.
 		serviceName := c.Param(serviceID)
-		service := p.GetService(serviceName)
+		resolvedID, service, routed := p.resolve(serviceName)
 
-		if service == nil {
+		switch {
+		case service == nil && routed:
+			return echo.NewHTTPError(http.StatusServiceUnavailable,
+				fmt.Sprintf("no service available for route: %s", serviceName))
+		case service == nil:
 			return echo.NewHTTPError(http.StatusNotFound,
 				fmt.Sprintf("service not found: %s", serviceName))
 		}
+		c.Set(serviceIDKey, resolvedID)
+
+		rec := newAccessRecorder(c, resolvedID, service.ProxyTCP || c.IsWebSocket())
+		defer func() { p.record(c, rec, err) }()
 
 		if !service.AllowUnauthenticated {
 			switch done, err := p.HTTPAuth(c); {
@@ -156,19 +171,33 @@ func (p *Proxy) NewProxyHandler(serviceID string) echo.HandlerFunc {
 		if c.IsWebSocket() && req.Header.Get(echo.HeaderXForwardedFor) == "" {
 			req.Header.Set(echo.HeaderXForwardedFor, c.RealIP())
 		}
+		if routed {
+			stripRoutePrefix(req, serviceName)
+		}
 
 		// Proxy the request to the target host.
 		var proxy http.Handler
 		switch {
 		case service.ProxyTCP:
-			proxy = newSingleHostReverseTCPOverWebSocketProxy(c, service.URL)
+			proxy = newSingleHostReverseTCPOverWebSocketProxy(c, service.URL, rec)
 		case c.IsWebSocket():
-			proxy = newSingleHostReverseWebSocketProxy(c, service.URL)
+			proxy = newSingleHostReverseWebSocketProxy(c, service.URL, rec)
 		default:
+			if req.Body != nil {
+				req.Body = &countingReadCloser{ReadCloser: req.Body, n: &rec.bytesIn}
+			}
 			newProxy, err := setUpProxy(service.URL)
 			if err != nil {
 				return err
 			}
+			if routed {
+				newProxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+					p.syslog.WithError(err).Warnf("proxying to service %s of route %s",
+						resolvedID, serviceName)
+					p.markUnhealthy(serviceName, resolvedID)
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}
 
 			proxy = newProxy
 		}
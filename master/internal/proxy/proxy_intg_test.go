@@ -4,17 +4,22 @@
 package proxy
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 var (
@@ -181,3 +186,95 @@ func TestNewProxyHandler(t *testing.T) {
 	handler = DefaultProxy.NewProxyHandler("wrong")
 	require.Error(t, handler(c))
 }
+
+func TestProxyAccessLogs(t *testing.T) {
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, "file://../../static/migrations")
+	require.NoError(t, etc.SetRootPath("../../static/srv"))
+
+	user := db.RequireMockUser(t, pgDB)
+	InitProxy(func(c echo.Context) (done bool, err error) {
+		SetUserID(c, user.ID)
+		return false, nil
+	})
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.Copy(io.Discard, r.Body)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("hello"))
+		require.NoError(t, err)
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	taskID := model.NewTaskID()
+	serviceID := string(taskID) + ":8080"
+	DefaultProxy.Register(serviceID, backendURL, false, false)
+	defer DefaultProxy.Unregister(serviceID)
+
+	req := httptest.NewRequest(
+		http.MethodPost, "/proxy/"+serviceID+"/api?token=secret", strings.NewReader("abc"))
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("service")
+	c.SetParamValues(serviceID)
+	require.NoError(t, DefaultProxy.NewProxyHandler("service")(c))
+
+	var logs []*AccessLog
+	require.Eventually(t, func() bool {
+		logs, err = AccessLogs(context.Background(), taskID, AccessLogFilter{})
+		require.NoError(t, err)
+		return len(logs) == 1
+	}, 10*time.Second, 100*time.Millisecond)
+
+	l := logs[0]
+	require.Equal(t, serviceID, l.ServiceID)
+	require.Equal(t, user.ID, *l.UserID)
+	require.Equal(t, http.MethodPost, l.Method)
+	// The query string, which can carry tokens, is not recorded.
+	require.Equal(t, "/proxy/"+serviceID+"/api", l.Path)
+	require.Equal(t, http.StatusOK, l.Status)
+	require.Equal(t, int64(3), l.BytesIn)
+	require.Equal(t, int64(5), l.BytesOut)
+	require.False(t, l.WebSocket)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(proxyRequests.WithLabelValues(serviceID, "false", "200")))
+	require.Equal(t, 3.0, testutil.ToFloat64(proxyBytes.WithLabelValues(serviceID, "in")))
+	require.Equal(t, 5.0, testutil.ToFloat64(proxyBytes.WithLabelValues(serviceID, "out")))
+
+	other := model.UserID(-1)
+	logs, err = AccessLogs(context.Background(), taskID, AccessLogFilter{UserID: &other})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+}
+
+func TestDeleteAccessLogsBefore(t *testing.T) {
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, "file://../../static/migrations")
+	ctx := context.Background()
+
+	taskID := model.NewTaskID()
+	now := time.Now().UTC()
+	accessLog := func(end time.Time) *AccessLog {
+		return &AccessLog{
+			TaskID:    taskID,
+			ServiceID: string(taskID),
+			Method:    http.MethodGet,
+			Path:      "/",
+			Status:    http.StatusOK,
+			StartTime: end,
+			EndTime:   end,
+		}
+	}
+	logs := []*AccessLog{accessLog(now.Add(-48 * time.Hour)), accessLog(now)}
+	_, err := db.Bun().NewInsert().Model(&logs).Exec(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, deleteAccessLogsBefore(ctx, now.Add(-24*time.Hour)))
+	remaining, err := AccessLogs(ctx, taskID, AccessLogFilter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	require.Equal(t, logs[1].ID, remaining[0].ID)
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// serviceIDKey is the echo context key the handler stores the ID of the service a request is
+	// forwarded to under.
+	serviceIDKey = "proxy-service-id"
+	// unhealthyCooldown is how long a service of a route that failed to answer is skipped for.
+	unhealthyCooldown = 10 * time.Second
+)
+
+// route balances the requests to a stable ID across a set of services.
+type route struct {
+	serviceIDs []string
+	next       int
+	// unhealthy holds when the services that failed to answer are tried again.
+	unhealthy map[string]time.Time
+}
+
+// RegisterRoute registers a route that balances the requests to routeID round-robin across the
+// given services, replacing the services of the route if it is already registered. Services that
+// are not registered, or recently failed to answer, are skipped. Requests reach the services
+// without the /proxy/:route prefix, which is passed in the X-Forwarded-Prefix header instead.
+func (p *Proxy) RegisterRoute(routeID string, serviceIDs []string) {
+	if routeID == "" {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	r, ok := p.routes[routeID]
+	if !ok {
+		p.syslog.Infof("registering route: %s (%v)", routeID, serviceIDs)
+		r = &route{unhealthy: make(map[string]time.Time)}
+		p.routes[routeID] = r
+	}
+	r.serviceIDs = append([]string(nil), serviceIDs...)
+	for id := range r.unhealthy {
+		if !slices.Contains(r.serviceIDs, id) {
+			delete(r.unhealthy, id)
+		}
+	}
+}
+
+// UnregisterRoute removes a route from the proxy. The services of the route stay registered.
+func (p *Proxy) UnregisterRoute(routeID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.routes, routeID)
+}
+
+// ServiceID returns the ID of the service a proxy request is forwarded to. For requests to a
+// route, it is the service the route picked.
+func ServiceID(c echo.Context) string {
+	id, _ := c.Get(serviceIDKey).(string)
+	return id
+}
+
+// resolve returns the service requests to a service or route ID are forwarded to, along with its
+// ID, and whether the ID is a route. The service is nil if there is none available.
+func (p *Proxy) resolve(id string) (string, *Service, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+	r, ok := p.routes[id]
+	if !ok {
+		service := p.services[id]
+		if service == nil {
+			return id, nil, false
+		}
+		service.LastRequested = now
+		clone := service.Clone()
+		return id, &clone, false
+	}
+
+	for i := range r.serviceIDs {
+		serviceID := r.serviceIDs[(r.next+i)%len(r.serviceIDs)]
+		service := p.services[serviceID]
+		if service == nil || now.Before(r.unhealthy[serviceID]) {
+			continue
+		}
+		r.next = (r.next + i + 1) % len(r.serviceIDs)
+		service.LastRequested = now
+		clone := service.Clone()
+		return serviceID, &clone, true
+	}
+	return "", nil, true
+}
+
+// markUnhealthy makes a route skip one of its services for a while after it failed to answer.
+func (p *Proxy) markUnhealthy(routeID, serviceID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if r, ok := p.routes[routeID]; ok && slices.Contains(r.serviceIDs, serviceID) {
+		p.syslog.Warnf("skipping service %s of route %s for %s", serviceID, routeID, unhealthyCooldown)
+		r.unhealthy[serviceID] = time.Now().Add(unhealthyCooldown)
+	}
+}
+
+// stripRoutePrefix removes the /proxy/:route prefix from the path of a request to a route and
+// passes it on in the X-Forwarded-Prefix header, so services behind routes can be served from
+// the root.
+func stripRoutePrefix(req *http.Request, routeID string) {
+	marker := "/" + routeID
+	i := strings.Index(req.URL.Path, marker)
+	if i < 0 {
+		return
+	}
+	prefix := req.URL.Path[:i+len(marker)]
+	req.Header.Set("X-Forwarded-Prefix", prefix)
+	req.URL.Path = "/" + strings.TrimPrefix(req.URL.Path[len(prefix):], "/")
+	req.URL.RawPath = ""
+}
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProxy(serviceIDs ...string) *Proxy {
+	p := &Proxy{
+		services: make(map[string]*Service),
+		routes:   make(map[string]*route),
+		syslog:   logrus.WithField("component", "proxy"),
+	}
+	for _, id := range serviceIDs {
+		p.services[id] = &Service{URL: &url.URL{Host: id}}
+	}
+	return p
+}
+
+func TestResolveRoute(t *testing.T) {
+	p := newTestProxy("a", "b", "c")
+
+	id, s, routed := p.resolve("a")
+	require.Equal(t, "a", id)
+	require.NotNil(t, s)
+	require.False(t, routed)
+	_, s, routed = p.resolve("missing")
+	require.Nil(t, s)
+	require.False(t, routed)
+
+	// Requests are balanced across the registered services of a route.
+	p.RegisterRoute("r", []string{"a", "b", "missing"})
+	var picked []string
+	for i := 0; i < 4; i++ {
+		id, s, routed := p.resolve("r")
+		require.NotNil(t, s)
+		require.True(t, routed)
+		picked = append(picked, id)
+	}
+	require.Equal(t, []string{"a", "b", "a", "b"}, picked)
+
+	// Services that failed to answer are skipped for a while.
+	p.markUnhealthy("r", "a")
+	id, _, _ = p.resolve("r")
+	require.Equal(t, "b", id)
+	id, _, _ = p.resolve("r")
+	require.Equal(t, "b", id)
+	p.routes["r"].unhealthy["a"] = time.Now().Add(-time.Second)
+	id, _, _ = p.resolve("r")
+	require.Equal(t, "a", id)
+
+	p.markUnhealthy("r", "a")
+	p.markUnhealthy("r", "b")
+	_, s, routed = p.resolve("r")
+	require.Nil(t, s)
+	require.True(t, routed)
+
+	// Replacing the services of a route forgets the health of the removed ones.
+	p.RegisterRoute("r", []string{"c"})
+	require.Empty(t, p.routes["r"].unhealthy)
+	id, _, _ = p.resolve("r")
+	require.Equal(t, "c", id)
+
+	p.UnregisterRoute("r")
+	_, s, routed = p.resolve("r")
+	require.Nil(t, s)
+	require.False(t, routed)
+}
+
+func TestStripRoutePrefix(t *testing.T) {
+	req := httptest.NewRequest("GET", "/proxy/deployment-bert/v1/predict?x=1", nil)
+	stripRoutePrefix(req, "deployment-bert")
+	require.Equal(t, "/v1/predict", req.URL.Path)
+	require.Equal(t, "x=1", req.URL.RawQuery)
+	require.Equal(t, "/proxy/deployment-bert", req.Header.Get("X-Forwarded-Prefix"))
+
+	req = httptest.NewRequest("GET", "/proxy/deployment-bert", nil)
+	stripRoutePrefix(req, "deployment-bert")
+	require.Equal(t, "/", req.URL.Path)
+}
@@ -45,7 +45,9 @@ func (w *websocketReadWriter) Write(buf []byte) (int, error) {
 	return len(buf), nil
 }
 
-func newSingleHostReverseTCPOverWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
+func newSingleHostReverseTCPOverWebSocketProxy(
+	c echo.Context, t *url.URL, rec *accessRecorder,
+) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		dialer := proxy.FromEnvironment()
 
@@ -69,8 +71,8 @@ func newSingleHostReverseTCPOverWebSocketProxy(c echo.Context, t *url.URL) http.
 		}
 
 		rw := &websocketReadWriter{ws: ws, buf: new(bytes.Buffer)}
-		copyReqErr := asyncCopy(rw, out)
-		copyResErr := asyncCopy(out, rw)
+		copyReqErr := asyncCopy(&countingWriter{Writer: rw, n: &rec.bytesOut}, out)
+		copyResErr := asyncCopy(&countingWriter{Writer: out, n: &rec.bytesIn}, rw)
 
 		if cerr := <-copyReqErr; cerr != nil {
 			c.Logger().Errorf("error copying request body for %v: %v", t, cerr)
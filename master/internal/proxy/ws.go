@@ -25,7 +25,9 @@ func connectWithbackoff(tlsConn *tls.Conn) error {
 	return err
 }
 
-func newSingleHostReverseWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
+func newSingleHostReverseWebSocketProxy(
+	c echo.Context, t *url.URL, rec *accessRecorder,
+) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		in, _, err := c.Response().Hijack()
 		if err != nil {
@@ -93,8 +95,8 @@ func newSingleHostReverseWebSocketProxy(c echo.Context, t *url.URL) http.Handler
 			return
 		}
 
-		copyReqErr := asyncCopy(out, in)
-		copyResErr := asyncCopy(in, out)
+		copyReqErr := asyncCopy(&countingWriter{Writer: out, n: &rec.bytesIn}, in)
+		copyResErr := asyncCopy(&countingWriter{Writer: in, n: &rec.bytesOut}, out)
 		if cerr := <-copyReqErr; cerr != nil {
 			c.Logger().Errorf("error copying request body for %v: %v", t, cerr)
 		}
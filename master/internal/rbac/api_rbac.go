@@ -32,6 +32,12 @@ type RBACAPIServer interface {
 		*apiv1.AssignRolesResponse, error)
 	RemoveAssignments(context.Context, *apiv1.RemoveAssignmentsRequest) (
 		*apiv1.RemoveAssignmentsResponse, error)
+	CreateRole(context.Context, *apiv1.CreateRoleRequest) (
+		*apiv1.CreateRoleResponse, error)
+	PatchRole(context.Context, *apiv1.PatchRoleRequest) (
+		*apiv1.PatchRoleResponse, error)
+	DeleteRole(context.Context, *apiv1.DeleteRoleRequest) (
+		*apiv1.DeleteRoleResponse, error)
 	AssignWorkspaceAdminToUserTx(
 		ctx context.Context, idb bun.IDB, workspaceID int, userID model.UserID,
 	) error
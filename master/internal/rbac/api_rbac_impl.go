@@ -449,6 +449,87 @@ func (a *RBACAPIServerImpl) RemoveAssignments(ctx context.Context,
 	return &apiv1.RemoveAssignmentsResponse{}, nil
 }
 
+// CreateRole creates a custom role from a set of permissions.
+func (a *RBACAPIServerImpl) CreateRole(ctx context.Context, req *apiv1.CreateRoleRequest,
+) (resp *apiv1.CreateRoleResponse, err error) {
+	defer func() {
+		err = apiutils.MapAndFilterErrors(err, nil, errorMapping)
+	}()
+
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = AuthZProvider.Get().CanUpdateRoles(ctx, *u); err != nil {
+		return nil, err
+	}
+
+	role, err := CreateRole(ctx, req.Name, permissionTypesToIDs(req.PermissionIds),
+		req.ScopeTypeMask)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.CreateRoleResponse{Role: role.Proto()}, nil
+}
+
+// PatchRole renames a custom role or replaces the permissions it grants.
+func (a *RBACAPIServerImpl) PatchRole(ctx context.Context, req *apiv1.PatchRoleRequest,
+) (resp *apiv1.PatchRoleResponse, err error) {
+	defer func() {
+		err = apiutils.MapAndFilterErrors(err, nil, errorMapping)
+	}()
+
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = AuthZProvider.Get().CanUpdateRoles(ctx, *u); err != nil {
+		return nil, err
+	}
+
+	mask := req.ScopeTypeMask
+	if id, ok := workspaceCreatorRoleID(); ok && id == int(req.RoleId) {
+		// Workspace creators are assigned this role on their workspaces.
+		mask = &rbacv1.ScopeTypeMask{Cluster: true, Workspace: true}
+	}
+
+	role, err := UpdateRole(ctx, int(req.RoleId), req.Name,
+		permissionTypesToIDs(req.PermissionIds), mask)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.PatchRoleResponse{Role: role.Proto()}, nil
+}
+
+// DeleteRole deletes a custom role, along with its assignments if cascade is set.
+func (a *RBACAPIServerImpl) DeleteRole(ctx context.Context, req *apiv1.DeleteRoleRequest,
+) (resp *apiv1.DeleteRoleResponse, err error) {
+	defer func() {
+		err = apiutils.MapAndFilterErrors(err, nil, errorMapping)
+	}()
+
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = AuthZProvider.Get().CanUpdateRoles(ctx, *u); err != nil {
+		return nil, err
+	}
+
+	if id, ok := workspaceCreatorRoleID(); ok && id == int(req.RoleId) {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"role %d is assigned to workspace creators by the master configuration", id)
+	}
+
+	if err = DeleteRole(ctx, int(req.RoleId), req.Cascade); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.DeleteRoleResponse{}, nil
+}
+
 // AssignWorkspaceAdminToUserTx assigns workspace admin to a given user.
 func (a *RBACAPIServerImpl) AssignWorkspaceAdminToUserTx(
 	ctx context.Context, idb bun.IDB, workspaceID int, userID model.UserID,
@@ -514,6 +595,21 @@ func ensureGroupsAreNotPersonal(ctx context.Context,
 	return nil
 }
 
+func permissionTypesToIDs(permissions []rbacv1.PermissionType) []int {
+	ids := make([]int, 0, len(permissions))
+	for _, p := range permissions {
+		ids = append(ids, int(p))
+	}
+	return ids
+}
+
+// workspaceCreatorRoleID returns the role assigned to users on the workspaces they create, if
+// any.
+func workspaceCreatorRoleID() (int, bool) {
+	c := config.GetMasterConfig().Security.AuthZ.AssignWorkspaceCreator
+	return c.RoleID, c.Enabled
+}
+
 func groupIDsFromAssignments(assignments []*rbacv1.GroupRoleAssignment) []int32 {
 	groupIDs := make([]int32, 0, len(assignments))
 	for _, ra := range assignments {
@@ -530,4 +626,6 @@ func init() {
 	}
 
 	errorMapping[ErrGlobalAssignedLocally] = ErrGlobalAssignedLocally
+	errorMapping[ErrBuiltInRole] = status.Error(codes.FailedPrecondition, ErrBuiltInRole.Error())
+	errorMapping[ErrRoleAssigned] = status.Error(codes.FailedPrecondition, ErrRoleAssigned.Error())
 }
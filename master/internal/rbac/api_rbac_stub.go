@@ -74,6 +74,24 @@ func (s *rbacAPIServerStub) RemoveAssignments(ctx context.Context,
 	return nil, UnimplementedError
 }
 
+func (s *rbacAPIServerStub) CreateRole(ctx context.Context, req *apiv1.CreateRoleRequest) (
+	*apiv1.CreateRoleResponse, error,
+) {
+	return nil, UnimplementedError
+}
+
+func (s *rbacAPIServerStub) PatchRole(ctx context.Context, req *apiv1.PatchRoleRequest) (
+	*apiv1.PatchRoleResponse, error,
+) {
+	return nil, UnimplementedError
+}
+
+func (s *rbacAPIServerStub) DeleteRole(ctx context.Context, req *apiv1.DeleteRoleRequest) (
+	*apiv1.DeleteRoleResponse, error,
+) {
+	return nil, UnimplementedError
+}
+
 func (s *rbacAPIServerStub) AssignWorkspaceAdminToUserTx(
 	ctx context.Context, idb bun.IDB, workspaceID int, userID model.UserID,
 ) error {
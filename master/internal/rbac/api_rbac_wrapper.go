@@ -76,6 +76,27 @@ func (s *RBACAPIServerWrapper) RemoveAssignments(ctx context.Context,
 	return rbacAPIServer.RemoveAssignments(ctx, req)
 }
 
+// CreateRole is a wrapper the same function the RBACAPIServer interface.
+func (s *RBACAPIServerWrapper) CreateRole(ctx context.Context, req *apiv1.CreateRoleRequest) (
+	*apiv1.CreateRoleResponse, error,
+) {
+	return rbacAPIServer.CreateRole(ctx, req)
+}
+
+// PatchRole is a wrapper the same function the RBACAPIServer interface.
+func (s *RBACAPIServerWrapper) PatchRole(ctx context.Context, req *apiv1.PatchRoleRequest) (
+	*apiv1.PatchRoleResponse, error,
+) {
+	return rbacAPIServer.PatchRole(ctx, req)
+}
+
+// DeleteRole is a wrapper the same function the RBACAPIServer interface.
+func (s *RBACAPIServerWrapper) DeleteRole(ctx context.Context, req *apiv1.DeleteRoleRequest) (
+	*apiv1.DeleteRoleResponse, error,
+) {
+	return rbacAPIServer.DeleteRole(ctx, req)
+}
+
 // AssignWorkspaceAdminToUserTx is a wrapper the same function the RBACAPIServer interface.
 func (s *RBACAPIServerWrapper) AssignWorkspaceAdminToUserTx(
 	ctx context.Context, idb bun.IDB, workspaceID int, userID model.UserID,
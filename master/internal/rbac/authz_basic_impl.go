@@ -79,6 +79,14 @@ func (a *RBACAuthZBasic) CanRemoveRoles(
 	return a.CanAssignRoles(ctx, curUser, groupRoleAssignments, userRoleAssignments)
 }
 
+// CanUpdateRoles returns nil if a user has admin privileges.
+func (a *RBACAuthZBasic) CanUpdateRoles(ctx context.Context, curUser model.User) error {
+	if curUser.Admin {
+		return nil
+	}
+	return authz.PermissionDeniedError{}
+}
+
 func init() {
 	AuthZProvider.Register("basic", &RBACAuthZBasic{})
 }
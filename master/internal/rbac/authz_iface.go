@@ -63,6 +63,12 @@ type RBACAuthZ interface {
 		curUser model.User,
 		groupRoleAssignments []*rbacv1.GroupRoleAssignment,
 		userRoleAssignments []*rbacv1.UserRoleAssignment) error
+
+	// CanUpdateRoles checks if a user can create, update and delete custom roles.
+	// POST /api/v1/roles
+	// PATCH /api/v1/roles/{role_id}
+	// DELETE /api/v1/roles/{role_id}
+	CanUpdateRoles(ctx context.Context, curUser model.User) error
 }
 
 // AuthZProvider is the authz registry for RBAC.
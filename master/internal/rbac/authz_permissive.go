@@ -78,6 +78,12 @@ func (p *RBACAuthZPermissive) CanRemoveRoles(
 	return (&RBACAuthZBasic{}).CanRemoveRoles(ctx, curUser, groupRoleAssignments, userRoleAssignments)
 }
 
+// CanUpdateRoles calls RBAC authz but enforces basic authz.
+func (p *RBACAuthZPermissive) CanUpdateRoles(ctx context.Context, curUser model.User) error {
+	_ = (&RBACAuthZRBAC{}).CanUpdateRoles(ctx, curUser)
+	return (&RBACAuthZBasic{}).CanUpdateRoles(ctx, curUser)
+}
+
 func init() {
 	AuthZProvider.Register("permissive", &RBACAuthZPermissive{})
 }
@@ -291,6 +291,25 @@ func (a *RBACAuthZRBAC) CanRemoveRoles(
 	return a.CanAssignRoles(ctx, curUser, groupRoleAssignments, userRoleAssignments)
 }
 
+// CanUpdateRoles checks if a user can create, update and delete custom roles.
+func (a *RBACAuthZRBAC) CanUpdateRoles(ctx context.Context, curUser model.User) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	fields["userID"] = curUser.ID
+	fields["permissionRequired"] = []audit.PermissionWithSubject{
+		{
+			PermissionTypes: []rbacv1.PermissionType{
+				rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_ROLES,
+			},
+			SubjectType: "role",
+		},
+	}
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	return db.DoesPermissionMatch(ctx, curUser.ID, nil, rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_ROLES)
+}
+
 func init() {
 	AuthZProvider.Register("rbac", &RBACAuthZRBAC{})
 }
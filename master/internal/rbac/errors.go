@@ -8,3 +8,10 @@ import (
 // permission using a non-global scope.
 // nolint:lll
 var ErrGlobalAssignedLocally = errors.New("a global-only permission cannot be assigned to a local scope")
+
+// ErrBuiltInRole occurs when an attempt is made to modify or delete a predefined role.
+var ErrBuiltInRole = errors.New("built-in roles cannot be modified or deleted")
+
+// ErrRoleAssigned occurs when an attempt is made to delete a role that is still assigned
+// without removing its assignments.
+var ErrRoleAssigned = errors.New("role is still assigned")
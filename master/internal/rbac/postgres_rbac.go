@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -638,3 +640,245 @@ func whichAreGlobalOnly(ctx context.Context, idb bun.IDB, roles []int32) ([]int3
 
 	return results, nil
 }
+
+// CreateRole creates a custom role granting the given permissions. The role must be assignable
+// to every scope type in mask, if one is given.
+func CreateRole(ctx context.Context, name string, permissionIDs []int,
+	mask *rbacv1.ScopeTypeMask,
+) (*Role, error) {
+	var role *Role
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := validateRoleName(name); err != nil {
+			return err
+		}
+		perms, err := validateRolePermissions(ctx, tx, permissionIDs, mask)
+		if err != nil {
+			return err
+		}
+
+		r := Role{Name: name, Created: time.Now().UTC(), BuiltIn: false}
+		if _, err := tx.NewInsert().Model(&r).Exec(ctx); err != nil {
+			return errors.Wrapf(db.MatchSentinelError(err), "error creating role %q", name)
+		}
+		if err := setRolePermissionsTx(ctx, tx, r.ID, perms); err != nil {
+			return err
+		}
+
+		role, err = roleByIDTx(ctx, tx, r.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole renames a custom role and, when permissionIDs is not empty, replaces the
+// permissions it grants. Permissions that are global-only cannot be added to a role that is
+// assigned to workspaces.
+func UpdateRole(ctx context.Context, roleID int, name *string, permissionIDs []int,
+	mask *rbacv1.ScopeTypeMask,
+) (*Role, error) {
+	var role *Role
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		r, err := customRoleForUpdateTx(ctx, tx, roleID)
+		if err != nil {
+			return err
+		}
+
+		if name != nil && *name != r.Name {
+			if err := validateRoleName(*name); err != nil {
+				return err
+			}
+			if _, err := tx.NewUpdate().Table("roles").
+				Set("role_name = ?", *name).
+				Where("id = ?", roleID).
+				Exec(ctx); err != nil {
+				return errors.Wrapf(db.MatchSentinelError(err), "error renaming role %d", roleID)
+			}
+		}
+
+		if len(permissionIDs) == 0 {
+			permissionIDs = Permissions(r.Permissions).IDs()
+		}
+		perms, err := validateRolePermissions(ctx, tx, permissionIDs, mask)
+		if err != nil {
+			return err
+		}
+		if !perms.ScopeTypeMask().Workspace {
+			assigned, err := tx.NewSelect().
+				TableExpr("role_assignments AS ra").
+				Join("JOIN role_assignment_scopes AS ras ON ra.scope_id = ras.id").
+				Where("ra.role_id = ?", roleID).
+				Where("ras.scope_workspace_id IS NOT NULL").
+				Exists(ctx)
+			if err != nil {
+				return errors.Wrapf(db.MatchSentinelError(err),
+					"error checking workspace assignments of role %d", roleID)
+			}
+			if assigned {
+				return errors.Wrapf(db.ErrInvalidInput,
+					"role %d is assigned to workspaces, but permissions %v are global-only",
+					roleID, globalOnlyNames(perms))
+			}
+		}
+		if err := setRolePermissionsTx(ctx, tx, roleID, perms); err != nil {
+			return err
+		}
+
+		role, err = roleByIDTx(ctx, tx, roleID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole deletes a custom role. A role that is still assigned is only deleted, along with
+// its assignments, if cascade is set.
+func DeleteRole(ctx context.Context, roleID int, cascade bool) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := customRoleForUpdateTx(ctx, tx, roleID); err != nil {
+			return err
+		}
+
+		assignments, err := tx.NewSelect().Table("role_assignments").
+			Where("role_id = ?", roleID).
+			Count(ctx)
+		if err != nil {
+			return errors.Wrapf(db.MatchSentinelError(err),
+				"error counting assignments of role %d", roleID)
+		}
+		if assignments > 0 && !cascade {
+			return errors.Wrapf(ErrRoleAssigned,
+				"role %d has %d assignments; remove them or delete it with cascade", roleID,
+				assignments)
+		}
+		if assignments > 0 {
+			if _, err := tx.NewUpdate().Table("users").
+				Set("modified_at = NOW()").
+				Where("id IN (?)", tx.NewSelect().
+					TableExpr("groups AS g").
+					Column("g.user_id").
+					Join("JOIN role_assignments AS ra ON ra.group_id = g.id").
+					Where("ra.role_id = ?", roleID)).
+				Exec(ctx); err != nil {
+				return fmt.Errorf("error updating user timestamps: %w", db.MatchSentinelError(err))
+			}
+		}
+
+		// Permission and role assignments are removed by the foreign keys' ON DELETE CASCADE.
+		if _, err := tx.NewDelete().Table("roles").Where("id = ?", roleID).Exec(ctx); err != nil {
+			return errors.Wrapf(db.MatchSentinelError(err), "error deleting role %d", roleID)
+		}
+		return nil
+	})
+}
+
+func roleByIDTx(ctx context.Context, idb bun.IDB, roleID int) (*Role, error) {
+	var r Role
+	if err := idb.NewSelect().Model(&r).
+		Relation("Permissions").
+		Where("id = ?", roleID).
+		Scan(ctx); err != nil {
+		return nil, errors.Wrapf(db.MatchSentinelError(err), "error getting role %d", roleID)
+	}
+	return &r, nil
+}
+
+// customRoleForUpdateTx locks a role for the rest of the transaction, making sure it is not
+// predefined.
+func customRoleForUpdateTx(ctx context.Context, tx bun.Tx, roleID int) (*Role, error) {
+	if _, err := tx.NewSelect().Table("roles").
+		Column("id").
+		Where("id = ?", roleID).
+		For("UPDATE").
+		Exec(ctx); err != nil {
+		return nil, errors.Wrapf(db.MatchSentinelError(err), "error locking role %d", roleID)
+	}
+	r, err := roleByIDTx(ctx, tx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if r.BuiltIn {
+		return nil, errors.Wrapf(ErrBuiltInRole, "role %q", r.Name)
+	}
+	return r, nil
+}
+
+func validateRoleName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.Wrap(db.ErrInvalidInput, "role name cannot be empty")
+	}
+	return nil
+}
+
+// validateRolePermissions looks up the permissions a role is made of and checks that the role
+// can be assigned to every scope type in mask.
+func validateRolePermissions(ctx context.Context, idb bun.IDB, permissionIDs []int,
+	mask *rbacv1.ScopeTypeMask,
+) (Permissions, error) {
+	if len(permissionIDs) == 0 {
+		return nil, errors.Wrap(db.ErrInvalidInput, "a role must grant at least one permission")
+	}
+
+	var perms Permissions
+	if err := idb.NewSelect().Model(&perms).
+		Where("id IN (?)", bun.In(permissionIDs)).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, errors.Wrap(db.MatchSentinelError(err), "error looking up permissions")
+	}
+	found := make(map[int]bool, len(perms))
+	for _, p := range perms {
+		found[p.ID] = true
+	}
+	for _, id := range permissionIDs {
+		if !found[id] {
+			return nil, errors.Wrapf(db.ErrInvalidInput, "unknown permission %d", id)
+		}
+	}
+
+	if mask != nil {
+		if !mask.Cluster {
+			return nil, errors.Wrap(db.ErrInvalidInput,
+				"roles are always assignable cluster-wide")
+		}
+		if mask.Workspace && !perms.ScopeTypeMask().Workspace {
+			return nil, errors.Wrapf(db.ErrInvalidInput,
+				"role cannot be assignable to workspaces, permissions %v are global-only",
+				globalOnlyNames(perms))
+		}
+	}
+	return perms, nil
+}
+
+func setRolePermissionsTx(ctx context.Context, tx bun.Tx, roleID int, perms Permissions) error {
+	if _, err := tx.NewDelete().Table("permission_assignments").
+		Where("role_id = ?", roleID).
+		Exec(ctx); err != nil {
+		return errors.Wrapf(db.MatchSentinelError(err),
+			"error removing permissions of role %d", roleID)
+	}
+
+	assignments := make([]PermissionAssignment, 0, len(perms))
+	for _, p := range perms {
+		assignments = append(assignments, PermissionAssignment{PermissionID: p.ID, RoleID: roleID})
+	}
+	if _, err := tx.NewInsert().Model(&assignments).Exec(ctx); err != nil {
+		return errors.Wrapf(db.MatchSentinelError(err),
+			"error assigning permissions to role %d", roleID)
+	}
+	return nil
+}
+
+func globalOnlyNames(perms Permissions) []string {
+	var names []string
+	for _, p := range perms {
+		if p.Global {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
@@ -111,6 +111,7 @@ type Role struct {
 	ID              int               `bun:"id,pk,autoincrement" json:"id"`
 	Name            string            `bun:"role_name,notnull" json:"name"`
 	Created         time.Time         `bun:"created_at,notnull" json:"created"`
+	BuiltIn         bool              `bun:"built_in,notnull" json:"built_in"`
 	Permissions     []Permission      `bun:"m2m:permission_assignments,join:Role=Permission"`
 	RoleAssignments []*RoleAssignment `bun:"rel:has-many,join:id=role_id"`
 }
@@ -122,6 +123,7 @@ func (r *Role) Proto() *rbacv1.Role {
 		Name:          r.Name,
 		Permissions:   Permissions(r.Permissions).Proto(),
 		ScopeTypeMask: Permissions(r.Permissions).ScopeTypeMask(),
+		BuiltIn:       r.BuiltIn,
 	}
 }
 
@@ -181,6 +183,7 @@ func (ra RoleAssignments) Proto() ([]*rbacv1.UserRoleAssignment, []*rbacv1.Group
 				Name:          a.Role.Name,
 				Permissions:   Permissions(a.Role.Permissions).Proto(),
 				ScopeTypeMask: Permissions(a.Role.Permissions).ScopeTypeMask(),
+				BuiltIn:       a.Role.BuiltIn,
 			}
 		}
 
@@ -598,6 +598,61 @@ func TestRbac(t *testing.T) {
 		require.Equal(t, testRole2.ID, int(roles[1]), "incorrect roleID returned")
 		require.Equal(t, testRole3.ID, int(roles[2]), "incorrect roleID returned")
 	})
+
+	t.Run("test custom roles", func(t *testing.T) {
+		name := "custom role " + uuid.NewString()
+		_, err := CreateRole(ctx, name, nil, nil)
+		require.ErrorIs(t, err, db.ErrInvalidInput, "roles need permissions")
+		_, err = CreateRole(ctx, name, []int{testPermission.ID, -1}, nil)
+		require.ErrorIs(t, err, db.ErrInvalidInput, "permissions must exist")
+		_, err = CreateRole(ctx, name, []int{testPermission.ID, globalTestPermission.ID},
+			&rbacv1.ScopeTypeMask{Cluster: true, Workspace: true})
+		require.ErrorIs(t, err, db.ErrInvalidInput, "global-only roles aren't workspace-assignable")
+
+		role, err := CreateRole(ctx, name, []int{testPermission.ID, testPermission2.ID},
+			&rbacv1.ScopeTypeMask{Cluster: true, Workspace: true})
+		require.NoError(t, err)
+		require.False(t, role.BuiltIn)
+		require.ElementsMatch(t, []int{testPermission.ID, testPermission2.ID},
+			Permissions(role.Permissions).IDs())
+
+		_, err = CreateRole(ctx, name, []int{testPermission.ID}, nil)
+		require.ErrorIs(t, err, db.ErrDuplicateRecord)
+
+		// Predefined roles are protected.
+		_, err = UpdateRole(ctx, 1, ptrs.Ptr("renamed"), nil, nil)
+		require.ErrorIs(t, err, ErrBuiltInRole)
+		require.ErrorIs(t, DeleteRole(ctx, 1, true), ErrBuiltInRole)
+
+		role, err = UpdateRole(ctx, role.ID, ptrs.Ptr(name+" renamed"),
+			[]int{testPermission3.ID}, nil)
+		require.NoError(t, err)
+		require.Equal(t, name+" renamed", role.Name)
+		require.Equal(t, []int{testPermission3.ID}, Permissions(role.Permissions).IDs())
+
+		workspaceID := int32(testWorkspace.ID)
+		err = AddRoleAssignments(ctx, []*rbacv1.GroupRoleAssignment{{
+			GroupId: int32(testGroupStatic2.ID),
+			RoleAssignment: &rbacv1.RoleAssignment{
+				Role:             &rbacv1.Role{RoleId: int32(role.ID)},
+				ScopeWorkspaceId: &workspaceID,
+			},
+		}}, nil)
+		require.NoError(t, err)
+
+		// Global-only permissions can't be added to a role assigned to workspaces.
+		_, err = UpdateRole(ctx, role.ID, nil, []int{globalTestPermission.ID}, nil)
+		require.ErrorIs(t, err, db.ErrInvalidInput)
+
+		// Assigned roles are only deleted along with their assignments.
+		require.ErrorIs(t, DeleteRole(ctx, role.ID, false), ErrRoleAssigned)
+		require.NoError(t, DeleteRole(ctx, role.ID, true))
+		exists, err := db.Bun().NewSelect().Table("role_assignments").
+			Where("role_id = ?", role.ID).Exists(ctx)
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.ErrorIs(t, DeleteRole(ctx, role.ID, false), db.ErrNotFound)
+	})
 }
 
 func setUp(ctx context.Context, t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -92,7 +93,7 @@ func (m *Master) restoreExperiment(expModel *model.Experiment) error {
 	}
 	taskContainerDefaults, err := m.rm.TaskContainerDefaults(
 		poolName,
-		m.config.TaskContainerDefaults,
+		config.GetMasterConfig().TaskContainerDefaults,
 	)
 	if err != nil {
 		return fmt.Errorf("error getting TaskContainerDefaults: %w", err)
@@ -105,7 +106,7 @@ func (m *Master) restoreExperiment(expModel *model.Experiment) error {
 	}
 	taskSpec.Owner = owner
 
-	token, err := user.StartSession(context.Background(), owner)
+	token, err := user.StartSession(context.Background(), owner, user.WithTaskSession())
 	if err != nil {
 		return fmt.Errorf("unable to create user session inside task: %w", err)
 	}
@@ -505,28 +505,7 @@ func (a *ResourceManager) TaskContainerDefaults(
 	resourcePoolName rm.ResourcePoolName,
 	defaultConfig model.TaskContainerDefaultsConfig,
 ) (model.TaskContainerDefaultsConfig, error) {
-	result := defaultConfig
-
-	// Iterate through configured pools looking for a TaskContainerDefaults setting.
-	var poolConfigOverrides *model.TaskContainerDefaultsConfig
-	for _, pool := range a.poolsConfig {
-		if resourcePoolName.String() == pool.PoolName {
-			if pool.TaskContainerDefaults != nil {
-				poolConfigOverrides = pool.TaskContainerDefaults
-			}
-			break
-		}
-	}
-
-	if poolConfigOverrides != nil {
-		tmp, err := result.Merge(*poolConfigOverrides)
-		if err != nil {
-			return model.TaskContainerDefaultsConfig{}, err
-		}
-		result = tmp
-	}
-
-	return result, nil
+	return config.MergePoolTaskContainerDefaults(resourcePoolName.String(), defaultConfig)
 }
 
 // ValidateResources implements rm.ResourceManager.
@@ -714,6 +693,12 @@ func (a *ResourceManager) createResourcePoolSummary(
 				accelerator = pool.Provider.GCP.Accelerator()
 			}
 		}
+		if pool.Provider.Exec != nil {
+			instanceType = pool.Provider.Exec.InstanceType.Name()
+			slotsPerAgent = pool.Provider.Exec.SlotsPerInstance()
+			slotType = pool.Provider.Exec.SlotType()
+			accelerator = pool.Provider.Exec.Accelerator()
+		}
 	}
 
 	var schedulerType resourcepoolv1.SchedulerType
@@ -838,7 +823,7 @@ func (a *ResourceManager) createResourcePoolSummary(
 	if schedulerType == resourcepoolv1.SchedulerType_SCHEDULER_TYPE_PRIORITY {
 		resp.Details.PriorityScheduler = &resourcepoolv1.ResourcePoolPrioritySchedulerDetail{
 			Preemption:      pool.Scheduler.Priority.Preemption,
-			DefaultPriority: int32(*pool.Scheduler.Priority.DefaultPriority),
+			DefaultPriority: int32(config.DefaultPriorityForPool(poolName)),
 		}
 	}
 
@@ -27,11 +27,13 @@ type MockTask struct {
 	Group          *MockGroup
 	SlotsNeeded    int
 	NonPreemptible bool
+	Elastic        *sproto.ElasticConfig
 	ResourcePool   string
 	AllocatedAgent *MockAgent
 	// Any test that set this to false is half wrong. It is used as a proxy to oversubscribe agents.
 	ContainerStarted  bool
 	JobSubmissionTime time.Time
+	RequestTime       time.Time
 
 	BlockedNodes []string
 }
@@ -63,9 +65,14 @@ func MockTaskToAllocateRequest(mockTask *MockTask) *sproto.AllocateRequest {
 		Preemption: sproto.PreemptionConfig{
 			Preemptible: !mockTask.NonPreemptible,
 		},
+		RequestTime:       mockTask.RequestTime,
 		JobSubmissionTime: jobSubmissionTime,
 		BlockedNodes:      mockTask.BlockedNodes,
 	}
+	if mockTask.Elastic != nil {
+		elastic := *mockTask.Elastic
+		req.Elastic = &elastic
+	}
 	return req
 }
 
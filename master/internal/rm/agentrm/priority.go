@@ -3,6 +3,7 @@ package agentrm
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -15,6 +16,10 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
+// elasticGrowCooldown is how long an elastic task runs before it is grown into free slots, so that
+// slots freeing up one at a time don't checkpoint and restart it over and over.
+const elasticGrowCooldown = 10 * time.Minute
+
 type priorityScheduler struct {
 	preemptionEnabled      bool
 	allowHeterogeneousFits bool
@@ -112,18 +117,26 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 		log.Debugf("processing priority %d with %d pending tasks (backfilling: %v)",
 			priority, len(allocationRequests), backfilling)
 
-		successfulAllocations, unSuccessfulAllocations := p.trySchedulingPendingTasksInPriority(
+		successfulAllocations, unSuccessfulAllocations, placedSlots := p.trySchedulingPendingTasksInPriority(
 			allocationRequests,
 			localAgentsState,
 			fittingMethod,
 		)
+		allocate := func(allocatedTask *sproto.AllocateRequest) {
+			if slots, ok := placedSlots[allocatedTask.AllocationID]; ok {
+				log.Debugf("placing elastic task %s with %d of %d slots",
+					allocatedTask.Name, slots, allocatedTask.SlotsNeeded)
+				allocatedTask.SlotsNeeded = slots
+			}
+			toAllocate = append(toAllocate, allocatedTask)
+		}
 
 		// Only start tasks if there are no tasks of higher priorities to preempt.
 		if len(toRelease) == 0 {
 			if !backfilling {
 				for _, allocatedTask := range successfulAllocations {
 					log.Debugf("scheduled task: %s", allocatedTask.Name)
-					toAllocate = append(toAllocate, allocatedTask)
+					allocate(allocatedTask)
 				}
 			} else if p.preemptionEnabled {
 				for _, allocatedTask := range successfulAllocations {
@@ -132,7 +145,7 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 					}
 					log.Debugf("scheduled task via backfilling: %s", allocatedTask.Name)
 					allocatedTask.State = sproto.SchedulingStateScheduledBackfilled
-					toAllocate = append(toAllocate, allocatedTask)
+					allocate(allocatedTask)
 				}
 			}
 		}
@@ -175,12 +188,24 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 
 				if taskPlaced {
 					localAgentsState = updatedLocalAgentState
-					for preemptedTask := range preemptedTasks {
-						log.Debugf(
-							"preempting task %s for task %s",
-							preemptedTask,
-							prioritizedAllocation.Name,
-						)
+					for preemptedTask, resizeTo := range preemptedTasks {
+						if resizeTo > 0 {
+							log.Debugf(
+								"shrinking task %s to %d slots for task %s",
+								preemptedTask,
+								resizeTo,
+								prioritizedAllocation.Name,
+							)
+						} else {
+							log.Debugf(
+								"preempting task %s for task %s",
+								preemptedTask,
+								prioritizedAllocation.Name,
+							)
+						}
+						if req, ok := taskList.TaskByID(preemptedTask); ok && req.Elastic != nil {
+							req.Elastic.TargetSlots = resizeTo
+						}
 						toRelease[preemptedTask] = true
 					}
 				}
@@ -188,6 +213,22 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 		}
 	}
 
+	// Elastic tasks only grow into slots that no pending task is waiting for.
+	if p.preemptionEnabled && !backfilling && len(toRelease) == 0 {
+		for _, priority := range getOrderedPriorities(priorityToScheduledTaskMap) {
+			for _, req := range priorityToScheduledTaskMap[priority] {
+				var resizeTo int
+				resizeTo, localAgentsState = p.tryGrowingTask(taskList, req, localAgentsState, fittingMethod)
+				if resizeTo == 0 {
+					continue
+				}
+				log.Debugf("growing task %s to %d slots", req.Name, resizeTo)
+				req.Elastic.TargetSlots = resizeTo
+				toRelease[req.AllocationID] = true
+			}
+		}
+	}
+
 	toReleaseSlice := make([]model.AllocationID, 0, len(toRelease))
 	for r := range toRelease {
 		toReleaseSlice = append(toReleaseSlice, r)
@@ -196,7 +237,9 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 }
 
 // trySchedulingTaskViaPreemption checks whether preempting lower priority tasks
-// would allow this task to be scheduled.
+// would allow this task to be scheduled. Elastic tasks are shrunk rather than preempted outright
+// when that is enough. The returned tasks map to the size they are shrunk to, or zero if they are
+// preempted outright.
 func (p priorityScheduler) trySchedulingTaskViaPreemption(
 	taskList *tasklist.TaskList,
 	allocationRequest *sproto.AllocateRequest,
@@ -207,20 +250,23 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 	priorityToScheduledTaskMap map[int][]*sproto.AllocateRequest,
 	tasksAlreadyPreempted map[model.AllocationID]bool,
 	filter func(*sproto.AllocateRequest) bool,
-) (bool, map[aproto.ID]*agentState, map[model.AllocationID]bool) {
+) (bool, map[aproto.ID]*agentState, map[model.AllocationID]int) {
 	localAgentsState := deepCopyAgents(agents)
-	preemptedTasks := make(map[model.AllocationID]bool)
+	preemptedTasks := make(map[model.AllocationID]int)
 	log.Debugf("trying to schedule task %s by preempting other tasks", allocationRequest.Name)
 
 	for priority := model.MaxUserSchedulingPriority; priority >= allocationPriority; priority-- {
 		for i := len(priorityToScheduledTaskMap[priority]) - 1; i >= 0; i-- {
 			allocationJobID := allocationRequest.JobID
-			candidateJobID := priorityToScheduledTaskMap[priority][i].JobID
-			if priority == allocationPriority &&
-				jobPositions[allocationJobID].GreaterThanOrEqual(jobPositions[candidateJobID]) {
-				break
-			}
 			preemptionCandidate := priorityToScheduledTaskMap[priority][i]
+			candidateJobID := preemptionCandidate.JobID
+			// Tasks of the same priority that are ahead in the queue are left alone, except that
+			// elastic tasks give back the slots they grew into.
+			ahead := priority == allocationPriority &&
+				jobPositions[allocationJobID].GreaterThanOrEqual(jobPositions[candidateJobID])
+			if ahead && !hasGrown(preemptionCandidate) {
+				continue
+			}
 			if !preemptionCandidate.Preemption.Preemptible || !filter(preemptionCandidate) {
 				continue
 			}
@@ -230,8 +276,42 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 			}
 
 			allocated := taskList.Allocation(preemptionCandidate.AllocationID)
-			removeTaskFromAgents(localAgentsState, allocated)
-			preemptedTasks[preemptionCandidate.AllocationID] = true
+			withoutCandidate := deepCopyAgents(localAgentsState)
+			removeTaskFromAgents(withoutCandidate, allocated)
+
+			minSlots := 0
+			if preemptionCandidate.Elastic != nil {
+				minSlots = preemptionCandidate.Elastic.MinSlots
+				if ahead {
+					minSlots = preemptionCandidate.Elastic.BaseSlots
+				}
+			}
+			if resizeTo, shrunkAgentsState := p.tryShrinkingTask(
+				allocationRequest,
+				preemptionCandidate,
+				minSlots,
+				withoutCandidate,
+				fittingMethod,
+			); resizeTo > 0 {
+				preemptedTasks[preemptionCandidate.AllocationID] = resizeTo
+				return true, shrunkAgentsState, preemptedTasks
+			}
+
+			if ahead {
+				// Shrinking this task alone isn't enough, so shrink it all the way back and keep
+				// looking for room.
+				shrunk := *preemptionCandidate
+				shrunk.SlotsNeeded = minSlots
+				fits := findFits(&shrunk, withoutCandidate, fittingMethod, p.allowHeterogeneousFits)
+				if len(fits) == 0 {
+					continue
+				}
+				addTaskToAgents(fits)
+				preemptedTasks[preemptionCandidate.AllocationID] = minSlots
+			} else {
+				preemptedTasks[preemptionCandidate.AllocationID] = 0
+			}
+			localAgentsState = withoutCandidate
 
 			if fits := findFits(
 				allocationRequest,
@@ -248,19 +328,110 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 	return false, localAgentsState, preemptedTasks
 }
 
+// hasGrown returns whether an elastic task runs with more slots than it asked for.
+func hasGrown(req *sproto.AllocateRequest) bool {
+	return req.Elastic != nil && req.Elastic.TargetSlots == 0 &&
+		req.Elastic.BaseSlots > 0 && req.SlotsNeeded > req.Elastic.BaseSlots
+}
+
+// tryShrinkingTask checks whether shrinking an elastic task, whose resources have already been
+// removed from the agents, to no fewer than minSlots leaves enough room for the allocation request.
+// It returns the largest size the task can be shrunk to along with the resulting agent state, or
+// zero if shrinking the task isn't enough.
+func (p priorityScheduler) tryShrinkingTask(
+	allocationRequest *sproto.AllocateRequest,
+	elasticTask *sproto.AllocateRequest,
+	minSlots int,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+) (int, map[aproto.ID]*agentState) {
+	if elasticTask.Elastic == nil || elasticTask.SlotsNeeded <= minSlots {
+		return 0, nil
+	}
+
+	withRequest := deepCopyAgents(agents)
+	fits := findFits(allocationRequest, withRequest, fittingMethod, p.allowHeterogeneousFits)
+	if len(fits) == 0 {
+		return 0, nil
+	}
+	addTaskToAgents(fits)
+
+	for slots := elasticTask.SlotsNeeded - 1; slots >= minSlots; slots-- {
+		localAgentsState := deepCopyAgents(withRequest)
+		shrunk := *elasticTask
+		shrunk.SlotsNeeded = slots
+		if fits := findFits(
+			&shrunk,
+			localAgentsState,
+			fittingMethod,
+			p.allowHeterogeneousFits,
+		); len(fits) > 0 {
+			addTaskToAgents(fits)
+			return slots, localAgentsState
+		}
+	}
+	return 0, nil
+}
+
+// tryGrowingTask checks whether an elastic task can grow into free slots. It returns the largest
+// size the task can grow to along with the resulting agent state, or zero and the unchanged agent
+// state if the task can't grow. Tasks are only grown once they have been requested for
+// elasticGrowCooldown, since every resize checkpoints and restarts them.
+func (p priorityScheduler) tryGrowingTask(
+	taskList *tasklist.TaskList,
+	elasticTask *sproto.AllocateRequest,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+) (int, map[aproto.ID]*agentState) {
+	if elasticTask.Elastic == nil || elasticTask.Elastic.TargetSlots > 0 ||
+		!elasticTask.Preemption.Preemptible ||
+		elasticTask.SlotsNeeded >= elasticTask.Elastic.MaxSlots ||
+		time.Since(elasticTask.RequestTime) < elasticGrowCooldown {
+		return 0, agents
+	}
+
+	withoutTask := deepCopyAgents(agents)
+	removeTaskFromAgents(withoutTask, taskList.Allocation(elasticTask.AllocationID))
+
+	for slots := elasticTask.Elastic.MaxSlots; slots > elasticTask.SlotsNeeded; slots-- {
+		localAgentsState := deepCopyAgents(withoutTask)
+		grown := *elasticTask
+		grown.SlotsNeeded = slots
+		if fits := findFits(
+			&grown,
+			localAgentsState,
+			fittingMethod,
+			p.allowHeterogeneousFits,
+		); len(fits) > 0 {
+			addTaskToAgents(fits)
+			return slots, localAgentsState
+		}
+	}
+	return 0, agents
+}
+
 // trySchedulingPendingTasksInPriority tries to schedule all the tasks in the
 // current priority. Note tasks are scheduled based on the order in which they
-// are listed.
+// are listed. Elastic tasks that don't fit as requested are placed at the largest size in their
+// range that fits; the returned map holds those sizes.
 func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 	allocationRequests []*sproto.AllocateRequest,
 	agents map[aproto.ID]*agentState,
 	fittingMethod SoftConstraint,
-) ([]*sproto.AllocateRequest, []*sproto.AllocateRequest) {
+) ([]*sproto.AllocateRequest, []*sproto.AllocateRequest, map[model.AllocationID]int) {
 	successfulAllocations := make([]*sproto.AllocateRequest, 0)
 	unSuccessfulAllocations := make([]*sproto.AllocateRequest, 0)
 
+	placedSlots := make(map[model.AllocationID]int)
+
 	for _, allocationRequest := range allocationRequests {
 		fits := findFits(allocationRequest, agents, fittingMethod, p.allowHeterogeneousFits)
+		if len(fits) == 0 {
+			var slots int
+			if fits, slots = p.findElasticFits(allocationRequest, agents, fittingMethod); len(fits) > 0 {
+				placedSlots[allocationRequest.AllocationID] = slots
+			}
+		}
 		if len(fits) == 0 {
 			unSuccessfulAllocations = append(unSuccessfulAllocations, allocationRequest)
 			continue
@@ -269,7 +440,28 @@ func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 		successfulAllocations = append(successfulAllocations, allocationRequest)
 	}
 
-	return successfulAllocations, unSuccessfulAllocations
+	return successfulAllocations, unSuccessfulAllocations, placedSlots
+}
+
+// findElasticFits finds the largest size an elastic task that doesn't fit as requested fits at,
+// down to its minimum size. It returns the fits and the size, or no fits if the task isn't elastic
+// or doesn't fit at any size.
+func (p priorityScheduler) findElasticFits(
+	allocationRequest *sproto.AllocateRequest,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+) ([]*fittingState, int) {
+	if allocationRequest.Elastic == nil {
+		return nil, 0
+	}
+	for slots := allocationRequest.SlotsNeeded - 1; slots >= allocationRequest.Elastic.MinSlots; slots-- {
+		shrunk := *allocationRequest
+		shrunk.SlotsNeeded = slots
+		if fits := findFits(&shrunk, agents, fittingMethod, p.allowHeterogeneousFits); len(fits) > 0 {
+			return fits, slots
+		}
+	}
+	return nil, 0
 }
 
 // sortTasksByPriorityAndPositionAndTimestamp sorts all pending and scheduled tasks
@@ -742,6 +742,297 @@ func TestPrioritySchedulingNoPreemptionByPosition(t *testing.T) {
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
 
+func TestPrioritySchedulingShrinksElasticTask(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "low-priority elastic task should be shrunk",
+			SlotsNeeded: 8, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 2, MaxSlots: 8},
+		},
+		{
+			ID:          "high-priority task causes the shrink",
+			SlotsNeeded: 3, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{tasks[0]}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+
+	req, ok := taskList.TaskByID(tasks[0].ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, req.TargetSlots(), 5)
+}
+
+func TestPrioritySchedulingPreemptsElasticTaskAtMinSlots(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "low-priority elastic task cannot shrink enough",
+			SlotsNeeded: 4, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 2, MaxSlots: 4},
+		},
+		{
+			ID:          "high-priority task needs every slot",
+			SlotsNeeded: 4, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{tasks[0]}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+
+	req, ok := taskList.TaskByID(tasks[0].ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, req.TargetSlots(), 4)
+}
+
+func TestPrioritySchedulingGrowsElasticTask(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &priority},
+		{ID: "group2", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "elastic task should grow",
+			SlotsNeeded: 2, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 1, MaxSlots: 6},
+		},
+		{
+			ID:          "task is scheduled before the elastic task grows",
+			SlotsNeeded: 1, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{tasks[1]}
+	expectedToRelease := []*MockTask{tasks[0]}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+
+	req, ok := taskList.TaskByID(tasks[0].ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, req.TargetSlots(), 6)
+}
+
+func TestPrioritySchedulingElasticTaskDoesNotGrowPastPendingTask(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "1", Priority: &priority},
+		{ID: "2", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "1",
+			SlotsNeeded: 2, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 2, MaxSlots: 4},
+		},
+		{
+			ID:          "2",
+			SlotsNeeded: 4, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{}
+	jobsList := map[model.JobID]decimal.Decimal{
+		"1": decimal.New(1, sproto.DecimalExp),
+		"2": decimal.New(2, sproto.DecimalExp),
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		jobsList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
+func TestPrioritySchedulingPlacesElasticTaskInRange(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &priority},
+		{ID: "group2", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "task holds half of the slots",
+			SlotsNeeded: 2, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+		},
+		{
+			ID:          "elastic task is placed with the slots that are left",
+			SlotsNeeded: 4, Group: groups[1],
+			Elastic: &sproto.ElasticConfig{MinSlots: 1, MaxSlots: 4, BaseSlots: 4},
+		},
+	}
+
+	expectedToAllocate := []*MockTask{tasks[1]}
+	expectedToRelease := []*MockTask{}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+	assert.Equal(t, toAllocate[0].SlotsNeeded, 2)
+}
+
+func TestPrioritySchedulingShrinksGrownElasticTaskForSamePriority(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "1", Priority: &priority},
+		{ID: "2", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "1",
+			SlotsNeeded: 8, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 1, MaxSlots: 8, BaseSlots: 2},
+		},
+		{
+			ID:          "2",
+			SlotsNeeded: 4, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{tasks[0]}
+	jobsList := map[model.JobID]decimal.Decimal{
+		"1": decimal.New(1, sproto.DecimalExp),
+		"2": decimal.New(2, sproto.DecimalExp),
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		jobsList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+
+	req, ok := taskList.TaskByID(tasks[0].ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, req.TargetSlots(), 4)
+}
+
+func TestPrioritySchedulingDoesNotShrinkElasticTaskBelowBaseForSamePriority(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "1", Priority: &priority},
+		{ID: "2", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "1",
+			SlotsNeeded: 8, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic: &sproto.ElasticConfig{MinSlots: 1, MaxSlots: 8, BaseSlots: 6},
+		},
+		{
+			ID:          "2",
+			SlotsNeeded: 4, Group: groups[1],
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{}
+	jobsList := map[model.JobID]decimal.Decimal{
+		"1": decimal.New(1, sproto.DecimalExp),
+		"2": decimal.New(2, sproto.DecimalExp),
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		jobsList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
+func TestPrioritySchedulingElasticTaskWaitsBeforeGrowing(t *testing.T) {
+	priority := 42
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &priority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "elastic task was just started",
+			SlotsNeeded: 2, Group: groups[0], AllocatedAgent: agents[0], ContainerStarted: true,
+			Elastic:     &sproto.ElasticConfig{MinSlots: 1, MaxSlots: 6},
+			RequestTime: time.Now(),
+		},
+	}
+
+	expectedToAllocate := []*MockTask{}
+	expectedToRelease := []*MockTask{}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
 func AllocateTasks(
 	toAllocate []*sproto.AllocateRequest,
 	agents map[aproto.ID]*agentState,
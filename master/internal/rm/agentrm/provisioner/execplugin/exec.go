@@ -0,0 +1,304 @@
+package execplugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Verbs passed as the last argument to the external command.
+const (
+	launchVerb    = "launch"
+	listVerb      = "list"
+	terminateVerb = "terminate"
+)
+
+const (
+	// maxStderrLength bounds how much of the stderr of a failed command is included in its error.
+	maxStderrLength = 4096
+	// waitDelay bounds how long to wait for the output of a killed command to be closed, since
+	// processes it spawned may hold on to it.
+	waitDelay = time.Second
+)
+
+var instanceStates = map[string]model.InstanceState{
+	"starting":    model.Starting,
+	"running":     model.Running,
+	"stopping":    model.Stopping,
+	"stopped":     model.Stopped,
+	"terminating": model.Terminating,
+}
+
+// launchRequest is written to the stdin of the `launch` verb.
+type launchRequest struct {
+	ResourcePool  string `json:"resource_pool"`
+	InstanceType  string `json:"instance_type"`
+	InstanceNum   int    `json:"instance_num"`
+	StartupScript string `json:"startup_script"`
+}
+
+// listRequest is written to the stdin of the `list` verb.
+type listRequest struct {
+	ResourcePool string `json:"resource_pool"`
+}
+
+// terminateRequest is written to the stdin of the `terminate` verb.
+type terminateRequest struct {
+	ResourcePool string   `json:"resource_pool"`
+	InstanceIDs  []string `json:"instance_ids"`
+}
+
+// instancesResponse is read from the stdout of every verb. It may be empty for `launch` and
+// `terminate`.
+type instancesResponse struct {
+	Instances []instance `json:"instances"`
+}
+
+type instance struct {
+	ID         string     `json:"id"`
+	AgentName  string     `json:"agent_name"`
+	State      string     `json:"state"`
+	LaunchTime *time.Time `json:"launch_time"`
+}
+
+// execCluster manages instances by running an external command. Determined recognizes agent
+// instances by the agent name reported by the `list` verb, which must match the agent ID the
+// instances evaluate from the agent_id of the config.
+type execCluster struct {
+	config        *provconfig.ExecClusterConfig
+	resourcePool  string
+	startupScript string
+
+	// firstSeen approximates the launch time of instances listed without one.
+	firstSeen map[string]time.Time
+
+	syslog *logrus.Entry
+}
+
+// New creates a new cluster managed by an external command.
+func New(
+	resourcePool string, config *provconfig.Config, cert *tls.Certificate,
+) (agentsetup.Provider, error) {
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	startupScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.StartupScript))
+	containerScriptBase64 := base64.StdEncoding.EncodeToString(
+		[]byte(config.ContainerStartupScript),
+	)
+
+	var certBytes []byte
+	if masterURL.Scheme == agentsetup.SecureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			b := pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})
+			certBytes = append(certBytes, b...)
+		}
+	}
+	masterCertBase64 := base64.StdEncoding.EncodeToString(certBytes)
+	configFileBase64 := base64.StdEncoding.EncodeToString(config.AgentConfigFileContents)
+
+	return &execCluster{
+		config:       config.Exec,
+		resourcePool: resourcePool,
+		startupScript: string(agentsetup.MustMakeAgentSetupScript(agentsetup.AgentSetupScriptConfig{
+			MasterHost:                   masterURL.Hostname(),
+			MasterPort:                   masterURL.Port(),
+			MasterCertName:               config.MasterCertName,
+			StartupScriptBase64:          startupScriptBase64,
+			ContainerStartupScriptBase64: containerScriptBase64,
+			MasterCertBase64:             masterCertBase64,
+			ConfigFileBase64:             configFileBase64,
+			SlotType:                     config.Exec.SlotType(),
+			AgentDockerRuntime:           config.AgentDockerRuntime,
+			AgentNetwork:                 config.AgentDockerNetwork,
+			AgentDockerImage:             config.AgentDockerImage,
+			AgentReconnectAttempts:       config.AgentReconnectAttempts,
+			AgentReconnectBackoff:        config.AgentReconnectBackoff,
+			AgentID:                      config.Exec.AgentID,
+			ResourcePool:                 resourcePool,
+		})),
+		firstSeen: make(map[string]time.Time),
+		syslog:    logrus.WithField("exec-cluster", resourcePool),
+	}, nil
+}
+
+func (c *execCluster) InstanceType() model.InstanceType {
+	return c.config.InstanceType
+}
+
+func (c *execCluster) SlotsPerInstance() int {
+	return c.config.SlotsPerInstance()
+}
+
+func (c *execCluster) List() ([]*model.Instance, error) {
+	var resp instancesResponse
+	if err := c.run(listVerb, listRequest{ResourcePool: c.resourcePool}, &resp); err != nil {
+		return nil, err
+	}
+	instances, err := c.newInstances(resp.Instances)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid output of %s", listVerb)
+	}
+
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		seen[inst.ID] = true
+	}
+	for id := range c.firstSeen {
+		if !seen[id] {
+			delete(c.firstSeen, id)
+		}
+	}
+	return instances, nil
+}
+
+func (c *execCluster) Launch(instanceNum int) error {
+	if instanceNum <= 0 {
+		return nil
+	}
+
+	var resp instancesResponse
+	err := c.run(launchVerb, launchRequest{
+		ResourcePool:  c.resourcePool,
+		InstanceType:  c.config.InstanceType.Name(),
+		InstanceNum:   instanceNum,
+		StartupScript: c.startupScript,
+	}, &resp)
+	if err != nil {
+		c.syslog.WithError(err).Error("cannot launch instances")
+		return err
+	}
+
+	launched, err := c.newInstances(resp.Instances)
+	if err != nil {
+		c.syslog.WithError(err).Warnf("invalid output of %s", launchVerb)
+	}
+	c.syslog.Infof(
+		"launched %d/%d instances: %s",
+		len(launched),
+		instanceNum,
+		model.FmtInstances(launched),
+	)
+	return nil
+}
+
+func (c *execCluster) Terminate(instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+
+	err := c.run(terminateVerb, terminateRequest{
+		ResourcePool: c.resourcePool,
+		InstanceIDs:  instanceIDs,
+	}, nil)
+	if err != nil {
+		c.syslog.WithError(err).Error("cannot terminate instances")
+		return
+	}
+	c.syslog.Infof("terminated %d instances: %s", len(instanceIDs), strings.Join(instanceIDs, ", "))
+}
+
+// run invokes the command with the given verb, writing req to its stdin and decoding its stdout
+// into resp if resp is not nil.
+func (c *execCluster) run(verb string, req interface{}, resp interface{}) error {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal %s request", verb)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.config.Timeout))
+	defer cancel()
+
+	args := append(append([]string{}, c.config.Command[1:]...), verb)
+	cmd := exec.CommandContext(ctx, c.config.Command[0], args...) // #nosec G204
+	cmd.Env = os.Environ()
+	for k, v := range c.config.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.Env = append(cmd.Env, "DET_RESOURCE_POOL="+c.resourcePool)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = errors.Errorf("timed out after %s", time.Duration(c.config.Timeout))
+		}
+		msg := stderr.String()
+		if len(msg) > maxStderrLength {
+			msg = msg[:maxStderrLength] + "..."
+		}
+		return errors.Wrapf(err, "%s failed: %s", verb, strings.TrimSpace(msg))
+	}
+
+	if resp == nil || len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return errors.Wrapf(err, "cannot parse output of %s", verb)
+	}
+	return nil
+}
+
+func (c *execCluster) newInstances(input []instance) ([]*model.Instance, error) {
+	now := time.Now()
+	output := make([]*model.Instance, 0, len(input))
+	ids := make(map[string]bool, len(input))
+	for _, inst := range input {
+		if inst.ID == "" {
+			return nil, errors.New("instance is missing an id")
+		}
+		if ids[inst.ID] {
+			return nil, errors.Errorf("duplicate instance id %s", inst.ID)
+		}
+		ids[inst.ID] = true
+		if inst.AgentName == "" {
+			return nil, errors.Errorf("instance %s is missing an agent_name", inst.ID)
+		}
+
+		launchTime, ok := c.firstSeen[inst.ID]
+		if inst.LaunchTime != nil {
+			launchTime = *inst.LaunchTime
+		} else if !ok {
+			launchTime = now
+		}
+		c.firstSeen[inst.ID] = launchTime
+
+		state, ok := instanceStates[strings.ToLower(inst.State)]
+		if !ok {
+			c.syslog.Errorf("unknown instance state %q for instance %v", inst.State, inst.ID)
+			state = model.Unknown
+		}
+
+		output = append(output, &model.Instance{
+			ID:         inst.ID,
+			LaunchTime: launchTime,
+			AgentName:  inst.AgentName,
+			State:      state,
+		})
+	}
+	return output, nil
+}
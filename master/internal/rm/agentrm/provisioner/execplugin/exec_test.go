@@ -0,0 +1,160 @@
+package execplugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// stubScript records the verb and stdin of each invocation in $STUB_DIR and replies with the
+// contents of $STUB_DIR/<verb>.json, if present.
+const stubScript = `#!/bin/sh
+verb="$1"
+cat > "$STUB_DIR/$verb.stdin"
+echo "$verb" >> "$STUB_DIR/calls"
+if [ -f "$STUB_DIR/$verb.fail" ]; then
+    cat "$STUB_DIR/$verb.fail" >&2
+    exit 1
+fi
+if [ -f "$STUB_DIR/$verb.json" ]; then
+    cat "$STUB_DIR/$verb.json"
+fi
+`
+
+func newStubCluster(t *testing.T) (*execCluster, string) {
+	require.NoError(t, etc.SetRootPath("../../../../../static/srv/"))
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "provider.sh")
+	require.NoError(t, os.WriteFile(script, []byte(stubScript), 0o700)) // #nosec G306
+
+	config := provconfig.DefaultConfig()
+	config.MasterURL = "http://master:8080"
+	config.Exec = provconfig.DefaultExecClusterConfig()
+	config.Exec.Command = []string{script}
+	config.Exec.Env = map[string]string{"STUB_DIR": dir}
+	config.Exec.Timeout = model.Duration(10 * time.Second)
+	config.Exec.InstanceType.MachineType = "bm.gpu8"
+	config.Exec.InstanceType.SlotsPerInstance = 8
+
+	provider, err := New("gpu-pool", config, nil)
+	require.NoError(t, err)
+	return provider.(*execCluster), dir
+}
+
+func writeStubFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestExecClusterList(t *testing.T) {
+	c, dir := newStubCluster(t)
+	writeStubFile(t, dir, "list.json", `{"instances": [
+		{"id": "i-1", "agent_name": "node-1", "state": "running",
+		 "launch_time": "2024-01-01T08:00:00Z"},
+		{"id": "i-2", "agent_name": "node-2", "state": "Starting"},
+		{"id": "i-3", "agent_name": "node-3", "state": "rebooting"}
+	]}`)
+
+	instances, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	require.Equal(t, model.Instance{
+		ID:         "i-1",
+		LaunchTime: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+		AgentName:  "node-1",
+		State:      model.Running,
+	}, *instances[0])
+	require.Equal(t, "node-2", instances[1].AgentName)
+	require.Equal(t, model.Starting, instances[1].State)
+	require.Equal(t, model.Unknown, instances[2].State)
+
+	// Instances listed without a launch time keep the time they were first seen.
+	firstSeen := instances[1].LaunchTime
+	require.False(t, firstSeen.IsZero())
+	instances, err = c.List()
+	require.NoError(t, err)
+	require.Equal(t, firstSeen, instances[1].LaunchTime)
+
+	var req listRequest
+	stdin, err := os.ReadFile(filepath.Join(dir, "list.stdin")) // #nosec G304
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(stdin, &req))
+	require.Equal(t, listRequest{ResourcePool: "gpu-pool"}, req)
+}
+
+func TestExecClusterListInvalidOutput(t *testing.T) {
+	c, dir := newStubCluster(t)
+
+	writeStubFile(t, dir, "list.json", `not json`)
+	_, err := c.List()
+	require.ErrorContains(t, err, "cannot parse output of list")
+
+	writeStubFile(t, dir, "list.json",
+		`{"instances": [{"id": "i-1", "agent_name": "a"}, {"id": "i-1", "agent_name": "a"}]}`)
+	_, err = c.List()
+	require.ErrorContains(t, err, "duplicate instance id i-1")
+
+	writeStubFile(t, dir, "list.json", `{"instances": [{"state": "running"}]}`)
+	_, err = c.List()
+	require.ErrorContains(t, err, "instance is missing an id")
+
+	// Determined can't tell which agent runs on an instance without its name.
+	writeStubFile(t, dir, "list.json", `{"instances": [{"id": "i-1", "state": "running"}]}`)
+	_, err = c.List()
+	require.ErrorContains(t, err, "instance i-1 is missing an agent_name")
+}
+
+func TestExecClusterLaunch(t *testing.T) {
+	c, dir := newStubCluster(t)
+
+	require.NoError(t, c.Launch(0))
+	_, err := os.Stat(filepath.Join(dir, "calls"))
+	require.True(t, os.IsNotExist(err), "launching no instances must not run the command")
+
+	require.NoError(t, c.Launch(2))
+	var req launchRequest
+	stdin, err := os.ReadFile(filepath.Join(dir, "launch.stdin")) // #nosec G304
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(stdin, &req))
+	require.Equal(t, "gpu-pool", req.ResourcePool)
+	require.Equal(t, "bm.gpu8", req.InstanceType)
+	require.Equal(t, 2, req.InstanceNum)
+	require.Contains(t, req.StartupScript, `DET_AGENT_ID="$(hostname)"`)
+	require.Contains(t, req.StartupScript, `DET_MASTER_HOST="master"`)
+
+	writeStubFile(t, dir, "launch.fail", "quota exceeded")
+	require.ErrorContains(t, c.Launch(1), "launch failed: quota exceeded")
+}
+
+func TestExecClusterTerminate(t *testing.T) {
+	c, dir := newStubCluster(t)
+
+	c.Terminate([]string{"i-1", "i-2"})
+	var req terminateRequest
+	stdin, err := os.ReadFile(filepath.Join(dir, "terminate.stdin")) // #nosec G304
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(stdin, &req))
+	require.Equal(t, terminateRequest{
+		ResourcePool: "gpu-pool",
+		InstanceIDs:  []string{"i-1", "i-2"},
+	}, req)
+}
+
+func TestExecClusterTimeout(t *testing.T) {
+	c, dir := newStubCluster(t)
+	script := filepath.Join(dir, "slow.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nsleep 5\n"), 0o700)) // #nosec G306
+	c.config.Command = []string{script}
+	c.config.Timeout = model.Duration(100 * time.Millisecond)
+
+	_, err := c.List()
+	require.ErrorContains(t, err, "timed out")
+}
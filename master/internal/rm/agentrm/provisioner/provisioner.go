@@ -14,6 +14,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/aws"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/execplugin"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/gcp"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/scaledecider"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -49,6 +50,11 @@ type Provisioner struct {
 	telemetryLimiter *rate.Limiter
 	launchErr        *errInfo.StickyError
 
+	// minInstances and maxInstances are the last instance bounds in effect, used to report
+	// changes caused by scaling schedules.
+	minInstances int
+	maxInstances int
+
 	syslog *logrus.Entry
 }
 
@@ -71,6 +77,11 @@ func New(
 		if cluster, err = gcp.New(resourcePool, config, cert); err != nil {
 			return nil, errors.Wrap(err, "cannot create a GCP cluster")
 		}
+	case config.Exec != nil:
+		var err error
+		if cluster, err = execplugin.New(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create an exec cluster")
+		}
 	}
 
 	var launchErrorTimeout time.Duration
@@ -78,19 +89,27 @@ func New(
 		launchErrorTimeout = time.Duration(*config.LaunchErrorTimeout)
 	}
 
+	scaleDecider, err := scaledecider.New(
+		resourcePool,
+		time.Duration(config.MaxIdleAgentPeriod),
+		time.Duration(config.MaxAgentStartingPeriod),
+		maxDisconnectPeriod,
+		config.MinInstances,
+		config.MaxInstances,
+		config.Schedules,
+		db,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create a scale decider")
+	}
+
 	return &Provisioner{
-		provider: cluster,
-		scaleDecider: scaledecider.New(
-			resourcePool,
-			time.Duration(config.MaxIdleAgentPeriod),
-			time.Duration(config.MaxAgentStartingPeriod),
-			maxDisconnectPeriod,
-			config.MinInstances,
-			config.MaxInstances,
-			db,
-		),
+		provider:         cluster,
+		scaleDecider:     scaleDecider,
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
 		launchErr:        errInfo.NewStickyError(launchErrorTimeout, config.LaunchErrorRetries),
+		minInstances:     config.MinInstances,
+		maxInstances:     config.MaxInstances,
 
 		syslog: logrus.WithField("component", "provisioner").
 			WithField("resource-pool", resourcePool),
@@ -161,6 +180,13 @@ func (p *Provisioner) Provision() {
 
 	p.scaleDecider.CalculateInstanceStates()
 
+	if minInstances, maxInstances := p.scaleDecider.InstanceBounds(); minInstances != p.minInstances ||
+		maxInstances != p.maxInstances {
+		p.syslog.Infof("instance bounds changed from [%d, %d] to [%d, %d]",
+			p.minInstances, p.maxInstances, minInstances, maxInstances)
+		p.minInstances, p.maxInstances = minInstances, maxInstances
+	}
+
 	if updated {
 		err = p.scaleDecider.RecordInstanceStats(p.provider.SlotsPerInstance())
 		if err != nil {
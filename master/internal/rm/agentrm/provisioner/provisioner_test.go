@@ -61,17 +61,22 @@ func newMockEnvironment(t *testing.T, setup *mockConfig) (*mockEnvironment, *Pro
 	if setup.LaunchErrorTimeout != nil {
 		launchErrorTimeout = time.Duration(*setup.LaunchErrorTimeout)
 	}
+	scaleDecider, err := scaledecider.New(
+		"default",
+		time.Duration(setup.MaxIdleAgentPeriod),
+		time.Duration(setup.MaxAgentStartingPeriod),
+		setup.maxDisconnectPeriod,
+		setup.MinInstances,
+		setup.MaxInstances,
+		setup.Schedules,
+		nil,
+	)
+	assert.NilError(t, err)
 	p := &Provisioner{
-		provider: cluster,
-		scaleDecider: scaledecider.New(
-			"default",
-			time.Duration(setup.MaxIdleAgentPeriod),
-			time.Duration(setup.MaxAgentStartingPeriod),
-			setup.maxDisconnectPeriod,
-			setup.MinInstances,
-			setup.MaxInstances,
-			nil,
-		),
+		provider:         cluster,
+		scaleDecider:     scaleDecider,
+		minInstances:     setup.MinInstances,
+		maxInstances:     setup.MaxInstances,
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
 		launchErr:        errInfo.NewStickyError(launchErrorTimeout, setup.LaunchErrorRetries),
 		syslog:           logrus.WithField("test-provisioner", "default"),
@@ -5,6 +5,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jonboulle/clockwork"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/mathx"
@@ -32,6 +35,8 @@ type ScaleDecider struct {
 	maxDisconnectPeriod time.Duration
 	minInstanceNum      int
 	maxInstanceNum      int
+	schedules           []schedule
+	clock               clockwork.Clock
 
 	instanceSnapshot       map[string]*model.Instance
 	connectedAgentSnapshot map[string]sproto.AgentSummary
@@ -51,21 +56,29 @@ type ScaleDecider struct {
 	resourcePool string
 }
 
-// New creates a new scale decider.
+// New creates a new scale decider. The schedules override minInstanceNum and maxInstanceNum while
+// they are active.
 func New(
 	resourcePool string,
 	maxIdlePeriod, maxStartingPeriod,
 	maxDisconnectPeriod time.Duration,
 	minInstanceNum int,
 	maxInstanceNum int,
+	schedules []provconfig.ScalingSchedule,
 	db db.DB,
-) *ScaleDecider {
+) (*ScaleDecider, error) {
+	parsed, err := parseSchedules(schedules)
+	if err != nil {
+		return nil, err
+	}
 	return &ScaleDecider{
 		maxStartingPeriod:      maxStartingPeriod,
 		maxIdlePeriod:          maxIdlePeriod,
 		maxDisconnectPeriod:    maxDisconnectPeriod,
 		minInstanceNum:         minInstanceNum,
 		maxInstanceNum:         maxInstanceNum,
+		schedules:              parsed,
+		clock:                  clockwork.NewRealClock(),
 		instanceSnapshot:       make(map[string]*model.Instance),
 		connectedAgentSnapshot: make(map[string]sproto.AgentSummary),
 		idleAgentSnapshot:      make(map[string]sproto.AgentSummary),
@@ -79,7 +92,27 @@ func New(
 		longIdle:               make(map[string]bool),
 		db:                     db,
 		resourcePool:           resourcePool,
+	}, nil
+}
+
+func (s *ScaleDecider) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
 	}
+	return s.clock.Now()
+}
+
+// InstanceBounds returns the minimum and maximum number of instances currently in effect, taking
+// active scaling schedules into account.
+func (s *ScaleDecider) InstanceBounds() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.instanceBounds()
+}
+
+func (s *ScaleDecider) instanceBounds() (int, int) {
+	return instanceBounds(s.schedules, s.minInstanceNum, s.maxInstanceNum, s.now())
 }
 
 // UpdateScalingInfo updates the scaling information.
@@ -104,7 +137,7 @@ func (s *ScaleDecider) UpdateInstanceSnapshot(instances []*model.Instance) bool
 	defer s.mu.Unlock()
 
 	updateSnapshot := func() {
-		now := time.Now()
+		now := s.now()
 		pastSnapshot := s.instanceSnapshot
 		s.instanceSnapshot = make(map[string]*model.Instance, len(instances))
 		for _, inst := range instances {
@@ -198,7 +231,7 @@ func (s *ScaleDecider) CalculateInstanceStates() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now()
+	now := s.now()
 	pastDisconnected := s.disconnected
 	pastIdle := s.idle
 	s.instances = make(map[string]*model.Instance)
@@ -262,6 +295,7 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 	defer s.mu.Unlock()
 
 	toTerminate := make(map[string]string)
+	minInstanceNum, maxInstanceNum := s.instanceBounds()
 
 	// Terminate stopped instances and find idle and disconnected instances.
 	for id := range s.stopped {
@@ -277,7 +311,7 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 
 	// Terminate instances that are idle for a long time.
 	for id := range s.longIdle {
-		if len(s.instances)-len(toTerminate) <= s.minInstanceNum {
+		if len(s.instances)-len(toTerminate) <= minInstanceNum {
 			break
 		}
 		toTerminate[id] = sproto.TerminateLongIdleInstances
@@ -288,21 +322,21 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 	// We start by terminating unfulfilled spot requests, then idle instances, then
 	// disconnected instances, then the most recently provisioned instances
 	for id := range s.pending {
-		if len(s.instances)-len(toTerminate) <= s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) <= maxInstanceNum {
 			break
 		}
 		toTerminate[id] = sproto.InstanceNumberExceedsMaximum
 		delete(s.pending, id)
 	}
 	for id := range s.idle {
-		if len(s.instances)-len(toTerminate) <= s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) <= maxInstanceNum {
 			break
 		}
 		toTerminate[id] = sproto.InstanceNumberExceedsMaximum
 		delete(s.idle, id)
 	}
 	for id := range s.disconnected {
-		if len(s.instances)-len(toTerminate) <= s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) <= maxInstanceNum {
 			break
 		}
 		toTerminate[id] = sproto.InstanceNumberExceedsMaximum
@@ -315,7 +349,7 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 	sort.Slice(instances, func(i, j int) bool {
 		return instances[i].LaunchTime.After(instances[j].LaunchTime)
 	})
-	for i := 0; i < len(instances) && len(instances)-len(toTerminate) > s.maxInstanceNum; i++ {
+	for i := 0; i < len(instances) && len(instances)-len(toTerminate) > maxInstanceNum; i++ {
 		toTerminate[instances[i].ID] = sproto.InstanceNumberExceedsMaximum
 	}
 
@@ -333,9 +367,10 @@ func (s *ScaleDecider) CalculateNumInstancesToLaunch() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	minInstanceNum, maxInstanceNum := s.instanceBounds()
 	return mathx.Max(0, mathx.Clamp(
-		s.minInstanceNum-len(s.instances),
+		minInstanceNum-len(s.instances),
 		s.desiredNewInstances-len(s.recentlyLaunched),
-		s.maxInstanceNum-len(s.instances),
+		maxInstanceNum-len(s.instances),
 	))
 }
@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/mocks"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -443,3 +445,86 @@ func TestRecordInstanceStats(t *testing.T) {
 	err := sd.RecordInstanceStats(2)
 	assert.NilError(t, err)
 }
+
+func TestInstanceBoundsWithSchedules(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	schedules, err := parseSchedules([]provconfig.ScalingSchedule{
+		{
+			Name:         "weekday-warm-pool",
+			Cron:         "0 8 * * 1-5",
+			Duration:     model.Duration(11 * time.Hour),
+			MinInstances: intPtr(8),
+		},
+		{
+			Name:         "maintenance",
+			Cron:         "0 12 * * 3",
+			Duration:     model.Duration(2 * time.Hour),
+			MaxInstances: intPtr(2),
+		},
+	})
+	assert.NilError(t, err)
+
+	tcs := []struct {
+		name     string
+		now      time.Time
+		min, max int
+	}{
+		// 2024-01-01 is a Monday.
+		{"before the window", time.Date(2024, 1, 1, 7, 59, 0, 0, time.UTC), 0, 5},
+		{"window opens", time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), 8, 8},
+		{"inside the window", time.Date(2024, 1, 1, 18, 59, 0, 0, time.UTC), 8, 8},
+		{"window closes", time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC), 0, 5},
+		{"weekend", time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC), 0, 5},
+		{"maintenance caps the warm pool", time.Date(2024, 1, 3, 13, 0, 0, 0, time.UTC), 2, 2},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sd := ScaleDecider{
+				minInstanceNum: 0,
+				maxInstanceNum: 5,
+				schedules:      schedules,
+				clock:          clockwork.NewFakeClockAt(tc.now),
+			}
+			minInstances, maxInstances := sd.InstanceBounds()
+			assert.Equal(t, minInstances, tc.min)
+			assert.Equal(t, maxInstances, tc.max)
+		})
+	}
+}
+
+func TestSchedulesOverrideScaling(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	schedules, err := parseSchedules([]provconfig.ScalingSchedule{{
+		Name:         "nightly-shutdown",
+		Cron:         "0 20 * * *",
+		Duration:     model.Duration(10 * time.Hour),
+		MaxInstances: intPtr(0),
+	}})
+	assert.NilError(t, err)
+
+	clock := clockwork.NewFakeClockAt(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	sd := ScaleDecider{
+		maxStartingPeriod:   time.Minute,
+		minInstanceNum:      1,
+		maxInstanceNum:      10,
+		desiredNewInstances: 4,
+		schedules:           schedules,
+		clock:               clock,
+		instances: map[string]*model.Instance{
+			"instance1": {
+				ID:         "instance1",
+				LaunchTime: clock.Now().Add(-time.Hour),
+				AgentName:  "agent1",
+				State:      model.Running,
+			},
+		},
+	}
+	assert.Equal(t, sd.CalculateNumInstancesToLaunch(), 4)
+	assert.Equal(t, len(sd.FindInstancesToTerminate().InstanceIDs), 0)
+
+	clock.Advance(9 * time.Hour)
+	assert.Equal(t, sd.CalculateNumInstancesToLaunch(), 0)
+	decision := sd.FindInstancesToTerminate()
+	assert.DeepEqual(t, decision.InstanceIDs, []string{"instance1"})
+	assert.Equal(t, decision.Reasons["instance1"], sproto.InstanceNumberExceedsMaximum)
+}
@@ -0,0 +1,74 @@
+package scaledecider
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+// schedule is a parsed provconfig.ScalingSchedule.
+type schedule struct {
+	name         string
+	cron         cron.Schedule
+	duration     time.Duration
+	minInstances *int
+	maxInstances *int
+}
+
+func parseSchedules(configs []provconfig.ScalingSchedule) ([]schedule, error) {
+	schedules := make([]schedule, 0, len(configs))
+	for _, c := range configs {
+		sched, err := c.ParseCron()
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule{
+			name:         c.Name,
+			cron:         sched,
+			duration:     time.Duration(c.Duration),
+			minInstances: c.MinInstances,
+			maxInstances: c.MaxInstances,
+		})
+	}
+	return schedules, nil
+}
+
+// activeAt returns true if the window of the schedule contains the given time. The window is open
+// if the schedule fired within the last duration, i.e. the first activation after
+// (now - duration) is not after now.
+func (s schedule) activeAt(now time.Time) bool {
+	return !s.cron.Next(now.Add(-s.duration)).After(now)
+}
+
+// instanceBounds returns the minimum and maximum number of instances at the given time. Active
+// schedules replace the configured bounds: the largest scheduled minimum and the smallest
+// scheduled maximum win. A scheduled minimum raises the configured maximum if needed, but a
+// scheduled maximum always takes precedence over any minimum.
+func instanceBounds(
+	schedules []schedule, minInstanceNum, maxInstanceNum int, now time.Time,
+) (int, int) {
+	var schedMin, schedMax *int
+	for _, sched := range schedules {
+		if !sched.activeAt(now) {
+			continue
+		}
+		if sched.minInstances != nil && (schedMin == nil || *sched.minInstances > *schedMin) {
+			schedMin = sched.minInstances
+		}
+		if sched.maxInstances != nil && (schedMax == nil || *sched.maxInstances < *schedMax) {
+			schedMax = sched.maxInstances
+		}
+	}
+
+	if schedMin != nil {
+		minInstanceNum = *schedMin
+		maxInstanceNum = mathx.Max(maxInstanceNum, minInstanceNum)
+	}
+	if schedMax != nil {
+		maxInstanceNum = *schedMax
+	}
+	return mathx.Min(minInstanceNum, maxInstanceNum), maxInstanceNum
+}
@@ -26,6 +26,9 @@ func Setup(
 	if config.GCP != nil {
 		syslog.Info("connecting to GCP")
 	}
+	if config.Exec != nil {
+		syslog.Infof("using external command %q", config.Exec.Command)
+	}
 	provisioner, err := New(resourcePool, config, cert, db)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating provisioner")
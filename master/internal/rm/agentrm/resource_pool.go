@@ -332,10 +332,9 @@ func (rp *resourcePool) getOrCreateGroup(jobID model.JobID) *tasklist.Group {
 	g := &tasklist.Group{JobID: jobID, Weight: 1}
 
 	if rp.config.Scheduler.Priority != nil {
-		if rp.config.Scheduler.Priority.DefaultPriority == nil {
-			panic("default priority is not configured")
-		}
-		g.Priority = rp.config.Scheduler.Priority.DefaultPriority
+		// The default priority can be reloaded, so it is read from the master config.
+		priority := config.DefaultPriorityForPool(rp.config.PoolName)
+		g.Priority = &priority
 	}
 
 	rp.groups[jobID] = g
@@ -464,6 +463,7 @@ func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
 		ResourcePool:      rp.config.PoolName,
 		Resources:         sprotoResources,
 		JobSubmissionTime: req.JobSubmissionTime,
+		Slots:             req.SlotsNeeded,
 	}
 	rp.taskList.AddAllocation(req.AllocationID, &allocated)
 	rmevents.Publish(req.AllocationID, allocated.Clone())
@@ -482,6 +482,15 @@ func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
 }
 
 func (rp *resourcePool) releaseResource(aID model.AllocationID) {
+	if req, ok := rp.taskList.TaskByID(aID); ok && req.Elastic != nil && req.Elastic.TargetSlots > 0 {
+		rp.syslog.Infof("releasing resources taken by %s (resized by the scheduler to %d slots)",
+			aID, req.Elastic.TargetSlots)
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:   fmt.Sprintf("resized by the scheduler to %d slots", req.Elastic.TargetSlots),
+			ResizeTo: req.Elastic.TargetSlots,
+		})
+		return
+	}
 	rp.syslog.Infof("releasing resources taken by %s (preempted by the scheduler)", aID)
 	rmevents.Publish(aID, &sproto.ReleaseResources{Reason: "preempted by the scheduler"})
 }
@@ -640,6 +649,14 @@ func (rp *resourcePool) CapacityCheck(msg sproto.CapacityCheck) (sproto.Capacity
 	case rp.config.Provider.GCP != nil:
 		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.GCP.SlotsPerInstance()
 
+		for id, a := range rp.agentStatesCache {
+			if blockedNodeSet.Contains(string(id)) {
+				totalSlots -= len(a.slotStates)
+			}
+		}
+	case rp.config.Provider.Exec != nil:
+		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.Exec.SlotsPerInstance()
+
 		for id, a := range rp.agentStatesCache {
 			if blockedNodeSet.Contains(string(id)) {
 				totalSlots -= len(a.slotStates)
@@ -2226,24 +2226,7 @@ func (m *DispatcherResourceManager) TaskContainerDefaults(
 		result = tmp
 	}
 
-	var poolConfigOverrides *model.TaskContainerDefaultsConfig
-	for _, pool := range m.poolConfig {
-		if resourcePoolName.String() == pool.PoolName {
-			if pool.TaskContainerDefaults == nil {
-				break
-			}
-			poolConfigOverrides = pool.TaskContainerDefaults
-		}
-	}
-	if poolConfigOverrides != nil {
-		tmp, err := result.Merge(*poolConfigOverrides)
-		if err != nil {
-			return model.TaskContainerDefaultsConfig{}, err
-		}
-		result = tmp
-	}
-
-	return result, nil
+	return config.MergePoolTaskContainerDefaults(resourcePoolName.String(), result)
 }
 
 // EnableSlot implements 'det slot enable...' functionality.
@@ -823,8 +823,13 @@ func Test_dispatcherResourceManager_getTaskContainerDefaults(t *testing.T) {
 			wantErr: false,
 		},
 	}
+	masterConfig := config.GetMasterConfig()
+	previousPools := masterConfig.RootPoolsInternal
+	defer func() { masterConfig.RootPoolsInternal = previousPools }()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Pool overrides can be reloaded, so they are read from the master config.
+			masterConfig.RootPoolsInternal = tt.fields.poolConfig
 			m := &DispatcherResourceManager{
 				rmConfig:   tt.fields.rmConfig,
 				poolConfig: tt.fields.poolConfig,
@@ -103,6 +103,9 @@ type job struct {
 	slotType             device.Type
 	slotResourceRequests config.PodSlotResourceRequests
 	restore              bool
+	// kueueQueueName, if set, is the Kueue queue the job is submitted to suspended.
+	kueueQueueName string
+	podTemplate    *config.PodTemplateConfig
 
 	// System dependencies. Also set in initialization and never modified after.
 	syslog               *logrus.Entry
@@ -122,6 +125,7 @@ type job struct {
 	sentStartingEvent     bool
 	sentRunningEvent      bool
 	sentTerminationEvent  bool
+	kueueAdmitted         bool
 	// TODO(DET-10013) : Remove container field from pod struct. And get away from having several IDs, just use job name.
 	container        cproto.Container
 	resourcesDeleted atomic.Bool
@@ -242,6 +246,8 @@ func (j *job) jobUpdatedCallback(updatedJob *batchV1.Job) (cproto.State, error)
 		return j.container.State, nil
 	}
 
+	j.updateKueueAdmission(updatedJob)
+
 	conds := updatedJob.Status.Conditions
 	if len(conds) == 0 {
 		return j.container.State, nil
@@ -282,6 +288,46 @@ func (j *job) jobUpdatedCallback(updatedJob *batchV1.Job) (cproto.State, error)
 	return j.container.State, nil
 }
 
+// updateKueueAdmission tracks Kueue admitting the job, by unsuspending it, and evicting it, by
+// suspending it again. An eviction is surfaced to the task as a preemption.
+func (j *job) updateKueueAdmission(updatedJob *batchV1.Job) {
+	admitted := !jobSuspended(updatedJob)
+	if j.kueueQueueName == "" || admitted == j.kueueAdmitted {
+		return
+	}
+	j.kueueAdmitted = admitted
+
+	if admitted {
+		j.syslog.Infof("job admitted by Kueue queue %s", j.kueueQueueName)
+		j.insertLog(time.Now().UTC(), fmt.Sprintf("Job admitted by Kueue queue %s", j.kueueQueueName))
+		return
+	}
+
+	j.syslog.Infof("job evicted by Kueue queue %s", j.kueueQueueName)
+	j.insertLog(time.Now().UTC(), fmt.Sprintf("Job evicted by Kueue queue %s", j.kueueQueueName))
+	if j.jobExitCause == nil {
+		j.jobExitCause = &exitReason{
+			failureType: sproto.ResourcesPreempted,
+			msg:         "job was evicted by Kueue",
+		}
+	}
+	rmevents.Publish(j.allocationID, &sproto.ReleaseResources{
+		Reason:          "preempted by Kueue",
+		ForcePreemption: true,
+	})
+}
+
+// admitted returns false while the job is waiting on Kueue to admit it.
+func (j *job) admitted() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.kueueQueueName == "" || j.kueueAdmitted
+}
+
+func jobSuspended(job *batchV1.Job) bool {
+	return job.Spec.Suspend != nil && *job.Spec.Suspend
+}
+
 func (j *job) jobDeletedCallback() {
 	j.mu.Lock()
 	defer j.mu.Unlock()
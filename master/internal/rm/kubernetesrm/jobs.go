@@ -630,6 +630,12 @@ func (j *jobsService) startJob(msg startJob) error {
 		j.internalTaskGWConfig,
 		j.gatewayService,
 	)
+	if rp := j.resourcePoolConfig(msg.resourcePool); rp != nil {
+		if rp.Kueue != nil {
+			newJobHandler.kueueQueueName = rp.Kueue.QueueName
+		}
+		newJobHandler.podTemplate = rp.PodTemplate
+	}
 
 	if _, alreadyExists := j.jobNameToJobHandler[newJobHandler.jobName]; alreadyExists {
 		return fmt.Errorf("attempting to register same job name: %s multiple times", newJobHandler.jobName)
@@ -925,6 +931,8 @@ func (j *jobsService) recreateJobHandler(
 	newJobHandler.restore = true
 	newJobHandler.jobName = job.Name
 	newJobHandler.configMapName = job.Name
+	newJobHandler.kueueQueueName = job.Labels[kueueQueueNameLabel]
+	newJobHandler.kueueAdmitted = !jobSuspended(job)
 
 	newJobHandler.gatewayProxyResources = gatewayProxyResources
 
@@ -1234,7 +1242,15 @@ func (j *jobsService) jobUpdatedCallback(obj any) {
 		return
 	}
 
+	wasAdmitted := jobHandler.admitted()
 	state, err := jobHandler.jobUpdatedCallback(job)
+	if admitted := jobHandler.admitted(); admitted != wasAdmitted {
+		if !admitted {
+			// An evicted job's pods are deleted, so forget their scheduling states.
+			j.jobNameToPodNameToSchedulingState[job.Name] = make(map[string]sproto.SchedulingState)
+		}
+		j.notifyJobSchedulingStateChanged(job.Name, jobHandler)
+	}
 	if err != nil {
 		syslog.WithError(err).Error("failed to process job status update")
 		if err := j.cleanUpJobHandler(jobHandler); err != nil {
@@ -1305,13 +1321,7 @@ func (j *jobsService) podStatusCallback(obj any) {
 	}
 
 	j.updatePodSchedulingState(jobName, *pod)
-	if j.jobSchedulingStateCallback != nil {
-		go j.jobSchedulingStateCallback(jobSchedulingStateChanged{
-			AllocationID: jobHandler.req.AllocationID,
-			NumPods:      jobHandler.numPods,
-			State:        j.jobSchedulingState(jobName),
-		})
-	}
+	j.notifyJobSchedulingStateChanged(jobName, jobHandler)
 }
 
 func (j *jobsService) podDeletedCallback(obj any) {
@@ -1337,10 +1347,25 @@ func (j *jobsService) podDeletedCallback(obj any) {
 	jobHandler.podDeletedCallback(pod)
 }
 
-// jobSchedulingState is a roll-up of the scheduling states of its individual pods.
+func (j *jobsService) notifyJobSchedulingStateChanged(jobName string, jobHandler *job) {
+	if j.jobSchedulingStateCallback != nil {
+		go j.jobSchedulingStateCallback(jobSchedulingStateChanged{
+			AllocationID: jobHandler.req.AllocationID,
+			NumPods:      jobHandler.numPods,
+			State:        j.jobSchedulingState(jobName),
+		})
+	}
+}
+
+// jobSchedulingState is a roll-up of the scheduling states of its individual pods. Jobs waiting
+// on Kueue admission are queued regardless of their pods.
 func (j *jobsService) jobSchedulingState(jobName string) sproto.SchedulingState {
+	if jobHandler, ok := j.jobNameToJobHandler[jobName]; ok && !jobHandler.admitted() {
+		return sproto.SchedulingStateQueued
+	}
+
 	states, ok := j.jobNameToPodNameToSchedulingState[jobName]
-	if !ok {
+	if !ok || len(states) == 0 {
 		return sproto.SchedulingStateQueued
 	}
 	if !allEqual(sproto.SchedulingStateScheduled, maps.Values(states)...) {
@@ -1364,6 +1389,15 @@ func (j *jobsService) updatePodSchedulingState(jobName string, pod k8sV1.Pod) {
 	j.jobNameToPodNameToSchedulingState[jobName] = states
 }
 
+func (j *jobsService) resourcePoolConfig(resourcePool string) *config.ResourcePoolConfig {
+	for i := range j.resourcePoolConfigs {
+		if j.resourcePoolConfigs[i].PoolName == resourcePool {
+			return &j.resourcePoolConfigs[i]
+		}
+	}
+	return nil
+}
+
 var (
 	clusterID string
 	once      sync.Once
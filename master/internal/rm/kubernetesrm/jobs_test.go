@@ -3,22 +3,29 @@
 package kubernetesrm
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	batchV1 "k8s.io/api/batch/v1"
 	k8sV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/rm/rmevents"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
 )
 
 func TestGetNonDetPods(t *testing.T) {
@@ -80,6 +87,85 @@ func TestGetNonDetPods(t *testing.T) {
 	require.ElementsMatch(t, expectedPods, actualPods)
 }
 
+func TestKueueJobSchedulingState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const jobName = "test-job"
+	allocationID := model.AllocationID("test-kueue-allocation")
+	clientSet := fake.NewSimpleClientset()
+	states := make(chan sproto.SchedulingState, 10)
+	js := jobsService{
+		resourcePoolConfigs: []config.ResourcePoolConfig{
+			{PoolName: "kueue-rp", Kueue: &config.KueueConfig{QueueName: "team-a"}},
+			{PoolName: "default"},
+		},
+		syslog:                            logrus.WithField("component", "jobs"),
+		clientSet:                         clientSet,
+		jobNameToJobHandler:               make(map[string]*job),
+		jobNameToPodNameToSchedulingState: make(map[string]map[string]sproto.SchedulingState),
+		jobSchedulingStateCallback: func(msg jobSchedulingStateChanged) {
+			states <- msg.State
+		},
+	}
+	require.Equal(t, "team-a", js.resourcePoolConfig("kueue-rp").Kueue.QueueName)
+	require.Nil(t, js.resourcePoolConfig("default").Kueue)
+
+	handler := &job{
+		req:            &sproto.AllocateRequest{AllocationID: allocationID, ResourcePool: "kueue-rp"},
+		allocationID:   allocationID,
+		jobName:        jobName,
+		numPods:        1,
+		kueueQueueName: "team-a",
+		syslog:         logrus.WithField("job", jobName),
+	}
+	js.jobNameToJobHandler[jobName] = handler
+	js.jobNameToPodNameToSchedulingState[jobName] = make(map[string]sproto.SchedulingState)
+	sub := rmevents.Subscribe(allocationID)
+	defer sub.Close()
+
+	jobs := clientSet.BatchV1().Jobs("default")
+	submitted, err := jobs.Create(ctx, handler.configureJobSpec(
+		&tasks.TaskSpec{}, nil, k8sV1.Container{}, k8sV1.Container{}, nil, &k8sV1.Pod{}, "scheduler",
+	), metaV1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Submitted suspended, the job is queued until Kueue admits it.
+	js.jobUpdatedCallback(submitted)
+	require.Equal(t, sproto.SchedulingStateQueued, js.jobSchedulingState(jobName))
+	require.Empty(t, states)
+
+	// Kueue admits the job by unsuspending it; it is scheduled once its pods are.
+	setSuspend := func(suspend bool) *batchV1.Job {
+		submitted.Spec.Suspend = &suspend
+		updated, err := jobs.Update(ctx, submitted, metaV1.UpdateOptions{})
+		require.NoError(t, err)
+		return updated
+	}
+	js.jobUpdatedCallback(setSuspend(false))
+	require.Equal(t, sproto.SchedulingStateQueued, <-states)
+
+	js.updatePodSchedulingState(jobName, k8sV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "test-pod"},
+		Status: k8sV1.PodStatus{
+			Conditions: []k8sV1.PodCondition{{Type: k8sV1.PodScheduled, Status: k8sV1.ConditionTrue}},
+		},
+	})
+	require.Equal(t, sproto.SchedulingStateScheduled, js.jobSchedulingState(jobName))
+
+	// Kueue preempts the job by suspending it again, which requeues and preempts the task.
+	js.jobUpdatedCallback(setSuspend(true))
+	require.Equal(t, sproto.SchedulingStateQueued, <-states)
+	require.Empty(t, js.jobNameToPodNameToSchedulingState[jobName])
+	release := poll[*sproto.ReleaseResources](ctx, t, sub)
+	require.Equal(t, "preempted by Kueue", release.Reason)
+	require.True(t, release.ForcePreemption)
+	exit := handler.exitCause()
+	require.NotNil(t, exit)
+	require.Equal(t, sproto.ResourcesPreempted, exit.FailureType)
+	require.True(t, sproto.IsTransientSystemError(*exit))
+}
+
 func TestJobScheduledStatus(t *testing.T) {
 	// Pod has been created, but has zero PodConditions yet.
 	pendingPod := k8sV1.Pod{
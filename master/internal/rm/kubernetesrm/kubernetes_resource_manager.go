@@ -361,7 +361,7 @@ func (k *ResourceManager) SetGroupWeight(msg sproto.SetGroupWeight) error {
 func (k *ResourceManager) ValidateResources(
 	msg sproto.ValidateResourcesRequest,
 ) ([]command.LaunchWarning, error) {
-	if msg.Slots == 0 {
+	if msg.Slots == 0 && msg.PodSpec == nil {
 		return nil, nil
 	}
 
@@ -552,28 +552,7 @@ func (k ResourceManager) TaskContainerDefaults(
 	resourcePoolName rm.ResourcePoolName,
 	defaultConfig model.TaskContainerDefaultsConfig,
 ) (model.TaskContainerDefaultsConfig, error) {
-	result := defaultConfig
-
-	// Iterate through configured pools looking for a TaskContainerDefaults setting.
-	var poolConfigOverrides *model.TaskContainerDefaultsConfig
-	for _, pool := range k.poolsConfig {
-		if resourcePoolName.String() == pool.PoolName {
-			if pool.TaskContainerDefaults != nil {
-				poolConfigOverrides = pool.TaskContainerDefaults
-			}
-			break
-		}
-	}
-
-	if poolConfigOverrides != nil {
-		tmp, err := result.Merge(*poolConfigOverrides)
-		if err != nil {
-			return model.TaskContainerDefaultsConfig{}, err
-		}
-		result = tmp
-	}
-
-	return result, nil
+	return config.MergePoolTaskContainerDefaults(resourcePoolName.String(), defaultConfig)
 }
 
 type jobSchedulingStateCallback func(jobSchedulingStateChanged)
@@ -0,0 +1,149 @@
+package kubernetesrm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	k8sV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+// applyPodTemplate strategically merges a task's pod spec on top of the resource pool's pod
+// template, then restores any fields the template locks.
+func applyPodTemplate(tmpl *config.PodTemplateConfig, pod *k8sV1.Pod) (*k8sV1.Pod, error) {
+	base, err := podToMap(tmpl.Template)
+	if err != nil {
+		return nil, fmt.Errorf("encoding pod template: %w", err)
+	}
+	overrides := map[string]any{}
+	if pod != nil {
+		if overrides, err = podToMap(pod); err != nil {
+			return nil, fmt.Errorf("encoding pod spec: %w", err)
+		}
+	}
+
+	// Merging modifies the template's map in place, so look up locked values in a separate copy.
+	original, err := podToMap(tmpl.Template)
+	if err != nil {
+		return nil, fmt.Errorf("encoding pod template: %w", err)
+	}
+
+	merged, err := strategicpatch.StrategicMergeMapPatch(base, overrides, k8sV1.Pod{})
+	if err != nil {
+		return nil, fmt.Errorf("merging pod spec into pod template: %w", err)
+	}
+	for _, field := range tmpl.LockedFields {
+		path := strings.Split(field, ".")
+		if v, ok := lookupPath(original, path); ok {
+			setPath(merged, path, v)
+		} else {
+			deletePath(merged, path)
+		}
+	}
+
+	bytes, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var result k8sV1.Pod
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil, fmt.Errorf("decoding merged pod spec: %w", err)
+	}
+	return &result, nil
+}
+
+// checkPodTemplate returns an error if a task's pod spec sets fields the resource pool's pod
+// template locks, or cannot be merged into the template.
+func checkPodTemplate(tmpl *config.PodTemplateConfig, pod *k8sV1.Pod) error {
+	if pod == nil {
+		return nil
+	}
+
+	overrides, err := podToMap(pod)
+	if err != nil {
+		return fmt.Errorf("encoding pod spec: %w", err)
+	}
+	var locked []string
+	for _, field := range tmpl.LockedFields {
+		if _, ok := lookupPath(overrides, strings.Split(field, ".")); ok {
+			locked = append(locked, field)
+		}
+	}
+	if len(locked) > 0 {
+		return fmt.Errorf(
+			"pod_spec sets fields locked by the resource pool's pod template: %s",
+			strings.Join(locked, ", "),
+		)
+	}
+
+	_, err = applyPodTemplate(tmpl, pod)
+	return err
+}
+
+// podToMap converts a pod to its JSON object form, dropping null fields. Zero-valued pods marshal
+// fields such as spec.containers to null, which a strategic merge patch would treat as deletions.
+func podToMap(pod *k8sV1.Pod) (map[string]any, error) {
+	bytes, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(bytes, &m); err != nil {
+		return nil, err
+	}
+	dropNulls(m)
+	return m, nil
+}
+
+func dropNulls(m map[string]any) {
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]any:
+			dropNulls(v)
+		}
+	}
+}
+
+func lookupPath(m map[string]any, path []string) (any, bool) {
+	for i, key := range path {
+		v, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(path)-1 {
+			return v, true
+		}
+		if m, ok = v.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func setPath(m map[string]any, path []string, v any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
+func deletePath(m map[string]any, path []string) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]any)
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, path[len(path)-1])
+}
@@ -0,0 +1,106 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func testPodTemplate() *config.PodTemplateConfig {
+	return &config.PodTemplateConfig{
+		Template: &k8sV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{"team": "a"}},
+			Spec: k8sV1.PodSpec{
+				ServiceAccountName: "pool-sa",
+				InitContainers:     []k8sV1.Container{{Name: "secret-injector", Image: "injector"}},
+				Containers: []k8sV1.Container{
+					{Name: "log-forwarder", Image: "forwarder"},
+					{
+						Name: model.DeterminedK8ContainerName,
+						Env:  []k8sV1.EnvVar{{Name: "POOL", Value: "a"}},
+					},
+				},
+				Tolerations: []k8sV1.Toleration{{Key: "dedicated", Value: "a"}},
+			},
+		},
+		LockedFields: []string{"spec.serviceAccountName", "spec.tolerations"},
+	}
+}
+
+func TestApplyPodTemplate(t *testing.T) {
+	tmpl := testPodTemplate()
+
+	pod, err := applyPodTemplate(tmpl, nil)
+	require.NoError(t, err)
+	require.Equal(t, tmpl.Template, pod)
+
+	pod, err = applyPodTemplate(tmpl, &k8sV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{"user": "b"}},
+		Spec: k8sV1.PodSpec{
+			Containers: []k8sV1.Container{{
+				Name: model.DeterminedK8ContainerName,
+				Env:  []k8sV1.EnvVar{{Name: "USER", Value: "b"}},
+			}},
+			NodeSelector:       map[string]string{"gpu": "a100"},
+			ServiceAccountName: "user-sa",
+			Tolerations:        []k8sV1.Toleration{{Key: "other"}},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{"team": "a", "user": "b"}, pod.Labels)
+	require.Equal(t, map[string]string{"gpu": "a100"}, pod.Spec.NodeSelector)
+	require.Equal(t, tmpl.Template.Spec.InitContainers, pod.Spec.InitContainers)
+	require.Len(t, pod.Spec.Containers, 2)
+	require.Equal(t, "log-forwarder", pod.Spec.Containers[0].Name)
+	require.ElementsMatch(t, []k8sV1.EnvVar{{Name: "POOL", Value: "a"}, {Name: "USER", Value: "b"}},
+		pod.Spec.Containers[1].Env)
+
+	// Locked fields keep the template's values.
+	require.Equal(t, "pool-sa", pod.Spec.ServiceAccountName)
+	require.Equal(t, tmpl.Template.Spec.Tolerations, pod.Spec.Tolerations)
+
+	// The template itself is left untouched.
+	require.Equal(t, testPodTemplate(), tmpl)
+}
+
+func TestCheckPodTemplate(t *testing.T) {
+	tmpl := testPodTemplate()
+
+	require.NoError(t, checkPodTemplate(tmpl, nil))
+	require.NoError(t, checkPodTemplate(tmpl, &k8sV1.Pod{
+		Spec: k8sV1.PodSpec{NodeSelector: map[string]string{"gpu": "a100"}},
+	}))
+
+	err := checkPodTemplate(tmpl, &k8sV1.Pod{
+		Spec: k8sV1.PodSpec{
+			ServiceAccountName: "user-sa",
+			Tolerations:        []k8sV1.Toleration{{Key: "other"}},
+		},
+	})
+	require.ErrorContains(t, err, "locked by the resource pool's pod template: "+
+		"spec.serviceAccountName, spec.tolerations")
+
+	rp := &kubernetesResourcePool{
+		maxSlotsPerPod: 1,
+		poolConfig:     &config.ResourcePoolConfig{PoolName: "a", PodTemplate: tmpl},
+	}
+	err = rp.ValidateResources(sproto.ValidateResourcesRequest{
+		ResourcePool: "a",
+		PodSpec:      &expconf.PodSpec{Spec: k8sV1.PodSpec{ServiceAccountName: "user-sa"}},
+	})
+	require.ErrorContains(t, err, "invalid pod spec: pod_spec sets fields locked")
+	require.NoError(t, rp.ValidateResources(sproto.ValidateResourcesRequest{
+		ResourcePool: "a",
+		Slots:        1,
+		PodSpec:      &expconf.PodSpec{},
+	}))
+}
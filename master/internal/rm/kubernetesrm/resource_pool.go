@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
+	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
@@ -258,6 +259,15 @@ func (k *kubernetesResourcePool) ValidateResources(
 	defer k.mu.Unlock()
 	k.tryAdmitPendingTasks = true
 
+	if k.poolConfig != nil && k.poolConfig.PodTemplate != nil {
+		if err := checkPodTemplate(k.poolConfig.PodTemplate, (*k8sV1.Pod)(msg.PodSpec)); err != nil {
+			return fmt.Errorf("invalid pod spec: %w", err)
+		}
+	}
+	if msg.Slots == 0 {
+		return nil
+	}
+
 	fulfillable := k.maxSlotsPerPod >= msg.Slots
 
 	if msg.IsSingleNode {
@@ -3,6 +3,7 @@ package kubernetesrm
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math"
 	"path"
 	"reflect"
@@ -38,6 +39,8 @@ import (
 const (
 	coscheduler = "coscheduler"
 
+	kueueQueueNameLabel = "kueue.x-k8s.io/queue-name"
+
 	initContainerTarSrcPath = "/run/determined/temp/tar/src"
 	initContainerTarDstPath = "/run/determined/temp/tar/dst"
 	initContainerWorkDir    = "/run/determined/temp/"
@@ -654,7 +657,7 @@ func (j *job) configureJobSpec(
 	podSpec.Spec.RestartPolicy = k8sV1.RestartPolicyNever
 	podSpec.ObjectMeta.Namespace = j.namespace
 
-	return &batchV1.Job{
+	jobSpec := &batchV1.Job{
 		ObjectMeta: podSpec.ObjectMeta,
 		Spec: batchV1.JobSpec{
 			Parallelism:  ptrs.Ptr(int32(j.numPods)),
@@ -670,6 +673,21 @@ func (j *job) configureJobSpec(
 			TTLSecondsAfterFinished: &defaultTTLSecondsAfterFinished,
 		},
 	}
+	j.configureKueue(jobSpec)
+	return jobSpec
+}
+
+// configureKueue submits the job suspended and labeled with the pool's Kueue queue, leaving it to
+// Kueue to unsuspend the job once it is admitted.
+func (j *job) configureKueue(jobSpec *batchV1.Job) {
+	if j.kueueQueueName == "" {
+		return
+	}
+
+	// The job shares its metadata with the pod template; only the job should carry the queue label.
+	jobSpec.ObjectMeta.Labels = maps.Clone(jobSpec.ObjectMeta.Labels)
+	jobSpec.ObjectMeta.Labels[kueueQueueNameLabel] = j.kueueQueueName
+	jobSpec.Spec.Suspend = ptrs.Ptr(true)
 }
 
 func (j *job) createSpec(scheduler string, taskSpec *tasks.TaskSpec) (*batchV1.Job, *k8sV1.ConfigMap, error) {
@@ -739,13 +757,20 @@ func (j *job) createSpec(scheduler string, taskSpec *tasks.TaskSpec) (*batchV1.J
 	volumes = append(volumes, rootVolumes...)
 	container.VolumeMounts = append(container.VolumeMounts, rootVolumeMounts...)
 
+	podSpec := (*k8sV1.Pod)(env.PodSpec())
+	if j.podTemplate != nil {
+		if podSpec, err = applyPodTemplate(j.podTemplate, podSpec); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return j.configureJobSpec(
 		taskSpec,
 		volumes,
 		initContainer,
 		container,
 		sidecars,
-		(*k8sV1.Pod)(env.PodSpec()),
+		podSpec,
 		scheduler,
 	), configMapSpec, nil
 }
@@ -527,3 +527,25 @@ func TestDeterminedLabels(t *testing.T) {
 	require.NotNil(t, spec)
 	require.Equal(t, expectedLabels, spec.ObjectMeta.Labels)
 }
+
+func TestConfigureKueue(t *testing.T) {
+	taskSpec := tasks.TaskSpec{TaskType: model.TaskTypeCommand}
+	p := job{
+		req:            &sproto.AllocateRequest{ResourcePool: "test-rp"},
+		kueueQueueName: "team-a",
+	}
+
+	spec := p.configureJobSpec(
+		&taskSpec, nil, k8sV1.Container{}, k8sV1.Container{}, nil, &k8sV1.Pod{}, "scheduler",
+	)
+	require.True(t, *spec.Spec.Suspend)
+	require.Equal(t, "team-a", spec.ObjectMeta.Labels[kueueQueueNameLabel])
+	require.NotContains(t, spec.Spec.Template.ObjectMeta.Labels, kueueQueueNameLabel)
+
+	p.kueueQueueName = ""
+	spec = p.configureJobSpec(
+		&taskSpec, nil, k8sV1.Container{}, k8sV1.Container{}, nil, &k8sV1.Pod{}, "scheduler",
+	)
+	require.Nil(t, spec.Spec.Suspend)
+	require.NotContains(t, spec.ObjectMeta.Labels, kueueQueueNameLabel)
+}
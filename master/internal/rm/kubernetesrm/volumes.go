@@ -12,6 +12,7 @@ import (
 	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 func configureMountPropagation(b *mount.BindOptions) *k8sV1.MountPropagationMode {
@@ -46,13 +47,13 @@ func dockerMountsToHostVolumes(dockerMounts []mount.Mount) ([]k8sV1.VolumeMount,
 			MountPath:        d.Target,
 			MountPropagation: configureMountPropagation(d.BindOptions),
 		})
+		hostPath := &k8sV1.HostPathVolumeSource{Path: d.Source}
+		if d.BindOptions != nil && d.BindOptions.CreateMountpoint {
+			hostPath.Type = ptrs.Ptr(k8sV1.HostPathDirectoryOrCreate)
+		}
 		volumes = append(volumes, k8sV1.Volume{
-			Name: name,
-			VolumeSource: k8sV1.VolumeSource{
-				HostPath: &k8sV1.HostPathVolumeSource{
-					Path: d.Source,
-				},
-			},
+			Name:         name,
+			VolumeSource: k8sV1.VolumeSource{HostPath: hostPath},
 		})
 	}
 
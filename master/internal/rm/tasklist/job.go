@@ -35,6 +35,7 @@ func ReduceToJobQInfo(reqs AllocReqs) map[model.JobID]*sproto.RMJobInfo {
 			isAdded[req.JobID].State = req.State
 		}
 		v1JobInfo.RequestedSlots += req.SlotsNeeded
+		v1JobInfo.TargetSlots += req.TargetSlots()
 		if sproto.ScheduledStates[req.State] {
 			v1JobInfo.AllocatedSlots += req.SlotsNeeded
 		}
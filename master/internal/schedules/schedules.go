@@ -0,0 +1,467 @@
+// Package schedules runs experiment schedules, which create an experiment whenever their cron
+// expression fires. Schedules are stored in the database and run on the log retention scheduler.
+package schedules
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/logretention"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/schedulev1"
+)
+
+// CreateFunc creates an experiment as the user of ctx.
+type CreateFunc func(
+	ctx context.Context, req *apiv1.CreateExperimentRequest,
+) (*apiv1.CreateExperimentResponse, error)
+
+var (
+	log = logrus.WithField("component", "experiment-schedules")
+
+	mu               sync.Mutex
+	jobs             = map[int]gocron.Job{}
+	createExperiment CreateFunc
+)
+
+// ExperimentSchedule is a row of the experiment_schedules table.
+type ExperimentSchedule struct {
+	bun.BaseModel `bun:"table:experiment_schedules"`
+
+	ID                          int          `bun:"id,pk,autoincrement"`
+	Name                        string       `bun:"name"`
+	Description                 string       `bun:"description"`
+	Cron                        string       `bun:"cron"`
+	Config                      string       `bun:"config"`
+	Template                    *string      `bun:"template"`
+	ModelDefinitionExperimentID *int         `bun:"model_definition_experiment_id"`
+	ProjectID                   int          `bun:"project_id"`
+	UserID                      model.UserID `bun:"user_id"`
+	Username                    string       `bun:"username,scanonly"`
+	SkipIfRunning               bool         `bun:"skip_if_running"`
+	Paused                      bool         `bun:"paused"`
+	CreationTime                time.Time    `bun:"creation_time,nullzero,default:current_timestamp"`
+}
+
+// RunStatus is the outcome of a run of a schedule.
+type RunStatus string
+
+const (
+	// RunCreated means the run created an experiment.
+	RunCreated RunStatus = "CREATED"
+	// RunSkipped means the run was skipped since the previous run was still active.
+	RunSkipped RunStatus = "SKIPPED"
+	// RunFailed means the run failed to create an experiment.
+	RunFailed RunStatus = "FAILED"
+)
+
+var runStatusToProto = map[RunStatus]schedulev1.RunStatus{
+	RunCreated: schedulev1.RunStatus_RUN_STATUS_CREATED,
+	RunSkipped: schedulev1.RunStatus_RUN_STATUS_SKIPPED,
+	RunFailed:  schedulev1.RunStatus_RUN_STATUS_FAILED,
+}
+
+// Run is a row of the experiment_schedule_runs table.
+type Run struct {
+	bun.BaseModel `bun:"table:experiment_schedule_runs"`
+
+	ID           int       `bun:"id,pk,autoincrement"`
+	ScheduleID   int       `bun:"schedule_id"`
+	RunTime      time.Time `bun:"run_time"`
+	Status       RunStatus `bun:"status"`
+	ExperimentID *int      `bun:"experiment_id"`
+	Message      string    `bun:"message"`
+}
+
+// Proto converts a run to its proto representation.
+func (r *Run) Proto() *schedulev1.ExperimentScheduleRun {
+	pr := &schedulev1.ExperimentScheduleRun{
+		Id:         int32(r.ID),
+		ScheduleId: int32(r.ScheduleID),
+		RunTime:    timestamppb.New(r.RunTime),
+		Status:     runStatusToProto[r.Status],
+		Message:    r.Message,
+	}
+	if r.ExperimentID != nil {
+		pr.ExperimentId = ptrs.Ptr(int32(*r.ExperimentID))
+	}
+	return pr
+}
+
+// Proto converts a schedule to its proto representation, including when it fires next and its last
+// run.
+func (s *ExperimentSchedule) Proto(ctx context.Context) (*schedulev1.ExperimentSchedule, error) {
+	ps := &schedulev1.ExperimentSchedule{
+		Id:            int32(s.ID),
+		Name:          s.Name,
+		Description:   s.Description,
+		Cron:          s.Cron,
+		Config:        s.Config,
+		Template:      s.Template,
+		ProjectId:     int32(s.ProjectID),
+		UserId:        int32(s.UserID),
+		Username:      s.Username,
+		SkipIfRunning: s.SkipIfRunning,
+		Paused:        s.Paused,
+		CreationTime:  timestamppb.New(s.CreationTime),
+	}
+	if s.ModelDefinitionExperimentID != nil {
+		ps.ModelDefinitionExperimentId = ptrs.Ptr(int32(*s.ModelDefinitionExperimentID))
+	}
+
+	mu.Lock()
+	job, ok := jobs[s.ID]
+	mu.Unlock()
+	if ok {
+		if next, err := job.NextRun(); err == nil && !next.IsZero() {
+			ps.NextRunTime = timestamppb.New(next)
+		}
+	}
+
+	var last Run
+	switch err := db.Bun().NewSelect().Model(&last).
+		Where("schedule_id = ?", s.ID).
+		Order("run_time DESC").
+		Limit(1).
+		Scan(ctx); {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("getting last run of schedule %d: %w", s.ID, err)
+	default:
+		ps.LastRun = last.Proto()
+	}
+	return ps, nil
+}
+
+// Params are the values that can be substituted into the config of a scheduled run.
+type Params struct {
+	// Date is the date of the run, as YYYY-MM-DD.
+	Date string
+	// Time is the time of the run.
+	Time time.Time
+	// ScheduleID and ScheduleName identify the schedule.
+	ScheduleID   int
+	ScheduleName string
+}
+
+// NewParams returns the parameters of a run of s at t, in the time zone the cron expression of s
+// fires in.
+func NewParams(s *ExperimentSchedule, t time.Time) Params {
+	_, loc := cronTimeZone(s.Cron)
+	t = t.In(loc)
+	return Params{
+		Date:         t.Format(time.DateOnly),
+		Time:         t,
+		ScheduleID:   s.ID,
+		ScheduleName: s.Name,
+	}
+}
+
+// RenderConfig substitutes params into the config of a schedule.
+func RenderConfig(config string, params Params) (string, error) {
+	tmpl, err := template.New("config").Option("missingkey=error").Parse(config)
+	if err != nil {
+		return "", fmt.Errorf("parsing schedule config: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", fmt.Errorf("substituting schedule parameters: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// ValidateCron checks that a cron expression can be scheduled.
+func ValidateCron(expr string) error {
+	if _, err := cron.ParseStandard(expr); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return nil
+}
+
+// cronTimeZone returns a cron expression with its time zone made explicit, and that time zone. It
+// is the one named by a CRON_TZ= or TZ= prefix, or UTC, never the time zone of the master.
+func cronTimeZone(expr string) (string, *time.Location) {
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(expr, prefix); ok {
+			name, _, _ := strings.Cut(rest, " ")
+			if loc, err := time.LoadLocation(name); err == nil {
+				return expr, loc
+			}
+			return expr, time.UTC
+		}
+	}
+	return "CRON_TZ=UTC " + expr, time.UTC
+}
+
+// CreateRequest returns the request that creates the experiment of a run of s at t.
+func CreateRequest(s *ExperimentSchedule, t time.Time) (*apiv1.CreateExperimentRequest, error) {
+	config, err := RenderConfig(s.Config, NewParams(s, t))
+	if err != nil {
+		return nil, err
+	}
+	req := &apiv1.CreateExperimentRequest{
+		Config:    config,
+		Template:  s.Template,
+		ProjectId: int32(s.ProjectID),
+		Activate:  true,
+	}
+	if s.ModelDefinitionExperimentID != nil {
+		req.ParentId = int32(*s.ModelDefinitionExperimentID)
+	}
+	return req, nil
+}
+
+// Start schedules every schedule that is not paused. Experiments are created by create.
+func Start(ctx context.Context, create CreateFunc) error {
+	mu.Lock()
+	createExperiment = create
+	mu.Unlock()
+
+	var schedules []*ExperimentSchedule
+	if err := selectSchedules(&schedules).Where("s.paused = false").Scan(ctx); err != nil {
+		return fmt.Errorf("getting experiment schedules: %w", err)
+	}
+	for _, s := range schedules {
+		if err := schedule(s); err != nil {
+			log.WithError(err).Errorf("failed to schedule experiment schedule %d", s.ID)
+		}
+	}
+	return nil
+}
+
+// Get returns a schedule.
+func Get(ctx context.Context, id int) (*ExperimentSchedule, error) {
+	var s ExperimentSchedule
+	err := selectSchedules(&s).Where("s.id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting experiment schedule %d: %w", id, err)
+	}
+	return &s, nil
+}
+
+// List returns every schedule, or the schedules of a project if projectID is not 0.
+func List(ctx context.Context, projectID int) ([]*ExperimentSchedule, error) {
+	var schedules []*ExperimentSchedule
+	q := selectSchedules(&schedules).Order("s.id")
+	if projectID != 0 {
+		q.Where("s.project_id = ?", projectID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting experiment schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func selectSchedules(model any) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(model).
+		ModelTableExpr("experiment_schedules AS s").
+		ColumnExpr("s.*").
+		ColumnExpr("u.username").
+		Join("JOIN users AS u ON u.id = s.user_id")
+}
+
+// Runs returns the runs of a schedule, most recent first.
+func Runs(id int) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model((*Run)(nil)).
+		Where("schedule_id = ?", id).
+		Order("run_time DESC", "id DESC")
+}
+
+// Add stores a new schedule and schedules it unless it is paused.
+func Add(ctx context.Context, s *ExperimentSchedule) error {
+	if err := ValidateCron(s.Cron); err != nil {
+		return err
+	}
+	if _, err := db.Bun().NewInsert().Model(s).ExcludeColumn("username").
+		Returning("id, creation_time").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("inserting experiment schedule: %w", err)
+	}
+	if s.Paused {
+		return nil
+	}
+	return schedule(s)
+}
+
+// SetPaused pauses or unpauses a schedule.
+func SetPaused(ctx context.Context, id int, paused bool) error {
+	if _, err := db.Bun().NewUpdate().Table("experiment_schedules").
+		Set("paused = ?", paused).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("updating experiment schedule %d: %w", id, err)
+	}
+	if paused {
+		unschedule(id)
+		return nil
+	}
+	s, err := Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return schedule(s)
+}
+
+// Delete deletes a schedule. The experiments it created are kept.
+func Delete(ctx context.Context, id int) error {
+	unschedule(id)
+	if _, err := db.Bun().NewDelete().Table("experiment_schedules").
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting experiment schedule %d: %w", id, err)
+	}
+	return nil
+}
+
+func schedule(s *ExperimentSchedule) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := jobs[s.ID]; ok {
+		return nil
+	}
+	id := s.ID
+	crontab, _ := cronTimeZone(s.Cron)
+	job, err := logretention.Scheduler().NewJob(
+		gocron.CronJob(crontab, false),
+		gocron.NewTask(func() { run(context.Background(), id, time.Now()) }),
+		gocron.WithName(fmt.Sprintf("experiment-schedule-%d", id)),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return fmt.Errorf("scheduling experiment schedule %d: %w", id, err)
+	}
+	jobs[id] = job
+	return nil
+}
+
+func unschedule(id int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	job, ok := jobs[id]
+	if !ok {
+		return
+	}
+	if err := logretention.Scheduler().RemoveJob(job.ID()); err != nil {
+		log.WithError(err).Warnf("failed to unschedule experiment schedule %d", id)
+	}
+	delete(jobs, id)
+}
+
+// run creates the experiment of a run of a schedule and records the outcome.
+func run(ctx context.Context, id int, t time.Time) {
+	syslog := log.WithField("schedule-id", id)
+
+	r := &Run{ScheduleID: id, RunTime: t}
+	r.Status, r.ExperimentID, r.Message = runSchedule(ctx, id, t)
+	if r.Status == "" {
+		return
+	}
+	if _, err := db.Bun().NewInsert().Model(r).Exec(ctx); err != nil {
+		syslog.WithError(err).Error("failed to record experiment schedule run")
+	}
+
+	switch r.Status {
+	case RunCreated:
+		syslog.Infof("created experiment %d", *r.ExperimentID)
+	case RunSkipped:
+		syslog.Info(r.Message)
+	case RunFailed:
+		syslog.Errorf("failed to create experiment: %s", r.Message)
+	}
+}
+
+func runSchedule(ctx context.Context, id int, t time.Time) (RunStatus, *int, string) {
+	s, err := Get(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		// The schedule was deleted while the run was pending.
+		return "", nil, ""
+	} else if err != nil {
+		return RunFailed, nil, err.Error()
+	}
+	if s.Paused {
+		return "", nil, ""
+	}
+
+	if s.SkipIfRunning {
+		active, err := previousRunActive(ctx, id)
+		if err != nil {
+			return RunFailed, nil, err.Error()
+		}
+		if active != nil {
+			return RunSkipped, nil, fmt.Sprintf("skipped: experiment %d of the previous run is %s",
+				active.ID, active.State)
+		}
+	}
+
+	var owner model.User
+	if err := db.Bun().NewSelect().Model(&owner).Where("id = ?", s.UserID).Scan(ctx); err != nil {
+		return RunFailed, nil, fmt.Sprintf("getting owner %d: %s", s.UserID, err)
+	}
+	if !owner.Active {
+		return RunFailed, nil, fmt.Sprintf("owner %s is deactivated", owner.Username)
+	}
+
+	req, err := CreateRequest(s, t)
+	if err != nil {
+		return RunFailed, nil, err.Error()
+	}
+
+	mu.Lock()
+	create := createExperiment
+	mu.Unlock()
+	resp, err := create(grpcutil.WithUser(ctx, &owner), req)
+	if err != nil {
+		return RunFailed, nil, err.Error()
+	}
+	return RunCreated, ptrs.Ptr(int(resp.Experiment.Id)), ""
+}
+
+// previousRunActive returns the experiment of the last run of a schedule if it is not finished.
+func previousRunActive(ctx context.Context, id int) (*model.Experiment, error) {
+	var expID int
+	switch err := db.Bun().NewSelect().Model((*Run)(nil)).
+		Column("experiment_id").
+		Where("schedule_id = ?", id).
+		Where("status = ?", RunCreated).
+		Where("experiment_id IS NOT NULL").
+		Order("run_time DESC").
+		Limit(1).
+		Scan(ctx, &expID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting previous run: %w", err)
+	}
+
+	exp, err := db.ExperimentByID(ctx, expID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting experiment %d of previous run: %w", expID, err)
+	}
+	if model.TerminalStates[exp.State] {
+		return nil, nil
+	}
+	return exp, nil
+}
@@ -0,0 +1,248 @@
+//go:build integration
+// +build integration
+
+package schedules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+var pgDB *db.PgDB
+
+func TestMain(m *testing.M) {
+	var err error
+	pgDB, _, err = db.ResolveTestPostgres()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// mockCreate creates a mock experiment for each request, or fails with err if it is set, and
+// keeps the requests it was called with.
+type mockCreate struct {
+	t    *testing.T
+	user model.User
+	err  error
+	reqs []*apiv1.CreateExperimentRequest
+	exps []*model.Experiment
+}
+
+func (m *mockCreate) create(
+	ctx context.Context, req *apiv1.CreateExperimentRequest,
+) (*apiv1.CreateExperimentResponse, error) {
+	m.reqs = append(m.reqs, req)
+	if m.err != nil {
+		return nil, m.err
+	}
+	exp := db.RequireMockExperiment(m.t, pgDB, m.user)
+	m.exps = append(m.exps, exp)
+	return &apiv1.CreateExperimentResponse{
+		Experiment: &experimentv1.Experiment{Id: int32(exp.ID)},
+	}, nil
+}
+
+func requireAddSchedule(t *testing.T, user model.User, skipIfRunning, paused bool) *ExperimentSchedule {
+	s := &ExperimentSchedule{
+		Name:          "nightly",
+		Cron:          "30 2 * * *",
+		Config:        "name: {{.ScheduleName}}-{{.Date}}",
+		ProjectID:     db.DefaultProjectID,
+		UserID:        user.ID,
+		SkipIfRunning: skipIfRunning,
+		Paused:        paused,
+	}
+	// Insert the schedule without scheduling it, so only the test runs it.
+	_, err := db.Bun().NewInsert().Model(s).ExcludeColumn("username").
+		Returning("id, creation_time").
+		Exec(context.Background())
+	require.NoError(t, err)
+	return s
+}
+
+func requireRuns(t *testing.T, id int) []Run {
+	var runs []Run
+	require.NoError(t, Runs(id).Scan(context.Background(), &runs))
+	return runs
+}
+
+func unscheduleAll() {
+	mu.Lock()
+	ids := make([]int, 0, len(jobs))
+	for id := range jobs {
+		ids = append(ids, id)
+	}
+	mu.Unlock()
+	for _, id := range ids {
+		unschedule(id)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, pgDB)
+	mock := &mockCreate{t: t, user: user}
+	createExperiment = mock.create
+
+	s := requireAddSchedule(t, user, false, false)
+	runTime := time.Date(2024, 10, 8, 2, 30, 0, 0, time.UTC)
+
+	run(ctx, s.ID, runTime)
+	require.Len(t, mock.reqs, 1)
+	require.Equal(t, "name: nightly-2024-10-08", mock.reqs[0].Config)
+	require.Equal(t, int32(db.DefaultProjectID), mock.reqs[0].ProjectId)
+	require.True(t, mock.reqs[0].Activate)
+
+	runs := requireRuns(t, s.ID)
+	require.Len(t, runs, 1)
+	require.Equal(t, RunCreated, runs[0].Status)
+	require.Equal(t, mock.exps[0].ID, *runs[0].ExperimentID)
+	require.True(t, runTime.Equal(runs[0].RunTime))
+
+	// Without skip_if_running, a run creates an experiment even if the previous one is active.
+	run(ctx, s.ID, runTime.Add(24*time.Hour))
+	require.Len(t, mock.reqs, 2)
+	require.Equal(t, "name: nightly-2024-10-09", mock.reqs[1].Config)
+
+	mock.err = fmt.Errorf("no such template")
+	run(ctx, s.ID, runTime.Add(48*time.Hour))
+	runs = requireRuns(t, s.ID)
+	require.Len(t, runs, 3)
+	require.Equal(t, RunFailed, runs[0].Status)
+	require.Nil(t, runs[0].ExperimentID)
+	require.Equal(t, "no such template", runs[0].Message)
+	require.Equal(t, RunCreated, runs[1].Status)
+	require.Equal(t, mock.exps[1].ID, *runs[1].ExperimentID)
+
+	// Runs of paused or deleted schedules are not recorded.
+	require.NoError(t, SetPaused(ctx, s.ID, true))
+	run(ctx, s.ID, runTime.Add(72*time.Hour))
+	require.Len(t, requireRuns(t, s.ID), 3)
+	require.NoError(t, Delete(ctx, s.ID))
+	run(ctx, s.ID, runTime.Add(96*time.Hour))
+	require.Len(t, mock.reqs, 3)
+	require.Empty(t, requireRuns(t, s.ID))
+}
+
+func TestRunSkipIfRunning(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, pgDB)
+	mock := &mockCreate{t: t, user: user}
+	createExperiment = mock.create
+
+	s := requireAddSchedule(t, user, true, false)
+	runTime := time.Date(2024, 10, 8, 2, 30, 0, 0, time.UTC)
+
+	active, err := previousRunActive(ctx, s.ID)
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	run(ctx, s.ID, runTime)
+	require.Len(t, mock.exps, 1)
+	exp := mock.exps[0]
+
+	active, err = previousRunActive(ctx, s.ID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	require.Equal(t, exp.ID, active.ID)
+
+	run(ctx, s.ID, runTime.Add(24*time.Hour))
+	require.Len(t, mock.reqs, 1)
+	runs := requireRuns(t, s.ID)
+	require.Len(t, runs, 2)
+	require.Equal(t, RunSkipped, runs[0].Status)
+	require.Nil(t, runs[0].ExperimentID)
+	require.Equal(t,
+		fmt.Sprintf("skipped: experiment %d of the previous run is %s", exp.ID, model.ActiveState),
+		runs[0].Message)
+
+	// A skipped run does not hide the experiment of the last run that created one.
+	active, err = previousRunActive(ctx, s.ID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	require.Equal(t, exp.ID, active.ID)
+
+	_, err = db.Bun().NewUpdate().Table("experiments").
+		Set("state = ?", model.CompletedState).
+		Where("id = ?", exp.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	active, err = previousRunActive(ctx, s.ID)
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	run(ctx, s.ID, runTime.Add(48*time.Hour))
+	require.Len(t, mock.exps, 2)
+	runs = requireRuns(t, s.ID)
+	require.Len(t, runs, 3)
+	require.Equal(t, RunCreated, runs[0].Status)
+	require.Equal(t, mock.exps[1].ID, *runs[0].ExperimentID)
+}
+
+func TestStartRestoresSchedules(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, pgDB)
+	mock := &mockCreate{t: t, user: user}
+
+	s := requireAddSchedule(t, user, false, false)
+	paused := requireAddSchedule(t, user, false, true)
+
+	// A restarted master has no schedules until Start restores them from the database.
+	unscheduleAll()
+	defer unscheduleAll()
+	require.NoError(t, Start(ctx, mock.create))
+
+	mu.Lock()
+	_, ok := jobs[s.ID]
+	_, pausedOK := jobs[paused.ID]
+	mu.Unlock()
+	require.True(t, ok)
+	require.False(t, pausedOK)
+
+	restored, err := Get(ctx, s.ID)
+	require.NoError(t, err)
+	ps, err := restored.Proto(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, ps.NextRunTime)
+	next := ps.NextRunTime.AsTime()
+	require.Equal(t, 2, next.Hour())
+	require.Equal(t, 30, next.Minute())
+
+	// Restored schedules create experiments with the create func given to Start.
+	run(ctx, s.ID, time.Now())
+	require.Len(t, mock.reqs, 1)
+	runs := requireRuns(t, s.ID)
+	require.Len(t, runs, 1)
+	require.Equal(t, RunCreated, runs[0].Status)
+
+	// Unpausing a schedule after the restart schedules it too.
+	require.NoError(t, SetPaused(ctx, paused.ID, false))
+	mu.Lock()
+	_, pausedOK = jobs[paused.ID]
+	mu.Unlock()
+	require.True(t, pausedOK)
+}
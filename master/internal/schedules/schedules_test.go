@@ -0,0 +1,78 @@
+package schedules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestRenderConfig(t *testing.T) {
+	s := &ExperimentSchedule{ID: 3, Name: "nightly"}
+	runTime := time.Date(2024, 10, 8, 2, 30, 0, 0, time.UTC)
+
+	config, err := RenderConfig(
+		"name: {{.ScheduleName}}-{{.Date}}\n"+
+			"hyperparameters:\n"+
+			"  cutoff: {{.Time.Format \"2006-01\"}}\n"+
+			"  schedule: {{.ScheduleID}}\n",
+		NewParams(s, runTime))
+	require.NoError(t, err)
+	require.Equal(t,
+		"name: nightly-2024-10-08\nhyperparameters:\n  cutoff: 2024-10\n  schedule: 3\n", config)
+
+	_, err = RenderConfig("name: {{.Unknown}}", NewParams(s, runTime))
+	require.ErrorContains(t, err, "substituting schedule parameters")
+
+	_, err = RenderConfig("name: {{.Date", NewParams(s, runTime))
+	require.ErrorContains(t, err, "parsing schedule config")
+}
+
+func TestParamsUseCronTimeZone(t *testing.T) {
+	runTime := time.Date(2024, 10, 8, 2, 30, 0, 0, time.UTC)
+
+	params := NewParams(&ExperimentSchedule{Cron: "30 2 * * *"}, runTime.In(time.FixedZone("X", -5*3600)))
+	require.Equal(t, "2024-10-08", params.Date)
+	require.Equal(t, time.UTC, params.Time.Location())
+
+	params = NewParams(&ExperimentSchedule{Cron: "CRON_TZ=America/New_York 30 22 * * *"}, runTime)
+	require.Equal(t, "2024-10-07", params.Date)
+	require.Equal(t, 22, params.Time.Hour())
+
+	crontab, loc := cronTimeZone("@daily")
+	require.Equal(t, "CRON_TZ=UTC @daily", crontab)
+	require.Equal(t, time.UTC, loc)
+	require.NoError(t, ValidateCron(crontab))
+}
+
+func TestCreateRequest(t *testing.T) {
+	s := &ExperimentSchedule{
+		Name:                        "nightly",
+		Config:                      "name: {{.ScheduleName}}-{{.Date}}",
+		Template:                    ptrs.Ptr("defaults"),
+		ModelDefinitionExperimentID: ptrs.Ptr(12),
+		ProjectID:                   4,
+	}
+	req, err := CreateRequest(s, time.Date(2024, 10, 8, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, "name: nightly-2024-10-08", req.Config)
+	require.Equal(t, "defaults", *req.Template)
+	require.Equal(t, int32(12), req.ParentId)
+	require.Equal(t, int32(4), req.ProjectId)
+	require.True(t, req.Activate)
+
+	s.ModelDefinitionExperimentID = nil
+	req, err = CreateRequest(s, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, req.ParentId)
+}
+
+func TestValidateCron(t *testing.T) {
+	require.NoError(t, ValidateCron("0 2 * * *"))
+	require.NoError(t, ValidateCron("CRON_TZ=America/New_York 30 1 * * 1-5"))
+	require.NoError(t, ValidateCron("@daily"))
+	require.Error(t, ValidateCron("0 2 * *"))
+	require.Error(t, ValidateCron("CRON_TZ=Nowhere/City 0 2 * * *"))
+}
@@ -0,0 +1,221 @@
+// Package sharelink mints and checks expiring links that grant access to the proxied
+// services of a task to people who could not otherwise reach them.
+package sharelink
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	// QueryParam is the query parameter the proxy accepts share link tokens in.
+	QueryParam = "share_token"
+	// CookieName is the cookie the proxy keeps the token of an opened link in, so that the requests
+	// the proxied service makes afterwards are let in too.
+	CookieName = "det_share_token"
+	// MaxLifetime is the longest a link can be valid for.
+	MaxLifetime = 30 * 24 * time.Hour
+
+	tokenBytes = 32
+)
+
+var (
+	// ErrInvalidLink is returned for tokens that do not belong to a link, and for links that are
+	// expired, revoked or for another task.
+	ErrInvalidLink = errors.New("share link is invalid, expired or revoked")
+	// ErrNotAllowed is returned for requests a valid link does not let in.
+	ErrNotAllowed = errors.New("share link does not allow this request")
+)
+
+// ShareLink is a row of the proxy_share_links table.
+type ShareLink struct {
+	bun.BaseModel `bun:"table:proxy_share_links"`
+
+	ID        int          `bun:"id,pk,autoincrement"`
+	TokenHash string       `bun:"token_hash"`
+	TaskID    model.TaskID `bun:"task_id"`
+	UserID    model.UserID `bun:"user_id"`
+	ReadOnly  bool         `bun:"read_only"`
+	UserIDs   []int32      `bun:"user_ids,array"`
+	GroupIDs  []int32      `bun:"group_ids,array"`
+	CreatedAt time.Time    `bun:"created_at"`
+	ExpiresAt time.Time    `bun:"expires_at"`
+	RevokedAt *time.Time   `bun:"revoked_at"`
+}
+
+// Proto converts a link to its proto representation.
+func (l *ShareLink) Proto() *apiv1.ProxyShareLink {
+	pb := &apiv1.ProxyShareLink{
+		Id:        int32(l.ID),
+		TaskId:    string(l.TaskID),
+		UserId:    int32(l.UserID),
+		ReadOnly:  l.ReadOnly,
+		UserIds:   l.UserIDs,
+		GroupIds:  l.GroupIDs,
+		CreatedAt: timestamppb.New(l.CreatedAt),
+		ExpiresAt: timestamppb.New(l.ExpiresAt),
+	}
+	if l.RevokedAt != nil {
+		pb.RevokedAt = timestamppb.New(*l.RevokedAt)
+	}
+	return pb
+}
+
+// Create persists a link valid for the given duration and returns its token. Tokens are random
+// and only their hashes are stored, so they can't be mistaken for, or forged from, the signed
+// tokens of user sessions.
+func Create(ctx context.Context, l *ShareLink, lifetime time.Duration) (string, error) {
+	if lifetime <= 0 || lifetime > MaxLifetime {
+		return "", fmt.Errorf("%w: share links must be valid for more than 0s and at most %s",
+			db.ErrInvalidInput, MaxLifetime)
+	}
+	if l.UserIDs == nil {
+		l.UserIDs = []int32{}
+	}
+	if l.GroupIDs == nil {
+		l.GroupIDs = []int32{}
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	l.TokenHash = hashToken(token)
+	l.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	l.ExpiresAt = l.CreatedAt.Add(lifetime)
+
+	if _, err := db.Bun().NewInsert().Model(l).Returning("id").Exec(ctx); err != nil {
+		return "", fmt.Errorf("creating share link for task %s: %w", l.TaskID, err)
+	}
+	return token, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating share link token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// ByID returns a link, returning db.ErrNotFound if it does not exist.
+func ByID(ctx context.Context, id int) (*ShareLink, error) {
+	var l ShareLink
+	if err := db.Bun().NewSelect().Model(&l).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &l, nil
+}
+
+// ActiveForTask returns the links of a task that are neither expired nor revoked, newest first.
+func ActiveForTask(ctx context.Context, taskID model.TaskID) ([]*ShareLink, error) {
+	links := []*ShareLink{}
+	err := db.Bun().NewSelect().Model(&links).
+		Where("task_id = ?", taskID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > now()").
+		Order("id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting share links of task %s: %w", taskID, err)
+	}
+	return links, nil
+}
+
+// Revoke revokes a link, returning db.ErrNotFound if it does not exist or is already revoked.
+func Revoke(ctx context.Context, id int) error {
+	res, err := db.Bun().NewUpdate().Model((*ShareLink)(nil)).
+		Set("revoked_at = now()").
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("revoking share link %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// Authorize returns the link of a token if it lets a request to the proxied services of a task
+// in. The user is the logged-in user who made the request, or nil if there is none.
+func Authorize(
+	ctx context.Context, token string, taskID model.TaskID, method string, webSocket bool,
+	usr *model.User,
+) (*ShareLink, error) {
+	if len(token) != 2*tokenBytes {
+		return nil, ErrInvalidLink
+	}
+
+	var l ShareLink
+	err := db.Bun().NewSelect().Model(&l).Where("token_hash = ?", hashToken(token)).Scan(ctx)
+	switch {
+	case errors.Is(db.MatchSentinelError(err), db.ErrNotFound):
+		return nil, ErrInvalidLink
+	case err != nil:
+		return nil, fmt.Errorf("getting share link: %w", err)
+	case l.RevokedAt != nil || l.TaskID != taskID || !time.Now().Before(l.ExpiresAt):
+		return nil, ErrInvalidLink
+	}
+
+	if l.ReadOnly && !readOnlyAllows(method, webSocket) {
+		return nil, ErrNotAllowed
+	}
+	if len(l.UserIDs) == 0 && len(l.GroupIDs) == 0 {
+		return &l, nil
+	}
+	if usr == nil {
+		return nil, ErrNotAllowed
+	}
+	if slices.Contains(l.UserIDs, int32(usr.ID)) {
+		return &l, nil
+	}
+	if len(l.GroupIDs) > 0 {
+		member, err := db.Bun().NewSelect().Table("user_group_membership").
+			Where("user_id = ?", usr.ID).
+			Where("group_id IN (?)", bun.In(l.GroupIDs)).
+			Exists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checking group membership for share link %d: %w", l.ID, err)
+		}
+		if member {
+			return &l, nil
+		}
+	}
+	return nil, ErrNotAllowed
+}
+
+// readOnlyAllows returns whether a read-only link lets a request in. WebSockets, including TCP
+// proxying, are refused since they can carry writes.
+func readOnlyAllows(method string, webSocket bool) bool {
+	if webSocket {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
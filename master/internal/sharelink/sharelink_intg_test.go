@@ -0,0 +1,136 @@
+//go:build integration
+
+package sharelink
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/usergroup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+var pgDB *db.PgDB
+
+func TestMain(m *testing.M) {
+	var err error
+	pgDB, _, err = db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+	if err := db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up"); err != nil {
+		log.Panicln(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestShareLinkLifecycle(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, pgDB)
+	task := db.RequireMockTask(t, pgDB, &owner.ID)
+
+	_, err := Create(ctx, &ShareLink{TaskID: task.TaskID, UserID: owner.ID}, MaxLifetime+time.Hour)
+	require.ErrorIs(t, err, db.ErrInvalidInput)
+
+	l := &ShareLink{TaskID: task.TaskID, UserID: owner.ID, ReadOnly: true}
+	token, err := Create(ctx, l, time.Hour)
+	require.NoError(t, err)
+
+	// Anyone can read through an unrestricted read-only link, but not write.
+	actual, err := Authorize(ctx, token, task.TaskID, http.MethodGet, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, l.ID, actual.ID)
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodPost, false, nil)
+	require.ErrorIs(t, err, ErrNotAllowed)
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodGet, true, nil)
+	require.ErrorIs(t, err, ErrNotAllowed)
+
+	links, err := ActiveForTask(ctx, task.TaskID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, l.ID, links[0].ID)
+
+	require.NoError(t, Revoke(ctx, l.ID))
+	require.ErrorIs(t, Revoke(ctx, l.ID), db.ErrNotFound)
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodGet, false, nil)
+	require.ErrorIs(t, err, ErrInvalidLink)
+	links, err = ActiveForTask(ctx, task.TaskID)
+	require.NoError(t, err)
+	require.Empty(t, links)
+}
+
+func TestShareLinkRestrictedToUsersAndGroups(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, pgDB)
+	named := db.RequireMockUser(t, pgDB)
+	member := db.RequireMockUser(t, pgDB)
+	other := db.RequireMockUser(t, pgDB)
+	task := db.RequireMockTask(t, pgDB, &owner.ID)
+	group, _, err := usergroup.AddGroupWithMembers(ctx, model.Group{Name: uuid.NewString()}, member.ID)
+	require.NoError(t, err)
+
+	token, err := Create(ctx, &ShareLink{
+		TaskID:   task.TaskID,
+		UserID:   owner.ID,
+		UserIDs:  []int32{int32(named.ID)},
+		GroupIDs: []int32{int32(group.ID)},
+	}, time.Hour)
+	require.NoError(t, err)
+
+	for _, u := range []model.User{named, member} {
+		_, err = Authorize(ctx, token, task.TaskID, http.MethodPost, true, &u)
+		require.NoError(t, err, u.Username)
+	}
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodGet, false, &other)
+	require.ErrorIs(t, err, ErrNotAllowed)
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodGet, false, nil)
+	require.ErrorIs(t, err, ErrNotAllowed)
+}
+
+func TestShareLinkRefusedForOtherTasksAndWhenExpired(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, pgDB)
+	task := db.RequireMockTask(t, pgDB, &owner.ID)
+	other := db.RequireMockTask(t, pgDB, &owner.ID)
+
+	l := &ShareLink{TaskID: task.TaskID, UserID: owner.ID}
+	token, err := Create(ctx, l, time.Hour)
+	require.NoError(t, err)
+	_, err = Authorize(ctx, token, other.TaskID, http.MethodGet, false, nil)
+	require.ErrorIs(t, err, ErrInvalidLink)
+
+	_, err = db.Bun().NewUpdate().Model(l).
+		Set("expires_at = now() - interval '1 second'").
+		WherePK().
+		Exec(ctx)
+	require.NoError(t, err)
+	_, err = Authorize(ctx, token, task.TaskID, http.MethodGet, false, nil)
+	require.ErrorIs(t, err, ErrInvalidLink)
+}
+
+func TestShareLinkTokenIsNotASessionToken(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, pgDB)
+	task := db.RequireMockTask(t, pgDB, &owner.ID)
+
+	// Make sure there are sessions whose IDs a link's could collide with.
+	for i := 0; i < 3; i++ {
+		_, err := user.StartSession(ctx, &owner)
+		require.NoError(t, err)
+	}
+	l := &ShareLink{TaskID: task.TaskID, UserID: owner.ID}
+	token, err := Create(ctx, l, time.Hour)
+	require.NoError(t, err)
+
+	_, _, err = user.ByToken(ctx, token, &model.ExternalSessions{})
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
@@ -0,0 +1,45 @@
+package sharelink
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestReadOnlyAllows(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		require.True(t, readOnlyAllows(method, false), method)
+		require.False(t, readOnlyAllows(method, true), method)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		require.False(t, readOnlyAllows(method, false), method)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	a, err := newToken()
+	require.NoError(t, err)
+	b, err := newToken()
+	require.NoError(t, err)
+	require.Len(t, a, 2*tokenBytes)
+	require.NotEqual(t, a, b)
+
+	require.Equal(t, hashToken(a), hashToken(a))
+	require.NotEqual(t, hashToken(a), hashToken(b))
+	require.NotContains(t, hashToken(a), a)
+}
+
+func TestAuthorizeRejectsMalformedTokens(t *testing.T) {
+	// Malformed tokens are refused without looking a link up.
+	for _, token := range []string{"", "not-a-token", strings.Repeat("a", 2*tokenBytes+1)} {
+		_, err := Authorize(
+			context.Background(), token, model.NewTaskID(), http.MethodGet, false, nil,
+		)
+		require.ErrorIs(t, err, ErrInvalidLink, token)
+	}
+}
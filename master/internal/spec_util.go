@@ -48,7 +48,7 @@ func (m *Master) ResolveResources(
 	if err != nil {
 		return "", nil, fmt.Errorf("validating resources: %v", err)
 	}
-	if m.config.LaunchError && len(launchWarnings) > 0 {
+	if config.GetMasterConfig().LaunchError && len(launchWarnings) > 0 {
 		return "", nil, errors.New("slots requested exceeds cluster capacity")
 	}
 
@@ -63,7 +63,7 @@ func (m *Master) fillTaskSpec(
 ) (tasks.TaskSpec, error) {
 	taskContainerDefaults, err := m.rm.TaskContainerDefaults(
 		poolName,
-		m.config.TaskContainerDefaults,
+		config.GetMasterConfig().TaskContainerDefaults,
 	)
 	if err != nil {
 		return tasks.TaskSpec{}, fmt.Errorf("getting TaskContainerDefaults: %v", err)
@@ -124,7 +124,7 @@ func getTaskSessionToken(ctx context.Context, userModel *model.User) (string, er
 					"unable to get external user token").Error())
 		}
 	} else {
-		token, err = user.StartSession(ctx, userModel)
+		token, err = user.StartSession(ctx, userModel, user.WithTaskSession())
 		if err != nil {
 			return "", status.Errorf(codes.Internal,
 				errors.Wrapf(err,
@@ -32,6 +32,8 @@ type RMJobInfo struct { // rename ?
 	State          SchedulingState
 	RequestedSlots int
 	AllocatedSlots int
+	// TargetSlots differs from RequestedSlots while elastic tasks of the job are being resized.
+	TargetSlots int
 }
 
 // DeleteJob instructs the RM to clean up all metadata associated with a job external to
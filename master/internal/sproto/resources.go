@@ -271,6 +271,10 @@ const (
 	// AgentError denotes that the agent failed to launch the container.
 	AgentError FailureType = "agent failed to launch the container"
 
+	// ResourcesPreempted denotes that the resources were taken back by the resource manager, so the
+	// task stopped without failing.
+	ResourcesPreempted FailureType = "resources were preempted by the resource manager"
+
 	// RestoreError denotes a failure to restore a running allocation on master blip.
 	RestoreError FailureType = "RM failed to restore the allocation"
 
@@ -360,7 +364,7 @@ func IsTransientSystemError(err error) bool {
 		case AgentError, AgentFailed, RestoreError:
 			return true
 		// Definitely not a failure.
-		case TaskAborted, ResourcesAborted:
+		case TaskAborted, ResourcesAborted, ResourcesPreempted:
 			return true
 		default:
 			return false
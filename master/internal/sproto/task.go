@@ -39,6 +39,8 @@ type (
 		SlotsNeeded         int
 		ResourcePool        string
 		FittingRequirements FittingRequirements
+		// Elastic, if set, allows the scheduler to resize the task at runtime.
+		Elastic *ElasticConfig
 
 		// Behavioral configuration.
 		Preemption  PreemptionConfig
@@ -60,6 +62,23 @@ type (
 		UseRunnerState  bool
 		TimeoutDuration time.Duration
 		Debug           bool
+		// GPU, if set, also times the task out once the utilization of its GPUs stays low.
+		GPU *model.GPUIdleTimeout
+		// Suspend, if set, is called instead of terminating the task when it times out. The task is
+		// terminated anyway if it returns an error.
+		Suspend func() error
+	}
+
+	// ElasticConfig configures the range of slots an elastic task may be resized within.
+	ElasticConfig struct {
+		MinSlots int
+		MaxSlots int
+		// BaseSlots is the size the task asked for. Tasks that grew past it are shrunk back to it
+		// for pending tasks of the same priority, even ones that are behind them in the queue.
+		BaseSlots int
+		// TargetSlots is the size the scheduler is resizing the task to, or zero if the task is
+		// not being resized.
+		TargetSlots int
 	}
 
 	// PreemptionConfig configures task preemption.
@@ -104,6 +123,8 @@ type (
 		Slots        int
 		IsSingleNode bool
 		TaskID       *model.TaskID
+		// PodSpec, if set, is checked against the resource pool's pod template.
+		PodSpec *expconf.PodSpec
 	}
 
 	// ValidateResourcesResponse is the response to ValidateResourcesRequest.
@@ -115,6 +136,15 @@ type (
 	}
 )
 
+// TargetSlots returns the number of slots the task is being resized to, or SlotsNeeded if it is
+// not being resized.
+func (a *AllocateRequest) TargetSlots() int {
+	if a.Elastic != nil && a.Elastic.TargetSlots > 0 {
+		return a.Elastic.TargetSlots
+	}
+	return a.SlotsNeeded
+}
+
 // ResourcesEvent describes a change in status or state of an allocation's resources.
 type ResourcesEvent interface{ ResourcesEvent() }
 
@@ -254,6 +284,9 @@ type (
 		Resources         ResourceList
 		JobSubmissionTime time.Time
 		Recovered         bool
+		// Slots, if nonzero, is the number of slots the resources hold. It is less than SlotsNeeded
+		// when an elastic task is placed with fewer slots than it asked for.
+		Slots int
 	}
 	// PendingPreemption notifies the task actor that it should release
 	// resources due to a pending system-triggered preemption.
@@ -277,6 +310,9 @@ type (
 		// a preemption attempt instead of an immediate kill.
 		ForcePreemption bool
 		ForceKill       bool
+		// ResizeTo, if nonzero, asks an elastic task to stop gracefully so that it can be restarted
+		// with this many slots.
+		ResizeTo int
 	}
 	// ResourcesRuntimeInfo is all the information provided at runtime to make a task spec.
 	ResourcesRuntimeInfo struct {
@@ -311,6 +347,7 @@ func (ra ResourcesAllocated) Clone() *ResourcesAllocated {
 		Resources:         maps.Clone(ra.Resources),
 		JobSubmissionTime: ra.JobSubmissionTime,
 		Recovered:         ra.Recovered,
+		Slots:             ra.Slots,
 	}
 }
 
@@ -46,6 +46,8 @@ type ModelVersionMsg struct {
 	Comment         string    `bun:"comment" json:"comment"`
 	Labels          []string  `bun:"labels,array" json:"labels"`
 	Notes           string    `bun:"notes" json:"notes"`
+	Aliases         []string  `bun:"aliases,array,nullzero" json:"aliases"`
+	Stage           string    `bun:"stage,nullzero" json:"stage"`
 	WorkspaceID     string    `json:"workspace_id"`
 	// metadata
 	Seq int64 `bun:"seq" json:"seq"`
@@ -25,6 +25,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
 	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	detLogger "github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -79,6 +80,9 @@ type AllocationExited struct {
 	UserRequestedStop bool
 	Err               error
 	FinalState        AllocationState
+	// ResizeTo is set when the scheduler stopped an elastic allocation to restart it with this
+	// many slots.
+	ResizeTo int
 }
 
 func (a *AllocationExited) String() string {
@@ -129,6 +133,8 @@ type allocation struct {
 	killCooldown *time.Time
 	// tracks if we have finished termination.
 	exited *AllocationExited
+	// The number of slots the scheduler asked us to be restarted with, if it is resizing us.
+	resizeTo int
 
 	// State for specific sub-behaviors of an allocation.
 	// Encapsulates logic of rendezvousing containers of the currently
@@ -578,7 +584,12 @@ func (a *allocation) finalize(
 	a.purgeRestorableResources()
 	a.markResourcesReleased()
 
-	a.exited = &AllocationExited{UserRequestedStop: userRequestedStop, Err: exitErr, FinalState: a.state()}
+	a.exited = &AllocationExited{
+		UserRequestedStop: userRequestedStop,
+		Err:               exitErr,
+		FinalState:        a.state(),
+		ResizeTo:          a.resizeTo,
+	}
 	a.SetExitStatus(exitReason, exitErr, nil)
 	log := fmt.Sprintf("%s was terminated: %s", a.req.Name, exitReason)
 	a.syslog.Log(severity, log)
@@ -608,6 +619,11 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 	}
 
 	a.setMostProgressedModelState(model.AllocationStateAssigned)
+	if msg.Slots > 0 && msg.Slots != a.req.SlotsNeeded && a.req.Elastic != nil {
+		if err := a.placedWithSlots(msg.Slots); err != nil {
+			return err
+		}
+	}
 	err := a.resources.append(msg.Resources)
 	if err != nil {
 		return errors.Wrapf(err, "appending resources")
@@ -649,8 +665,26 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 
 	if cfg := a.req.IdleTimeout; cfg != nil {
 		idle.Register(*cfg, func(ctx context.Context, err error) {
+			if cfg.Suspend != nil {
+				suspendErr := cfg.Suspend()
+				if suspendErr == nil {
+					a.syslog.WithError(err).Infof("suspending %s due to inactivity", a.req.Name)
+					return
+				}
+				a.syslog.WithError(suspendErr).Warnf("failed to suspend %s", a.req.Name)
+			}
 			a.syslog.WithError(err).Infof("killing %s due to inactivity", a.req.Name)
 			a.Signal(TerminateAllocation, err.Error())
+		}, func(ctx context.Context, reason string, terminateTime time.Time) {
+			msg := fmt.Sprintf("%s will be killed at %s due to inactivity: %s",
+				a.req.Name, terminateTime.UTC().Format(time.RFC3339), reason)
+			a.syslog.Warn(msg)
+			a.sendTaskLog(&model.TaskLog{Log: msg})
+			if err := webhooks.ReportTaskIdle(
+				ctx, a.req.AllocationID, reason, terminateTime,
+			); err != nil {
+				a.syslog.WithError(err).Error("failed to report idle task to webhooks")
+			}
 		})
 		a.closers = append(a.closers, func() {
 			idle.Unregister(cfg.ServiceID)
@@ -721,6 +755,21 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 	return nil
 }
 
+// placedWithSlots records that an elastic allocation was placed with fewer slots than it asked for,
+// so that the task is started and restored at that size.
+func (a *allocation) placedWithSlots(slots int) error {
+	a.syslog.Infof("elastic allocation placed with %d of %d slots", slots, a.req.SlotsNeeded)
+	a.req.SlotsNeeded = slots
+	a.model.Slots = slots
+	if err := db.UpdateAllocationSlots(context.TODO(), a.model); err != nil {
+		return errors.Wrap(err, "updating allocation slots")
+	}
+	if resizable, ok := a.specifier.(tasks.ResizableTaskSpecifier); ok {
+		a.specifier = resizable.WithSlots(slots)
+	}
+	return nil
+}
+
 // resourcesStateChanged handles changes in container states. It can move us to ready,
 // kill us or close us normally depending on the changes, among other things.
 func (a *allocation) resourcesStateChanged(msg *sproto.ResourcesStateChanged) {
@@ -879,6 +928,7 @@ func (a *allocation) restoreResourceFailure(msg *sproto.ResourcesFailedError) {
 
 // releaseResources prompts the allocate to release resources.
 func (a *allocation) releaseResources(msg *sproto.ReleaseResources) {
+	a.resizeTo = msg.ResizeTo
 	if msg.ForceKill {
 		a.tryExitOrKill(msg.Reason)
 	} else {
@@ -1170,6 +1220,8 @@ func (a *allocation) calculateExitStatus(reason string) (
 				return fmt.Sprintf("allocation failed due to agent failure: %s", err), false, logrus.ErrorLevel, err
 			case sproto.TaskAborted, sproto.ResourcesAborted:
 				return fmt.Sprintf("allocation aborted: %s", err.FailureType), false, logrus.InfoLevel, err
+			case sproto.ResourcesPreempted:
+				return fmt.Sprintf("allocation preempted: %s", err), false, logrus.InfoLevel, nil
 			case sproto.RestoreError:
 				return fmt.Sprintf("allocation failed due to restore error: %s", err), false, logrus.ErrorLevel, err
 			default:
@@ -10,10 +10,11 @@ import (
 var idlers = mapx.New[string, *Watcher]()
 
 // Register an idler to default service. The action is called at most once when the idle timeout is
-// exceeded. The action can trigger until Unregister is called.
+// exceeded. The action can trigger until Unregister is called. If set, warn is called before the
+// action is triggered for low GPU utilization.
 // ID must be a globally unique identifier for the idler.
-func Register(cfg sproto.IdleTimeoutConfig, action TimeoutFn) {
-	idlers.Store(cfg.ServiceID, New(cfg, action))
+func Register(cfg sproto.IdleTimeoutConfig, action TimeoutFn, warn WarningFn) {
+	idlers.Store(cfg.ServiceID, New(cfg, action, warn))
 }
 
 // Unregister removes an idler from the service.
@@ -35,3 +36,13 @@ func RecordActivity(id string) {
 	}
 	iw.RecordActivity(time.Now())
 }
+
+// RecordGPUUtilization records the GPU utilization, in percent, of an idler.
+// ID must be a globally unique identifier for the idler.
+func RecordGPUUtilization(id string, utilization float64) {
+	iw, ok := idlers.Load(id)
+	if !ok {
+		return
+	}
+	iw.RecordGPUUtilization(time.Now(), utilization)
+}
@@ -25,6 +25,9 @@ var TickInterval = 5 * time.Second
 // TimeoutFn is called when the service is idle.
 type TimeoutFn func(context.Context, error)
 
+// WarningFn is called once the service will time out at terminateTime unless it becomes active.
+type WarningFn func(ctx context.Context, reason string, terminateTime time.Time)
+
 // Watcher watches the proxy activity to handle a task actor idle timeout.
 type Watcher struct {
 	// System dependencies.
@@ -33,19 +36,26 @@ type Watcher struct {
 	// Configuration.
 	cfg    sproto.IdleTimeoutConfig
 	action TimeoutFn
+	warn   WarningFn
 
 	// Mutable internal state.
 	mu                   sync.Mutex
 	wg                   waitgroupx.Group // TODO(mar): consistent pointer usage.
 	lastExplicitActivity *time.Time
+	// lastGPUActivity is when the GPU utilization was last at or above the threshold, or when it
+	// was first reported.
+	lastGPUActivity *time.Time
+	gpuWarned       bool
 }
 
-// New creates a new idle timeout watcher. The action can be triggered until Close is called.
-func New(cfg sproto.IdleTimeoutConfig, action TimeoutFn) *Watcher {
+// New creates a new idle timeout watcher. The action can be triggered until Close is called. If
+// set, warn is called before the action is triggered for low GPU utilization.
+func New(cfg sproto.IdleTimeoutConfig, action TimeoutFn, warn WarningFn) *Watcher {
 	w := &Watcher{
 		syslog: syslog.WithField("id", cfg.ServiceID),
 		cfg:    cfg,
 		action: action,
+		warn:   warn,
 		wg:     waitgroupx.WithContext(context.Background()),
 	}
 
@@ -61,6 +71,20 @@ func (w *Watcher) RecordActivity(instant time.Time) {
 	w.mu.Unlock()
 }
 
+// RecordGPUUtilization notes the GPU utilization, in percent, to delay the GPU idle timeout if it
+// is at or above the threshold.
+func (w *Watcher) RecordGPUUtilization(instant time.Time, utilization float64) {
+	if w.cfg.GPU == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.lastGPUActivity == nil || utilization >= w.cfg.GPU.Threshold() {
+		w.lastGPUActivity = &instant
+		w.gpuWarned = false
+	}
+}
+
 // Close closes the idle timeout watcher.
 func (w *Watcher) Close() {
 	w.wg.Close()
@@ -83,6 +107,10 @@ func (w *Watcher) run(ctx context.Context) {
 }
 
 func (w *Watcher) tick(ctx context.Context) (done bool) {
+	if w.cfg.GPU != nil && w.tickGPU(ctx) {
+		return true
+	}
+
 	var lastActivity *time.Time
 	if w.cfg.UseProxyState {
 		service, ok := proxy.DefaultProxy.Summary(w.cfg.ServiceID)
@@ -121,3 +149,35 @@ func (w *Watcher) tick(ctx context.Context) (done bool) {
 	}
 	return false
 }
+
+// tickGPU times the service out once its GPU utilization has stayed below the threshold for the
+// GPU timeout, warning once when the warning period before that starts. Nothing happens until the
+// utilization is first reported.
+func (w *Watcher) tickGPU(ctx context.Context) (done bool) {
+	w.mu.Lock()
+	lastActivity, warned := w.lastGPUActivity, w.gpuWarned
+	w.mu.Unlock()
+	if lastActivity == nil {
+		return false
+	}
+
+	cfg := w.cfg.GPU
+	timeout := time.Duration(cfg.Timeout)
+	terminateTime := lastActivity.Add(timeout)
+	reason := fmt.Sprintf("GPU utilization below %g%% for more than %s",
+		cfg.Threshold(), timeout.Round(time.Second))
+	now := time.Now()
+	switch {
+	case now.After(terminateTime):
+		w.action(ctx, fmt.Errorf("%s: %w", reason, ErrIdle))
+		return true
+	case !warned && now.After(terminateTime.Add(-cfg.Warning())):
+		w.mu.Lock()
+		w.gpuWarned = true
+		w.mu.Unlock()
+		if w.warn != nil {
+			w.warn(ctx, reason, terminateTime)
+		}
+	}
+	return false
+}
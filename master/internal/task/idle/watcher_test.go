@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 func TestIdleTimeoutWatcherUseRunnerState(t *testing.T) {
@@ -23,7 +25,7 @@ func TestIdleTimeoutWatcherUseRunnerState(t *testing.T) {
 
 	Register(cfg, func(context.Context, error) {
 		actionDone.Store(true)
-	})
+	}, nil)
 	defer Unregister(cfg.ServiceID)
 
 	RecordActivity(cfg.ServiceID)
@@ -31,6 +33,43 @@ func TestIdleTimeoutWatcherUseRunnerState(t *testing.T) {
 	require.True(t, waitForCondition(10*timeout, actionDone.Load))
 }
 
+func TestIdleTimeoutWatcherGPU(t *testing.T) {
+	TickInterval = 10 * time.Millisecond
+	var actionDone, warned atomic.Bool
+	timeout := time.Second
+	cfg := sproto.IdleTimeoutConfig{
+		ServiceID: "test-gpu",
+		GPU: &model.GPUIdleTimeout{
+			Timeout:       model.Duration(timeout),
+			WarningPeriod: ptrs.Ptr(model.Duration(timeout / 2)),
+		},
+	}
+
+	Register(cfg, func(context.Context, error) {
+		actionDone.Store(true)
+	}, func(context.Context, string, time.Time) {
+		warned.Store(true)
+	})
+	defer Unregister(cfg.ServiceID)
+
+	// Nothing happens until the utilization is reported.
+	time.Sleep(2 * timeout)
+	require.False(t, warned.Load())
+	require.False(t, actionDone.Load())
+
+	// Busy GPUs keep the task alive.
+	for i := 0; i < 4; i++ {
+		RecordGPUUtilization(cfg.ServiceID, 50)
+		time.Sleep(timeout / 4)
+	}
+	require.False(t, warned.Load())
+	require.False(t, actionDone.Load())
+
+	RecordGPUUtilization(cfg.ServiceID, 1)
+	require.True(t, waitForCondition(10*timeout, warned.Load))
+	require.True(t, waitForCondition(10*timeout, actionDone.Load))
+}
+
 func waitForCondition(timeout time.Duration, condition func() bool) bool {
 	for i := 0; i < int(timeout/TickInterval); i++ {
 		if condition() {
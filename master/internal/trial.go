@@ -87,6 +87,14 @@ type trial struct {
 	searcher experiment.TrialSearcherState
 	// restarts is a failure count, it increments when the trial fails and we retry it.
 	restarts int
+	// retries counts every automatic restart of the trial, including those that don't count against
+	// max_restarts. It sets the backoff delay of the next restart.
+	retries int
+	// retryTimer is set while the trial waits out the backoff delay before its next restart.
+	retryTimer *time.Timer
+	// slots is the number of slots the trial runs with. It only differs from slots_per_trial once
+	// the scheduler has resized an elastic trial.
+	slots int
 	// runID is a count of how many times the task container(s) have stopped and restarted, which
 	// could be due to a failure or due to normal pausing and continuing. When RunID increments,
 	// it effectively invalidates many outstanding messages associated with the previous run.
@@ -140,6 +148,7 @@ func newTrial(
 		taskSpec:            taskSpec,
 		generatedKeys:       generatedKeys,
 		warmStartCheckpoint: warmStartCheckpoint,
+		slots:               config.Resources().SlotsPerTrial(),
 
 		logCtx: logger.MergeContexts(logCtx, logger.Context{
 			"task-id":   taskID,
@@ -173,6 +182,12 @@ func newTrial(
 	})
 	t.syslog = t.syslog.WithFields(t.logCtx.Fields())
 
+	if id != nil {
+		if err := t.resumeBackoff(); err != nil {
+			return nil, fmt.Errorf("resuming trial backoff in prestart: %w", err)
+		}
+	}
+
 	err = t.maybeAllocateTask()
 	if err != nil {
 		return nil, fmt.Errorf("initial allocation: %w", err)
@@ -199,6 +214,9 @@ func (t *trial) exit(reason *model.ExitedReason) {
 
 func (t *trial) close() error {
 	t.wg.Close()
+	if t.retryTimer != nil {
+		t.retryTimer.Stop()
+	}
 	if !t.idSet {
 		return nil
 	}
@@ -327,6 +345,7 @@ func (t *trial) recover() error {
 	}
 	t.runID = runID
 	t.restarts = restarts
+	t.retries = restarts
 	return nil
 }
 
@@ -366,12 +385,13 @@ func (t *trial) maybeAllocateTask() error {
 	// Only allocate for active trials, or trials that have been restored and are stopping.
 	// We need to allocate for stopping because we need to reattach the allocation.
 	shouldAllocateState := t.state == model.ActiveState || (t.restored && model.StoppingStates[t.state])
-	if t.allocationID != nil || t.searcher.Complete || !shouldAllocateState {
+	if t.allocationID != nil || t.searcher.Complete || !shouldAllocateState || t.retryTimer != nil {
 		t.syslog.WithFields(logrus.Fields{
 			"allocation-id":    t.allocationID,
 			"sercher-complete": t.searcher.Complete,
 			"trial-state":      t.state,
 			"restored":         t.restored,
+			"retry-pending":    t.retryTimer != nil,
 		}).Trace("decided not to allocate trial")
 		return nil
 	}
@@ -397,6 +417,9 @@ func (t *trial) maybeAllocateTask() error {
 	if err != nil {
 		t.syslog.WithError(err).Warn("failed to restore trial allocation")
 	} else if restoredAllocation != nil {
+		if restoredAllocation.Slots > 0 {
+			t.slots = restoredAllocation.Slots
+		}
 		specifier, err := t.buildTaskSpecifier()
 		if err != nil {
 			return err
@@ -410,11 +433,12 @@ func (t *trial) maybeAllocateTask() error {
 			RequestTime:       time.Now().UTC(),
 			IsUserVisible:     true,
 			Name:              name,
-			SlotsNeeded:       t.config.Resources().SlotsPerTrial(),
+			SlotsNeeded:       t.slots,
 			ResourcePool:      t.config.Resources().ResourcePool(),
 			FittingRequirements: sproto.FittingRequirements{
 				SingleAgent: isSingleNode,
 			},
+			Elastic: t.elasticConfig(),
 			Preemption: sproto.PreemptionConfig{
 				Preemptible:     true,
 				TimeoutDuration: time.Duration(preemptionTimeout) * time.Second,
@@ -458,11 +482,12 @@ func (t *trial) maybeAllocateTask() error {
 		IsUserVisible:     true,
 		Name:              name,
 
-		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
+		SlotsNeeded:  t.slots,
 		ResourcePool: t.config.Resources().ResourcePool(),
 		FittingRequirements: sproto.FittingRequirements{
 			SingleAgent: isSingleNode,
 		},
+		Elastic: t.elasticConfig(),
 
 		Preemption: sproto.PreemptionConfig{
 			Preemptible:     true,
@@ -530,13 +555,19 @@ func (t *trial) buildTaskSpecifier() (*tasks.TrialSpec, error) {
 		stepsCompleted = latestCheckpoint.StepsCompleted
 	}
 
+	// Elastic trials run with however many slots the scheduler last resized them to.
+	config := schemas.Copy(t.config)
+	if config.RawResources != nil {
+		config.RawResources.RawSlotsPerTrial = ptrs.Ptr(t.slots)
+	}
+
 	return &tasks.TrialSpec{
 		Base: *t.taskSpec,
 
 		ExperimentID:     t.experimentID,
 		TrialID:          t.id,
 		TrialRunID:       t.runID,
-		ExperimentConfig: schemas.Copy(t.config),
+		ExperimentConfig: config,
 		HParams:          t.searcher.Create.Hparams,
 		TrialSeed:        t.searcher.Create.TrialSeed,
 		StepsCompleted:   stepsCompleted,
@@ -546,6 +577,19 @@ func (t *trial) buildTaskSpecifier() (*tasks.TrialSpec, error) {
 	}, nil
 }
 
+// elasticConfig returns the range the scheduler may resize the trial within, if it is elastic.
+func (t *trial) elasticConfig() *sproto.ElasticConfig {
+	elastic := t.config.Resources().Elastic()
+	if elastic == nil {
+		return nil
+	}
+	return &sproto.ElasticConfig{
+		MinSlots:  elastic.MinSlots(),
+		MaxSlots:  elastic.MaxSlots(),
+		BaseSlots: t.config.Resources().SlotsPerTrial(),
+	}
+}
+
 // AllocationExitedCallback cleans up after an allocation exit and exits permanently or reallocates.
 func (t *trial) AllocationExitedCallback(exit *task.AllocationExited) {
 	t.mu.Lock()
@@ -565,6 +609,13 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 	}
 	t.allocationID = nil
 
+	// Being resized is not a failure, so on its own it neither counts as a restart nor waits out a
+	// backoff; the trial reallocates below at its new size and resumes from its latest checkpoint.
+	if exit.ResizeTo > 0 && exit.ResizeTo != t.slots {
+		t.syslog.Infof("elastic trial resized from %d to %d slots", t.slots, exit.ResizeTo)
+		t.slots = exit.ResizeTo
+	}
+
 	prom.DisassociateJobExperiment(t.jobID, strconv.Itoa(t.experimentID), t.config.Labels())
 
 	// Decide if this is permanent.
@@ -642,22 +693,46 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 			})
 		}
 
-		// If we don't have a log_pattern_policy preventing us from retrying go to normal max_restarts.
-		t.syslog.
-			WithError(exit.Err).
-			Errorf("trial failed (restart %d/%d)", t.restarts, t.config.MaxRestarts())
-		t.restarts++
-		if err := t.db.UpdateTrialFields(t.id, nil, 0, t.restarts); err != nil {
+		// Then check the exit code against the retry_policy.
+		retryPolicy := t.config.RetryPolicy()
+		exitCode := allocationExitCode(exit.Err)
+		if exitCode != nil && retryPolicy.NeverRetries(*exitCode) {
+			exitReason := fmt.Sprintf(
+				"trial failed with exit code %d and not retrying due to retry_policy", *exitCode)
+			t.syslog.
+				WithError(exit.Err).
+				Errorf(exitReason)
+
 			return t.transition(model.StateWithReason{
 				State:               model.ErrorState,
-				InformationalReason: err.Error(),
+				InformationalReason: exitReason,
 			})
 		}
-		if t.restarts > t.config.MaxRestarts() {
-			return t.transition(model.StateWithReason{
-				State:               model.ErrorState,
-				InformationalReason: "trial exceeded max restarts",
-			})
+
+		counted := exitCode == nil || !retryPolicy.AlwaysRetries(*exitCode)
+		if counted {
+			// If neither policy prevents us from retrying go to normal max_restarts.
+			t.syslog.
+				WithError(exit.Err).
+				Errorf("trial failed (restart %d/%d)", t.restarts, t.config.MaxRestarts())
+			t.restarts++
+			if err := t.db.UpdateTrialFields(t.id, nil, 0, t.restarts); err != nil {
+				return t.transition(model.StateWithReason{
+					State:               model.ErrorState,
+					InformationalReason: err.Error(),
+				})
+			}
+			if t.restarts > t.config.MaxRestarts() {
+				return t.transition(model.StateWithReason{
+					State:               model.ErrorState,
+					InformationalReason: "trial exceeded max restarts",
+				})
+			}
+		} else {
+			t.syslog.
+				WithError(exit.Err).
+				Errorf("trial failed with exit code %d, retrying without counting against max restarts",
+					*exitCode)
 		}
 
 		blockedNodes, err := logpattern.GetBlockedNodes(context.TODO(), t.taskID)
@@ -676,6 +751,13 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 			}
 		}
 
+		if err := t.backoff(exit.Err.Error(), exitCode, counted); err != nil {
+			return t.transition(model.StateWithReason{
+				State:               model.ErrorState,
+				InformationalReason: err.Error(),
+			})
+		}
+
 	case exit.UserRequestedStop:
 		return t.transition(model.StateWithReason{
 			State:               model.CompletedState,
@@ -700,6 +782,80 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 	return nil
 }
 
+// backoff records the restart of a failed trial and, if its retry_policy asks for a delay, holds off
+// allocating it again until the delay has passed.
+func (t *trial) backoff(reason string, exitCode *int, counted bool) error {
+	t.retries++
+	delay := t.config.RetryPolicy().Delay(t.retries)
+	if err := db.AddTrialRestart(context.TODO(), &model.RunRestart{
+		RunID:        t.id,
+		RestartTime:  time.Now().UTC(),
+		Reason:       reason,
+		ExitCode:     exitCode,
+		DelaySeconds: delay.Seconds(),
+		Counted:      counted,
+	}); err != nil {
+		return err
+	}
+	t.waitToRetry(delay)
+	return nil
+}
+
+// resumeBackoff picks up the backoff delay a trial was waiting out when the master stopped. The
+// time of the next restart is recorded with the restart itself, so it outlives the master.
+func (t *trial) resumeBackoff() error {
+	restarts, err := db.TrialRestarts(context.TODO(), t.id)
+	if err != nil {
+		return err
+	}
+	if len(restarts) > t.retries {
+		t.retries = len(restarts)
+	}
+	if len(restarts) == 0 {
+		return nil
+	}
+	last := restarts[len(restarts)-1]
+	retryAt := last.RestartTime.Add(time.Duration(last.DelaySeconds * float64(time.Second)))
+	t.waitToRetry(time.Until(retryAt))
+	return nil
+}
+
+// waitToRetry holds off allocating the trial again until the delay has passed.
+func (t *trial) waitToRetry(delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+
+	t.syslog.Infof("waiting %s before restarting trial", delay.Round(time.Second))
+	t.retryTimer = time.AfterFunc(delay, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.retryTimer = nil
+		if model.TerminalStates[t.state] {
+			return
+		}
+		if err := t.maybeAllocateTask(); err != nil {
+			t.syslog.WithError(err).Error("failed to reschedule trial after backoff")
+			if err := t.transition(model.StateWithReason{
+				State:               model.CompletedState,
+				InformationalReason: "failed to reschedule trial",
+			}); err != nil {
+				t.syslog.WithError(err).Error("error transitioning trial")
+			}
+		}
+	})
+}
+
+// allocationExitCode returns the exit code of a failed allocation, if it is known.
+func allocationExitCode(err error) *int {
+	failure, ok := err.(sproto.ResourcesFailedError)
+	if !ok || failure.ExitCode == nil {
+		return nil
+	}
+	return ptrs.Ptr(int(*failure.ExitCode))
+}
+
 // patchState decide if the state patch is valid. If so, we'll transition the trial.
 func (t *trial) patchState(s model.StateWithReason) error {
 	switch {
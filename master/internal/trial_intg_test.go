@@ -22,6 +22,7 @@ import (
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/mocks/allocationmocks"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	detLogger "github.com/determined-ai/determined/master/pkg/logger"
@@ -118,6 +119,126 @@ func TestTrialRestarts(t *testing.T) {
 	require.True(t, model.TerminalStates[tr.state])
 }
 
+func TestTrialResizeIsNotRestart(t *testing.T) {
+	pgDB, rID, tr, _, _ := setup(t)
+	require.NoError(t, tr.PatchState(
+		model.StateWithReason{State: model.ActiveState}))
+	require.NoError(t, tr.PatchSearcherState(experiment.TrialSearcherState{
+		Create: searcher.Create{RequestID: rID},
+		Op: searcher.ValidateAfter{
+			RequestID: rID,
+			Length:    10,
+		},
+		Complete: false,
+		Closed:   true,
+	}))
+	require.NotNil(t, tr.allocationID)
+
+	tr.AllocationExitedCallback(&task.AllocationExited{ResizeTo: 4})
+
+	require.NotNil(t, tr.allocationID)
+	require.Equal(t, 4, tr.slots)
+	require.Equal(t, 0, tr.restarts)
+	_, restarts, err := pgDB.TrialRunIDAndRestarts(tr.id)
+	require.NoError(t, err)
+	require.Equal(t, 0, restarts)
+}
+
+func TestTrialRetryPolicyNeverRetries(t *testing.T) {
+	_, rID, tr, _, done := setup(t)
+	tr.config.RawRetryPolicy = retryPolicy(expconf.RetryPolicyConfigV0{
+		RawNeverRetryExitCodes: []expconf.ExitCodeV0{3},
+	})
+	activate(t, tr, rID)
+
+	tr.AllocationExitedCallback(&task.AllocationExited{Err: exitedWith(3)})
+
+	select {
+	case <-done: // success
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for trial to terminate")
+	}
+	require.Equal(t, 0, tr.restarts)
+	dbTrial, err := internaldb.TrialByID(context.TODO(), tr.id)
+	require.NoError(t, err)
+	require.Equal(t, model.ErrorState, dbTrial.State)
+}
+
+func TestTrialRetryPolicyAlwaysRetries(t *testing.T) {
+	_, rID, tr, _, _ := setup(t)
+	tr.config.RawRetryPolicy = retryPolicy(expconf.RetryPolicyConfigV0{
+		RawAlwaysRetryExitCodes: []expconf.ExitCodeV0{137},
+	})
+	activate(t, tr, rID)
+
+	for i := 0; i <= tr.config.MaxRestarts(); i++ {
+		tr.AllocationExitedCallback(&task.AllocationExited{Err: exitedWith(137)})
+		require.NotNil(t, tr.allocationID)
+	}
+
+	require.Equal(t, 0, tr.restarts)
+	restarts, err := internaldb.TrialRestarts(context.TODO(), tr.id)
+	require.NoError(t, err)
+	require.Len(t, restarts, tr.config.MaxRestarts()+1)
+	for _, r := range restarts {
+		require.False(t, r.Counted)
+		require.Equal(t, ptrs.Ptr(137), r.ExitCode)
+	}
+}
+
+func TestTrialRetryPolicyDelay(t *testing.T) {
+	pgDB, rID, tr, _, _ := setup(t)
+	tr.config.RawRetryPolicy = retryPolicy(expconf.RetryPolicyConfigV0{
+		RawInitialDelay: ptrs.Ptr(60.0),
+	})
+	activate(t, tr, rID)
+
+	tr.AllocationExitedCallback(&task.AllocationExited{Err: exitedWith(1)})
+
+	require.Nil(t, tr.allocationID)
+	require.NotNil(t, tr.retryTimer)
+	require.Equal(t, 1, tr.restarts)
+	restarts, err := internaldb.TrialRestarts(context.TODO(), tr.id)
+	require.NoError(t, err)
+	require.Len(t, restarts, 1)
+	require.Equal(t, 60.0, restarts[0].DelaySeconds)
+
+	// A trial recovered by a restarted master waits out what is left of the delay.
+	recovered := &trial{id: tr.id, db: pgDB, config: tr.config, syslog: tr.syslog}
+	require.NoError(t, recovered.recover())
+	require.NoError(t, recovered.resumeBackoff())
+	defer recovered.retryTimer.Stop()
+	require.NotNil(t, recovered.retryTimer)
+	require.Equal(t, 1, recovered.retries)
+}
+
+func activate(t *testing.T, tr *trial, rID model.RequestID) {
+	require.NoError(t, tr.PatchState(
+		model.StateWithReason{State: model.ActiveState}))
+	require.NoError(t, tr.PatchSearcherState(experiment.TrialSearcherState{
+		Create: searcher.Create{RequestID: rID},
+		Op: searcher.ValidateAfter{
+			RequestID: rID,
+			Length:    10,
+		},
+		Complete: false,
+		Closed:   true,
+	}))
+	require.NotNil(t, tr.allocationID)
+}
+
+func retryPolicy(policy expconf.RetryPolicyConfigV0) *expconf.RetryPolicyConfigV0 {
+	return ptrs.Ptr(schemas.WithDefaults(policy))
+}
+
+func exitedWith(code int) error {
+	return sproto.ResourcesFailedError{
+		FailureType: sproto.ResourcesFailed,
+		ErrMsg:      "trial exited",
+		ExitCode:    ptrs.Ptr(sproto.ExitCode(code)),
+	}
+}
+
 func setup(t *testing.T) (
 	*internaldb.PgDB,
 	model.RequestID,
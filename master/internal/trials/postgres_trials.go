@@ -95,17 +95,32 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 		ColumnExpr("trial_id").ColumnExpr("end_time as time")
 
 	type summary struct {
-		bun.BaseModel `bun:"table:trials"`
-		Metrics       map[string]any
+		bun.BaseModel         `bun:"table:runs"`
+		Metrics               map[string]any
+		TotalBatches          int
+		MetricRollupsComplete bool
 	}
 	var summaryMetrics summary
-	if err := db.Bun().NewSelect().Table("trials").
+	if err := db.Bun().NewSelect().Table("runs").
 		ColumnExpr("summary_metrics->? AS metrics", model.TrialSummaryMetricsJSONPath(metricGroup)).
+		Column("total_batches", "metric_rollups_complete").
 		Where("id = ?", trialID).
 		Scan(context.TODO(), &summaryMetrics); err != nil {
 		return nil, fmt.Errorf("getting summary metrics for trial %d: %w", trialID, err)
 	}
 
+	// Long trials are read from rollups rather than sampled from their raw metrics, unless the
+	// caller filters or pages by something other than batches.
+	if timeSeriesFilter == nil && timeSeriesColumn == batches && startTime.IsZero() &&
+		summaryMetrics.MetricRollupsComplete {
+		resolution := db.MetricRollupResolution(
+			startBatches, min(endBatches, summaryMetrics.TotalBatches), maxDatapoints)
+		if resolution > 0 {
+			return rollupTimeSeries(trialID, metricNames, metricGroup, resolution,
+				startBatches, endBatches)
+		}
+	}
+
 	for _, metricName := range append(metricNames, "epoch") {
 		metricType := db.MetricTypeString
 		if curSummary, ok := summaryMetrics.Metrics[metricName].(map[string]any); ok {
@@ -191,6 +206,57 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 	return metricMeasurements, nil
 }
 
+// rollupTimeSeries returns one measurement per bucket of the metric rollups of a trial at the given
+// resolution. Each measurement is taken at the last batch any of the metrics was reported in the
+// bucket.
+func rollupTimeSeries(trialID int32, metricNames []string, metricGroup model.MetricGroup,
+	resolution, startBatches, endBatches int,
+) ([]db.MetricMeasurements, error) {
+	rollups, err := db.MetricRollups(context.TODO(), trialID, metricGroup,
+		append(metricNames[:len(metricNames):len(metricNames)], "epoch"),
+		resolution, startBatches, endBatches)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := map[string]bool{}
+	for _, name := range metricNames {
+		requested[name] = true
+	}
+	metricMeasurements := []db.MetricMeasurements{}
+	for i := 0; i < len(rollups); {
+		bucket := rollups[i].Bucket
+		m := db.MetricMeasurements{
+			TrialID:    trialID,
+			Values:     map[string]any{},
+			MinValues:  map[string]any{},
+			MaxValues:  map[string]any{},
+			MeanValues: map[string]any{},
+		}
+		for ; i < len(rollups) && rollups[i].Bucket == bucket; i++ {
+			r := rollups[i]
+			if r.MetricName == "epoch" {
+				m.Epoch = ptrs.Ptr(r.Last)
+			}
+			if !requested[r.MetricName] {
+				continue
+			}
+			if uint(r.LastBatches) >= m.Batches {
+				m.Batches = uint(r.LastBatches)
+				m.Time = r.LastEndTime
+			}
+			m.Values[r.MetricName] = r.Last
+			m.MinValues[r.MetricName] = r.Min
+			m.MaxValues[r.MetricName] = r.Max
+			m.MeanValues[r.MetricName] = r.Sum / float64(r.Count)
+		}
+		if len(m.Values) > 0 {
+			metricMeasurements = append(metricMeasurements, m)
+		}
+	}
+	return metricMeasurements, nil
+}
+
 // CreateTrialSourceInfo creates a TrialSourceInfo object, which allows us to keep
 // track of the linkage between an inference/fine tuning trial and its checkpoint/model version.
 func CreateTrialSourceInfo(ctx context.Context, tsi *trialv1.TrialSourceInfo,
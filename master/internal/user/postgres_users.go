@@ -39,6 +39,14 @@ func WithInheritedClaims(claims map[string]string) UserSessionOption {
 	}
 }
 
+// WithTaskSession marks the session as one the master starts for a task of the user, which is not
+// revoked along with the sessions the user logged in with.
+func WithTaskSession() UserSessionOption {
+	return func(s *model.UserSession) {
+		s.TaskSession = true
+	}
+}
+
 // StartSession creates a row in the user_sessions table.
 func StartSession(ctx context.Context, user *model.User, opts ...UserSessionOption) (string, error) {
 	userSession := &model.UserSession{
@@ -53,7 +61,7 @@ func StartSession(ctx context.Context, user *model.User, opts ...UserSessionOpti
 	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		_, err := db.Bun().NewInsert().
 			Model(userSession).
-			Column("user_id", "expiry").
+			Column("user_id", "expiry", "task_session").
 			Returning("id").
 			Exec(ctx, &userSession.ID)
 		if err != nil {
@@ -177,6 +185,17 @@ func DeleteSessionByID(ctx context.Context, sessionID model.SessionID) error {
 	return err
 }
 
+// DeleteLoginSessionsByUserIDTx deletes the sessions the given user logged in with. The sessions of
+// their tasks are kept, so running tasks can still reach the master.
+func DeleteLoginSessionsByUserIDTx(ctx context.Context, idb bun.IDB, userID model.UserID) error {
+	_, err := idb.NewDelete().
+		Table("user_sessions").
+		Where("user_id = ?", userID).
+		Where("task_session = false").
+		Exec(ctx)
+	return err
+}
+
 // AddUserTx & addAgentUserGroup are helper methods for Add & Update.
 // AddUserTx UPSERT's the existence of a new user.
 func AddUserTx(ctx context.Context, idb bun.IDB, user *model.User) (model.UserID, error) {
@@ -53,6 +53,7 @@ var unauthenticatedPointsList = []string{
 	"/docs/.*",
 	"/info",
 	"/task-logs",
+	"/otlp/v1/metrics", // Authenticated with allocation tokens by the handler.
 	"/agents",
 	"/det",
 	"/det/.*",
@@ -386,6 +386,46 @@ func TestUserGroups(t *testing.T) {
 		require.ElementsMatch(t, []string{name1, name3}, []string{gps[0].Name, gps[1].Name},
 			"failed to end with %s group assignment.", name1)
 	})
+
+	t.Run("test SyncUserGroupMembership with a filter", func(t *testing.T) {
+		ctx := context.TODO()
+		tmpUser := db.RequireMockUser(t, pgDB)
+
+		prefix := "idp-" + uuid.NewString()[:8] + "-"
+		synced := prefix + "synced"
+		stale := prefix + "stale"
+		manual := uuid.NewString()
+		for _, name := range []string{stale, manual} {
+			_, _, err := AddGroupWithMembers(ctx, model.Group{Name: name}, tmpUser.ID)
+			require.NoError(t, err, "failed to add %s group", name)
+		}
+
+		filter, err := NewGroupSyncFilter(prefix, "synced$|stale$")
+		require.NoError(t, err)
+		added, removed, err := SyncUserGroupMembershipTx(ctx, db.Bun(), &tmpUser,
+			[]string{synced, synced, prefix + "ignored", uuid.NewString()}, filter)
+		require.NoError(t, err, "failed to sync user-group membership")
+		require.Equal(t, []string{synced}, added)
+		require.Equal(t, []string{stale}, removed)
+
+		// Groups that do not match the filter are left alone.
+		gps, err := SearchGroupsWithoutPersonalGroupsTx(ctx, db.Bun(), "", tmpUser.ID)
+		require.NoError(t, err, "failed to search groups")
+		var names []string
+		for _, g := range gps {
+			names = append(names, g.Name)
+		}
+		require.ElementsMatch(t, []string{synced, manual}, names)
+
+		added, removed, err = SyncUserGroupMembershipTx(ctx, db.Bun(), &tmpUser,
+			[]string{synced}, filter)
+		require.NoError(t, err)
+		require.Empty(t, added)
+		require.Empty(t, removed)
+
+		_, err = NewGroupSyncFilter("", "(")
+		require.Error(t, err)
+	})
 }
 
 var (
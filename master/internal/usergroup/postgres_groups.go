@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -487,24 +489,63 @@ func UsersInGroupTx(ctx context.Context, idb bun.IDB, gid int) ([]model.User, er
 	return users, errors.Wrapf(db.MatchSentinelError(err), "Error getting group %d info", gid)
 }
 
+// GroupSyncFilter limits the groups whose membership is synced from an identity provider to the
+// ones with names that start with a prefix and match a regex. The zero value matches all groups.
+type GroupSyncFilter struct {
+	Prefix string
+	Regex  *regexp.Regexp
+}
+
+// NewGroupSyncFilter returns a filter for a prefix and a regex, either of which may be empty.
+func NewGroupSyncFilter(prefix, regex string) (GroupSyncFilter, error) {
+	f := GroupSyncFilter{Prefix: prefix}
+	if regex != "" {
+		r, err := regexp.Compile(regex)
+		if err != nil {
+			return GroupSyncFilter{}, fmt.Errorf("invalid group sync regex %q: %w", regex, err)
+		}
+		f.Regex = r
+	}
+	return f, nil
+}
+
+// Matches returns whether the membership of a group is synced.
+func (f GroupSyncFilter) Matches(name string) bool {
+	return strings.HasPrefix(name, f.Prefix) && (f.Regex == nil || f.Regex.MatchString(name))
+}
+
 // UpdateUserGroupMembershipTx takes in slice of groups, and updates a user's membership in those groups.
 func UpdateUserGroupMembershipTx(ctx context.Context, tx bun.IDB, u *model.User, groups []string) error {
+	_, _, err := SyncUserGroupMembershipTx(ctx, tx, u, groups, GroupSyncFilter{})
+	return err
+}
+
+// SyncUserGroupMembershipTx makes a user a member of exactly the given groups among the groups
+// that match a filter, creating the groups that do not exist yet. Groups that do not match the
+// filter are left alone, whether or not they are given. It returns the names of the groups the
+// user was added to and removed from.
+func SyncUserGroupMembershipTx(
+	ctx context.Context, tx bun.IDB, u *model.User, groups []string, filter GroupSyncFilter,
+) (added, removed []string, err error) {
+	groups = slices.DeleteFunc(slices.Clone(groups), func(g string) bool { return !filter.Matches(g) })
+
 	// Get a list of groups a user is in.
 	currentGroups, err := SearchGroupsWithoutPersonalGroupsTx(ctx, tx, "", u.ID)
 	if err != nil {
-		return fmt.Errorf("finding current user groups: %w", err)
+		return nil, nil, fmt.Errorf("finding current user groups: %w", err)
 	}
 
 	groupsToRemove := set.New[int]()
 	// Remove the user from any groups no longer included in the slice.
 	for _, g := range currentGroups {
-		if !slices.Contains(groups, g.Name) {
+		if filter.Matches(g.Name) && !slices.Contains(groups, g.Name) {
 			groupsToRemove.Insert(g.ID)
+			removed = append(removed, g.Name)
 		}
 	}
 	if len(groupsToRemove) != 0 {
 		if err := RemoveUsersFromGroupsTx(ctx, tx, groupsToRemove.ToSlice(), u.ID); err != nil {
-			return fmt.Errorf("failed to remove user from group: %w", err)
+			return nil, nil, fmt.Errorf("failed to remove user from group: %w", err)
 		}
 	}
 
@@ -514,26 +555,28 @@ func UpdateUserGroupMembershipTx(ctx context.Context, tx bun.IDB, u *model.User,
 		// Check if the group already exists, regardless of if the user belongs to it.
 		gps, err := SearchGroupsWithoutPersonalGroupsTx(ctx, tx, g, model.UserID(0))
 		if err != nil {
-			return fmt.Errorf("failed to find usergroup: %w", err)
+			return nil, nil, fmt.Errorf("failed to find usergroup: %w", err)
 		}
 
 		if len(gps) == 0 {
 			newGroup, err := AddGroupTx(ctx, tx, model.Group{Name: g})
 			if err != nil {
-				return fmt.Errorf("failed to add usergroup: %w", err)
+				return nil, nil, fmt.Errorf("failed to add usergroup: %w", err)
 			}
 			groupsToAdd.Insert(newGroup.ID)
-		} else if !slices.Contains(currentGroups, gps[0]) {
+			added = append(added, g)
+		} else if !slices.Contains(currentGroups, gps[0]) && !groupsToAdd.Contains(gps[0].ID) {
 			// gps should be a slice of length 1 since group name is unique.
 			groupsToAdd.Insert(gps[0].ID)
+			added = append(added, g)
 		}
 	}
 
 	if len(groupsToAdd) != 0 {
 		if err := AddUsersToGroupsTx(ctx, tx, groupsToAdd.ToSlice(), true, u.ID); err != nil {
-			return fmt.Errorf("error adding user to group: %s", err)
+			return nil, nil, fmt.Errorf("error adding user to group: %s", err)
 		}
 	}
 
-	return nil
+	return added, removed, nil
 }
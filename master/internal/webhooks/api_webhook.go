@@ -118,6 +118,11 @@ func (a *WebhooksAPIServer) PostWebhook(
 					req.Webhook.Mode)
 			}
 		}
+		if t.TriggerType == webhookv1.TriggerType_TRIGGER_TYPE_TASK_IDLE &&
+			req.Webhook.Mode == webhookv1.WebhookMode_WEBHOOK_MODE_SPECIFIC {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"task idle trigger does not work on webhook with mode 'SPECIFIC'")
+		}
 	}
 
 	w := WebhookFromProto(req.Webhook)
@@ -216,23 +221,102 @@ func (a *WebhooksAPIServer) TestWebhook(
 	}
 
 	log.Infof("creating webhook request for event %v", eventID)
-	c := cleanhttp.DefaultClient()
-	resp, err := c.Do(tReq)
-	if err != nil {
+	d, err := send(cleanhttp.DefaultClient(), tReq)
+	d.WebhookID = webhook.ID
+	d.Attempt = 1
+	d.Test = true
+	if rerr := recordDelivery(ctx, d); rerr != nil {
+		log.WithError(rerr).Warnf("failed to record test delivery for event %v", eventID)
+	}
+	switch {
+	case d.StatusCode == nil:
 		return nil, status.Errorf(codes.InvalidArgument,
 			"error sending webhook request for event %v error: %v", eventID, err)
+	case err != nil:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"received error from webhook server for event %v error: %v ", eventID, *d.StatusCode)
 	}
-	defer func() {
-		if err = resp.Body.Close(); err != nil {
-			log.WithError(err).Error("unable to close response body")
-		}
-	}()
+	return &apiv1.TestWebhookResponse{Completed: true, Delivery: d.Proto()}, nil
+}
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		return nil, status.Errorf(codes.InvalidArgument,
-			"received error from webhook server for event %v error: %v ", eventID, resp.StatusCode)
+// GetWebhookDeliveries returns the delivery history of a Webhook, newest first.
+func (a *WebhooksAPIServer) GetWebhookDeliveries(
+	ctx context.Context, req *apiv1.GetWebhookDeliveriesRequest,
+) (*apiv1.GetWebhookDeliveriesResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := getDeliveries(ctx, webhook.ID, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWebhookDeliveriesResponse{
+		Pagination: &apiv1.Pagination{
+			Offset:     req.Offset,
+			Limit:      req.Limit,
+			StartIndex: req.Offset,
+			EndIndex:   req.Offset + int32(len(deliveries)),
+			Total:      int32(total),
+		},
+	}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, d.Proto())
+	}
+	return resp, nil
+}
+
+// GetDeadWebhookEvents returns the events of a Webhook that exhausted their delivery retries.
+func (a *WebhooksAPIServer) GetDeadWebhookEvents(
+	ctx context.Context, req *apiv1.GetDeadWebhookEventsRequest,
+) (*apiv1.GetDeadWebhookEventsResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	dead, err := getDeadEvents(ctx, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetDeadWebhookEventsResponse{}
+	for _, e := range dead {
+		resp.DeadEvents = append(resp.DeadEvents, e.Proto())
+	}
+	return resp, nil
+}
+
+// RedeliverWebhookEvents requeues one or all dead events of a Webhook.
+func (a *WebhooksAPIServer) RedeliverWebhookEvents(
+	ctx context.Context, req *apiv1.RedeliverWebhookEventsRequest,
+) (*apiv1.RedeliverWebhookEventsResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	var eventID *WebhookEventID
+	if req.EventId != nil {
+		eventID = ptrs.Ptr(WebhookEventID(*req.EventId))
+	}
+	n, err := redeliverDeadEvents(ctx, webhook.ID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if eventID != nil && n == 0 {
+		return nil, api.NotFoundErrs("dead webhook event", strconv.Itoa(int(*eventID)), true)
 	}
-	return &apiv1.TestWebhookResponse{}, nil
+	return &apiv1.RedeliverWebhookEventsResponse{Redelivered: int32(n)}, nil
 }
 
 // PostWebhookEventData handles data for custom trigger.
@@ -15,6 +15,9 @@ type WebhookAuthZ interface {
 	// POST /api/v1/webhooks
 	// DELETE /api/v1/webhooks/:webhook_id
 	// POST /api/v1/webhooks/test/:webhook_id
+	// GET /api/v1/webhooks/:webhook_id/deliveries
+	// GET /api/v1/webhooks/:webhook_id/dead-events
+	// POST /api/v1/webhooks/:webhook_id/redeliver
 	CanEditWebhooks(ctx context.Context, curUser *model.User, workspace *model.Workspace) (serverError error)
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
@@ -342,7 +343,8 @@ func handleCustomTriggerData(ctx context.Context, data CustomTriggerData, experi
 				continue
 			}
 			err = generateEventForCustomTrigger(
-				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.URL, m.Experiment, activeConfig, data, trialID)
+				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.ID, webhook.URL, m.Experiment,
+				activeConfig, data, trialID)
 			if err != nil {
 				return fmt.Errorf("error genrating event for webhook with ID %d %+v: %w", webhookID, webhook, err)
 			}
@@ -358,7 +360,8 @@ func handleCustomTriggerData(ctx context.Context, data CustomTriggerData, experi
 				continue
 			}
 			err = generateEventForCustomTrigger(
-				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.URL, m.Experiment, activeConfig, data, trialID)
+				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.ID, webhook.URL, m.Experiment,
+				activeConfig, data, trialID)
 			if err != nil {
 				return fmt.Errorf("error genrating event %s %+v: %w", webhookName, webhook, err)
 			}
@@ -382,6 +385,7 @@ func generateEventForCustomTrigger(
 	es *[]Event,
 	triggers Triggers,
 	webhookType WebhookType,
+	webhookID WebhookID,
 	webhookURL string,
 	e model.Experiment,
 	activeConfig expconf.ExperimentConfig,
@@ -398,7 +402,7 @@ func generateEventForCustomTrigger(
 		if err != nil {
 			return fmt.Errorf("error generating event payload: %w", err)
 		}
-		*es = append(*es, Event{Payload: p, URL: webhookURL})
+		*es = append(*es, Event{Payload: p, URL: webhookURL, WebhookID: &webhookID})
 	}
 	return nil
 }
@@ -502,7 +506,7 @@ func ReportExperimentStateChanged(
 		if err != nil {
 			return fmt.Errorf("error generating event payload: %w", err)
 		}
-		es = append(es, Event{Payload: p, URL: t.Webhook.URL})
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL, WebhookID: &t.Webhook.ID})
 	}
 	if len(es) == 0 {
 		return nil
@@ -553,8 +557,9 @@ func addTaskLogEvent(ctx context.Context,
 		}
 
 		if _, err := db.Bun().NewInsert().Model(&Event{
-			Payload: p,
-			URL:     trigger.Webhook.URL,
+			Payload:   p,
+			URL:       trigger.Webhook.URL,
+			WebhookID: &trigger.Webhook.ID,
 		}).Exec(ctx); err != nil {
 			return fmt.Errorf("inserting task logs event trigger: %w", err)
 		}
@@ -673,6 +678,103 @@ func generateLogPatternSlackPayload(
 	return message, nil
 }
 
+// ReportTaskIdle adds webhook events for a task that will be terminated for being idle at
+// terminateTime unless it becomes active again.
+func ReportTaskIdle(
+	ctx context.Context, allocationID model.AllocationID, reason string, terminateTime time.Time,
+) error {
+	var ts []Trigger
+	switch err := db.Bun().NewSelect().Model(&ts).Relation("Webhook").
+		Where("trigger_type = ?", TriggerTypeTaskIdle).
+		Scan(ctx); {
+	case err != nil:
+		return err
+	case len(ts) == 0:
+		return nil
+	}
+
+	taskID := allocationID.ToTaskID()
+	task, err := db.TaskByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("getting task %s: %w", taskID, err)
+	}
+	var workspaceID int32
+	err = db.Bun().NewSelect().Table("allocation_workspace_info").Column("workspace_id").
+		Where("allocation_id = ?", allocationID).Scan(ctx, &workspaceID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("getting workspace of allocation %s: %w", allocationID, err)
+	}
+
+	var es []Event
+	for _, t := range ts {
+		if !matchWebhook(&t, nil, workspaceID, nil) {
+			continue
+		}
+		p, err := generateTaskIdlePayload(t.Webhook.WebhookType, task, reason, terminateTime)
+		if err != nil {
+			return err
+		}
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL, WebhookID: &t.Webhook.ID})
+	}
+	if len(es) == 0 {
+		return nil
+	}
+
+	if _, err := db.Bun().NewInsert().Model(&es).Exec(ctx); err != nil {
+		return fmt.Errorf("report task idle inserting event trigger: %w", err)
+	}
+
+	singletonShipper.Wake()
+	return nil
+}
+
+func generateTaskIdlePayload(
+	wt WebhookType, task *model.Task, reason string, terminateTime time.Time,
+) ([]byte, error) {
+	switch wt {
+	case WebhookTypeDefault:
+		p, err := json.Marshal(EventPayload{
+			ID:        uuid.New(),
+			Type:      TriggerTypeTaskIdle,
+			Timestamp: time.Now().Unix(),
+			Data: EventData{
+				TaskIdle: &TaskIdlePayload{
+					TaskID:        task.TaskID,
+					TaskType:      task.TaskType,
+					Reason:        reason,
+					TerminateTime: terminateTime.Unix(),
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling json for task idle payload: %w", err)
+		}
+		return p, nil
+
+	case WebhookTypeSlack:
+		msg := fmt.Sprintf("Task ID `%s`, task type `%s`, will be terminated at %s: %s",
+			task.TaskID, task.TaskType, terminateTime.UTC().Format(time.RFC3339), reason)
+		p, err := json.Marshal(SlackMessageBody{
+			Blocks: []SlackBlock{
+				{
+					Type: "section",
+					Text: SlackField{
+						Type: "mrkdwn",
+						Text: msg,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating slack payload: %w", err)
+		}
+		return p, nil
+
+	default:
+		return nil, fmt.Errorf("unknown webhook type %+v while generating task idle payload", wt)
+	}
+}
+
 func generateEventPayload(
 	ctx context.Context,
 	wt WebhookType,
@@ -924,6 +1026,138 @@ WHERE q.id = webhook_events_queue.id RETURNING webhook_events_queue.*
 	return &eventBatch{tx: &tx, events: events}, nil
 }
 
+// addDeadEvents moves events that exhausted their retries to the dead-letter queue as part of the
+// batch, so they are only consumed once they are safely stored. Events of webhooks deleted since
+// they were queued are dropped, and a failure leaves the rest of the batch to be committed, since
+// the events were already attempted and must not be sent again.
+func (b *eventBatch) addDeadEvents(ctx context.Context, dead []DeadEvent) error {
+	if len(dead) == 0 {
+		return nil
+	}
+	if _, err := (*b.tx).ExecContext(ctx, "SAVEPOINT dead_events"); err != nil {
+		return fmt.Errorf("adding dead webhook events: %w", err)
+	}
+	if err := b.insertDeadEvents(ctx, dead); err != nil {
+		if _, rerr := (*b.tx).ExecContext(ctx, "ROLLBACK TO SAVEPOINT dead_events"); rerr != nil {
+			return fmt.Errorf("adding dead webhook events: %w (rolling back: %s)", err, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (b *eventBatch) insertDeadEvents(ctx context.Context, dead []DeadEvent) error {
+	ids := make([]WebhookID, 0, len(dead))
+	for _, e := range dead {
+		ids = append(ids, e.WebhookID)
+	}
+	// Locking the webhooks keeps them from being deleted before the batch is committed.
+	var live []WebhookID
+	if err := (*b.tx).NewSelect().Model((*Webhook)(nil)).
+		Column("id").
+		Where("id IN (?)", bun.In(ids)).
+		For("KEY SHARE").
+		Scan(ctx, &live); err != nil {
+		return fmt.Errorf("locking webhooks of dead events: %w", err)
+	}
+	dead = slices.DeleteFunc(dead, func(e DeadEvent) bool {
+		return !slices.Contains(live, e.WebhookID)
+	})
+	if len(dead) == 0 {
+		return nil
+	}
+	if _, err := (*b.tx).NewInsert().Model(&dead).Exec(ctx); err != nil {
+		return fmt.Errorf("adding dead webhook events: %w", err)
+	}
+	return nil
+}
+
+func recordDelivery(ctx context.Context, d *Delivery) error {
+	if _, err := db.Bun().NewInsert().Model(d).Exec(ctx); err != nil {
+		return fmt.Errorf("recording webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func deleteDeliveriesBefore(ctx context.Context, t time.Time) error {
+	if _, err := db.Bun().NewDelete().Model((*Delivery)(nil)).
+		Where("delivered_at < ?", t).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting webhook deliveries before %s: %w", t, err)
+	}
+	return nil
+}
+
+// getDeliveries returns a page of a webhook's deliveries, newest first, and the total count. A
+// limit of 0 returns all of them.
+func getDeliveries(
+	ctx context.Context, webhookID WebhookID, offset, limit int,
+) ([]Delivery, int, error) {
+	var deliveries []Delivery
+	q := db.Bun().NewSelect().Model(&deliveries).Where("webhook_id = ?", webhookID)
+	total, err := db.PaginateBun(q, "id", db.SortDirectionDesc, offset, limit).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting deliveries for webhook %d: %w", webhookID, err)
+	}
+	return deliveries, total, nil
+}
+
+func getDeadEvents(ctx context.Context, webhookID WebhookID) ([]DeadEvent, error) {
+	var dead []DeadEvent
+	if err := db.Bun().NewSelect().Model(&dead).
+		Where("webhook_id = ?", webhookID).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting dead events for webhook %d: %w", webhookID, err)
+	}
+	return dead, nil
+}
+
+// redeliverDeadEvents moves a webhook's dead events back onto the queue, keeping their IDs so
+// their delivery history carries over. If eventID is set, only that event is redelivered. It
+// returns how many events were requeued.
+func redeliverDeadEvents(
+	ctx context.Context, webhookID WebhookID, eventID *WebhookEventID,
+) (int, error) {
+	var dead []DeadEvent
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		q := tx.NewDelete().Model(&dead).
+			Where("webhook_id = ?", webhookID).
+			Returning("*")
+		if eventID != nil {
+			q = q.Where("id = ?", *eventID)
+		}
+		if _, err := q.Exec(ctx, &dead); err != nil {
+			return fmt.Errorf("removing dead events: %w", err)
+		}
+		if len(dead) == 0 {
+			return nil
+		}
+
+		events := make([]Event, 0, len(dead))
+		for _, e := range dead {
+			events = append(events, Event{
+				ID:        e.ID,
+				URL:       e.URL,
+				Payload:   e.Payload,
+				WebhookID: ptrs.Ptr(e.WebhookID),
+			})
+		}
+		if _, err := tx.NewInsert().Model(&events).Exec(ctx); err != nil {
+			return fmt.Errorf("requeueing dead events: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("redelivering events for webhook %d: %w", webhookID, err)
+	}
+
+	if len(dead) > 0 {
+		singletonShipper.Wake()
+	}
+	return len(dead), nil
+}
+
 // updateWebhook updates a webhook in the database.
 func (l *WebhookManager) updateWebhook(
 	ctx context.Context,
@@ -839,6 +839,119 @@ func TestDequeueEvents(t *testing.T) {
 	})
 }
 
+func TestDeadEventsAndRedelivery(t *testing.T) {
+	ctx := context.Background()
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+	clearWebhooksTables(ctx, t)
+
+	singletonShipper = &shipper{wake: make(chan struct{}, 1)} // mock shipper
+
+	w := mockWebhook()
+	require.NoError(t, AddWebhook(ctx, w))
+	e := Event{URL: w.URL, Payload: []byte(`{"test":true}`), WebhookID: &w.ID}
+	_, err := db.Bun().NewInsert().Model(&e).Exec(ctx)
+	require.NoError(t, err)
+
+	t.Log("fail the event and move it to the dead-letter queue")
+	batch, err := dequeueEvents(ctx, maxEventBatchSize)
+	require.NoError(t, err)
+	require.Len(t, batch.events, 1)
+	require.Equal(t, &w.ID, batch.events[0].WebhookID)
+	for attempt := 1; attempt <= 3; attempt++ {
+		require.NoError(t, recordDelivery(ctx, &Delivery{
+			WebhookID:   w.ID,
+			EventID:     &e.ID,
+			URL:         e.URL,
+			Attempt:     attempt,
+			StatusCode:  ptrs.Ptr(500),
+			Error:       "request returned 500",
+			DeliveredAt: time.Now().UTC(),
+		}))
+	}
+	require.NoError(t, batch.addDeadEvents(ctx, []DeadEvent{{
+		ID:        e.ID,
+		WebhookID: w.ID,
+		URL:       e.URL,
+		Payload:   e.Payload,
+		Attempts:  3,
+		Error:     "request returned 500",
+		FailedAt:  time.Now().UTC(),
+	}}))
+	require.NoError(t, batch.commit())
+
+	dead, err := getDeadEvents(ctx, w.ID)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, e.ID, dead[0].ID)
+	require.Equal(t, 3, dead[0].Attempts)
+
+	deliveries, total, err := getDeliveries(ctx, w.ID, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, 3, deliveries[0].Attempt, "deliveries should be newest first")
+
+	t.Log("redelivering an unknown event should do nothing")
+	n, err := redeliverDeadEvents(ctx, w.ID, ptrs.Ptr(e.ID+1))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	t.Log("redelivering should requeue the event under its original ID")
+	n, err = redeliverDeadEvents(ctx, w.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	dead, err = getDeadEvents(ctx, w.ID)
+	require.NoError(t, err)
+	require.Empty(t, dead)
+	batch, err = dequeueEvents(ctx, maxEventBatchSize)
+	require.NoError(t, err)
+	require.NoError(t, batch.commit())
+	require.Len(t, batch.events, 1)
+	require.Equal(t, e.ID, batch.events[0].ID)
+
+	t.Log("old deliveries should be pruned")
+	require.NoError(t, deleteDeliveriesBefore(ctx, time.Now().Add(time.Minute)))
+	_, total, err = getDeliveries(ctx, w.ID, 0, 0)
+	require.NoError(t, err)
+	require.Zero(t, total)
+}
+
+func TestDeadEventsOfDeletedWebhook(t *testing.T) {
+	ctx := context.Background()
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+	clearWebhooksTables(ctx, t)
+
+	singletonShipper = &shipper{wake: make(chan struct{}, 1)} // mock shipper
+
+	live, deleted := mockWebhook(), mockWebhook()
+	require.NoError(t, AddWebhook(ctx, live))
+	require.NoError(t, AddWebhook(ctx, deleted))
+	e := Event{URL: live.URL, Payload: []byte(`{"test":true}`), WebhookID: &live.ID}
+	_, err := db.Bun().NewInsert().Model(&e).Exec(ctx)
+	require.NoError(t, err)
+	require.NoError(t, DeleteWebhook(ctx, deleted.ID))
+
+	t.Log("the dead event of the deleted webhook should not keep the batch from being consumed")
+	batch, err := dequeueEvents(ctx, maxEventBatchSize)
+	require.NoError(t, err)
+	require.Len(t, batch.events, 1)
+	require.NoError(t, batch.addDeadEvents(ctx, []DeadEvent{
+		{ID: e.ID, WebhookID: live.ID, URL: e.URL, Payload: e.Payload, Attempts: 3, Error: "500"},
+		{ID: e.ID + 1, WebhookID: deleted.ID, URL: deleted.URL, Payload: e.Payload, Attempts: 3},
+	}))
+	require.NoError(t, batch.commit())
+
+	dead, err := getDeadEvents(ctx, live.ID)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, e.ID, dead[0].ID)
+	require.Zero(t, countEventsForURL(ctx, t, live.URL))
+}
+
 func clearWebhooksTables(ctx context.Context, t *testing.T) {
 	t.Log("clear webhooks db")
 	_, err := db.Bun().NewDelete().Model((*Webhook)(nil)).Where("true").Exec(ctx)
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,6 +28,12 @@ const (
 	backoffAttempts = 2
 	backoffInterval = time.Second
 	backoffMax      = time.Minute
+
+	// maxResponseBytes is how much of a receiver's response is kept with each delivery.
+	maxResponseBytes = 1024
+	// deliveryRetention is how long the history of deliveries is kept.
+	deliveryRetention = 30 * 24 * time.Hour
+	pruneInterval     = time.Hour
 )
 
 var singletonShipper *shipper
@@ -72,9 +79,32 @@ func newShipper() *shipper {
 		}()
 	}
 
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.pruneDeliveries(ctx)
+	}()
+
 	return s
 }
 
+// pruneDeliveries periodically deletes deliveries older than deliveryRetention.
+func (s *shipper) pruneDeliveries(ctx context.Context) {
+	t := time.NewTicker(pruneInterval)
+	defer t.Stop()
+	for {
+		if err := deleteDeliveriesBefore(ctx, time.Now().Add(-deliveryRetention)); err != nil &&
+			ctx.Err() == nil {
+			s.log.WithError(err).Warn("failed to prune webhook deliveries")
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // Wake attempts to wake the sender.
 func (s *shipper) Wake() {
 	select {
@@ -150,17 +180,45 @@ func (w *worker) shipBatch(ctx context.Context) (int, error) {
 		}
 	}()
 
-	var wg sync.WaitGroup
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		dead []DeadEvent
+	)
 	for _, e := range b.events {
 		wg.Add(1)
 		go func(e Event) {
 			defer wg.Done()
-			if err := back.Retry(
-				func() error { return w.deliver(ctx, e) },
+			attempts := 0
+			err := back.Retry(
+				func() error {
+					attempts++
+					return w.deliver(ctx, e, attempts)
+				},
 				backoff(),
-			); err != nil {
+			)
+			switch {
+			case err == nil:
+				return
+			case e.WebhookID == nil:
+				// Events queued before deliveries were tracked can't be told apart by webhook.
 				w.log.WithError(err).Error("failed to deliver webhook")
+				return
 			}
+
+			w.log.WithError(err).WithField("event-id", e.ID).
+				Error("failed to deliver webhook, moving it to the dead-letter queue")
+			mu.Lock()
+			defer mu.Unlock()
+			dead = append(dead, DeadEvent{
+				ID:        e.ID,
+				WebhookID: *e.WebhookID,
+				URL:       e.URL,
+				Payload:   e.Payload,
+				Attempts:  attempts,
+				Error:     err.Error(),
+				FailedAt:  time.Now().UTC(),
+			})
 		}(e)
 	}
 	wg.Wait()
@@ -168,6 +226,9 @@ func (w *worker) shipBatch(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
+	if err := b.addDeadEvents(ctx, dead); err != nil {
+		w.log.WithError(err).Error("failed to move webhook events to the dead-letter queue")
+	}
 	if err := b.commit(); err != nil {
 		return 0, fmt.Errorf("consuming batch: %w", err)
 	}
@@ -181,30 +242,62 @@ func backoff() back.BackOff {
 	return back.WithMaxRetries(bf, backoffAttempts)
 }
 
-func (w *worker) deliver(ctx context.Context, e Event) error {
+func (w *worker) deliver(ctx context.Context, e Event, attempt int) error {
 	req, err := generateWebhookRequest(ctx, e.URL, e.Payload, time.Now().Unix())
 	if err != nil {
 		return err
 	}
 
-	resp, err := w.cl.Do(req)
+	d, err := send(w.cl, req)
+	if e.WebhookID != nil {
+		d.WebhookID = *e.WebhookID
+		d.EventID = &e.ID
+		d.Attempt = attempt
+		if rerr := recordDelivery(ctx, d); rerr != nil {
+			w.log.WithError(rerr).Warn("failed to record webhook delivery")
+		}
+	}
+	return err
+}
+
+// send sends a webhook request and describes the attempt as a delivery. Errors that should not be
+// retried are marked permanent.
+func send(cl *http.Client, req *http.Request) (*Delivery, error) { //nolint:forbidigo
+	d := &Delivery{URL: req.URL.String(), DeliveredAt: time.Now().UTC()}
+
+	start := time.Now()
+	resp, err := cl.Do(req)
+	d.LatencyMs = int(time.Since(start).Milliseconds())
 	if err != nil {
-		return fmt.Errorf("sending webhook request: %w", err)
+		err = fmt.Errorf("sending webhook request: %w", err)
+		d.Error = err.Error()
+		return d, err
 	}
 	defer func() {
-		if err = resp.Body.Close(); err != nil {
-			w.log.WithError(err).Warn("failed to close response body")
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Warn("failed to close response body")
 		}
 	}()
 
+	d.StatusCode = &resp.StatusCode
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		log.WithError(err).Debug("failed to read webhook response body")
+	}
+	// Postgres text can't hold NUL bytes or invalid UTF-8, which truncation may have produced.
+	d.Response = strings.ToValidUTF8(strings.ReplaceAll(string(body), "\x00", ""), "")
+
 	switch {
 	case resp.StatusCode >= 500: //nolint: usestdlibvars
-		return fmt.Errorf("request returned %v: %w", resp.StatusCode, err)
+		err = fmt.Errorf("request returned %v", resp.StatusCode)
 	case resp.StatusCode >= 400: //nolint: usestdlibvars
-		return back.Permanent(fmt.Errorf("request returned %v: %w", resp.StatusCode, err))
+		err = back.Permanent(fmt.Errorf("request returned %v", resp.StatusCode))
 	default:
-		return nil
+		d.Success = true
+		return d, nil
 	}
+	d.Error = err.Error()
+	return d, err
 }
 
 func generateWebhookRequest(
@@ -7,12 +7,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	back "github.com/cenkalti/backoff/v4"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/maps"
@@ -221,6 +226,55 @@ func TestShipper(t *testing.T) {
 	}
 }
 
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, err := strconv.Atoi(r.URL.Query().Get("code"))
+		if err != nil {
+			code = http.StatusOK
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(strings.Repeat("a", 2*maxResponseBytes)))
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		code      int
+		success   bool
+		permanent bool
+	}{
+		{code: http.StatusOK, success: true},
+		{code: http.StatusBadRequest, permanent: true},
+		{code: http.StatusBadGateway},
+	}
+	for _, tc := range cases {
+		t.Run(strconv.Itoa(tc.code), func(t *testing.T) {
+			req, err := generateWebhookRequest(ctx, srv.URL+"?code="+strconv.Itoa(tc.code),
+				[]byte("{}"), time.Now().Unix())
+			require.NoError(t, err)
+
+			d, err := send(srv.Client(), req)
+			require.Equal(t, tc.success, err == nil)
+			var permanent *back.PermanentError
+			require.Equal(t, tc.permanent, errors.As(err, &permanent))
+			require.Equal(t, tc.success, d.Success)
+			require.Equal(t, &tc.code, d.StatusCode)
+			require.Len(t, d.Response, maxResponseBytes)
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		req, err := generateWebhookRequest(ctx, "http://localhost:1", []byte("{}"), 0)
+		require.NoError(t, err)
+
+		d, err := send(srv.Client(), req)
+		require.Error(t, err)
+		require.Nil(t, d.StatusCode)
+		require.False(t, d.Success)
+		require.NotEmpty(t, d.Error)
+	})
+}
+
 func scheduledWaitToDuration(factor int) time.Duration {
 	return 10 * time.Duration(factor) * time.Millisecond
 }
@@ -2,11 +2,14 @@ package webhooks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 
@@ -158,6 +161,9 @@ const (
 
 	// TriggerTypeCustom represents a custom trigger.
 	TriggerTypeCustom TriggerType = "CUSTOM"
+
+	// TriggerTypeTaskIdle represents a task about to be terminated for being idle.
+	TriggerTypeTaskIdle TriggerType = "TASK_IDLE"
 )
 
 const (
@@ -210,6 +216,8 @@ func TriggerTypeFromProto(t webhookv1.TriggerType) TriggerType {
 		return TriggerTypeTaskLog
 	case webhookv1.TriggerType_TRIGGER_TYPE_CUSTOM:
 		return TriggerTypeCustom
+	case webhookv1.TriggerType_TRIGGER_TYPE_TASK_IDLE:
+		return TriggerTypeTaskIdle
 	default:
 		// TODO(???): prob don't panic
 		panic(fmt.Errorf("missing mapping for trigger %s to SQL", t))
@@ -249,6 +257,8 @@ func (t TriggerType) Proto() webhookv1.TriggerType {
 		return webhookv1.TriggerType_TRIGGER_TYPE_TASK_LOG
 	case TriggerTypeCustom:
 		return webhookv1.TriggerType_TRIGGER_TYPE_CUSTOM
+	case TriggerTypeTaskIdle:
+		return webhookv1.TriggerType_TRIGGER_TYPE_TASK_IDLE
 	default:
 		return webhookv1.TriggerType_TRIGGER_TYPE_UNSPECIFIED
 	}
@@ -282,9 +292,79 @@ type WebhookEventID int
 type Event struct {
 	bun.BaseModel `bun:"table:webhook_events_queue"`
 
-	ID      WebhookEventID `bun:"id,pk,autoincrement"`
-	URL     string         `bun:"url,notnull"`
-	Payload []byte         `bun:"payload,notnull"`
+	ID        WebhookEventID `bun:"id,pk,autoincrement"`
+	URL       string         `bun:"url,notnull"`
+	Payload   []byte         `bun:"payload,notnull"`
+	WebhookID *WebhookID     `bun:"webhook_id"`
+}
+
+// Delivery corresponds to a row in the "webhook_deliveries" DB table. It records one attempt at
+// delivering an event.
+type Delivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries"`
+
+	ID          int             `bun:"id,pk,autoincrement"`
+	WebhookID   WebhookID       `bun:"webhook_id,notnull"`
+	EventID     *WebhookEventID `bun:"event_id"`
+	URL         string          `bun:"url,notnull"`
+	Attempt     int             `bun:"attempt,notnull"`
+	StatusCode  *int            `bun:"status_code"`
+	LatencyMs   int             `bun:"latency_ms,notnull"`
+	Response    string          `bun:"response,notnull"`
+	Error       string          `bun:"error,notnull"`
+	Success     bool            `bun:"success,notnull"`
+	Test        bool            `bun:"test,notnull"`
+	DeliveredAt time.Time       `bun:"delivered_at,notnull"`
+}
+
+// Proto converts a delivery to its protobuf representation.
+func (d *Delivery) Proto() *webhookv1.WebhookDelivery {
+	out := &webhookv1.WebhookDelivery{
+		Id:          int32(d.ID),
+		WebhookId:   int32(d.WebhookID),
+		Url:         d.URL,
+		Attempt:     int32(d.Attempt),
+		LatencyMs:   int32(d.LatencyMs),
+		Response:    d.Response,
+		Error:       d.Error,
+		Success:     d.Success,
+		Test:        d.Test,
+		DeliveredAt: timestamppb.New(d.DeliveredAt),
+	}
+	if d.EventID != nil {
+		out.EventId = ptrs.Ptr(int32(*d.EventID))
+	}
+	if d.StatusCode != nil {
+		out.StatusCode = ptrs.Ptr(int32(*d.StatusCode))
+	}
+	return out
+}
+
+// DeadEvent corresponds to a row in the "webhook_dead_events" DB table. It is an event that
+// exhausted its delivery retries, kept under its original ID until it is redelivered.
+type DeadEvent struct {
+	bun.BaseModel `bun:"table:webhook_dead_events"`
+
+	ID        WebhookEventID `bun:"id,pk"`
+	WebhookID WebhookID      `bun:"webhook_id,notnull"`
+	URL       string         `bun:"url,notnull"`
+	Payload   []byte         `bun:"payload,notnull"`
+	Attempts  int            `bun:"attempts,notnull"`
+	Error     string         `bun:"error,notnull"`
+	FailedAt  time.Time      `bun:"failed_at,notnull"`
+}
+
+// Proto converts a dead event to its protobuf representation.
+func (e *DeadEvent) Proto() *webhookv1.DeadWebhookEvent {
+	return &webhookv1.DeadWebhookEvent{
+		EventId:   int32(e.ID),
+		WebhookId: int32(e.WebhookID),
+		Url:       e.URL,
+		Payload:   string(e.Payload),
+		Attempts:  int32(e.Attempts),
+		Error:     e.Error,
+		FailedAt:  timestamppb.New(e.FailedAt),
+	}
 }
 
 // SlackMessageBody corresponds to an entire message as a Slack Block.
@@ -335,6 +415,7 @@ type EventData struct {
 	Experiment *ExperimentPayload `json:"experiment,omitempty"`
 	TaskLog    *TaskLogPayload    `json:"task_log,omitempty"`
 	CustomData *CustomTriggerData `json:"custom_data,omitempty"`
+	TaskIdle   *TaskIdlePayload   `json:"task_idle,omitempty"`
 }
 
 // ExperimentPayload is the webhook request representation of an experiment.
@@ -356,3 +437,12 @@ type TaskLogPayload struct {
 	NodeName      string       `json:"node_name"`
 	TriggeringLog string       `json:"triggering_log"`
 }
+
+// TaskIdlePayload is the webhook request representation of a task about to be terminated for
+// being idle.
+type TaskIdlePayload struct {
+	TaskID        model.TaskID   `json:"task_id"`
+	TaskType      model.TaskType `json:"task_type"`
+	Reason        string         `json:"reason"`
+	TerminateTime int64          `json:"terminate_time"`
+}
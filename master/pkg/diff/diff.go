@@ -0,0 +1,142 @@
+// Package diff computes the differences between decoded JSON documents and between text files.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// MaxTextSize is the size above which files are not diffed line by line.
+const MaxTextSize = 1 << 20
+
+// Kind is the kind of a difference.
+type Kind int
+
+const (
+	// Added means the entry only exists on the right side.
+	Added Kind = iota + 1
+	// Removed means the entry only exists on the left side.
+	Removed
+	// Changed means the entry exists on both sides with different values.
+	Changed
+)
+
+// Value is a difference of a single value between two documents.
+type Value struct {
+	// Path is the path to the value, as dot-separated keys with [i] for list elements.
+	Path  string
+	Kind  Kind
+	Left  any
+	Right any
+}
+
+// Values returns the differences between two decoded JSON values. Objects are compared key by key
+// and lists element by element; other values, and values of different types, are compared as a
+// whole. Differences are ordered by key and then by list index.
+func Values(left, right any) []Value {
+	var diffs []Value
+	values(&diffs, "", left, right)
+	return diffs
+}
+
+func values(diffs *[]Value, path string, left, right any) {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			objects(diffs, path, l, r)
+			return
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			lists(diffs, path, l, r)
+			return
+		}
+	}
+	if !reflect.DeepEqual(left, right) {
+		*diffs = append(*diffs, Value{Path: path, Kind: Changed, Left: left, Right: right})
+	}
+}
+
+func objects(diffs *[]Value, path string, left, right map[string]any) {
+	keys := make([]string, 0, len(left)+len(right))
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := k
+		if path != "" {
+			childPath = path + "." + k
+		}
+		l, inLeft := left[k]
+		r, inRight := right[k]
+		switch {
+		case !inLeft:
+			*diffs = append(*diffs, Value{Path: childPath, Kind: Added, Right: r})
+		case !inRight:
+			*diffs = append(*diffs, Value{Path: childPath, Kind: Removed, Left: l})
+		default:
+			values(diffs, childPath, l, r)
+		}
+	}
+}
+
+func lists(diffs *[]Value, path string, left, right []any) {
+	for i := 0; i < len(left) || i < len(right); i++ {
+		childPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(left):
+			*diffs = append(*diffs, Value{Path: childPath, Kind: Added, Right: right[i]})
+		case i >= len(right):
+			*diffs = append(*diffs, Value{Path: childPath, Kind: Removed, Left: left[i]})
+		default:
+			values(diffs, childPath, left[i], right[i])
+		}
+	}
+}
+
+// IsText reports whether content is small enough and free of binary data to be diffed line by
+// line.
+func IsText(content []byte) bool {
+	return len(content) <= MaxTextSize && utf8.Valid(content) && bytes.IndexByte(content, 0) < 0
+}
+
+// Text returns the differences between two versions of the file at path in unified diff format,
+// with three lines of context. It is empty if the versions are equal.
+func Text(path string, left, right []byte) (string, error) {
+	out, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(left),
+		B:        splitLines(right),
+		FromFile: "a/" + path,
+		ToFile:   "b/" + path,
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("diffing %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// splitLines splits content into lines that all end in a newline, as the unified diff format
+// expects.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := lines[len(lines)-1]; last == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] = last + "\n"
+	}
+	return lines
+}
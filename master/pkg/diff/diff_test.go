@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValues(t *testing.T) {
+	left := map[string]any{
+		"name":        "mnist",
+		"description": "baseline",
+		"hyperparameters": map[string]any{
+			"lr":     0.1,
+			"layers": []any{64.0, 32.0},
+		},
+		"labels": nil,
+	}
+	right := map[string]any{
+		"name": "mnist",
+		"hyperparameters": map[string]any{
+			"lr":     0.01,
+			"layers": []any{64.0, 16.0, 8.0},
+		},
+		"labels":  []any{"nightly"},
+		"max_gpu": 2.0,
+	}
+
+	require.Equal(t, []Value{
+		{Path: "description", Kind: Removed, Left: "baseline"},
+		{Path: "hyperparameters.layers[1]", Kind: Changed, Left: 32.0, Right: 16.0},
+		{Path: "hyperparameters.layers[2]", Kind: Added, Right: 8.0},
+		{Path: "hyperparameters.lr", Kind: Changed, Left: 0.1, Right: 0.01},
+		{Path: "labels", Kind: Changed, Left: nil, Right: []any{"nightly"}},
+		{Path: "max_gpu", Kind: Added, Right: 2.0},
+	}, Values(left, right))
+
+	require.Empty(t, Values(left, left))
+	require.Equal(t, []Value{{Kind: Changed, Left: 1.0, Right: "1"}}, Values(1.0, "1"))
+}
+
+func TestText(t *testing.T) {
+	out, err := Text("train.py", []byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+	require.NoError(t, err)
+	require.Equal(t, "--- a/train.py\n+++ b/train.py\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n", out)
+
+	out, err = Text("new.py", nil, []byte("x\n"))
+	require.NoError(t, err)
+	require.Equal(t, "--- a/new.py\n+++ b/new.py\n@@ -0,0 +1 @@\n+x\n", out)
+
+	out, err = Text("same.py", []byte("x\n"), []byte("x\n"))
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestIsText(t *testing.T) {
+	require.True(t, IsText([]byte("import torch\n")))
+	require.False(t, IsText([]byte{0x89, 'P', 'N', 'G', 0}))
+	require.False(t, IsText(make([]byte, MaxTextSize+1)))
+}
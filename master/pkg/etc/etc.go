@@ -14,6 +14,8 @@ import (
 const (
 	// CommandEntrypointResource is the pre-flight script for commands.
 	CommandEntrypointResource = "command-entrypoint.sh"
+	// DeploymentEntrypointResource is the script to set up a replica of a deployment.
+	DeploymentEntrypointResource = "deployment-entrypoint.sh"
 	// SSHConfigResource is the template SSH config file.
 	SSHConfigResource = "ssh_config"
 	// SSHDConfigResource is the template SSHD config file.
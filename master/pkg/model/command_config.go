@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 )
@@ -15,6 +17,12 @@ const (
 	// NotebookIdleTypeActivity indicates that a notebook should be considered active if any kernel is
 	// running a command or any terminal is inputting or outputting data.
 	NotebookIdleTypeActivity = "activity"
+
+	// NotebookIdleActionKill indicates that an idle notebook should be killed.
+	NotebookIdleActionKill = "kill"
+	// NotebookIdleActionSuspend indicates that an idle notebook should be suspended, archiving its
+	// working directory so it can be resumed later.
+	NotebookIdleActionSuspend = "suspend"
 )
 
 // DefaultConfig is the default configuration used by all
@@ -22,9 +30,10 @@ const (
 // does not specify any configuration options.
 func DefaultConfig(taskContainerDefaults *TaskContainerDefaultsConfig) CommandConfig {
 	out := CommandConfig{
-		Resources:        DefaultResourcesConfig(taskContainerDefaults),
-		Environment:      DefaultEnvConfig(taskContainerDefaults),
-		NotebookIdleType: NotebookIdleTypeKernelsOrTerminals,
+		Resources:          DefaultResourcesConfig(taskContainerDefaults),
+		Environment:        DefaultEnvConfig(taskContainerDefaults),
+		NotebookIdleType:   NotebookIdleTypeKernelsOrTerminals,
+		NotebookIdleAction: NotebookIdleActionKill,
 	}
 
 	if taskContainerDefaults != nil {
@@ -40,18 +49,20 @@ func DefaultConfig(taskContainerDefaults *TaskContainerDefaultsConfig) CommandCo
 // CommandConfig holds the necessary configurations to launch a command task in
 // the cluster.
 type CommandConfig struct {
-	Description      string              `json:"description"`
-	BindMounts       BindMountsConfig    `json:"bind_mounts"`
-	Environment      Environment         `json:"environment"`
-	Resources        ResourcesConfig     `json:"resources"`
-	Entrypoint       []string            `json:"entrypoint"`
-	TensorBoardArgs  []string            `json:"tensorboard_args,omitempty"`
-	IdleTimeout      *Duration           `json:"idle_timeout"`
-	NotebookIdleType string              `json:"notebook_idle_type"`
-	WorkDir          *string             `json:"work_dir"`
-	Debug            bool                `json:"debug"`
-	Pbs              expconf.PbsConfig   `json:"pbs,omitempty"`
-	Slurm            expconf.SlurmConfig `json:"slurm,omitempty"`
+	Description        string              `json:"description"`
+	BindMounts         BindMountsConfig    `json:"bind_mounts"`
+	Environment        Environment         `json:"environment"`
+	Resources          ResourcesConfig     `json:"resources"`
+	Entrypoint         []string            `json:"entrypoint"`
+	TensorBoardArgs    []string            `json:"tensorboard_args,omitempty"`
+	IdleTimeout        *Duration           `json:"idle_timeout"`
+	NotebookIdleType   string              `json:"notebook_idle_type"`
+	NotebookIdleAction string              `json:"notebook_idle_action,omitempty"`
+	GPUIdleTimeout     *GPUIdleTimeout     `json:"gpu_idle_timeout,omitempty"`
+	WorkDir            *string             `json:"work_dir"`
+	Debug              bool                `json:"debug"`
+	Pbs                expconf.PbsConfig   `json:"pbs,omitempty"`
+	Slurm              expconf.SlurmConfig `json:"slurm,omitempty"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -68,6 +79,57 @@ func (c *CommandConfig) Validate() []error {
 			},
 			"invalid notebook idle type",
 		),
+		check.Contains(
+			c.NotebookIdleAction,
+			[]interface{}{"", NotebookIdleActionKill, NotebookIdleActionSuspend},
+			"invalid notebook idle action",
+		),
 		check.True(c.Resources.IsSingleNode == nil, "resources.is_single_node cannot be set for NTSCs"),
 	}
 }
+
+const (
+	// DefaultGPUIdleUtilizationThreshold is the GPU utilization, in percent, below which a task is
+	// considered idle if the threshold is not configured.
+	DefaultGPUIdleUtilizationThreshold = 5.0
+	// DefaultGPUIdleWarningPeriod is how long before a task is killed for low GPU utilization a
+	// warning is sent if the warning period is not configured. It is capped at half the timeout.
+	DefaultGPUIdleWarningPeriod = 5 * time.Minute
+)
+
+// GPUIdleTimeout configures notebooks and shells to be terminated when the utilization of all
+// their GPUs stays below a threshold for a duration, regardless of other activity.
+type GPUIdleTimeout struct {
+	Timeout              Duration  `json:"timeout"`
+	UtilizationThreshold *float64  `json:"utilization_threshold,omitempty"`
+	WarningPeriod        *Duration `json:"warning_period,omitempty"`
+}
+
+// Threshold returns the configured utilization threshold or its default.
+func (g GPUIdleTimeout) Threshold() float64 {
+	if g.UtilizationThreshold != nil {
+		return *g.UtilizationThreshold
+	}
+	return DefaultGPUIdleUtilizationThreshold
+}
+
+// Warning returns the configured warning period or its default.
+func (g GPUIdleTimeout) Warning() time.Duration {
+	if g.WarningPeriod != nil {
+		return time.Duration(*g.WarningPeriod)
+	}
+	return min(DefaultGPUIdleWarningPeriod, time.Duration(g.Timeout)/2)
+}
+
+// Validate implements the check.Validatable interface.
+func (g GPUIdleTimeout) Validate() []error {
+	return []error{
+		check.GreaterThan(int64(g.Timeout), int64(0), "gpu_idle_timeout.timeout must be > 0"),
+		check.BetweenInclusive(g.Threshold(), 0.0, 100.0,
+			"gpu_idle_timeout.utilization_threshold must be between 0 and 100"),
+		check.GreaterThanOrEqualTo(int64(g.Warning()), int64(0),
+			"gpu_idle_timeout.warning_period must be >= 0"),
+		check.LessThan(int64(g.Warning()), int64(g.Timeout),
+			"gpu_idle_timeout.warning_period must be shorter than gpu_idle_timeout.timeout"),
+	}
+}
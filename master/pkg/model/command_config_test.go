@@ -2,18 +2,22 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 func TestConfigValidate(t *testing.T) {
 	type fields struct {
-		Description      string
-		BindMounts       []BindMount
-		Environment      Environment
-		Resources        ResourcesConfig
-		Entrypoint       []string
-		NotebookIdleType string
+		Description        string
+		BindMounts         []BindMount
+		Environment        Environment
+		Resources          ResourcesConfig
+		Entrypoint         []string
+		NotebookIdleType   string
+		NotebookIdleAction string
+		GPUIdleTimeout     *GPUIdleTimeout
 	}
 	type testCase struct {
 		name    string
@@ -58,16 +62,67 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid-notebook-idle-action",
+			fields: fields{
+				Resources:          resources,
+				Environment:        environment,
+				Entrypoint:         []string{"test"},
+				NotebookIdleType:   NotebookIdleTypeActivity,
+				NotebookIdleAction: NotebookIdleActionSuspend + "x",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid-gpu-idle-timeout",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				GPUIdleTimeout:   &GPUIdleTimeout{Timeout: Duration(time.Minute)},
+			},
+		},
+		{
+			name: "invalid-gpu-idle-timeout-threshold",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				GPUIdleTimeout: &GPUIdleTimeout{
+					Timeout:              Duration(time.Minute),
+					UtilizationThreshold: ptrs.Ptr(101.0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid-gpu-idle-timeout-warning-period",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				GPUIdleTimeout: &GPUIdleTimeout{
+					Timeout:       Duration(time.Minute),
+					WarningPeriod: ptrs.Ptr(Duration(time.Hour)),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	runTestCase := func(t *testing.T, tc testCase) {
 		t.Run(tc.name, func(t *testing.T) {
 			c := &CommandConfig{
-				Description:      tc.fields.Description,
-				BindMounts:       tc.fields.BindMounts,
-				Environment:      tc.fields.Environment,
-				Resources:        tc.fields.Resources,
-				Entrypoint:       tc.fields.Entrypoint,
-				NotebookIdleType: tc.fields.NotebookIdleType,
+				Description:        tc.fields.Description,
+				BindMounts:         tc.fields.BindMounts,
+				Environment:        tc.fields.Environment,
+				Resources:          tc.fields.Resources,
+				Entrypoint:         tc.fields.Entrypoint,
+				NotebookIdleType:   tc.fields.NotebookIdleType,
+				NotebookIdleAction: tc.fields.NotebookIdleAction,
+				GPUIdleTimeout:     tc.fields.GPUIdleTimeout,
 			}
 			if err := check.Validate(c); (err != nil) != tc.wantErr {
 				t.Errorf("config.Validate() error = %v, wantErr %v", err, tc.wantErr)
@@ -543,6 +543,33 @@ type RunHparam struct {
 	BoolVal   *bool    `bun:"bool_val"`
 }
 
+// RunRestart represents a row from the `run_restarts` table.
+type RunRestart struct {
+	bun.BaseModel `bun:"table:run_restarts"`
+
+	ID           int       `bun:"id,pk,autoincrement"`
+	RunID        int       `bun:"run_id"`
+	RestartTime  time.Time `bun:"restart_time"`
+	Reason       string    `bun:"reason"`
+	ExitCode     *int      `bun:"exit_code"`
+	DelaySeconds float64   `bun:"delay_seconds"`
+	Counted      bool      `bun:"counted"`
+}
+
+// Proto converts a run restart to its protobuf representation.
+func (r RunRestart) Proto() *trialv1.TrialRestart {
+	pr := &trialv1.TrialRestart{
+		RestartTime:  timestamppb.New(r.RestartTime),
+		Reason:       r.Reason,
+		DelaySeconds: r.DelaySeconds,
+		Counted:      r.Counted,
+	}
+	if r.ExitCode != nil {
+		pr.ExitCode = ptrs.Ptr(int32(*r.ExitCode))
+	}
+	return pr
+}
+
 // ProjectHparam represents a row from the `project_hparams` table.
 type ProjectHparam struct {
 	bun.BaseModel `bun:"table:project_hparams"`
@@ -39,6 +39,8 @@ const (
 	JobTypeCheckpointGC JobType = "CHECKPOINT_GC"
 	// JobTypeGeneric is the "GENERIC" job type for enum.job_type in Postgres.
 	JobTypeGeneric JobType = "GENERIC"
+	// JobTypeDeployment is the "DEPLOYMENT" job type for enum.job_type in Postgres.
+	JobTypeDeployment JobType = "DEPLOYMENT"
 )
 
 // Proto returns the proto representation of the job type.
@@ -58,6 +60,8 @@ func (jt JobType) Proto() jobv1.Type {
 		return jobv1.Type_TYPE_CHECKPOINT_GC
 	case JobTypeGeneric:
 		return jobv1.Type_TYPE_GENERIC
+	case JobTypeDeployment:
+		return jobv1.Type_TYPE_DEPLOYMENT
 	default:
 		panic("unknown job type")
 	}
@@ -78,6 +82,8 @@ func JobTypeFromProto(t jobv1.Type) JobType {
 		return JobTypeTensorboard
 	case jobv1.Type_TYPE_CHECKPOINT_GC:
 		return JobTypeCheckpointGC
+	case jobv1.Type_TYPE_DEPLOYMENT:
+		return JobTypeDeployment
 	default:
 		panic("unknown job type")
 	}
@@ -14,3 +14,11 @@ type NotebookSession struct {
 
 // NotebookSessionEnvVar is the environment variable name for notebook task tokens.
 const NotebookSessionEnvVar = "DET_NOTEBOOK_TOKEN"
+
+// NotebookRestoreStorageIDEnvVar is the environment variable name for the storage ID a resumed
+// notebook restores its working directory from.
+const NotebookRestoreStorageIDEnvVar = "DET_NOTEBOOK_RESTORE_STORAGE_ID"
+
+// NotebookDiscardStorageIDEnvVar is the environment variable name for the storage ID a killed
+// suspended notebook deletes before exiting, without starting JupyterLab.
+const NotebookDiscardStorageIDEnvVar = "DET_NOTEBOOK_DISCARD_STORAGE_ID"
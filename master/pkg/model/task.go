@@ -50,6 +50,8 @@ const (
 	TaskTypeCheckpointGC TaskType = "CHECKPOINT_GC"
 	// TaskTypeGeneric is the "GENERIC" job type for the enum public.job_type in Postgres.
 	TaskTypeGeneric TaskType = "GENERIC"
+	// TaskTypeDeployment is the "DEPLOYMENT" task type for the enum public.task_type in Postgres.
+	TaskTypeDeployment TaskType = "DEPLOYMENT"
 	// GlobalAccessScopeID represents global permission access.
 	GlobalAccessScopeID AccessScopeID = 0
 	// AggregationTypeQueued is the type of aggregation for queued tasks.
@@ -56,10 +56,13 @@ type User struct {
 
 // UserSession corresponds to a row in the "user_sessions" DB table.
 type UserSession struct {
-	bun.BaseModel   `bun:"table:user_sessions"`
-	ID              SessionID         `db:"id" json:"id"`
-	UserID          UserID            `db:"user_id" json:"user_id"`
-	Expiry          time.Time         `db:"expiry" json:"expiry"`
+	bun.BaseModel `bun:"table:user_sessions"`
+	ID            SessionID `db:"id" json:"id"`
+	UserID        UserID    `db:"user_id" json:"user_id"`
+	Expiry        time.Time `db:"expiry" json:"expiry"`
+	// TaskSession is set for sessions the master starts for the tasks of a user, rather than for
+	// the user logging in.
+	TaskSession     bool              `db:"task_session" bun:"task_session" json:"-"`
 	InheritedClaims map[string]string `bun:"-"` // InheritedClaims contains the OIDC raw ID token when OIDC is enabled
 }
 
@@ -31,6 +31,7 @@ type ExperimentConfigV0 struct {
 	RawLabels                   LabelsV0                    `json:"labels"`
 	RawLogPolicies              LogPoliciesConfigV0         `json:"log_policies"`
 	RawRetentionPolicy          *RetentionPolicyConfigV0    `json:"retention_policy,omitempty"`
+	RawRetryPolicy              *RetryPolicyConfigV0        `json:"retry_policy"`
 	RawMaxRestarts              *int                        `json:"max_restarts"`
 	RawMinCheckpointPeriod      *LengthV0                   `json:"min_checkpoint_period"`
 	RawMinValidationPeriod      *LengthV0                   `json:"min_validation_period"`
@@ -223,7 +224,34 @@ type ResourcesConfigV0 struct {
 	RawPriority       *int     `json:"priority"`
 	RawIsSingleNode   *bool    `json:"is_single_node"`
 
-	RawDevices DevicesConfigV0 `json:"devices"`
+	RawDevices DevicesConfigV0  `json:"devices"`
+	RawElastic *ElasticConfigV0 `json:"elastic"`
+}
+
+// ValidateElastic returns an error if an elastic range is configured that does not contain
+// slots_per_trial, which is the size elastic trials start at.
+func (r ResourcesConfigV0) ValidateElastic() error {
+	if r.RawElastic == nil {
+		return nil
+	}
+	slots := r.SlotsPerTrial()
+	if slots < r.RawElastic.MinSlots() || slots > r.RawElastic.MaxSlots() {
+		return fmt.Errorf(
+			"resources.slots_per_trial (%d) must be between resources.elastic.min_slots (%d) "+
+				"and resources.elastic.max_slots (%d)",
+			slots, r.RawElastic.MinSlots(), r.RawElastic.MaxSlots(),
+		)
+	}
+	return nil
+}
+
+// ElasticConfigV0 configures the range of slots an elastic trial may be resized within while it
+// runs.
+//
+//go:generate ../gen.sh
+type ElasticConfigV0 struct {
+	RawMinSlots int `json:"min_slots"`
+	RawMaxSlots int `json:"max_slots"`
 }
 
 // OptimizationsConfigV0 is a legacy config value.
@@ -4,6 +4,7 @@ package expconf
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
@@ -116,3 +117,47 @@ func TestName(t *testing.T) {
 
 	assert.DeepEqual(t, newConfig.Name().String(), "my_name")
 }
+
+func TestRetryPolicy(t *testing.T) {
+	var config RetryPolicyConfig
+	err := json.Unmarshal([]byte(`{
+		"initial_delay": 10,
+		"max_delay": 60,
+		"never_retry_exit_codes": [3],
+		"always_retry_exit_codes": ["SIGKILL"]
+	}`), &config)
+	require.NoError(t, err)
+	config = schemas.WithDefaults(config)
+
+	require.Equal(t, time.Duration(0), config.Delay(0))
+	require.Equal(t, 10*time.Second, config.Delay(1))
+	require.Equal(t, 20*time.Second, config.Delay(2))
+	require.Equal(t, 40*time.Second, config.Delay(3))
+	require.Equal(t, 60*time.Second, config.Delay(4))
+	require.Equal(t, 60*time.Second, config.Delay(10))
+
+	require.True(t, config.NeverRetries(3))
+	require.False(t, config.NeverRetries(137))
+	require.True(t, config.AlwaysRetries(137))
+	require.False(t, config.AlwaysRetries(3))
+
+	err = json.Unmarshal([]byte(`{"always_retry_exit_codes": ["SIGNOPE"]}`), &config)
+	require.ErrorContains(t, err, "unknown signal")
+}
+
+func TestValidateElastic(t *testing.T) {
+	var config ResourcesConfig
+	err := json.Unmarshal([]byte(`{"slots_per_trial": 4}`), &config)
+	require.NoError(t, err)
+	require.NoError(t, schemas.WithDefaults(config).ValidateElastic())
+
+	err = json.Unmarshal([]byte(`{
+		"slots_per_trial": 4,
+		"elastic": {"min_slots": 2, "max_slots": 8}
+	}`), &config)
+	require.NoError(t, err)
+	require.NoError(t, schemas.WithDefaults(config).ValidateElastic())
+
+	config.RawSlotsPerTrial = ptrs.Ptr(16)
+	require.ErrorContains(t, config.ValidateElastic(), "must be between")
+}
@@ -17,6 +17,7 @@ type (
 	DirectoryConfig           = DirectoryConfigV0
 	DoubleHyperparameter      = DoubleHyperparameterV0
 	Entrypoint                = EntrypointV0
+	ElasticConfig             = ElasticConfigV0
 	EnvironmentConfig         = EnvironmentConfigV0
 	EnvironmentImageMap       = EnvironmentImageMapV0
 	EnvironmentVariablesMap   = EnvironmentVariablesMapV0
@@ -43,6 +44,8 @@ type (
 	ReproducibilityConfig     = ReproducibilityConfigV0
 	ResourcesConfig           = ResourcesConfigV0
 	RetentionPolicy           = RetentionPolicyConfigV0
+	RetryPolicyConfig         = RetryPolicyConfigV0
+	ExitCode                  = ExitCodeV0
 	S3Config                  = S3ConfigV0
 	SearcherConfig            = SearcherConfigV0
 	SharedFSConfig            = SharedFSConfigV0
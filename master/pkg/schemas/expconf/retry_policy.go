@@ -0,0 +1,98 @@
+package expconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// signalExitCodeOffset is added to the number of a signal to get the exit code a shell reports for
+// a process killed by it.
+const signalExitCodeOffset = 128
+
+// signalNumbers maps the names of signals to their numbers on Linux.
+var signalNumbers = map[string]int{
+	"SIGHUP":  1,
+	"SIGINT":  2,
+	"SIGQUIT": 3,
+	"SIGILL":  4,
+	"SIGTRAP": 5,
+	"SIGABRT": 6,
+	"SIGBUS":  7,
+	"SIGFPE":  8,
+	"SIGKILL": 9,
+	"SIGUSR1": 10,
+	"SIGSEGV": 11,
+	"SIGUSR2": 12,
+	"SIGPIPE": 13,
+	"SIGALRM": 14,
+	"SIGTERM": 15,
+}
+
+// ExitCodeV0 is an exit code. It may be configured as the name of a signal, such as SIGKILL, which
+// stands for the exit code of a process killed by that signal.
+type ExitCodeV0 int
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *ExitCodeV0) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		*e = ExitCodeV0(code)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("exit code must be an integer or a signal name: %s", data)
+	}
+	num, ok := signalNumbers[name]
+	if !ok {
+		return fmt.Errorf("unknown signal %q", name)
+	}
+	*e = ExitCodeV0(signalExitCodeOffset + num)
+	return nil
+}
+
+// RetryPolicyConfigV0 configures how failed trials are restarted.
+//
+//go:generate ../gen.sh
+type RetryPolicyConfigV0 struct {
+	// Delays are in seconds.
+	RawInitialDelay         *float64     `json:"initial_delay"`
+	RawMaxDelay             *float64     `json:"max_delay"`
+	RawBackoffMultiplier    *float64     `json:"backoff_multiplier"`
+	RawNeverRetryExitCodes  []ExitCodeV0 `json:"never_retry_exit_codes"`
+	RawAlwaysRetryExitCodes []ExitCodeV0 `json:"always_retry_exit_codes"`
+}
+
+// Delay returns how long to wait before the given restart of a trial, counting from 1. The delay
+// grows exponentially from the initial delay up to the max delay.
+func (r RetryPolicyConfigV0) Delay(restart int) time.Duration {
+	if restart < 1 || r.InitialDelay() == 0 {
+		return 0
+	}
+	seconds := r.InitialDelay() * math.Pow(r.BackoffMultiplier(), float64(restart-1))
+	seconds = math.Min(seconds, math.Max(r.MaxDelay(), r.InitialDelay()))
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// NeverRetries returns whether a trial that exited with the given code must not be restarted.
+func (r RetryPolicyConfigV0) NeverRetries(code int) bool {
+	return containsExitCode(r.NeverRetryExitCodes(), code)
+}
+
+// AlwaysRetries returns whether a trial that exited with the given code is restarted without
+// counting against max_restarts.
+func (r RetryPolicyConfigV0) AlwaysRetries(code int) bool {
+	return containsExitCode(r.AlwaysRetryExitCodes(), code)
+}
+
+func containsExitCode(codes []ExitCodeV0, code int) bool {
+	for _, c := range codes {
+		if int(c) == code {
+			return true
+		}
+	}
+	return false
+}
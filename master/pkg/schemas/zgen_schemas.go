@@ -447,6 +447,33 @@ var (
         }
     }
 }
+`)
+	textElasticConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/elastic.json",
+    "title": "ElasticConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "min_slots",
+        "max_slots"
+    ],
+    "properties": {
+        "min_slots": {
+            "type": "integer",
+            "minimum": 1
+        },
+        "max_slots": {
+            "type": "integer",
+            "minimum": 1
+        }
+    },
+    "compareProperties": {
+        "type": "a<=b",
+        "a": "min_slots",
+        "b": "max_slots"
+    }
+}
 `)
 	textEnvironmentImageMapV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -724,6 +751,20 @@ var (
         }
     }
 }
+`)
+	textExitCodeV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/exit-code.json",
+    "title": "ExitCode",
+    "type": [
+        "integer",
+        "string"
+    ],
+    "minimum": 0,
+    "maximum": 255,
+    "pattern": "^SIG[A-Z0-9]+$",
+    "$comment": "an exit code, or the name of a signal that killed the process"
+}
 `)
 	textExperimentConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -861,6 +902,14 @@ var (
             "default": null,
             "optionalRef": "http://determined.ai/schemas/expconf/v0/retention-policy.json"
         },
+        "retry_policy": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/retry-policy.json"
+        },
         "max_restarts": {
             "type": [
                 "integer",
@@ -2002,6 +2051,14 @@ var (
             "default": [],
             "optionalRef": "http://determined.ai/schemas/expconf/v0/devices.json"
         },
+        "elastic": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/elastic.json"
+        },
         "is_single_node": {
             "type": [
                 "boolean",
@@ -2098,6 +2155,67 @@ var (
         }
     }
 }
+`)
+	textRetryPolicyConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/retry-policy.json",
+    "title": "RetryPolicyConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "eventuallyRequired": [
+        "initial_delay",
+        "max_delay",
+        "backoff_multiplier",
+        "never_retry_exit_codes",
+        "always_retry_exit_codes"
+    ],
+    "properties": {
+        "initial_delay": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "default": 0
+        },
+        "max_delay": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "default": 600
+        },
+        "backoff_multiplier": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 1,
+            "default": 2
+        },
+        "never_retry_exit_codes": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/exit-code.json"
+            }
+        },
+        "always_retry_exit_codes": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/exit-code.json"
+            }
+        }
+    }
+}
 `)
 	textS3ConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -3416,6 +3534,8 @@ var (
 
 	schemaDirectoryConfigV0 interface{}
 
+	schemaElasticConfigV0 interface{}
+
 	schemaEnvironmentImageMapV0 interface{}
 
 	schemaEnvironmentImageV0 interface{}
@@ -3426,6 +3546,8 @@ var (
 
 	schemaEnvironmentConfigV0 interface{}
 
+	schemaExitCodeV0 interface{}
+
 	schemaExperimentConfigV0 interface{}
 
 	schemaGCSConfigV0 interface{}
@@ -3486,6 +3608,8 @@ var (
 
 	schemaRetentionPolicyConfigV0 interface{}
 
+	schemaRetryPolicyConfigV0 interface{}
+
 	schemaS3ConfigV0 interface{}
 
 	schemaAdaptiveASHAConfigV0 interface{}
@@ -3715,6 +3839,26 @@ func ParsedDirectoryConfigV0() interface{} {
 	return schemaDirectoryConfigV0
 }
 
+func ParsedElasticConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaElasticConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaElasticConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaElasticConfigV0 != nil {
+		return schemaElasticConfigV0
+	}
+	err := json.Unmarshal(textElasticConfigV0, &schemaElasticConfigV0)
+	if err != nil {
+		panic("invalid embedded json for ElasticConfigV0")
+	}
+	return schemaElasticConfigV0
+}
+
 func ParsedEnvironmentImageMapV0() interface{} {
 	cacheLock.RLock()
 	if schemaEnvironmentImageMapV0 != nil {
@@ -3815,6 +3959,26 @@ func ParsedEnvironmentConfigV0() interface{} {
 	return schemaEnvironmentConfigV0
 }
 
+func ParsedExitCodeV0() interface{} {
+	cacheLock.RLock()
+	if schemaExitCodeV0 != nil {
+		cacheLock.RUnlock()
+		return schemaExitCodeV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaExitCodeV0 != nil {
+		return schemaExitCodeV0
+	}
+	err := json.Unmarshal(textExitCodeV0, &schemaExitCodeV0)
+	if err != nil {
+		panic("invalid embedded json for ExitCodeV0")
+	}
+	return schemaExitCodeV0
+}
+
 func ParsedExperimentConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaExperimentConfigV0 != nil {
@@ -4415,6 +4579,26 @@ func ParsedRetentionPolicyConfigV0() interface{} {
 	return schemaRetentionPolicyConfigV0
 }
 
+func ParsedRetryPolicyConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaRetryPolicyConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaRetryPolicyConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaRetryPolicyConfigV0 != nil {
+		return schemaRetryPolicyConfigV0
+	}
+	err := json.Unmarshal(textRetryPolicyConfigV0, &schemaRetryPolicyConfigV0)
+	if err != nil {
+		panic("invalid embedded json for RetryPolicyConfigV0")
+	}
+	return schemaRetryPolicyConfigV0
+}
+
 func ParsedS3ConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaS3ConfigV0 != nil {
@@ -4868,6 +5052,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textDevicesConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/directory.json"
 	cachedSchemaBytesMap[url] = textDirectoryConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/elastic.json"
+	cachedSchemaBytesMap[url] = textElasticConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/environment-image-map.json"
 	cachedSchemaBytesMap[url] = textEnvironmentImageMapV0
 	url = "http://determined.ai/schemas/expconf/v0/environment-image.json"
@@ -4878,6 +5064,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textEnvironmentVariablesV0
 	url = "http://determined.ai/schemas/expconf/v0/environment.json"
 	cachedSchemaBytesMap[url] = textEnvironmentConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/exit-code.json"
+	cachedSchemaBytesMap[url] = textExitCodeV0
 	url = "http://determined.ai/schemas/expconf/v0/experiment.json"
 	cachedSchemaBytesMap[url] = textExperimentConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/gcs.json"
@@ -4938,6 +5126,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textResourcesConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/retention-policy.json"
 	cachedSchemaBytesMap[url] = textRetentionPolicyConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/retry-policy.json"
+	cachedSchemaBytesMap[url] = textRetryPolicyConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/s3.json"
 	cachedSchemaBytesMap[url] = textS3ConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json"
@@ -561,7 +561,8 @@ func (t *TaskSpec) restrictCommandsShellsAndNotebooksToSingleNode(
 	resources *launcher.ResourceRequirements,
 ) {
 	switch t.TaskType {
-	case model.TaskTypeCommand, model.TaskTypeShell, model.TaskTypeNotebook:
+	case model.TaskTypeCommand, model.TaskTypeShell, model.TaskTypeNotebook,
+		model.TaskTypeDeployment:
 		resources.SetInstances(map[string]int32{
 			"nodes": 1,
 		})
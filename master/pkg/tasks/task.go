@@ -57,6 +57,13 @@ type TaskSpecifier interface {
 	ToTaskSpec() TaskSpec
 }
 
+// ResizableTaskSpecifier is a TaskSpecifier for tasks that can run with fewer slots than they
+// asked for.
+type ResizableTaskSpecifier interface {
+	TaskSpecifier
+	WithSlots(slots int) TaskSpecifier
+}
+
 // TaskSpec defines the spec of a task.
 type TaskSpec struct {
 	// Fields that are only for task logics.
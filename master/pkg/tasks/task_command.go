@@ -4,8 +4,11 @@ import (
 	"archive/tar"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 
+	"github.com/docker/docker/api/types/mount"
+
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/etc"
@@ -61,6 +64,11 @@ type GenericCommandSpec struct {
 	WatchRunnerIdleTimeout bool
 
 	TaskType model.TaskType
+
+	// SuspendStorage is where a notebook that can be suspended archives its working directory. A
+	// shared_fs storage is narrowed to a directory of the notebook's own, which is all that is
+	// mounted into it.
+	SuspendStorage *expconf.CheckpointStorageConfig
 }
 
 // ToTaskSpec generates a TaskSpec.
@@ -112,6 +120,22 @@ func (s GenericCommandSpec) ToTaskSpec() TaskSpec {
 	res.Entrypoint = s.Config.Entrypoint
 
 	res.Mounts = ToDockerMounts(s.Config.BindMounts.ToExpconf(), res.WorkDir)
+	if s.SuspendStorage != nil && s.SuspendStorage.RawSharedFSConfig != nil &&
+		!slices.ContainsFunc(res.Mounts, func(m mount.Mount) bool {
+			return m.Target == expconf.DefaultSharedFSContainerPath
+		}) {
+		// Unless the user mounted something else in its place, the archive directory is created
+		// on the host the first time the notebook starts.
+		res.Mounts = append(res.Mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: s.SuspendStorage.RawSharedFSConfig.HostPath(),
+			Target: expconf.DefaultSharedFSContainerPath,
+			BindOptions: &mount.BindOptions{
+				Propagation:      expconf.DefaultSharedFSPropagation,
+				CreateMountpoint: true,
+			},
+		})
+	}
 
 	if shm := s.Config.Resources.ShmSize; shm != nil {
 		res.ShmSize = int64(*shm)
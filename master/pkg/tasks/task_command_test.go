@@ -0,0 +1,50 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestGenericCommandSpecSuspendStorageMount(t *testing.T) {
+	spec := func(mounts ...model.BindMount) GenericCommandSpec {
+		return GenericCommandSpec{
+			Config: model.CommandConfig{BindMounts: mounts},
+			SuspendStorage: &expconf.CheckpointStorageConfig{
+				RawSharedFSConfig: &expconf.SharedFSConfig{
+					RawHostPath: ptrs.Ptr("/mnt/checkpoints/notebook-suspensions/abc"),
+				},
+			},
+		}
+	}
+
+	// Only the archive directory of the notebook is mounted, and created if it is missing.
+	res := spec().ToTaskSpec()
+	require.Equal(t, []mount.Mount{{
+		Type:   mount.TypeBind,
+		Source: "/mnt/checkpoints/notebook-suspensions/abc",
+		Target: expconf.DefaultSharedFSContainerPath,
+		BindOptions: &mount.BindOptions{
+			Propagation:      expconf.DefaultSharedFSPropagation,
+			CreateMountpoint: true,
+		},
+	}}, res.Mounts)
+
+	// A mount of the user's in its place is kept instead.
+	res = spec(model.BindMount{
+		HostPath:      "/data",
+		ContainerPath: expconf.DefaultSharedFSContainerPath,
+	}).ToTaskSpec()
+	require.Len(t, res.Mounts, 1)
+	require.Equal(t, "/data", res.Mounts[0].Source)
+
+	// Notebooks that can't be suspended get no mount.
+	s := spec()
+	s.SuspendStorage = nil
+	require.Empty(t, s.ToTaskSpec().Mounts)
+}
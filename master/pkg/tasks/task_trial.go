@@ -32,6 +32,16 @@ type TrialSpec struct {
 	Keys ssh.PrivateAndPublicKeys
 }
 
+// WithSlots returns a copy of the spec for the trial running with the given number of slots.
+func (s *TrialSpec) WithSlots(slots int) TaskSpecifier {
+	resized := *s
+	resized.ExperimentConfig = schemas.Copy(s.ExperimentConfig)
+	if resized.ExperimentConfig.RawResources != nil {
+		resized.ExperimentConfig.RawResources.RawSlotsPerTrial = &slots
+	}
+	return &resized
+}
+
 // ToTaskSpec generates a TaskSpec.
 func (s TrialSpec) ToTaskSpec() TaskSpec {
 	res := s.Base
@@ -0,0 +1,73 @@
+CREATE TYPE public.model_version_stage AS ENUM (
+    'NONE',
+    'STAGING',
+    'PRODUCTION',
+    'ARCHIVED'
+);
+
+ALTER TABLE public.model_versions
+    ADD COLUMN stage public.model_version_stage NOT NULL DEFAULT 'NONE',
+    -- aliases is maintained by model_aliases_update_versions from model_aliases so that alias moves
+    -- are streamed as model version updates.
+    ADD COLUMN aliases text[] NOT NULL DEFAULT '{}';
+
+-- An alias points at exactly one version of a model, and may be moved between versions.
+CREATE TABLE public.model_aliases (
+    model_id integer NOT NULL REFERENCES public.models(id) ON DELETE CASCADE,
+    alias text NOT NULL,
+    model_version_id integer NOT NULL REFERENCES public.model_versions(id) ON DELETE CASCADE,
+    PRIMARY KEY (model_id, alias)
+);
+
+CREATE INDEX ix_model_aliases_model_version_id ON public.model_aliases USING btree (model_version_id);
+
+-- Audit log of alias moves. Versions are recorded by number so that the history outlives them;
+-- a NULL previous_version means the alias was created and a NULL version that it was removed.
+CREATE TABLE public.model_alias_history (
+    id serial PRIMARY KEY,
+    model_id integer NOT NULL REFERENCES public.models(id) ON DELETE CASCADE,
+    alias text NOT NULL,
+    previous_version integer,
+    version integer,
+    user_id integer REFERENCES public.users(id) ON DELETE SET NULL,
+    change_time timestamptz NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX ix_model_alias_history_model_id_alias ON public.model_alias_history
+    USING btree (model_id, alias);
+
+CREATE OR REPLACE FUNCTION model_aliases_update_versions() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE model_versions mv SET aliases = COALESCE(
+        (SELECT array_agg(a.alias ORDER BY a.alias) FROM model_aliases a
+         WHERE a.model_version_id = mv.id),
+        '{}'
+    )
+    WHERE mv.id IN (
+        (CASE WHEN TG_OP <> 'INSERT' THEN OLD.model_version_id END),
+        (CASE WHEN TG_OP <> 'DELETE' THEN NEW.model_version_id END)
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER model_aliases_update_versions
+    AFTER INSERT OR UPDATE OR DELETE ON model_aliases
+    FOR EACH ROW EXECUTE PROCEDURE model_aliases_update_versions();
+
+-- Stream stage and alias changes of model versions.
+DROP TRIGGER IF EXISTS stream_model_version_trigger_seq ON model_versions;
+CREATE TRIGGER stream_model_version_trigger_seq
+    BEFORE INSERT OR UPDATE OF
+    name, version, checkpoint_uuid, last_updated_time, metadata, labels, user_id, model_id, notes,
+    comment, stage, aliases
+    ON model_versions
+    FOR EACH ROW EXECUTE PROCEDURE stream_model_version_seq_modify();
+
+DROP TRIGGER IF EXISTS stream_model_version_trigger_iu ON model_versions;
+CREATE TRIGGER stream_model_version_trigger_iu
+    AFTER INSERT OR UPDATE OF
+    name, version, checkpoint_uuid, last_updated_time, metadata, labels, user_id, model_id, notes,
+    comment, stage, aliases
+    ON model_versions
+    FOR EACH ROW EXECUTE PROCEDURE stream_model_version_change();
@@ -0,0 +1,13 @@
+-- Automatic restarts of a run after its allocation failed. Restarts that are always retried by the
+-- experiment's retry_policy are recorded with counted = false since they don't use up max_restarts.
+CREATE TABLE public.run_restarts (
+    id serial PRIMARY KEY,
+    run_id integer NOT NULL REFERENCES public.runs(id) ON DELETE CASCADE,
+    restart_time timestamp with time zone NOT NULL DEFAULT now(),
+    reason text NOT NULL,
+    exit_code integer,
+    delay_seconds double precision NOT NULL DEFAULT 0,
+    counted boolean NOT NULL
+);
+
+CREATE INDEX ix_run_restarts_run_id ON public.run_restarts USING btree (run_id);
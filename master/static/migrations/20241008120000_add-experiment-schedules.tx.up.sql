@@ -0,0 +1,39 @@
+-- Schedules that create an experiment whenever their cron expression fires. Runs fork
+-- model_definition_experiment_id for its model definition; it is not a foreign key so that runs fail
+-- visibly, rather than without a model definition, once that experiment is deleted.
+CREATE TABLE public.experiment_schedules (
+    id serial PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    cron text NOT NULL,
+    config text NOT NULL,
+    template text,
+    model_definition_experiment_id integer,
+    project_id integer NOT NULL REFERENCES public.projects(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    skip_if_running boolean NOT NULL DEFAULT false,
+    paused boolean NOT NULL DEFAULT false,
+    creation_time timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TYPE public.experiment_schedule_run_status AS ENUM (
+    'CREATED',
+    'SKIPPED',
+    'FAILED'
+);
+
+-- Every firing of a schedule, linking the schedule to the experiment it created. Experiment ids are
+-- kept when the experiment is deleted so that the history stays complete.
+CREATE TABLE public.experiment_schedule_runs (
+    id serial PRIMARY KEY,
+    schedule_id integer NOT NULL REFERENCES public.experiment_schedules(id) ON DELETE CASCADE,
+    run_time timestamp with time zone NOT NULL,
+    status public.experiment_schedule_run_status NOT NULL,
+    experiment_id integer,
+    message text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ix_experiment_schedule_runs_schedule_id ON public.experiment_schedule_runs
+    USING btree (schedule_id, run_time DESC);
+CREATE INDEX ix_experiment_schedule_runs_experiment_id ON public.experiment_schedule_runs
+    USING btree (experiment_id);
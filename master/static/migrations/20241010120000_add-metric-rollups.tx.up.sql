@@ -0,0 +1,22 @@
+-- Downsampled summaries of the numeric metrics of a run, at several resolutions. A row summarizes
+-- the values of one metric reported in the batches [bucket, bucket + resolution).
+CREATE TABLE public.metric_rollups (
+    trial_id integer NOT NULL REFERENCES public.runs(id) ON DELETE CASCADE,
+    metric_group text NOT NULL,
+    metric_name text NOT NULL,
+    resolution integer NOT NULL,
+    bucket integer NOT NULL,
+    count integer NOT NULL,
+    min double precision NOT NULL,
+    max double precision NOT NULL,
+    sum double precision NOT NULL,
+    last double precision NOT NULL,
+    last_batches integer NOT NULL,
+    last_end_time timestamp with time zone NOT NULL,
+    PRIMARY KEY (trial_id, metric_group, resolution, metric_name, bucket)
+);
+
+-- Runs created from now on get rollups as their metrics are reported. Existing runs only get them
+-- once `determined-master backfill-metric-rollups` has processed them.
+ALTER TABLE public.runs ADD COLUMN metric_rollups_complete boolean NOT NULL DEFAULT false;
+ALTER TABLE public.runs ALTER COLUMN metric_rollups_complete SET DEFAULT true;
@@ -0,0 +1,10 @@
+-- The cost of a slot-hour in a resource pool. A rate is in effect from effective_from until the next
+-- rate of the same pool and slot type takes effect.
+CREATE TABLE public.cost_rates (
+    id serial PRIMARY KEY,
+    resource_pool text NOT NULL,
+    slot_type text NOT NULL,
+    rate_per_slot_hour double precision NOT NULL CHECK (rate_per_slot_hour >= 0),
+    effective_from timestamp with time zone NOT NULL,
+    UNIQUE (resource_pool, slot_type, effective_from)
+);
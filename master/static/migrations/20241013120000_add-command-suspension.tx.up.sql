@@ -0,0 +1,3 @@
+ALTER TABLE command_state
+    ADD COLUMN suspend_storage_id uuid NULL,
+    ADD COLUMN suspended boolean NOT NULL DEFAULT false;
@@ -0,0 +1,17 @@
+-- Expiring links that grant access to the proxied services of a task. Links are authenticated by
+-- random tokens of which only the hash is kept. A link limited to users or groups only lets those
+-- users, or members of those groups, in.
+CREATE TABLE public.proxy_share_links (
+    id serial PRIMARY KEY,
+    task_id text NOT NULL REFERENCES public.tasks(task_id) ON DELETE CASCADE,
+    token_hash text NOT NULL UNIQUE,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    read_only boolean NOT NULL DEFAULT false,
+    user_ids integer[] NOT NULL DEFAULT '{}',
+    group_ids integer[] NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone NULL
+);
+
+CREATE INDEX ix_proxy_share_links_task_id ON public.proxy_share_links (task_id);
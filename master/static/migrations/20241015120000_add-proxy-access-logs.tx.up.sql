@@ -0,0 +1,19 @@
+-- Requests proxied by the master to the services of tasks. WebSocket and TCP sessions are recorded
+-- once, when they end.
+CREATE TABLE public.proxy_access_logs (
+    id bigserial PRIMARY KEY,
+    task_id text NOT NULL,
+    service_id text NOT NULL,
+    user_id integer NULL,
+    remote_ip text NOT NULL,
+    method text NOT NULL,
+    path text NOT NULL,
+    status integer NOT NULL,
+    bytes_in bigint NOT NULL,
+    bytes_out bigint NOT NULL,
+    websocket boolean NOT NULL,
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL
+);
+
+CREATE INDEX ix_proxy_access_logs_task_id_start_time ON public.proxy_access_logs (task_id, start_time);
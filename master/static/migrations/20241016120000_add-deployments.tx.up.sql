@@ -0,0 +1,80 @@
+-- Replicas of deployments run as tasks and jobs of their own type.
+DO $$ 
+BEGIN
+    IF current_setting('server_version_num')::int > 120000 THEN
+       ALTER TYPE task_type ADD VALUE 'DEPLOYMENT';
+       ALTER TYPE job_type ADD VALUE 'DEPLOYMENT';
+    ELSE
+      ALTER TYPE task_type RENAME TO _task_type;
+
+      CREATE TYPE task_type AS ENUM (
+        'TRIAL',
+        'NOTEBOOK',
+        'SHELL',
+        'COMMAND',
+        'TENSORBOARD',
+        'CHECKPOINT_GC',
+        'GENERIC',
+        'DEPLOYMENT'
+      );
+
+      ALTER TABLE tasks ALTER COLUMN task_type
+          SET DATA TYPE task_type USING (task_type::text::task_type);
+
+      DROP TYPE public._task_type;
+
+      ALTER TYPE job_type RENAME TO _job_type;
+
+      CREATE TYPE job_type AS ENUM (
+        'EXPERIMENT',
+        'NOTEBOOK',
+        'SHELL',
+        'COMMAND',
+        'TENSORBOARD',
+        'CHECKPOINT_GC',
+        'GENERIC',
+        'DEPLOYMENT'
+      );
+
+      ALTER TABLE jobs ALTER COLUMN job_type
+          SET DATA TYPE job_type USING (job_type::text::job_type);
+
+      DROP TYPE public._job_type;
+    END IF;
+END $$;
+
+CREATE TYPE public.deployment_state AS ENUM (
+    'ACTIVE',
+    'DELETING',
+    'DELETED'
+);
+
+-- Model versions served by replicas behind a load-balanced proxy route. The spec is the command
+-- spec replicas of the current model version are launched with.
+CREATE TABLE public.deployments (
+    id serial PRIMARY KEY,
+    name text NOT NULL,
+    model_id integer NOT NULL REFERENCES public.models(id) ON DELETE CASCADE,
+    model_version integer NOT NULL,
+    checkpoint_uuid uuid NOT NULL,
+    replicas integer NOT NULL,
+    spec jsonb NOT NULL,
+    state public.deployment_state NOT NULL DEFAULT 'ACTIVE',
+    workspace_id integer NOT NULL REFERENCES public.workspaces(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ix_deployments_name ON public.deployments (name) WHERE state != 'DELETED';
+
+-- The replicas of deployments that have not exited yet.
+CREATE TABLE public.deployment_replicas (
+    task_id text PRIMARY KEY REFERENCES public.tasks(task_id) ON DELETE CASCADE,
+    deployment_id integer NOT NULL REFERENCES public.deployments(id) ON DELETE CASCADE,
+    model_version integer NOT NULL,
+    stopping boolean NOT NULL DEFAULT false,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_deployment_replicas_deployment_id ON public.deployment_replicas (deployment_id);
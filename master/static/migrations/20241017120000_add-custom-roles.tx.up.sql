@@ -0,0 +1,6 @@
+-- Roles inserted by migrations are predefined; roles created through the API set this to false.
+ALTER TABLE roles ADD COLUMN built_in BOOLEAN NOT NULL DEFAULT true;
+
+-- Predefined roles are inserted with explicit ids, which leaves the identity sequence behind.
+-- Start custom roles well above them so predefined roles added later don't collide.
+SELECT setval(pg_get_serial_sequence('roles', 'id'), GREATEST((SELECT MAX(id) FROM roles), 1000));
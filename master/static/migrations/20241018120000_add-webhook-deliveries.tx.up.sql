@@ -0,0 +1,33 @@
+-- Events remember their webhook so that their deliveries can be listed per webhook. Events queued
+-- before this migration, and events of webhooks deleted while they were queued, have no webhook.
+ALTER TABLE webhook_events_queue
+  ADD COLUMN webhook_id INT REFERENCES webhooks(id) ON DELETE SET NULL;
+
+CREATE TABLE webhook_deliveries (
+  id SERIAL PRIMARY KEY,
+  webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  -- Events keep their id across redeliveries. Test events have none.
+  event_id INT,
+  url TEXT NOT NULL,
+  attempt INT NOT NULL,
+  status_code INT,
+  latency_ms INT NOT NULL,
+  response TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  success BOOLEAN NOT NULL,
+  test BOOLEAN NOT NULL DEFAULT false,
+  delivered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ix_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
+CREATE INDEX ix_webhook_deliveries_delivered_at ON webhook_deliveries (delivered_at);
+
+CREATE TABLE webhook_dead_events (
+  id INT PRIMARY KEY,
+  webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  payload BYTEA NOT NULL,
+  attempts INT NOT NULL,
+  error TEXT NOT NULL,
+  failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ix_webhook_dead_events_webhook_id ON webhook_dead_events (webhook_id);
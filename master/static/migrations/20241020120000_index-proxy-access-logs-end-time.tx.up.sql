@@ -0,0 +1,2 @@
+-- Access logs are pruned by age.
+CREATE INDEX ix_proxy_access_logs_end_time ON public.proxy_access_logs (end_time);
@@ -0,0 +1,3 @@
+-- Sessions the master starts on behalf of a user for their tasks, as opposed to sessions the user
+-- logged in with. Revoking a user's logins leaves their running tasks alone.
+ALTER TABLE public.user_sessions ADD COLUMN task_session BOOLEAN NOT NULL DEFAULT false;
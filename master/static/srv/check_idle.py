@@ -8,6 +8,8 @@ import requests
 
 from determined.common import api
 from determined.common.api import authentication, certs
+from determined.core import _profiler
+from determined.exec import gpu_utilization, notebook_suspension
 
 
 class IdleType(enum.Enum):
@@ -79,9 +81,11 @@ def main():
     requests.packages.urllib3.disable_warnings()
     port = os.environ["NOTEBOOK_PORT"]
     notebook_id = os.environ["DET_TASK_ID"]
+    allocation_id = os.environ["DET_ALLOCATION_ID"]
     token = os.environ["DET_NOTEBOOK_TOKEN"]
     notebook_server = f"https://127.0.0.1:{port}/proxy/{notebook_id}"
     master_url = api.canonicalize_master_url(os.environ["DET_MASTER"])
+    work_dir = os.getcwd()
     cert = certs.default_load(master_url)
     sess = authentication.login_from_task(master_url, cert=cert)
     try:
@@ -93,18 +97,25 @@ def main():
         )
         idle_type = IdleType.KERNELS_OR_TERMINALS
 
+    gpus = _profiler._GPU()
+
     wait_for_jupyter(("127.0.0.1", int(port)))
 
     while True:
         try:
             idle = is_idle(notebook_server, token, idle_type)
-            sess.put(
+            resp = sess.put(
                 f"/api/v1/notebooks/{notebook_id}/report_idle",
                 json={"idle": idle},
             )
+            storage_id = resp.json().get("suspendStorageId")
+            if storage_id:
+                notebook_suspension.suspend(sess, notebook_id, storage_id, work_dir)
+            gpu_utilization.report(sess, allocation_id, gpus)
         except Exception:
             logging.warning("ignoring error communicating with master", exc_info=True)
-        time.sleep(REPORT_IDLE_INTERVAL)
+        # Sample the GPU utilization until the next report.
+        gpu_utilization.sample(gpus, REPORT_IDLE_INTERVAL)
 
 
 if __name__ == "__main__":
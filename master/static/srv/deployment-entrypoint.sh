@@ -0,0 +1,25 @@
+#!/usr/bin/env bash
+
+source /run/determined/task-setup.sh
+
+set -e
+
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.prep_container --proxy
+
+# The checkpoint is only downloaded if it is not mounted from shared_fs checkpoint storage.
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.deployment download
+
+STARTUP_HOOK="startup-hook.sh"
+set -x
+test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
+test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
+set +x
+
+# Requests are only routed to the replica once it answers on its health path.
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.deployment wait-ready &
+
+if [ "$#" -eq 1 ]; then
+    exec /bin/sh -c "$@"
+else
+    exec "$@"
+fi
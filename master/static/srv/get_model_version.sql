@@ -11,7 +11,9 @@ WITH mv AS (
         notes,
         username,
         user_id,
-        last_updated_time
+        last_updated_time,
+        aliases,
+        stage
     FROM model_versions
     LEFT JOIN users ON users.id = model_versions.user_id
     WHERE model_id = $1 AND model_versions.version = $2
@@ -59,5 +61,7 @@ SELECT
     mv.metadata,
     mv.username,
     mv.user_id,
-    mv.last_updated_time
+    mv.last_updated_time,
+    array_to_json(mv.aliases) AS aliases,
+    'STAGE_' || mv.stage AS stage
 FROM c, m, mv;
@@ -11,7 +11,9 @@ WITH mv AS (
         notes,
         username,
         user_id,
-        last_updated_time
+        last_updated_time,
+        aliases,
+        stage
     FROM model_versions
     LEFT JOIN users ON users.id = model_versions.user_id
     WHERE model_id = $1
@@ -52,6 +54,8 @@ SELECT
     mv.name,
     mv.comment,
     mv.metadata,
-    mv.last_updated_time
+    mv.last_updated_time,
+    array_to_json(mv.aliases) AS aliases,
+    'STAGE_' || mv.stage AS stage
 FROM proto_checkpoints_view c, mv, m
 WHERE c.uuid = mv.checkpoint_uuid;
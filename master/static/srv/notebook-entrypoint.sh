@@ -13,6 +13,17 @@ export SHELL
 
 "$DET_PYTHON_EXECUTABLE" -m determined.exec.prep_container --resources --proxy --download_context_directory
 
+if [ -n "$DET_NOTEBOOK_DISCARD_STORAGE_ID" ]; then
+    # The notebook was killed while suspended and only starts to delete its archive.
+    exec "$DET_PYTHON_EXECUTABLE" -m determined.exec.notebook_suspension \
+        --storage-id "$DET_NOTEBOOK_DISCARD_STORAGE_ID" --discard
+fi
+
+if [ -n "$DET_NOTEBOOK_RESTORE_STORAGE_ID" ]; then
+    "$DET_PYTHON_EXECUTABLE" -m determined.exec.notebook_suspension \
+        --storage-id "$DET_NOTEBOOK_RESTORE_STORAGE_ID"
+fi
+
 STARTUP_HOOK="startup-hook.sh"
 set -x
 test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
@@ -12,6 +12,10 @@ test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
 test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
 set +x
 
+if [ "$DET_REPORT_GPU_UTILIZATION" = "1" ]; then
+    "$DET_PYTHON_EXECUTABLE" -m determined.exec.gpu_utilization &
+fi
+
 # Prepend each key in authorized_keys with a set of environment="KEY=VALUE"
 # options to inject the entire docker environment into the eventual ssh
 # session via an options in the authorized keys file.  See syntax described in
@@ -2,7 +2,7 @@ WITH mv AS (
     UPDATE model_versions
     SET
         name = $3, comment = $4, notes = $5, metadata = $6, labels = string_to_array($7, ','),
-        last_updated_time = current_timestamp
+        stage = $8, last_updated_time = current_timestamp
     WHERE id = $1
     RETURNING id,
     version,
@@ -14,7 +14,9 @@ WITH mv AS (
     comment,
     notes,
     labels,
-    metadata
+    metadata,
+    aliases,
+    stage
 ),
 
 m AS (
@@ -54,5 +56,7 @@ SELECT
     mv.name,
     mv.comment,
     mv.notes,
-    mv.metadata
+    mv.metadata,
+    array_to_json(mv.aliases) AS aliases,
+    'STAGE_' || mv.stage AS stage
 FROM c, m, mv;